@@ -0,0 +1,340 @@
+package envauditv1
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ScanServiceName is the fully qualified name of the service in scan.proto
+const ScanServiceName = "envaudit.v1.ScanService"
+
+// scanPath is the HTTP/2 path of the Scan method
+const scanPath = "/" + ScanServiceName + "/Scan"
+
+// MaxMessageSize bounds a received message, as gRPC's own default does
+const MaxMessageSize = 4 << 20
+
+// Code is a gRPC status code
+type Code uint32
+
+// The gRPC status codes the scan service returns
+const (
+	OK                Code = 0
+	Canceled          Code = 1
+	Unknown           Code = 2
+	InvalidArgument   Code = 3
+	ResourceExhausted Code = 8
+	Unimplemented     Code = 12
+	Internal          Code = 13
+	Unavailable       Code = 14
+)
+
+// Status is a failed call's gRPC status, returned as its error
+type Status struct {
+	Code    Code
+	Message string
+}
+
+func (s *Status) Error() string {
+	return fmt.Sprintf("rpc error: code = %d desc = %s", s.Code, s.Message)
+}
+
+// Errorf returns a status error with the given code
+func Errorf(code Code, format string, a ...interface{}) error {
+	return &Status{Code: code, Message: fmt.Sprintf(format, a...)}
+}
+
+// statusOf converts an error returned by a handler to its status
+func statusOf(err error) *Status {
+	var st *Status
+	switch {
+	case err == nil:
+		return &Status{Code: OK}
+	case errors.As(err, &st):
+		return st
+	case errors.Is(err, context.Canceled):
+		return &Status{Code: Canceled, Message: err.Error()}
+	default:
+		return &Status{Code: Unknown, Message: err.Error()}
+	}
+}
+
+// ScanServiceServer is the server side of the scan service
+type ScanServiceServer interface {
+	// Scan audits the files of req, sending each issue and then a summary
+	// on stream. A returned *Status is sent to the client as is; any other
+	// error as Unknown.
+	Scan(req *ScanRequest, stream ScanServerStream) error
+}
+
+// ScanServerStream sends the responses of one Scan call
+type ScanServerStream interface {
+	// Context is cancelled when the client goes away
+	Context() context.Context
+	Send(*ScanResponse) error
+}
+
+// NewScanServiceHandler serves srv as gRPC over HTTP/2. The handler needs
+// a server accepting HTTP/2, such as one from NewServer.
+func NewScanServiceHandler(srv ScanServiceServer) http.Handler {
+	return &scanHandler{srv: srv}
+}
+
+// NewServer returns an HTTP server for handler that accepts HTTP/2 without
+// TLS (prior knowledge), as gRPC clients connect to plaintext targets
+func NewServer(addr string, handler http.Handler) *http.Server {
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+	return &http.Server{Addr: addr, Handler: handler, Protocols: &protocols}
+}
+
+type scanHandler struct {
+	srv ScanServiceServer
+}
+
+func (h *scanHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Plain HTTP callers get a plain HTTP error
+	switch {
+	case r.Method != http.MethodPost:
+		http.Error(w, "gRPC requires POST", http.StatusMethodNotAllowed)
+		return
+	case !isGRPCContentType(r.Header.Get("Content-Type")):
+		http.Error(w, "content type must be application/grpc", http.StatusUnsupportedMediaType)
+		return
+	case r.ProtoMajor != 2:
+		http.Error(w, "gRPC requires HTTP/2", http.StatusHTTPVersionNotSupported)
+		return
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", "application/grpc")
+	header.Set("Grpc-Accept-Encoding", "identity")
+	header.Add("Trailer", "Grpc-Status")
+	header.Add("Trailer", "Grpc-Message")
+	w.WriteHeader(http.StatusOK)
+
+	switch {
+	case r.URL.Path != scanPath:
+		writeStatus(w, &Status{Code: Unimplemented, Message: "unknown method " + r.URL.Path})
+		return
+	case r.Header.Get("Grpc-Encoding") != "" && r.Header.Get("Grpc-Encoding") != "identity":
+		writeStatus(w, &Status{Code: Unimplemented, Message: "unsupported compression " + r.Header.Get("Grpc-Encoding")})
+		return
+	}
+
+	req := &ScanRequest{}
+	payload, err := readMessage(r.Body)
+	if err == io.EOF {
+		err = Errorf(InvalidArgument, "missing request message")
+	}
+	if err == nil {
+		// Scan is a unary-request call
+		if _, extra := readMessage(r.Body); extra != io.EOF {
+			err = Errorf(InvalidArgument, "expected a single request message")
+		}
+	}
+	if err == nil {
+		if err = req.Unmarshal(payload); err != nil {
+			err = Errorf(InvalidArgument, "%v", err)
+		}
+	}
+	if err == nil {
+		err = h.srv.Scan(req, &serverStream{ctx: r.Context(), w: w})
+	}
+	writeStatus(w, statusOf(err))
+}
+
+// serverStream writes length-prefixed responses to the HTTP/2 stream
+type serverStream struct {
+	ctx context.Context
+	w   http.ResponseWriter
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *serverStream) Send(m *ScanResponse) error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	if _, err := s.w.Write(frame(m.Marshal())); err != nil {
+		return err
+	}
+	// Each issue reaches the client as soon as it is found
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// writeStatus sets the trailers that end a call
+func writeStatus(w http.ResponseWriter, st *Status) {
+	w.Header().Set("Grpc-Status", strconv.FormatUint(uint64(st.Code), 10))
+	if st.Message != "" {
+		w.Header().Set("Grpc-Message", encodeGRPCMessage(st.Message))
+	}
+}
+
+// ScanServiceClient calls the scan service of a server
+type ScanServiceClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewScanServiceClient returns a client for the server at target, a
+// host:port or an http:// URL. A nil client connects over HTTP/2 without
+// TLS, as to a server from NewServer.
+func NewScanServiceClient(target string, client *http.Client) *ScanServiceClient {
+	if !strings.Contains(target, "://") {
+		target = "http://" + target
+	}
+	if client == nil {
+		var protocols http.Protocols
+		protocols.SetUnencryptedHTTP2(true)
+		client = &http.Client{Transport: &http.Transport{Protocols: &protocols}}
+	}
+	return &ScanServiceClient{baseURL: strings.TrimRight(target, "/"), client: client}
+}
+
+// Scan starts a Scan call; read its responses with Recv. Cancelling ctx
+// cancels the call.
+func (c *ScanServiceClient) Scan(ctx context.Context, req *ScanRequest) (*ScanClientStream, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+scanPath, bytes.NewReader(frame(req.Marshal())))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/grpc")
+	httpReq.Header.Set("Te", "trailers")
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, Errorf(Unavailable, "server returned HTTP %s", resp.Status)
+	}
+	return &ScanClientStream{resp: resp}, nil
+}
+
+// ScanClientStream receives the responses of one Scan call
+type ScanClientStream struct {
+	resp *http.Response
+	err  error
+}
+
+// Recv returns the next response. After the last one it returns io.EOF if
+// the call succeeded, or its *Status.
+func (s *ScanClientStream) Recv() (*ScanResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	payload, err := readMessage(s.resp.Body)
+	if err == io.EOF {
+		err = s.status()
+	}
+	if err == nil {
+		m := &ScanResponse{}
+		if err = m.Unmarshal(payload); err == nil {
+			return m, nil
+		}
+		err = Errorf(Internal, "%v", err)
+	}
+	s.err = err
+	s.resp.Body.Close()
+	return nil, err
+}
+
+// Close abandons the call
+func (s *ScanClientStream) Close() error {
+	return s.resp.Body.Close()
+}
+
+// status reads the call's status once the stream has ended: io.EOF for OK
+func (s *ScanClientStream) status() error {
+	// A call that fails before sending anything may put its status in the headers
+	code := s.resp.Trailer.Get("Grpc-Status")
+	message := s.resp.Trailer.Get("Grpc-Message")
+	if code == "" {
+		code = s.resp.Header.Get("Grpc-Status")
+		message = s.resp.Header.Get("Grpc-Message")
+	}
+	if code == "" {
+		return Errorf(Internal, "stream ended without a grpc-status")
+	}
+	n, err := strconv.ParseUint(code, 10, 32)
+	if err != nil {
+		return Errorf(Internal, "invalid grpc-status %q", code)
+	}
+	if Code(n) == OK {
+		return io.EOF
+	}
+	if decoded, err := url.PathUnescape(message); err == nil {
+		message = decoded
+	}
+	return &Status{Code: Code(n), Message: message}
+}
+
+// frame prefixes a message with the gRPC header: an uncompressed flag and
+// the message length
+func frame(payload []byte) []byte {
+	b := make([]byte, 5, 5+len(payload))
+	binary.BigEndian.PutUint32(b[1:], uint32(len(payload)))
+	return append(b, payload...)
+}
+
+// readMessage reads one length-prefixed message, returning io.EOF if the
+// stream ends cleanly before it
+func readMessage(r io.Reader) ([]byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, Errorf(Internal, "truncated message header")
+		}
+		return nil, err
+	}
+	if header[0] != 0 {
+		return nil, Errorf(Internal, "compressed message without a negotiated encoding")
+	}
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > MaxMessageSize {
+		return nil, Errorf(ResourceExhausted, "message of %d bytes exceeds the limit of %d", size, MaxMessageSize)
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, Errorf(Internal, "truncated message")
+		}
+		return nil, err
+	}
+	return payload, nil
+}
+
+// isGRPCContentType accepts application/grpc and its +proto form
+func isGRPCContentType(contentType string) bool {
+	return contentType == "application/grpc" || contentType == "application/grpc+proto"
+}
+
+// encodeGRPCMessage percent-encodes a status message, as the gRPC wire
+// protocol requires for bytes outside printable ASCII
+func encodeGRPCMessage(message string) string {
+	var sb strings.Builder
+	for i := 0; i < len(message); i++ {
+		c := message[i]
+		if c < ' ' || c > '~' || c == '%' {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		} else {
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
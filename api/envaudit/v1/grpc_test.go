@@ -0,0 +1,165 @@
+package envauditv1
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+)
+
+// stubService answers every Scan with fixed responses, then err
+type stubService struct {
+	responses []*ScanResponse
+	err       error
+	got       *ScanRequest
+}
+
+func (s *stubService) Scan(req *ScanRequest, stream ScanServerStream) error {
+	s.got = req
+	for _, resp := range s.responses {
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+	return s.err
+}
+
+// startServer serves srv on a loopback port and returns its address
+func startServer(t *testing.T, srv ScanServiceServer) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewServer("", NewScanServiceHandler(srv))
+	go server.Serve(ln)
+	t.Cleanup(func() { server.Close() })
+	return ln.Addr().String()
+}
+
+// recvAll reads a stream to its end, returning the responses and final error
+func recvAll(stream *ScanClientStream) ([]*ScanResponse, error) {
+	var responses []*ScanResponse
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return responses, err
+		}
+		responses = append(responses, resp)
+	}
+}
+
+func TestScan_StreamsResponses(t *testing.T) {
+	srv := &stubService{responses: []*ScanResponse{
+		{Issue: &Issue{ID: "1", Key: "A"}},
+		{Issue: &Issue{ID: "2", Key: "B"}},
+		{Summary: &Summary{HasRisks: true}},
+	}}
+	client := NewScanServiceClient(startServer(t, srv), nil)
+
+	stream, err := client.Scan(context.Background(), &ScanRequest{Files: []*File{{Path: ".env", Content: []byte("A=1")}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	responses, err := recvAll(stream)
+
+	if err != io.EOF {
+		t.Fatalf("expected the stream to end with io.EOF, got %v", err)
+	}
+	if len(responses) != 3 || responses[1].Issue.Key != "B" || !responses[2].Summary.HasRisks {
+		t.Errorf("unexpected responses %+v", responses)
+	}
+	if srv.got == nil || srv.got.Files[0].Path != ".env" || string(srv.got.Files[0].Content) != "A=1" {
+		t.Errorf("server received %+v", srv.got)
+	}
+}
+
+func TestScan_ReturnsStatus(t *testing.T) {
+	srv := &stubService{
+		responses: []*ScanResponse{{Issue: &Issue{ID: "1"}}},
+		err:       &Status{Code: InvalidArgument, Message: "bad file «.env»: 100%"},
+	}
+	client := NewScanServiceClient(startServer(t, srv), nil)
+
+	stream, err := client.Scan(context.Background(), &ScanRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	responses, err := recvAll(stream)
+
+	var st *Status
+	if !errors.As(err, &st) || st.Code != InvalidArgument || st.Message != "bad file «.env»: 100%" {
+		t.Fatalf("expected the handler's status, got %v", err)
+	}
+	if len(responses) != 1 {
+		t.Errorf("expected the response sent before the error, got %d", len(responses))
+	}
+}
+
+func TestScan_OtherErrorsAreUnknown(t *testing.T) {
+	client := NewScanServiceClient(startServer(t, &stubService{err: errors.New("disk on fire")}), nil)
+
+	stream, err := client.Scan(context.Background(), &ScanRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = recvAll(stream)
+
+	var st *Status
+	if !errors.As(err, &st) || st.Code != Unknown || st.Message != "disk on fire" {
+		t.Errorf("expected an Unknown status, got %v", err)
+	}
+}
+
+func TestHandler_RejectsBadCalls(t *testing.T) {
+	addr := startServer(t, &stubService{})
+	h2c := NewScanServiceClient(addr, nil).client
+
+	// Unknown methods of the service are Unimplemented
+	req, _ := http.NewRequest(http.MethodPost, "http://"+addr+"/"+ScanServiceName+"/Watch", bytes.NewReader(frame(nil)))
+	req.Header.Set("Content-Type", "application/grpc")
+	resp, err := h2c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if got := resp.Trailer.Get("Grpc-Status"); got != "12" {
+		t.Errorf("expected Unimplemented for an unknown method, got grpc-status %q", got)
+	}
+
+	// A request that is not a single message is InvalidArgument
+	stream := &ScanClientStream{}
+	req, _ = http.NewRequest(http.MethodPost, "http://"+addr+scanPath, bytes.NewReader(append(frame(nil), frame(nil)...)))
+	req.Header.Set("Content-Type", "application/grpc")
+	if stream.resp, err = h2c.Do(req); err != nil {
+		t.Fatal(err)
+	}
+	var st *Status
+	if _, err := recvAll(stream); !errors.As(err, &st) || st.Code != InvalidArgument {
+		t.Errorf("expected InvalidArgument for two request messages, got %v", err)
+	}
+
+	// gRPC needs HTTP/2
+	resp, err = http.Post("http://"+addr+scanPath, "application/grpc", bytes.NewReader(frame(nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusHTTPVersionNotSupported {
+		t.Errorf("expected HTTP 505 over HTTP/1.1, got %d", resp.StatusCode)
+	}
+}
+
+func TestReadMessage_EnforcesLimit(t *testing.T) {
+	header := []byte{0, 0xff, 0xff, 0xff, 0xff}
+	_, err := readMessage(bytes.NewReader(header))
+
+	var st *Status
+	if !errors.As(err, &st) || st.Code != ResourceExhausted {
+		t.Errorf("expected ResourceExhausted, got %v", err)
+	}
+}
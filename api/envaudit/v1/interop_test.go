@@ -0,0 +1,217 @@
+package envauditv1
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// These tests check the hand-written transport and codec against grpc-go
+// and the protobuf runtime, with messages built from scan.proto itself
+// rather than from this package.
+
+// compileContract compiles scan.proto to its descriptors
+func compileContract(t *testing.T) protoreflect.FileDescriptor {
+	t.Helper()
+	compiler := protocompile.Compiler{Resolver: &protocompile.SourceResolver{}}
+	files, err := compiler.Compile(context.Background(), "scan.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files[0]
+}
+
+// dynamicMessage returns the message name of the contract parsed from its
+// JSON form
+func dynamicMessage(t *testing.T, fd protoreflect.FileDescriptor, name, json string) *dynamicpb.Message {
+	t.Helper()
+	m := dynamicpb.NewMessage(fd.Messages().ByName(protoreflect.Name(name)))
+	if err := protojson.Unmarshal([]byte(json), m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// dialGRPC connects a grpc-go client to addr over plaintext HTTP/2
+func dialGRPC(t *testing.T, addr string) *grpc.ClientConn {
+	t.Helper()
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// callScan makes a Scan call with a grpc-go client, returning the
+// responses and the error that ended the stream
+func callScan(t *testing.T, fd protoreflect.FileDescriptor, conn *grpc.ClientConn, method string, req proto.Message) ([]*dynamicpb.Message, error) {
+	t.Helper()
+	stream, err := conn.NewStream(context.Background(), &grpc.StreamDesc{ServerStreams: true}, method)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.SendMsg(req); err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+	var responses []*dynamicpb.Message
+	for {
+		resp := dynamicpb.NewMessage(fd.Messages().ByName("ScanResponse"))
+		if err := stream.RecvMsg(resp); err != nil {
+			return responses, err
+		}
+		responses = append(responses, resp)
+	}
+}
+
+func TestInterop_GRPCGoClient(t *testing.T) {
+	fd := compileContract(t)
+	srv := &stubService{responses: []*ScanResponse{
+		{Issue: &Issue{
+			ID: "EA001", Type: "missing", Key: "API_KEY", Message: "required key is missing «café»",
+			File: ".env", Line: -1, Severity: "error",
+			Remediation: &Remediation{Action: "add", Key: "API_KEY", File: ".env"},
+		}},
+		{Summary: &Summary{
+			HasRisks:   true,
+			ByType:     map[string]int32{"missing": 1, "leak": 0},
+			BySeverity: map[string]int32{"error": 1},
+			Files:      []*FileStatus{{Path: ".env", Status: "ok"}, {Path: "bad.env", Status: "error", Error: "unterminated quote"}},
+		}},
+	}}
+	conn := dialGRPC(t, startServer(t, srv))
+
+	req := dynamicMessage(t, fd, "ScanRequest", `{
+		"files": [{"path": ".env", "content": "QT0xCg=="}, {"path": "bad.env"}],
+		"example": {"path": ".env.example", "content": "QT0K"},
+		"required": ["API_KEY", ""],
+		"ignore": ["DEBUG"],
+		"checkLeaks": true,
+		"failOn": "warning",
+		"only": ["missing"],
+		"exclude": ["empty", "leak"]
+	}`)
+	responses, err := callScan(t, fd, conn, "/envaudit.v1.ScanService/Scan", req)
+
+	if err != io.EOF {
+		t.Fatalf("expected the stream to end cleanly, got %v", err)
+	}
+	wantReq := &ScanRequest{
+		Files:      []*File{{Path: ".env", Content: []byte("A=1\n")}, {Path: "bad.env"}},
+		Example:    &File{Path: ".env.example", Content: []byte("A=\n")},
+		Required:   []string{"API_KEY", ""},
+		Ignore:     []string{"DEBUG"},
+		CheckLeaks: true,
+		FailOn:     "warning",
+		Only:       []string{"missing"},
+		Exclude:    []string{"empty", "leak"},
+	}
+	if !reflect.DeepEqual(srv.got, wantReq) {
+		t.Errorf("server decoded %+v, want %+v", srv.got, wantReq)
+	}
+
+	want := []*dynamicpb.Message{
+		dynamicMessage(t, fd, "ScanResponse", `{"issue": {
+			"id": "EA001", "type": "missing", "key": "API_KEY", "message": "required key is missing «café»",
+			"file": ".env", "line": -1, "severity": "error",
+			"remediation": {"action": "add", "key": "API_KEY", "file": ".env"}
+		}}`),
+		dynamicMessage(t, fd, "ScanResponse", `{"summary": {
+			"hasRisks": true,
+			"byType": {"missing": 1, "leak": 0},
+			"bySeverity": {"error": 1},
+			"files": [{"path": ".env", "status": "ok"}, {"path": "bad.env", "status": "error", "error": "unterminated quote"}]
+		}}`),
+	}
+	if len(responses) != len(want) {
+		t.Fatalf("expected %d responses, got %d", len(want), len(responses))
+	}
+	for i := range want {
+		if !proto.Equal(responses[i], want[i]) {
+			t.Errorf("response %d: got %v, want %v", i, responses[i], want[i])
+		}
+	}
+}
+
+func TestInterop_GRPCGoClientStatus(t *testing.T) {
+	fd := compileContract(t)
+	srv := &stubService{err: &Status{Code: InvalidArgument, Message: "bad file «.env»: 100%"}}
+	conn := dialGRPC(t, startServer(t, srv))
+	req := dynamicMessage(t, fd, "ScanRequest", `{"files": [{"path": ".env"}]}`)
+
+	_, err := callScan(t, fd, conn, "/envaudit.v1.ScanService/Scan", req)
+	if st := status.Convert(err); st.Code() != codes.InvalidArgument || st.Message() != "bad file «.env»: 100%" {
+		t.Errorf("expected the handler's status, got %v", err)
+	}
+
+	_, err = callScan(t, fd, conn, "/envaudit.v1.ScanService/Watch", req)
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("expected Unimplemented for an unknown method, got %v", err)
+	}
+}
+
+func TestInterop_GRPCGoServer(t *testing.T) {
+	fd := compileContract(t)
+	var got *dynamicpb.Message
+	handler := func(_ interface{}, stream grpc.ServerStream) error {
+		got = dynamicpb.NewMessage(fd.Messages().ByName("ScanRequest"))
+		if err := stream.RecvMsg(got); err != nil {
+			return err
+		}
+		resp := dynamicMessage(t, fd, "ScanResponse", `{"issue": {"id": "EA001", "key": "API_KEY", "line": 3}}`)
+		if err := stream.SendMsg(resp); err != nil {
+			return err
+		}
+		return status.Error(codes.ResourceExhausted, "too many files: 100%")
+	}
+	server := grpc.NewServer()
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: ScanServiceName,
+		HandlerType: (*interface{})(nil),
+		Streams:     []grpc.StreamDesc{{StreamName: "Scan", Handler: handler, ServerStreams: true}},
+	}, struct{}{})
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve(ln)
+	t.Cleanup(server.Stop)
+
+	client := NewScanServiceClient(ln.Addr().String(), nil)
+	stream, err := client.Scan(context.Background(), &ScanRequest{
+		Files:      []*File{{Path: ".env", Content: []byte("A=1\n")}},
+		CheckLeaks: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	responses, err := recvAll(stream)
+
+	var st *Status
+	if !errors.As(err, &st) || st.Code != ResourceExhausted || st.Message != "too many files: 100%" {
+		t.Errorf("expected the server's status, got %v", err)
+	}
+	if len(responses) != 1 || !reflect.DeepEqual(responses[0].Issue, &Issue{ID: "EA001", Key: "API_KEY", Line: 3}) {
+		t.Errorf("unexpected responses %+v", responses)
+	}
+	wantReq := dynamicMessage(t, fd, "ScanRequest", `{"files": [{"path": ".env", "content": "QT0xCg=="}], "checkLeaks": true}`)
+	if !proto.Equal(got, wantReq) {
+		t.Errorf("grpc-go server decoded %v, want %v", got, wantReq)
+	}
+}
@@ -0,0 +1,352 @@
+// Package envauditv1 implements the env-audit scan service of scan.proto:
+// its messages in the protobuf wire format, and the gRPC transport over
+// HTTP/2 for both the server and a client. It is written against the
+// standard library, so the module needs no gRPC or protobuf dependency
+// outside the tests that check it against grpc-go; clients in other
+// languages are generated from scan.proto as usual.
+package envauditv1
+
+// ScanRequest lists the env files to audit and the checks to run
+type ScanRequest struct {
+	Files      []*File
+	Example    *File // optional
+	Required   []string
+	Ignore     []string
+	CheckLeaks bool
+	FailOn     string // error (default), warning or info
+	Only       []string
+	Exclude    []string
+}
+
+// File is an env file, by name and content
+type File struct {
+	Path    string
+	Content []byte
+}
+
+// ScanResponse is one event of the Scan stream. Exactly one of Issue and
+// Summary is set (the event oneof).
+type ScanResponse struct {
+	Issue   *Issue
+	Summary *Summary
+}
+
+// Issue is a finding, with the fields of an issue in the JSON report
+type Issue struct {
+	ID          string
+	Type        string
+	Key         string
+	Message     string
+	File        string
+	Line        int32
+	Rule        string
+	Docs        string
+	Severity    string
+	Remediation *Remediation
+}
+
+// Remediation is a machine-readable fix for an issue
+type Remediation struct {
+	Action  string
+	Key     string
+	File    string
+	Command string
+}
+
+// Summary closes the stream with the outcome of the scan
+type Summary struct {
+	HasRisks   bool
+	ByType     map[string]int32
+	BySeverity map[string]int32
+	Files      []*FileStatus
+}
+
+// FileStatus is the scan status of one requested file
+type FileStatus struct {
+	Path   string
+	Status string // ok or error
+	Error  string
+}
+
+// Marshal encodes the request in the protobuf wire format
+func (m *ScanRequest) Marshal() []byte {
+	var e encoder
+	for _, f := range m.Files {
+		e.message(1, f.Marshal())
+	}
+	if m.Example != nil {
+		e.message(2, m.Example.Marshal())
+	}
+	e.strings(3, m.Required)
+	e.strings(4, m.Ignore)
+	e.bool(5, m.CheckLeaks)
+	e.string(6, m.FailOn)
+	e.strings(7, m.Only)
+	e.strings(8, m.Exclude)
+	return e.buf
+}
+
+// Unmarshal decodes a request in the protobuf wire format
+func (m *ScanRequest) Unmarshal(b []byte) error {
+	d := decoder{buf: b}
+	lists := map[int]*[]string{3: &m.Required, 4: &m.Ignore, 7: &m.Only, 8: &m.Exclude}
+	for !d.done() {
+		field, wireType, err := d.key()
+		if err != nil {
+			return err
+		}
+		switch {
+		case (field == 1 || field == 2) && wireType == wireBytes:
+			var raw []byte
+			if raw, err = d.bytes(); err != nil {
+				return err
+			}
+			f := &File{}
+			err = f.Unmarshal(raw)
+			if field == 1 {
+				m.Files = append(m.Files, f)
+			} else {
+				m.Example = f
+			}
+		case lists[field] != nil && wireType == wireBytes:
+			var s string
+			s, err = d.string()
+			*lists[field] = append(*lists[field], s)
+		case field == 5 && wireType == wireVarint:
+			m.CheckLeaks, err = d.bool()
+		case field == 6 && wireType == wireBytes:
+			m.FailOn, err = d.string()
+		default:
+			err = d.skip(wireType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Marshal encodes the file in the protobuf wire format
+func (m *File) Marshal() []byte {
+	var e encoder
+	e.string(1, m.Path)
+	e.bytes(2, m.Content)
+	return e.buf
+}
+
+// Unmarshal decodes a file in the protobuf wire format
+func (m *File) Unmarshal(b []byte) error {
+	d := decoder{buf: b}
+	for !d.done() {
+		field, wireType, err := d.key()
+		if err != nil {
+			return err
+		}
+		switch {
+		case field == 1 && wireType == wireBytes:
+			m.Path, err = d.string()
+		case field == 2 && wireType == wireBytes:
+			var raw []byte
+			raw, err = d.bytes()
+			m.Content = append([]byte(nil), raw...)
+		default:
+			err = d.skip(wireType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Marshal encodes the response in the protobuf wire format
+func (m *ScanResponse) Marshal() []byte {
+	var e encoder
+	switch {
+	case m.Issue != nil:
+		e.message(1, m.Issue.Marshal())
+	case m.Summary != nil:
+		e.message(2, m.Summary.Marshal())
+	}
+	return e.buf
+}
+
+// Unmarshal decodes a response in the protobuf wire format. As with any
+// oneof, the last member on the wire wins.
+func (m *ScanResponse) Unmarshal(b []byte) error {
+	d := decoder{buf: b}
+	for !d.done() {
+		field, wireType, err := d.key()
+		if err != nil {
+			return err
+		}
+		if (field != 1 && field != 2) || wireType != wireBytes {
+			if err := d.skip(wireType); err != nil {
+				return err
+			}
+			continue
+		}
+		raw, err := d.bytes()
+		if err != nil {
+			return err
+		}
+		if field == 1 {
+			m.Issue, m.Summary = &Issue{}, nil
+			err = m.Issue.Unmarshal(raw)
+		} else {
+			m.Issue, m.Summary = nil, &Summary{}
+			err = m.Summary.Unmarshal(raw)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Marshal encodes the issue in the protobuf wire format
+func (m *Issue) Marshal() []byte {
+	var e encoder
+	e.string(1, m.ID)
+	e.string(2, m.Type)
+	e.string(3, m.Key)
+	e.string(4, m.Message)
+	e.string(5, m.File)
+	e.int32(6, m.Line)
+	e.string(7, m.Rule)
+	e.string(8, m.Docs)
+	e.string(9, m.Severity)
+	if m.Remediation != nil {
+		e.message(10, m.Remediation.Marshal())
+	}
+	return e.buf
+}
+
+// Unmarshal decodes an issue in the protobuf wire format
+func (m *Issue) Unmarshal(b []byte) error {
+	d := decoder{buf: b}
+	text := map[int]*string{1: &m.ID, 2: &m.Type, 3: &m.Key, 4: &m.Message, 5: &m.File, 7: &m.Rule, 8: &m.Docs, 9: &m.Severity}
+	for !d.done() {
+		field, wireType, err := d.key()
+		if err != nil {
+			return err
+		}
+		switch {
+		case text[field] != nil && wireType == wireBytes:
+			*text[field], err = d.string()
+		case field == 6 && wireType == wireVarint:
+			m.Line, err = d.int32()
+		case field == 10 && wireType == wireBytes:
+			var raw []byte
+			if raw, err = d.bytes(); err == nil {
+				m.Remediation = &Remediation{}
+				err = m.Remediation.Unmarshal(raw)
+			}
+		default:
+			err = d.skip(wireType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Marshal encodes the remediation in the protobuf wire format
+func (m *Remediation) Marshal() []byte {
+	var e encoder
+	e.string(1, m.Action)
+	e.string(2, m.Key)
+	e.string(3, m.File)
+	e.string(4, m.Command)
+	return e.buf
+}
+
+// Unmarshal decodes a remediation in the protobuf wire format
+func (m *Remediation) Unmarshal(b []byte) error {
+	return unmarshalStrings(b, map[int]*string{1: &m.Action, 2: &m.Key, 3: &m.File, 4: &m.Command})
+}
+
+// Marshal encodes the summary in the protobuf wire format
+func (m *Summary) Marshal() []byte {
+	var e encoder
+	e.bool(1, m.HasRisks)
+	e.counts(2, m.ByType)
+	e.counts(3, m.BySeverity)
+	for _, f := range m.Files {
+		e.message(4, f.Marshal())
+	}
+	return e.buf
+}
+
+// Unmarshal decodes a summary in the protobuf wire format
+func (m *Summary) Unmarshal(b []byte) error {
+	d := decoder{buf: b}
+	for !d.done() {
+		field, wireType, err := d.key()
+		if err != nil {
+			return err
+		}
+		switch {
+		case field == 1 && wireType == wireVarint:
+			m.HasRisks, err = d.bool()
+		case field == 2 && wireType == wireBytes:
+			if m.ByType == nil {
+				m.ByType = make(map[string]int32)
+			}
+			err = d.count(m.ByType)
+		case field == 3 && wireType == wireBytes:
+			if m.BySeverity == nil {
+				m.BySeverity = make(map[string]int32)
+			}
+			err = d.count(m.BySeverity)
+		case field == 4 && wireType == wireBytes:
+			var raw []byte
+			if raw, err = d.bytes(); err == nil {
+				f := &FileStatus{}
+				err = f.Unmarshal(raw)
+				m.Files = append(m.Files, f)
+			}
+		default:
+			err = d.skip(wireType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Marshal encodes the file status in the protobuf wire format
+func (m *FileStatus) Marshal() []byte {
+	var e encoder
+	e.string(1, m.Path)
+	e.string(2, m.Status)
+	e.string(3, m.Error)
+	return e.buf
+}
+
+// Unmarshal decodes a file status in the protobuf wire format
+func (m *FileStatus) Unmarshal(b []byte) error {
+	return unmarshalStrings(b, map[int]*string{1: &m.Path, 2: &m.Status, 3: &m.Error})
+}
+
+// unmarshalStrings decodes a message whose fields are all strings
+func unmarshalStrings(b []byte, fields map[int]*string) error {
+	d := decoder{buf: b}
+	for !d.done() {
+		field, wireType, err := d.key()
+		if err != nil {
+			return err
+		}
+		if target := fields[field]; target != nil && wireType == wireBytes {
+			*target, err = d.string()
+		} else {
+			err = d.skip(wireType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package envauditv1
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestScanRequest_RoundTrip(t *testing.T) {
+	req := &ScanRequest{
+		Files:      []*File{{Path: ".env", Content: []byte("A=1\n")}, {Path: ".env.local"}},
+		Example:    &File{Path: ".env.example", Content: []byte("A=\n")},
+		Required:   []string{"A", ""},
+		Ignore:     []string{"B"},
+		CheckLeaks: true,
+		FailOn:     "warning",
+		Only:       []string{"leak"},
+		Exclude:    []string{"extra"},
+	}
+
+	got := &ScanRequest{}
+	if err := got.Unmarshal(req.Marshal()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, req) {
+		t.Errorf("round trip changed the request:\n got %+v\nwant %+v", got, req)
+	}
+}
+
+func TestScanResponse_RoundTrip(t *testing.T) {
+	responses := []*ScanResponse{
+		{Issue: &Issue{
+			ID: "abc", Type: "leak", Key: "TOKEN", Message: "looks like a token", File: ".env", Line: 3,
+			Rule: "github-pat", Docs: "https://example.com", Severity: "error",
+			Remediation: &Remediation{Action: "rotate_secret", Key: "TOKEN", File: ".env"},
+		}},
+		{Summary: &Summary{
+			HasRisks:   true,
+			ByType:     map[string]int32{"leak": 1, "extra": 2},
+			BySeverity: map[string]int32{"error": 1, "info": 2},
+			Files:      []*FileStatus{{Path: ".env", Status: "ok"}, {Path: "bad.env", Status: "error", Error: "boom"}},
+		}},
+		// An empty summary is still a summary
+		{Summary: &Summary{}},
+	}
+	for _, resp := range responses {
+		got := &ScanResponse{}
+		if err := got.Unmarshal(resp.Marshal()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(got, resp) {
+			t.Errorf("round trip changed the response:\n got %+v\nwant %+v", got, resp)
+		}
+	}
+}
+
+func TestMarshal_WireFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		got  []byte
+		want []byte
+	}{
+		{"file", (&File{Path: "a", Content: []byte("x")}).Marshal(), []byte{0x0a, 1, 'a', 0x12, 1, 'x'}},
+		{"map entry", (&Summary{ByType: map[string]int32{"leak": 2}}).Marshal(), []byte{0x12, 8, 0x0a, 4, 'l', 'e', 'a', 'k', 0x10, 2}},
+		{"negative int32", (&Issue{Line: -1}).Marshal(), []byte{0x30, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}},
+		{"empty oneof member", (&ScanResponse{Summary: &Summary{}}).Marshal(), []byte{0x12, 0}},
+	}
+	for _, tt := range tests {
+		if !bytes.Equal(tt.got, tt.want) {
+			t.Errorf("%s: got % x, want % x", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestUnmarshal_SkipsUnknownFields(t *testing.T) {
+	var e encoder
+	e.string(1, ".env")
+	e.int32(15, 7)                                // varint
+	e.message(16, []byte("future"))               // length-delimited
+	e.buf = append(e.buf, 0x8d, 0x01, 1, 2, 3, 4) // field 17, fixed32
+	e.bytes(2, []byte("A=1"))
+
+	f := &File{}
+	if err := f.Unmarshal(e.buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.Path != ".env" || string(f.Content) != "A=1" {
+		t.Errorf("unexpected file %+v", f)
+	}
+}
+
+func TestUnmarshal_RejectsMalformed(t *testing.T) {
+	inputs := map[string][]byte{
+		"truncated length": {0x0a, 5, 'a'},
+		"truncated varint": {0x0a, 0x80},
+		"invalid UTF-8":    {0x0a, 1, 0xff},
+		"field zero":       {0x02, 0},
+	}
+	for name, b := range inputs {
+		if err := (&File{}).Unmarshal(b); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package envauditv1
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// Protobuf wire types used by scan.proto
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errTruncated = errors.New("proto: truncated message")
+
+// encoder appends fields in the protobuf wire format. Scalars at their
+// zero value are omitted, as proto3 does.
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) key(field, wireType int) {
+	e.buf = binary.AppendUvarint(e.buf, uint64(field)<<3|uint64(wireType))
+}
+
+func (e *encoder) string(field int, s string) {
+	if s != "" {
+		e.key(field, wireBytes)
+		e.buf = binary.AppendUvarint(e.buf, uint64(len(s)))
+		e.buf = append(e.buf, s...)
+	}
+}
+
+func (e *encoder) bytes(field int, b []byte) {
+	if len(b) > 0 {
+		e.message(field, b)
+	}
+}
+
+// strings writes a repeated string field; empty elements are kept
+func (e *encoder) strings(field int, list []string) {
+	for _, s := range list {
+		e.key(field, wireBytes)
+		e.buf = binary.AppendUvarint(e.buf, uint64(len(s)))
+		e.buf = append(e.buf, s...)
+	}
+}
+
+func (e *encoder) bool(field int, v bool) {
+	if v {
+		e.key(field, wireVarint)
+		e.buf = append(e.buf, 1)
+	}
+}
+
+func (e *encoder) int32(field int, v int32) {
+	if v != 0 {
+		e.key(field, wireVarint)
+		// Negative int32 values are sign-extended to 64 bits
+		e.buf = binary.AppendUvarint(e.buf, uint64(int64(v)))
+	}
+}
+
+// message writes an embedded message, even an empty one, so that a set
+// oneof member or a present optional message survives the round trip
+func (e *encoder) message(field int, b []byte) {
+	e.key(field, wireBytes)
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+// counts writes a map<string, int32> field as its entry messages, in key
+// order so that the encoding is deterministic
+func (e *encoder) counts(field int, m map[string]int32) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		var entry encoder
+		entry.string(1, k)
+		entry.int32(2, m[k])
+		e.message(field, entry.buf)
+	}
+}
+
+// decoder reads fields in the protobuf wire format
+type decoder struct {
+	buf []byte
+}
+
+func (d *decoder) done() bool {
+	return len(d.buf) == 0
+}
+
+func (d *decoder) varint() (uint64, error) {
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		return 0, errTruncated
+	}
+	d.buf = d.buf[n:]
+	return v, nil
+}
+
+// key reads the next field number and wire type
+func (d *decoder) key() (int, int, error) {
+	v, err := d.varint()
+	if err != nil {
+		return 0, 0, err
+	}
+	field, wireType := int(v>>3), int(v&7)
+	if field < 1 || v>>3 > 1<<29-1 {
+		return 0, 0, fmt.Errorf("proto: invalid field number %d", v>>3)
+	}
+	return field, wireType, nil
+}
+
+func (d *decoder) bytes() ([]byte, error) {
+	n, err := d.varint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(d.buf)) {
+		return nil, errTruncated
+	}
+	b := d.buf[:n:n]
+	d.buf = d.buf[n:]
+	return b, nil
+}
+
+// string reads a string field, which proto3 requires to be valid UTF-8
+func (d *decoder) string() (string, error) {
+	b, err := d.bytes()
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(b) {
+		return "", errors.New("proto: string field contains invalid UTF-8")
+	}
+	return string(b), nil
+}
+
+func (d *decoder) bool() (bool, error) {
+	v, err := d.varint()
+	return v != 0, err
+}
+
+func (d *decoder) int32() (int32, error) {
+	v, err := d.varint()
+	return int32(v), err
+}
+
+// count reads one entry of a map<string, int32> field into m
+func (d *decoder) count(m map[string]int32) error {
+	b, err := d.bytes()
+	if err != nil {
+		return err
+	}
+	entry := decoder{buf: b}
+	var key string
+	var value int32
+	for !entry.done() {
+		field, wireType, err := entry.key()
+		if err != nil {
+			return err
+		}
+		switch {
+		case field == 1 && wireType == wireBytes:
+			key, err = entry.string()
+		case field == 2 && wireType == wireVarint:
+			value, err = entry.int32()
+		default:
+			err = entry.skip(wireType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	m[key] = value
+	return nil
+}
+
+// skip passes over a field this version of the contract does not know
+func (d *decoder) skip(wireType int) error {
+	switch wireType {
+	case wireVarint:
+		_, err := d.varint()
+		return err
+	case wireBytes:
+		_, err := d.bytes()
+		return err
+	case wireFixed64, wireFixed32:
+		n := 8
+		if wireType == wireFixed32 {
+			n = 4
+		}
+		if len(d.buf) < n {
+			return errTruncated
+		}
+		d.buf = d.buf[n:]
+		return nil
+	default:
+		return fmt.Errorf("proto: unsupported wire type %d", wireType)
+	}
+}
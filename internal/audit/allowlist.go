@@ -0,0 +1,33 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// ValueHash returns the hex SHA-256 digest of value, the form in which
+// allowlists name known-safe values without storing them
+func ValueHash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// SuppressAllowed drops leak findings for values whose SHA-256 digest is in
+// hashes, such as a public key or a build hash that only looks random. Only
+// the listed values are exempt; other values of the same key still flag.
+func SuppressAllowed(issues []Issue, env map[string]string, hashes []string) []Issue {
+	if len(hashes) == 0 {
+		return issues
+	}
+	allowed := toSet(hashes)
+	kept := issues[:0:0]
+	for _, issue := range issues {
+		if issue.Type.IsSecret() {
+			if value, ok := env[issue.Key]; ok && allowed[ValueHash(value)] {
+				continue
+			}
+		}
+		kept = append(kept, issue)
+	}
+	return kept
+}
@@ -0,0 +1,39 @@
+package audit
+
+import "testing"
+
+func TestValueHash(t *testing.T) {
+	// printf %s abc | sha256sum
+	if got := ValueHash("abc"); got != "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" {
+		t.Errorf("unexpected digest %s", got)
+	}
+}
+
+func TestSuppressAllowed(t *testing.T) {
+	env := map[string]string{
+		"BUILD_HASH":  "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		"API_TOKEN":   "Zx8Qp2Lm5Nv7Rt1Yw4Ks6Hd9Gf3Jb0Ce",
+		"OTHER_TOKEN": "Zx8Qp2Lm5Nv7Rt1Yw4Ks6Hd9Gf3Jb0Cf",
+	}
+	issues := []Issue{
+		{Type: IssueLeak, Key: "BUILD_HASH"},
+		{Type: IssueLeak, Key: "API_TOKEN"},
+		{Type: IssueSensitive, Key: "API_TOKEN"},
+		{Type: IssueLeak, Key: "OTHER_TOKEN"},
+	}
+
+	kept := SuppressAllowed(issues, env, []string{ValueHash(env["BUILD_HASH"]), ValueHash(env["API_TOKEN"])})
+
+	if len(kept) != 2 {
+		t.Fatalf("expected 2 issues to remain, got %+v", kept)
+	}
+	if kept[0].Type != IssueSensitive || kept[0].Key != "API_TOKEN" {
+		t.Errorf("expected the sensitive finding to be kept, got %+v", kept[0])
+	}
+	if kept[1].Key != "OTHER_TOKEN" {
+		t.Errorf("expected an unlisted value to still leak, got %+v", kept[1])
+	}
+	if len(issues) != 4 {
+		t.Error("expected the input slice to be left untouched")
+	}
+}
@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"encoding/base64"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minBase64Length is the shortest value decoded as base64; shorter values
+// are mostly words and numbers that happen to be valid base64
+const minBase64Length = 16
+
+// base64Encodings are tried in order when decoding a value
+var base64Encodings = []*base64.Encoding{
+	base64.StdEncoding,
+	base64.URLEncoding,
+	base64.RawStdEncoding,
+	base64.RawURLEncoding,
+}
+
+// decodeBase64 returns the text value encodes, if value is standard or
+// URL-safe base64, padded or not, of printable UTF-8 text. Binary content,
+// such as random key material, is left to the entropy check on the value.
+func decodeBase64(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if len(value) < minBase64Length {
+		return "", false
+	}
+	for _, enc := range base64Encodings {
+		data, err := enc.Strict().DecodeString(value)
+		if err != nil {
+			continue
+		}
+		if text := string(data); isPrintableText(text) {
+			return text, true
+		}
+		return "", false
+	}
+	return "", false
+}
+
+// isPrintableText reports whether s is valid UTF-8 made of printable
+// characters and whitespace
+func isPrintableText(s string) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
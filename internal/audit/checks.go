@@ -1,6 +1,11 @@
 package audit
 
-import "strings"
+import (
+	"sort"
+	"strconv"
+
+	"env-audit/internal/i18n"
+)
 
 // IssueType represents the category of an audit issue
 type IssueType int
@@ -12,6 +17,21 @@ const (
 	IssueDuplicate
 	IssueLeak
 	IssueExtra
+	IssueDefault
+	IssueUnsafeDefault
+	IssueOverride
+	IssueOrder
+	IssueMalformed
+	IssueRotation
+	IssueInvalid
+	IssueUnresolved
+	IssueExampleSecret
+	IssueLimit
+	IssuePlatform
+	IssueSystem
+	IssueDocs
+	IssueSourceDrift
+	IssueParse
 )
 
 // Issue represents a single audit finding
@@ -19,23 +39,65 @@ type Issue struct {
 	Type    IssueType
 	Key     string
 	Message string
+	File    string // source file path, empty when scanning the OS environment
+	Line    int    // line of the offending entry, 0 if unknown
+	Rule    string // id of the detection rule, e.g. a leak pattern id
+
+	// Severity is the level a SeverityPolicy set, empty for the type's
+	// default; use Level to read it
+	Severity string
+}
+
+// Location identifies where a variable was defined
+type Location struct {
+	File string
+	Line int
+}
+
+// String formats the location as file:line
+func (l Location) String() string {
+	if l.Line == 0 {
+		return l.File
+	}
+	return l.File + ":" + strconv.Itoa(l.Line)
 }
 
 // CheckEmpty finds variables with empty values
 func CheckEmpty(env map[string]string, ignore []string) []Issue {
+	return CheckEmptyWith(env, EmptyPolicy{}, ignore)
+}
+
+// EmptyPolicy ties the empty check to what is declared about each variable
+type EmptyPolicy struct {
+	Required   []string // an empty value counts as missing, an error
+	AllowEmpty []string // optional keys that may be empty without a finding
+}
+
+// CheckEmptyWith finds variables with empty values under policy: allowed
+// keys are skipped, required keys are reported as missing and the rest as
+// empty
+func CheckEmptyWith(env map[string]string, policy EmptyPolicy, ignore []string) []Issue {
 	ignoreSet := toSet(ignore)
+	allowed := toSet(policy.AllowEmpty)
+	required := toSet(policy.Required)
 	var issues []Issue
-	for key, value := range env {
-		if ignoreSet[key] {
+	for _, key := range sortedKeys(env) {
+		if ignoreSet[key] || allowed[key] || env[key] != "" {
 			continue
 		}
-		if value == "" {
+		if required[key] {
 			issues = append(issues, Issue{
-				Type:    IssueEmpty,
+				Type:    IssueMissing,
 				Key:     key,
-				Message: "variable has empty value",
+				Message: i18n.T("required variable has empty value"),
 			})
+			continue
 		}
+		issues = append(issues, Issue{
+			Type:    IssueEmpty,
+			Key:     key,
+			Message: i18n.T("variable has empty value"),
+		})
 	}
 	return issues
 }
@@ -54,52 +116,110 @@ func CheckMissing(env map[string]string, required, ignore []string) []Issue {
 			issues = append(issues, Issue{
 				Type:    IssueMissing,
 				Key:     key,
-				Message: "required variable is missing",
+				Message: i18n.T("required variable is missing"),
 			})
 		}
 	}
 	return issues
 }
 
+// Default describes a fallback value applied when a variable is absent
+type Default struct {
+	Value  string
+	Unsafe bool // the default is known to be unsafe for production
+}
 
-// CheckSensitive finds keys matching sensitive patterns
-func CheckSensitive(env map[string]string, ignore []string) []Issue {
+// CheckDefaults reports absent variables that fall back to a default (info)
+// and variables whose value equals a default marked unsafe (warning)
+func CheckDefaults(env map[string]string, defaults map[string]Default, ignore []string) []Issue {
 	ignoreSet := toSet(ignore)
 	var issues []Issue
-	for key := range env {
+	for _, key := range sortedKeys(defaults) {
+		def := defaults[key]
 		if ignoreSet[key] {
 			continue
 		}
-		if IsSensitiveKey(key) {
+		value, exists := env[key]
+		switch {
+		case !exists && def.Unsafe:
 			issues = append(issues, Issue{
-				Type:    IssueSensitive,
+				Type:    IssueUnsafeDefault,
+				Key:     key,
+				Message: i18n.T("variable is missing and its default is unsafe for production"),
+			})
+		case !exists:
+			issues = append(issues, Issue{
+				Type:    IssueDefault,
 				Key:     key,
-				Message: "sensitive key detected",
+				Message: describeDefault(key, def.Value),
+			})
+		case def.Unsafe && value == def.Value:
+			issues = append(issues, Issue{
+				Type:    IssueUnsafeDefault,
+				Key:     key,
+				Message: i18n.T("value equals a default that is unsafe for production"),
 			})
 		}
 	}
 	return issues
 }
 
-// IsSensitiveKey returns true if key matches sensitive patterns
-// Matches: SECRET, PASSWORD, TOKEN, API_KEY, APIKEY, KEY suffix, CREDENTIAL, PRIVATE, AUTH
-func IsSensitiveKey(key string) bool {
-	upper := strings.ToUpper(key)
-
-	// Check for exact patterns contained anywhere in the key
-	patterns := []string{"SECRET", "PASSWORD", "TOKEN", "API_KEY", "APIKEY", "CREDENTIAL", "PRIVATE", "AUTH"}
-	for _, p := range patterns {
-		if strings.Contains(upper, p) {
-			return true
+// CheckOverrides reports variables whose value in one file is overridden by
+// another, describing the chain from the effective definition downwards
+func CheckOverrides(overrides map[string][]Location, ignore []string) []Issue {
+	ignoreSet := toSet(ignore)
+	var issues []Issue
+	for _, key := range sortedKeys(overrides) {
+		chain := overrides[key]
+		if ignoreSet[key] || len(chain) < 2 {
+			continue
+		}
+		message := chain[0].String()
+		for _, loc := range chain[1:] {
+			message = i18n.T("%s overrides %s", message, loc.String())
 		}
+		issues = append(issues, Issue{
+			Type:    IssueOverride,
+			Key:     key,
+			Message: message,
+		})
+	}
+	return issues
+}
+
+// describeDefault reports the use of a default value, quoting it unless the
+// key is sensitive
+func describeDefault(key, value string) string {
+	if IsSensitiveKey(key) {
+		return i18n.T("using default value")
 	}
+	return i18n.T("using default %s", strconv.Quote(value))
+}
 
-	// Check for KEY suffix (e.g., STRIPE_KEY, AWS_KEY)
-	if strings.HasSuffix(upper, "KEY") {
-		return true
+// CheckSensitive finds keys matching sensitive patterns
+func CheckSensitive(env map[string]string, ignore []string) []Issue {
+	ignoreSet := toSet(ignore)
+	var issues []Issue
+	for _, key := range sortedKeys(env) {
+		if ignoreSet[key] {
+			continue
+		}
+		if IsSensitiveKey(key) {
+			issues = append(issues, Issue{
+				Type:    IssueSensitive,
+				Key:     key,
+				Message: i18n.T("sensitive key detected"),
+			})
+		}
 	}
+	return issues
+}
 
-	return false
+// IsSensitiveKey returns true if key matches the active sensitive patterns,
+// by default SECRET, PASSWORD, TOKEN, API_KEY, APIKEY, KEY suffix,
+// CREDENTIAL, PRIVATE and AUTH
+func IsSensitiveKey(key string) bool {
+	return SensitiveKeys.Match(key)
 }
 
 // toSet converts a slice to a map for O(1) lookups
@@ -110,3 +230,14 @@ func toSet(slice []string) map[string]bool {
 	}
 	return set
 }
+
+// sortedKeys returns the keys of m in sorted order, so checks report their
+// findings in a stable order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
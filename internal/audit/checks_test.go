@@ -182,6 +182,25 @@ func TestCheckEmpty_SingleEntry(t *testing.T) {
 	}
 }
 
+func TestCheckEmptyWith_Policy(t *testing.T) {
+	env := map[string]string{"DATABASE_URL": "", "SENTRY_DSN": "", "DEBUG": "", "PORT": "8080"}
+	policy := EmptyPolicy{Required: []string{"DATABASE_URL", "PORT", "SENTRY_DSN"}, AllowEmpty: []string{"SENTRY_DSN"}}
+
+	byKey := make(map[string]Issue)
+	for _, issue := range CheckEmptyWith(env, policy, nil) {
+		byKey[issue.Key] = issue
+	}
+	if len(byKey) != 2 {
+		t.Fatalf("expected issues for DATABASE_URL and DEBUG only, got %v", byKey)
+	}
+	if issue := byKey["DATABASE_URL"]; issue.Type != IssueMissing || issue.Message != "required variable has empty value" {
+		t.Errorf("expected an empty required key to be an error, got %+v", issue)
+	}
+	if issue := byKey["DEBUG"]; issue.Type != IssueEmpty {
+		t.Errorf("expected an empty optional key to stay a warning, got %+v", issue)
+	}
+}
+
 func TestCheckEmpty_SpecialCharacters(t *testing.T) {
 	env := map[string]string{
 		"MY_VAR-1":    "",
@@ -424,3 +443,42 @@ func TestProperty_IgnoreFiltering(t *testing.T) {
 
 	properties.TestingRun(t)
 }
+
+func TestCheckDefaults(t *testing.T) {
+	defaults := map[string]Default{
+		"LOG_LEVEL":   {Value: "info"},
+		"SESSION_KEY": {Value: "changeme", Unsafe: true},
+		"ADMIN_PASS":  {Value: "admin", Unsafe: true},
+		"PRESENT":     {Value: "x"},
+	}
+	env := map[string]string{
+		"SESSION_KEY": "changeme",
+		"PRESENT":     "y",
+	}
+
+	issues := CheckDefaults(env, defaults, nil)
+	byKey := make(map[string]Issue)
+	for _, issue := range issues {
+		byKey[issue.Key] = issue
+	}
+
+	if len(issues) != 3 {
+		t.Fatalf("expected 3 issues, got %v", issues)
+	}
+	if byKey["LOG_LEVEL"].Type != IssueDefault || byKey["LOG_LEVEL"].Message != `using default "info"` {
+		t.Errorf("expected LOG_LEVEL to use default, got %v", byKey["LOG_LEVEL"])
+	}
+	if byKey["SESSION_KEY"].Type != IssueUnsafeDefault {
+		t.Errorf("expected SESSION_KEY unsafe default, got %v", byKey["SESSION_KEY"])
+	}
+	if byKey["ADMIN_PASS"].Type != IssueUnsafeDefault {
+		t.Errorf("expected missing ADMIN_PASS with unsafe default, got %v", byKey["ADMIN_PASS"])
+	}
+}
+
+func TestCheckDefaults_SensitiveValueHidden(t *testing.T) {
+	issues := CheckDefaults(nil, map[string]Default{"API_TOKEN": {Value: "dev-token"}}, nil)
+	if len(issues) != 1 || strings.Contains(issues[0].Message, "dev-token") {
+		t.Errorf("expected sensitive default value hidden, got %v", issues)
+	}
+}
@@ -0,0 +1,34 @@
+package audit
+
+import "env-audit/internal/i18n"
+
+// Diagnostic is a line of an input file the parser could not read as an
+// assignment. Only the line's length is kept, never its text, which may be
+// a secret pasted without a key.
+type Diagnostic struct {
+	File   string
+	Line   int
+	Reason string // e.g. "missing '=' separator"
+	Length int    // length of the line in bytes
+}
+
+// Message describes the diagnostic in the active language, without its
+// location
+func (d Diagnostic) Message() string {
+	return i18n.T("%s (%d bytes)", i18n.T(d.Reason), d.Length)
+}
+
+// String formats the diagnostic as file:line: message
+func (d Diagnostic) String() string {
+	return Location{File: d.File, Line: d.Line}.String() + ": " + d.Message()
+}
+
+// DiagnosticIssues reports diagnostics as parse warnings, for --warn-parse.
+// The issues have no key, since the line they point at has none to trust.
+func DiagnosticIssues(diagnostics []Diagnostic) []Issue {
+	issues := make([]Issue, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		issues = append(issues, Issue{Type: IssueParse, Message: d.Message(), File: d.File, Line: d.Line})
+	}
+	return issues
+}
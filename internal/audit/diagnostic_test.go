@@ -0,0 +1,25 @@
+package audit
+
+import "testing"
+
+func TestDiagnosticIssues(t *testing.T) {
+	diagnostics := []Diagnostic{{File: ".env", Line: 4, Reason: "missing '=' separator", Length: 31}}
+
+	if got := diagnostics[0].String(); got != ".env:4: missing '=' separator (31 bytes)" {
+		t.Errorf("unexpected string %q", got)
+	}
+	issues := DiagnosticIssues(diagnostics)
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %d", len(issues))
+	}
+	issue := issues[0]
+	if issue.Type != IssueParse || issue.Key != "" || issue.File != ".env" || issue.Line != 4 {
+		t.Errorf("unexpected issue %+v", issue)
+	}
+	if issue.Message != "missing '=' separator (31 bytes)" {
+		t.Errorf("unexpected message %q", issue.Message)
+	}
+	if !issue.Type.IsWarning() {
+		t.Error("expected parse errors to be warnings")
+	}
+}
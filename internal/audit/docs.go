@@ -0,0 +1,39 @@
+package audit
+
+import "env-audit/internal/i18n"
+
+// CheckDocs compares the keys documented in a docs file, such as
+// ENVIRONMENT.md, with those of an example file. Each map holds a file's
+// keys and their lines. Keys documented but absent from the example are
+// reported on the docs file, keys of the example left undocumented on the
+// example file.
+func CheckDocs(docsFile string, documented map[string]int, exampleFile string, example map[string]int, ignore []string) []Issue {
+	ignoreSet := toSet(ignore)
+
+	var issues []Issue
+	for _, key := range sortedKeys(documented) {
+		if _, ok := example[key]; ok || ignoreSet[key] {
+			continue
+		}
+		issues = append(issues, Issue{
+			Type:    IssueDocs,
+			Key:     key,
+			Message: i18n.T("documented in %s but missing from %s", docsFile, exampleFile),
+			File:    docsFile,
+			Line:    documented[key],
+		})
+	}
+	for _, key := range sortedKeys(example) {
+		if _, ok := documented[key]; ok || ignoreSet[key] {
+			continue
+		}
+		issues = append(issues, Issue{
+			Type:    IssueDocs,
+			Key:     key,
+			Message: i18n.T("not documented in %s", docsFile),
+			File:    exampleFile,
+			Line:    example[key],
+		})
+	}
+	return issues
+}
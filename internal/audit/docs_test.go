@@ -0,0 +1,25 @@
+package audit
+
+import "testing"
+
+func TestCheckDocs(t *testing.T) {
+	documented := map[string]int{"DATABASE_URL": 5, "LEGACY_TOKEN": 9, "SKIPPED": 12}
+	example := map[string]int{"DATABASE_URL": 1, "PORT": 2}
+
+	issues := CheckDocs("ENVIRONMENT.md", documented, ".env.example", example, []string{"SKIPPED"})
+	want := []Issue{
+		{Type: IssueDocs, Key: "LEGACY_TOKEN", Message: "documented in ENVIRONMENT.md but missing from .env.example", File: "ENVIRONMENT.md", Line: 9},
+		{Type: IssueDocs, Key: "PORT", Message: "not documented in ENVIRONMENT.md", File: ".env.example", Line: 2},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("expected %d issues, got %+v", len(want), issues)
+	}
+	for i := range want {
+		if issues[i] != want[i] {
+			t.Errorf("issue %d = %+v, want %+v", i, issues[i], want[i])
+		}
+	}
+	if !IssueDocs.IsWarning() {
+		t.Error("expected docs drift to be a warning")
+	}
+}
@@ -0,0 +1,20 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Fingerprint identifies a finding by check name, key and file. It is stable
+// across runs and machines, so reports can tell new findings from fixed ones.
+func Fingerprint(check, key, file string) string {
+	sum := sha256.Sum256([]byte(check + "\x00" + key + "\x00" + file))
+	return hex.EncodeToString(sum[:16])
+}
+
+// ID returns the issue's fingerprint, derived from its type, key and file.
+// The message and line are left out so that rewording a check or moving an
+// entry keeps the ID.
+func (i Issue) ID() string {
+	return Fingerprint(i.Type.Name(), i.Key, i.File)
+}
@@ -0,0 +1,48 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestIssue_ID(t *testing.T) {
+	issue := Issue{Type: IssueLeak, Key: "API_KEY", File: ".env", Line: 3, Message: "looks like a token"}
+	if got := issue.ID(); got != Fingerprint("leak", "API_KEY", ".env") || len(got) != 32 {
+		t.Fatalf("expected the 32 character fingerprint of type, key and file, got %q", got)
+	}
+
+	moved := issue
+	moved.Line, moved.Message, moved.Severity = 9, "reworded", SeverityWarning
+	if moved.ID() != issue.ID() {
+		t.Error("expected the line, message and severity not to change the ID")
+	}
+
+	for _, other := range []Issue{
+		{Type: IssueSensitive, Key: "API_KEY", File: ".env"},
+		{Type: IssueLeak, Key: "API_TOKEN", File: ".env"},
+		{Type: IssueLeak, Key: "API_KEY", File: ".env.local"},
+	} {
+		if other.ID() == issue.ID() {
+			t.Errorf("expected %+v to have a different ID", other)
+		}
+	}
+}
+
+// Property: the separator keeps fields from running together, so moving
+// characters between key and file changes the ID
+func TestProperty_FingerprintSeparatesFields(t *testing.T) {
+	properties := gopter.NewProperties(gopter.DefaultTestParameters())
+
+	properties.Property("distinct key/file splits have distinct fingerprints", prop.ForAll(
+		func(key, file string) bool {
+			return Fingerprint("leak", key, file+"x") != Fingerprint("leak", key+"x", file)
+		},
+		gen.AlphaString(),
+		gen.AlphaString(),
+	))
+
+	properties.TestingRun(t)
+}
@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"regexp"
+	"strings"
+
+	"env-audit/internal/i18n"
+)
+
+// ProviderFormat describes the value format of a credential whose key name
+// identifies the issuing provider
+type ProviderFormat struct {
+	Name   string         // credential name used in messages, e.g. "Stripe secret key"
+	Key    *regexp.Regexp // matched against the variable name
+	Value  *regexp.Regexp // expected value format
+	Expect string         // human description of the expected format
+}
+
+// ProviderFormats are checked in order; the first one whose Key matches applies
+var ProviderFormats = []ProviderFormat{
+	{"Stripe secret key", regexp.MustCompile(`^STRIPE_(SECRET|API)_KEY$`), regexp.MustCompile(`^(sk|rk)_(live|test)_[A-Za-z0-9]{10,}$`), "sk_live_…, sk_test_… or rk_…"},
+	{"Stripe publishable key", regexp.MustCompile(`^(NEXT_PUBLIC_|VITE_|REACT_APP_)?STRIPE_PUBLISHABLE_KEY$`), regexp.MustCompile(`^pk_(live|test)_[A-Za-z0-9]{10,}$`), "pk_live_… or pk_test_…"},
+	{"Stripe webhook secret", regexp.MustCompile(`^STRIPE_WEBHOOK_SECRET$`), regexp.MustCompile(`^whsec_[A-Za-z0-9]{20,}$`), "whsec_…"},
+	{"GitHub token", regexp.MustCompile(`^(GITHUB_TOKEN|GH_TOKEN|GITHUB_PAT)$`), regexp.MustCompile(`^(gh[pousr]_[A-Za-z0-9]{36}|github_pat_[A-Za-z0-9_]{82})$`), "ghp_… (40 characters) or github_pat_…"},
+	{"AWS access key ID", regexp.MustCompile(`^AWS_ACCESS_KEY_ID$`), regexp.MustCompile(`^(AKIA|ASIA)[0-9A-Z]{16}$`), "AKIA or ASIA followed by 16 characters"},
+	{"AWS secret access key", regexp.MustCompile(`^AWS_SECRET_ACCESS_KEY$`), regexp.MustCompile(`^[A-Za-z0-9/+=]{40}$`), "40 base64 characters"},
+	{"Slack bot token", regexp.MustCompile(`^SLACK_BOT_TOKEN$`), regexp.MustCompile(`^xoxb-[0-9]+-[0-9]+-[A-Za-z0-9]+$`), "xoxb-…"},
+	{"OpenAI API key", regexp.MustCompile(`^OPENAI_API_KEY$`), regexp.MustCompile(`^sk-[A-Za-z0-9_-]{20,}$`), "sk-…"},
+	{"SendGrid API key", regexp.MustCompile(`^SENDGRID_API_KEY$`), regexp.MustCompile(`^SG\.[A-Za-z0-9_-]{22}\.[A-Za-z0-9_-]{43}$`), "SG.<22 chars>.<43 chars>"},
+}
+
+// percentEscape matches a percent-encoded byte such as %3D
+var percentEscape = regexp.MustCompile(`%[0-9A-Fa-f]{2}`)
+
+// CheckFormats flags credentials that will fail at runtime: sensitive values
+// carrying encoding artifacts from copy-paste (percent-encoding, leftover
+// quotes), and values that don't match the format of the provider their key
+// name implies. Either usually means a stale, truncated or mangled credential
+// rather than a leak. Empty values are left to CheckEmpty.
+func CheckFormats(env map[string]string, ignore []string) []Issue {
+	ignoreSet := toSet(ignore)
+	var issues []Issue
+	for _, key := range sortedKeys(env) {
+		value := env[key]
+		if ignoreSet[key] || value == "" {
+			continue
+		}
+		format, hasFormat := providerFormatFor(key)
+
+		message := ""
+		if hasFormat || IsSensitiveKey(key) {
+			message = encodingArtifact(value)
+		}
+		if message == "" && hasFormat && !format.Value.MatchString(value) {
+			message = i18n.T("value does not match the %s format (expected %s)", format.Name, format.Expect)
+		}
+		if message != "" {
+			issues = append(issues, Issue{Type: IssueMalformed, Key: key, Message: message})
+		}
+	}
+	return issues
+}
+
+// encodingArtifact describes copy-paste damage in a secret value, or returns
+// "" if there is none. URLs are exempt from the percent-encoding check since
+// credentials inside them must be encoded.
+func encodingArtifact(value string) string {
+	switch {
+	case len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0]:
+		return i18n.T("value is wrapped in an extra pair of quotes")
+	case strings.Contains(value, `\"`) || strings.Contains(value, `\'`):
+		return i18n.T("value contains escaped quotes")
+	case !strings.Contains(value, "://") && percentEscape.MatchString(value):
+		return i18n.T("value looks percent-encoded; decode it before use")
+	}
+	return ""
+}
+
+// providerFormatFor returns the provider format implied by a key name
+func providerFormatFor(key string) (ProviderFormat, bool) {
+	for _, f := range ProviderFormats {
+		if f.Key.MatchString(key) {
+			return f, true
+		}
+	}
+	return ProviderFormat{}, false
+}
@@ -3,33 +3,46 @@ package audit
 import (
 	"math"
 	"regexp"
+	"strings"
+
+	"env-audit/internal/i18n"
 )
 
 // LeakPattern defines a known secret pattern
 type LeakPattern struct {
-	Name    string
-	Pattern *regexp.Regexp
+	ID       string // stable identifier, reported as the issue's rule
+	Name     string
+	Provider string // issuing service, empty for generic formats
+	Severity string // low, medium, high or critical
+	DocsURL  string // where to read about the credential and how to rotate it
+	Pattern  *regexp.Regexp
 }
 
-// KnownPatterns contains patterns for detecting hardcoded secrets
-var KnownPatterns = []LeakPattern{
-	{"GitHub Token", regexp.MustCompile(`^ghp_[a-zA-Z0-9]{36}$`)},
-	{"Stripe Live Key", regexp.MustCompile(`^sk_live_[a-zA-Z0-9]+$`)},
-	{"Stripe Test Key", regexp.MustCompile(`^sk_test_[a-zA-Z0-9]+$`)},
-	{"AWS Access Key", regexp.MustCompile(`^AKIA[0-9A-Z]{16}$`)},
-	{"JWT", regexp.MustCompile(`^eyJ[a-zA-Z0-9_-]+\.eyJ[a-zA-Z0-9_-]+\.[a-zA-Z0-9_-]+$`)},
-}
+// HighEntropyRule is the rule reported for high entropy findings
+const HighEntropyRule = "high-entropy"
+
+// KnownPatterns contains patterns for detecting hardcoded secrets, loaded
+// from the embedded patterns.json pack
+var KnownPatterns []LeakPattern
 
 // MatchesLeakPattern checks if a value matches any known secret pattern
 func MatchesLeakPattern(value string) (bool, string) {
-	for _, lp := range KnownPatterns {
-		if lp.Pattern.MatchString(value) {
-			return true, lp.Name
-		}
+	if lp := matchLeakPattern(value); lp != nil {
+		return true, lp.Name
 	}
 	return false, ""
 }
 
+// matchLeakPattern returns the first known pattern matching value
+func matchLeakPattern(value string) *LeakPattern {
+	for i := range KnownPatterns {
+		if KnownPatterns[i].Pattern.MatchString(value) {
+			return &KnownPatterns[i]
+		}
+	}
+	return nil
+}
+
 // CalculateEntropy computes Shannon entropy in bits per character
 func CalculateEntropy(s string) float64 {
 	if len(s) == 0 {
@@ -69,32 +82,153 @@ func CheckLeaks(env map[string]string, ignore []string) []Issue {
 	}
 
 	var issues []Issue
-	for key, value := range env {
+	for _, key := range sortedKeys(env) {
 		if ignoreSet[key] {
 			continue
 		}
-		if value == "" {
-			continue
-		}
-
-		// Check known patterns first
-		if matched, patternName := MatchesLeakPattern(value); matched {
+		if message, rule, found := detectLeak(env[key]); found {
 			issues = append(issues, Issue{
 				Type:    IssueLeak,
 				Key:     key,
-				Message: "potential " + patternName + " detected",
+				Message: message,
+				Rule:    rule,
 			})
-			continue
 		}
+	}
+	return issues
+}
 
-		// Check high entropy
-		if IsHighEntropy(value) {
+// conventionalKey matches key names written in UPPER_SNAKE or lower_snake
+// case, which are never mistaken for high-entropy secrets
+var conventionalKey = regexp.MustCompile(`^[A-Z0-9_.-]+$|^[a-z0-9_.-]+$`)
+
+// CheckKeyLeaks flags keys that are secrets themselves, such as a token
+// pasted on the left of "=" or a swapped key and value. Keys are checked
+// against the known patterns, and for high entropy unless they follow a
+// naming convention. Issues name the key as written; Scan masks it.
+func CheckKeyLeaks(env map[string]string, ignore []string) []Issue {
+	ignoreSet := toSet(ignore)
+	var issues []Issue
+	for _, key := range sortedKeys(env) {
+		if ignoreSet[key] {
+			continue
+		}
+		if message, rule, found := matchSecretKey(key); found {
 			issues = append(issues, Issue{
 				Type:    IssueLeak,
 				Key:     key,
-				Message: "potential secret detected (high entropy)",
+				Message: i18n.T("key looks like a secret (%s)", message),
+				Rule:    rule,
 			})
 		}
 	}
 	return issues
 }
+
+// matchSecretKey checks a key name as CheckKeyLeaks does
+func matchSecretKey(key string) (string, string, bool) {
+	if message, rule, found := matchSecret(key); found {
+		return message, rule, true
+	}
+	if !conventionalKey.MatchString(key) && IsHighEntropy(key) {
+		return i18n.T("high entropy"), HighEntropyRule, true
+	}
+	return "", "", false
+}
+
+// IsSecretKey reports whether a key name is itself a secret, by the rules
+// of CheckKeyLeaks. Such keys are masked wherever they are shown.
+func IsSecretKey(key string) bool {
+	_, _, found := matchSecretKey(key)
+	return found
+}
+
+// keyMask replaces all but the kept prefix of a masked key
+const keyMask = "****"
+
+// MaskKey hides a key that is itself a secret, keeping a short prefix so
+// the line can still be found
+func MaskKey(key string) string {
+	const keep = 4
+	if len(key) <= 2*keep {
+		return keyMask
+	}
+	return key[:keep] + keyMask
+}
+
+// IsMaskedKey reports whether key is the output of MaskKey, and so not a
+// name that can be written back to a file
+func IsMaskedKey(key string) bool {
+	return strings.HasSuffix(key, keyMask)
+}
+
+// CheckExampleLeaks analyzes an example file's values for real-looking secrets.
+// Examples are committed, so anything other than a placeholder is reported as
+// an IssueExampleSecret.
+func CheckExampleLeaks(example map[string]string, ignore []string) []Issue {
+	issues := CheckLeaks(example, ignore)
+	for i := range issues {
+		issues[i].Type = IssueExampleSecret
+		issues[i].Message = i18n.T("example value looks like a real secret (%s)", issues[i].Message)
+	}
+	return issues
+}
+
+// detectLeak checks a value against known patterns and entropy, returning
+// a message describing the finding and the id of the rule that matched
+func detectLeak(value string) (string, string, bool) {
+	if value == "" {
+		return "", "", false
+	}
+
+	if message, rule, found := matchSecret(value); found {
+		return message, rule, true
+	}
+
+	// Base64 hides a token's shape, so look inside values that decode to text
+	if decoded, ok := decodeBase64(value); ok {
+		if message, rule, found := matchSecret(decoded); found {
+			return i18n.T("%s in base64-encoded value", message), rule, true
+		}
+		if IsHighEntropy(decoded) {
+			return i18n.T("potential secret in base64-encoded value (high entropy)"), HighEntropyRule, true
+		}
+	}
+
+	// Check high entropy
+	if IsHighEntropy(value) {
+		return i18n.T("potential secret detected (high entropy)"), HighEntropyRule, true
+	}
+	return "", "", false
+}
+
+// LooksLikeSecret reports whether value matches a known secret pattern or
+// embeds URL credentials, as written or base64-encoded, whatever its key is
+// called. High entropy alone doesn't count: hashes and IDs have it too.
+func LooksLikeSecret(value string) bool {
+	if value == "" {
+		return false
+	}
+	if _, _, found := matchSecret(value); found {
+		return true
+	}
+	if decoded, ok := decodeBase64(value); ok {
+		_, _, found := matchSecret(decoded)
+		return found
+	}
+	return false
+}
+
+// matchSecret checks value against the known patterns and for credentials
+// embedded in a URL
+func matchSecret(value string) (string, string, bool) {
+	if lp := matchLeakPattern(value); lp != nil {
+		return i18n.T("potential %s detected", lp.Name), lp.ID, true
+	}
+
+	// Key names like DATABASE_URL don't look sensitive, the password inside does
+	if redacted, found := urlCredentials(value); found {
+		return i18n.T("credentials embedded in URL %s", redacted), URLCredentialsRule, true
+	}
+	return "", "", false
+}
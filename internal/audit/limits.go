@@ -0,0 +1,80 @@
+package audit
+
+import (
+	"sort"
+
+	"env-audit/internal/i18n"
+)
+
+// Limits caps the size of an environment, so it fits runtimes that reject
+// large ones, such as AWS Lambda's 4 KB. Zero fields are not checked.
+type Limits struct {
+	MaxVars        int // variables per file
+	MaxValueLength int // bytes in a single value
+	MaxTotalSize   int // bytes in all keys and values together
+}
+
+// IsZero reports whether no limit is set
+func (l Limits) IsZero() bool {
+	return l == Limits{}
+}
+
+// CheckLimits reports values over the length limit, and the variable that
+// pushes the file over the count or total size limit. Keys are taken in
+// the order they are defined, by locations, so the reported key is the
+// first one that does not fit. Ignored keys still count towards the
+// totals, since the runtime sees them too.
+func CheckLimits(env map[string]string, limits Limits, locations map[string]Location, ignore []string) []Issue {
+	if limits.IsZero() {
+		return nil
+	}
+	ignoreSet := toSet(ignore)
+
+	keys := sortedKeys(env)
+	sort.SliceStable(keys, func(i, j int) bool {
+		a, aok := locations[keys[i]]
+		b, bok := locations[keys[j]]
+		if aok != bok {
+			return aok
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+
+	total := 0
+	for _, key := range keys {
+		total += len(key) + len(env[key])
+	}
+
+	var issues []Issue
+	size := 0
+	for i, key := range keys {
+		value := env[key]
+		if limits.MaxValueLength > 0 && len(value) > limits.MaxValueLength && !ignoreSet[key] {
+			issues = append(issues, Issue{
+				Type:    IssueLimit,
+				Key:     key,
+				Message: i18n.T("value is %d bytes, over the limit of %d", len(value), limits.MaxValueLength),
+			})
+		}
+		if limits.MaxVars > 0 && i == limits.MaxVars {
+			issues = append(issues, Issue{
+				Type:    IssueLimit,
+				Key:     key,
+				Message: i18n.T("%d variables defined, over the limit of %d", len(keys), limits.MaxVars),
+			})
+		}
+		before := size
+		size += len(key) + len(value)
+		if limits.MaxTotalSize > 0 && before <= limits.MaxTotalSize && size > limits.MaxTotalSize {
+			issues = append(issues, Issue{
+				Type:    IssueLimit,
+				Key:     key,
+				Message: i18n.T("variables total %d bytes, over the limit of %d", total, limits.MaxTotalSize),
+			})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckLimits(t *testing.T) {
+	env := map[string]string{
+		"A":      "1",
+		"B":      "22",
+		"BLOB":   strings.Repeat("x", 20),
+		"IGNORE": strings.Repeat("y", 20),
+	}
+	locations := map[string]Location{
+		"B":      {File: ".env", Line: 1},
+		"A":      {File: ".env", Line: 2},
+		"BLOB":   {File: ".env", Line: 3},
+		"IGNORE": {File: ".env", Line: 4},
+	}
+	limits := Limits{MaxVars: 2, MaxValueLength: 10, MaxTotalSize: 20}
+
+	issues := CheckLimits(env, limits, locations, []string{"IGNORE"})
+	want := []struct{ key, message string }{
+		{"BLOB", "value is 20 bytes, over the limit of 10"},
+		{"BLOB", "4 variables defined, over the limit of 2"},
+		{"BLOB", "variables total 55 bytes, over the limit of 20"},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("expected %d issues, got %v", len(want), issues)
+	}
+	for i, w := range want {
+		if issues[i].Type != IssueLimit || issues[i].Key != w.key || issues[i].Message != w.message {
+			t.Errorf("issue %d = %+v, want %s: %s", i, issues[i], w.key, w.message)
+		}
+	}
+	if IssueLimit.IsWarning() || IssueLimit.IsInfo() {
+		t.Error("expected exceeded limits to be errors")
+	}
+}
+
+func TestCheckLimits_FirstKeyOverLimit(t *testing.T) {
+	env := map[string]string{"FIRST": "1", "SECOND": "2", "THIRD": "3"}
+	locations := map[string]Location{
+		"THIRD":  {File: ".env", Line: 1},
+		"SECOND": {File: ".env", Line: 2},
+		"FIRST":  {File: ".env", Line: 3},
+	}
+
+	issues := CheckLimits(env, Limits{MaxVars: 1, MaxTotalSize: 8}, locations, nil)
+	if len(issues) != 2 || issues[0].Key != "SECOND" || issues[1].Key != "SECOND" {
+		t.Errorf("expected the second definition to cross both limits, got %v", issues)
+	}
+}
+
+func TestCheckLimits_WithinLimits(t *testing.T) {
+	env := map[string]string{"A": "1", "B": "2"}
+	if issues := CheckLimits(env, Limits{MaxVars: 2, MaxValueLength: 1, MaxTotalSize: 4}, nil, nil); len(issues) != 0 {
+		t.Errorf("expected no issues at the limits, got %v", issues)
+	}
+	if issues := CheckLimits(env, Limits{}, nil, nil); len(issues) != 0 {
+		t.Errorf("expected no issues without limits, got %v", issues)
+	}
+}
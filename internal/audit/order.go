@@ -0,0 +1,125 @@
+package audit
+
+import (
+	"strings"
+
+	"env-audit/internal/i18n"
+)
+
+// Ordering describes the key order and blank-line sections of an env file
+type Ordering struct {
+	Keys    []string       // keys in order of first definition
+	Section map[string]int // section index of each key
+}
+
+// CheckOrder reports keys whose order or section grouping in env differs
+// from the example. Only keys present in both files are compared.
+func CheckOrder(env, example Ordering, ignore []string) []Issue {
+	ignoreSet := toSet(ignore)
+
+	exampleIndex := make(map[string]int)
+	for i, key := range example.Keys {
+		exampleIndex[key] = i
+	}
+
+	// Common keys in env order, and in example order
+	var common []string
+	for _, key := range env.Keys {
+		if _, ok := exampleIndex[key]; ok && !ignoreSet[key] {
+			common = append(common, key)
+		}
+	}
+	var expected []string
+	for _, key := range example.Keys {
+		if _, ok := env.Section[key]; ok && !ignoreSet[key] {
+			expected = append(expected, key)
+		}
+	}
+
+	var issues []Issue
+
+	// Keys outside the longest run already in example order are out of place
+	inOrder := longestOrderedRun(common, exampleIndex)
+	reported := make(map[string]bool)
+	for i, key := range expected {
+		if inOrder[key] {
+			continue
+		}
+		message := i18n.T("key order differs from example (expected first)")
+		if i > 0 {
+			message = i18n.T("key order differs from example (expected after %s)", expected[i-1])
+		}
+		issues = append(issues, Issue{Type: IssueOrder, Key: key, Message: message})
+		reported[key] = true
+	}
+
+	// Keys sharing an example section should share a section in env too
+	sections := make(map[int][]string)
+	var sectionOrder []int
+	for _, key := range expected {
+		s := example.Section[key]
+		if _, ok := sections[s]; !ok {
+			sectionOrder = append(sectionOrder, s)
+		}
+		sections[s] = append(sections[s], key)
+	}
+	for _, s := range sectionOrder {
+		keys := sections[s]
+		home := majoritySection(keys, env.Section)
+		for _, key := range keys {
+			if env.Section[key] == home || reported[key] {
+				continue
+			}
+			issues = append(issues, Issue{
+				Type:    IssueOrder,
+				Key:     key,
+				Message: i18n.T("key is not grouped with its example section (%s)", strings.Join(keys, ", ")),
+			})
+		}
+	}
+
+	return issues
+}
+
+// longestOrderedRun returns the largest set of keys whose relative order
+// already matches index (a longest increasing subsequence)
+func longestOrderedRun(keys []string, index map[string]int) map[string]bool {
+	n := len(keys)
+	length := make([]int, n)
+	prev := make([]int, n)
+	best := -1
+	for i := range keys {
+		length[i] = 1
+		prev[i] = -1
+		for j := 0; j < i; j++ {
+			if index[keys[j]] < index[keys[i]] && length[j]+1 > length[i] {
+				length[i] = length[j] + 1
+				prev[i] = j
+			}
+		}
+		if best == -1 || length[i] > length[best] {
+			best = i
+		}
+	}
+
+	result := make(map[string]bool)
+	for i := best; i >= 0; i = prev[i] {
+		result[keys[i]] = true
+	}
+	return result
+}
+
+// majoritySection returns the env section holding most of keys, preferring
+// the earliest section on ties
+func majoritySection(keys []string, section map[string]int) int {
+	counts := make(map[int]int)
+	home, max := 0, 0
+	for _, key := range keys {
+		s := section[key]
+		counts[s]++
+		if counts[s] > max || (counts[s] == max && s < home) {
+			home, max = s, counts[s]
+		}
+	}
+	return home
+}
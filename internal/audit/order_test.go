@@ -0,0 +1,55 @@
+package audit
+
+import "testing"
+
+func ordering(sections ...[]string) Ordering {
+	o := Ordering{Section: make(map[string]int)}
+	for i, keys := range sections {
+		for _, key := range keys {
+			o.Keys = append(o.Keys, key)
+			o.Section[key] = i
+		}
+	}
+	return o
+}
+
+func TestCheckOrder_Matching(t *testing.T) {
+	example := ordering([]string{"A", "B"}, []string{"C"})
+	env := ordering([]string{"A", "B", "EXTRA"}, []string{"C"})
+
+	if issues := CheckOrder(env, example, nil); len(issues) != 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+}
+
+func TestCheckOrder_OutOfOrder(t *testing.T) {
+	example := ordering([]string{"A", "B", "C", "D"})
+	env := ordering([]string{"A", "C", "D", "B"})
+
+	issues := CheckOrder(env, example, nil)
+	if len(issues) != 1 || issues[0].Key != "B" || issues[0].Type != IssueOrder {
+		t.Fatalf("expected B out of order, got %v", issues)
+	}
+	if issues[0].Message != "key order differs from example (expected after A)" {
+		t.Errorf("unexpected message: %s", issues[0].Message)
+	}
+}
+
+func TestCheckOrder_SectionGrouping(t *testing.T) {
+	example := ordering([]string{"DB_HOST", "DB_PORT", "DB_NAME"}, []string{"SMTP_HOST"})
+	env := ordering([]string{"DB_HOST", "DB_PORT"}, []string{"DB_NAME", "SMTP_HOST"})
+
+	issues := CheckOrder(env, example, nil)
+	if len(issues) != 1 || issues[0].Key != "DB_NAME" {
+		t.Fatalf("expected DB_NAME outside its section, got %v", issues)
+	}
+}
+
+func TestCheckOrder_Ignore(t *testing.T) {
+	example := ordering([]string{"A", "B"})
+	env := ordering([]string{"B", "A"})
+
+	if issues := CheckOrder(env, example, []string{"A"}); len(issues) != 0 {
+		t.Errorf("expected ignored key to be skipped, got %v", issues)
+	}
+}
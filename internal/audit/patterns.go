@@ -0,0 +1,120 @@
+package audit
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// embeddedPatterns is the pattern pack shipped with the binary
+//
+//go:embed patterns.json
+var embeddedPatterns []byte
+
+// PatternsVersion is the version of the active leak pattern pack
+var PatternsVersion string
+
+// PatternPack is a versioned set of leak patterns, as shipped in
+// patterns.json or loaded at runtime to roll out new token formats
+type PatternPack struct {
+	Version  string
+	Patterns []LeakPattern
+}
+
+// patternPackFile is the JSON layout of a pattern pack
+type patternPackFile struct {
+	Version  string `json:"version"`
+	Patterns []struct {
+		ID       string `json:"id"`
+		Name     string `json:"name"`
+		Provider string `json:"provider"`
+		Severity string `json:"severity"`
+		Docs     string `json:"docs"`
+		Pattern  string `json:"pattern"`
+	} `json:"patterns"`
+}
+
+func init() {
+	pack, err := ParsePatternPack(embeddedPatterns)
+	if err != nil {
+		panic("audit: invalid embedded patterns.json: " + err.Error())
+	}
+	KnownPatterns = pack.Patterns
+	PatternsVersion = pack.Version
+}
+
+// ReadPatternPack reads a pattern pack in the patterns.json format
+func ReadPatternPack(r io.Reader) (*PatternPack, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePatternPack(data)
+}
+
+// ParsePatternPack parses and validates a pattern pack
+func ParsePatternPack(data []byte) (*PatternPack, error) {
+	var file patternPackFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid pattern pack: %w", err)
+	}
+	if file.Version == "" {
+		return nil, fmt.Errorf("invalid pattern pack: missing version")
+	}
+
+	pack := &PatternPack{Version: file.Version}
+	seen := make(map[string]bool)
+	for i, p := range file.Patterns {
+		if p.ID == "" || p.Name == "" || p.Pattern == "" {
+			return nil, fmt.Errorf("invalid pattern pack: pattern %d needs id, name and pattern", i+1)
+		}
+		if seen[p.ID] {
+			return nil, fmt.Errorf("invalid pattern pack: duplicate pattern id %s", p.ID)
+		}
+		seen[p.ID] = true
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern pack: pattern %s: %w", p.ID, err)
+		}
+		pack.Patterns = append(pack.Patterns, LeakPattern{
+			ID:       p.ID,
+			Name:     p.Name,
+			Provider: p.Provider,
+			Severity: p.Severity,
+			DocsURL:  p.Docs,
+			Pattern:  re,
+		})
+	}
+	return pack, nil
+}
+
+// ApplyPatternPack layers pack over the active patterns: patterns with a
+// known id are replaced in place, new ones are appended
+func ApplyPatternPack(pack *PatternPack) {
+	index := make(map[string]int, len(KnownPatterns))
+	for i, p := range KnownPatterns {
+		index[p.ID] = i
+	}
+	patterns := append([]LeakPattern{}, KnownPatterns...)
+	for _, p := range pack.Patterns {
+		if i, ok := index[p.ID]; ok {
+			patterns[i] = p
+		} else {
+			patterns = append(patterns, p)
+		}
+	}
+	KnownPatterns = patterns
+	PatternsVersion = pack.Version
+}
+
+// PatternByID returns the active pattern with the given id
+func PatternByID(id string) (LeakPattern, bool) {
+	for _, p := range KnownPatterns {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return LeakPattern{}, false
+}
@@ -0,0 +1,137 @@
+package audit
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"env-audit/internal/i18n"
+)
+
+// Platform describes the constraints a deployment platform puts on the
+// environment it accepts
+type Platform struct {
+	Name             string
+	Title            string   // display name used in messages
+	MaxTotalSize     int      // bytes in all keys and values together, 0 for no limit
+	MaxValueLength   int      // bytes in a single value, 0 for no limit
+	Reserved         []string // keys the platform sets or refuses
+	ReservedPrefixes []string // key prefixes the platform keeps for itself
+	KeyPattern       *regexp.Regexp
+	KeyRule          string // KeyPattern in words
+}
+
+// platforms are the built-in profiles for --platform
+var platforms = map[string]*Platform{
+	"lambda": {
+		Name:         "lambda",
+		Title:        "AWS Lambda",
+		MaxTotalSize: 4 << 10,
+		Reserved: []string{
+			"_HANDLER", "_X_AMZN_TRACE_ID", "AWS_ACCESS_KEY", "AWS_ACCESS_KEY_ID",
+			"AWS_DEFAULT_REGION", "AWS_EXECUTION_ENV", "AWS_LAMBDA_FUNCTION_MEMORY_SIZE",
+			"AWS_LAMBDA_FUNCTION_NAME", "AWS_LAMBDA_FUNCTION_VERSION",
+			"AWS_LAMBDA_INITIALIZATION_TYPE", "AWS_LAMBDA_LOG_GROUP_NAME",
+			"AWS_LAMBDA_LOG_STREAM_NAME", "AWS_LAMBDA_RUNTIME_API", "AWS_REGION",
+			"AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "LAMBDA_RUNTIME_DIR",
+			"LAMBDA_TASK_ROOT",
+		},
+		KeyPattern: regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]+$`),
+		KeyRule:    "a letter followed by letters, digits or underscores",
+	},
+	"cloudrun": {
+		Name:           "cloudrun",
+		Title:          "Cloud Run",
+		MaxValueLength: 32 << 10,
+		Reserved: []string{
+			"CLOUD_RUN_EXECUTION", "CLOUD_RUN_JOB", "CLOUD_RUN_TASK_ATTEMPT",
+			"CLOUD_RUN_TASK_COUNT", "CLOUD_RUN_TASK_INDEX", "K_CONFIGURATION",
+			"K_REVISION", "K_SERVICE", "PORT",
+		},
+		ReservedPrefixes: []string{"X_GOOGLE_"},
+	},
+	"heroku": {
+		Name:             "heroku",
+		Title:            "Heroku",
+		MaxTotalSize:     32 << 10,
+		Reserved:         []string{"DYNO", "PORT"},
+		ReservedPrefixes: []string{"HEROKU_", "__"},
+		KeyPattern:       regexp.MustCompile(`^[A-Za-z0-9_]+$`),
+		KeyRule:          "letters, digits and underscores",
+	},
+}
+
+// PlatformByName returns the built-in profile of a platform
+func PlatformByName(name string) (*Platform, bool) {
+	p, ok := platforms[name]
+	return p, ok
+}
+
+// PlatformNames lists the built-in platform profiles in sorted order
+func PlatformNames() []string {
+	names := make([]string, 0, len(platforms))
+	for name := range platforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CheckPlatform validates env against a platform profile: keys the platform
+// reserves, key names it rejects, and its size limits, which are checked
+// like CheckLimits
+func CheckPlatform(env map[string]string, p *Platform, locations map[string]Location, ignore []string) []Issue {
+	if p == nil {
+		return nil
+	}
+	ignoreSet := toSet(ignore)
+	reserved := toSet(p.Reserved)
+
+	var issues []Issue
+	for _, key := range sortedKeys(env) {
+		if ignoreSet[key] {
+			continue
+		}
+		if reserved[key] {
+			issues = append(issues, Issue{
+				Type:    IssuePlatform,
+				Key:     key,
+				Message: i18n.T("key is reserved by %s", p.Title),
+			})
+			continue
+		}
+		if prefix, ok := reservedPrefix(key, p.ReservedPrefixes); ok {
+			issues = append(issues, Issue{
+				Type:    IssuePlatform,
+				Key:     key,
+				Message: i18n.T("keys starting with %s are reserved by %s", prefix, p.Title),
+			})
+			continue
+		}
+		if p.KeyPattern != nil && !p.KeyPattern.MatchString(key) {
+			issues = append(issues, Issue{
+				Type:    IssuePlatform,
+				Key:     key,
+				Message: i18n.T("key name not accepted by %s, use %s", p.Title, i18n.T(p.KeyRule)),
+			})
+		}
+	}
+
+	limits := Limits{MaxValueLength: p.MaxValueLength, MaxTotalSize: p.MaxTotalSize}
+	for _, issue := range CheckLimits(env, limits, locations, ignore) {
+		issue.Type = IssuePlatform
+		issue.Message = i18n.T("%s on %s", issue.Message, p.Title)
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// reservedPrefix returns the prefix of key that is in prefixes, if any
+func reservedPrefix(key string, prefixes []string) (string, bool) {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return prefix, true
+		}
+	}
+	return "", false
+}
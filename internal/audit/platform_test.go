@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckPlatform(t *testing.T) {
+	lambda, ok := PlatformByName("lambda")
+	if !ok {
+		t.Fatal("expected a lambda profile")
+	}
+	env := map[string]string{
+		"AWS_REGION":   "eu-west-1",
+		"APP_NAME":     "api",
+		"1ST_RUN":      "true",
+		"IGNORED_KEY":  "x",
+		"AWS_PROFILE":  "dev",
+		"LARGE_CONFIG": strings.Repeat("x", 4096),
+	}
+
+	issues := CheckPlatform(env, lambda, nil, []string{"IGNORED_KEY"})
+	want := map[string]string{
+		"1ST_RUN":      "key name not accepted by AWS Lambda, use a letter followed by letters, digits or underscores",
+		"AWS_REGION":   "key is reserved by AWS Lambda",
+		"LARGE_CONFIG": "variables total 4175 bytes, over the limit of 4096 on AWS Lambda",
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("expected %d issues, got %v", len(want), issues)
+	}
+	for _, issue := range issues {
+		if issue.Type != IssuePlatform || want[issue.Key] != issue.Message {
+			t.Errorf("unexpected issue: %+v", issue)
+		}
+	}
+}
+
+func TestCheckPlatform_ReservedPrefixes(t *testing.T) {
+	heroku, _ := PlatformByName("heroku")
+	env := map[string]string{"HEROKU_APP_NAME": "api", "__SECRET": "x", "PORT": "3000", "APP-NAME": "api"}
+
+	issues := CheckPlatform(env, heroku, nil, nil)
+	if len(issues) != 4 {
+		t.Fatalf("expected 4 issues, got %v", issues)
+	}
+	if issues[1].Key != "HEROKU_APP_NAME" || issues[1].Message != "keys starting with HEROKU_ are reserved by Heroku" {
+		t.Errorf("unexpected issue: %+v", issues[1])
+	}
+}
+
+func TestPlatformNames(t *testing.T) {
+	if got := strings.Join(PlatformNames(), ","); got != "cloudrun,heroku,lambda" {
+		t.Errorf("PlatformNames() = %s", got)
+	}
+	if issues := CheckPlatform(map[string]string{"PORT": "1"}, nil, nil, nil); len(issues) != 0 {
+		t.Errorf("expected no issues without a platform, got %v", issues)
+	}
+}
@@ -0,0 +1,84 @@
+package audit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"env-audit/internal/i18n"
+)
+
+// Source is where a value is declared to come from, written as a
+// "# source: vault:secret/app#API_KEY" comment above the entry. Store is
+// the kind of store (vault, ssm or file), Path the location in it and Key
+// the name the value has there, which defaults to the entry's own key.
+type Source struct {
+	Store string
+	Path  string
+	Key   string
+}
+
+// String formats the source as it is written in a comment
+func (s Source) String() string {
+	return s.Store + ":" + s.Path + "#" + s.Key
+}
+
+// ParseSource parses a source reference of the entry key
+func ParseSource(ref, key string) (Source, error) {
+	store, rest, ok := strings.Cut(ref, ":")
+	if !ok || store == "" || rest == "" {
+		return Source{}, fmt.Errorf("invalid source %q (expected store:path#KEY)", ref)
+	}
+	path, sourceKey, _ := strings.Cut(rest, "#")
+	if path == "" {
+		return Source{}, fmt.Errorf("invalid source %q (expected store:path#KEY)", ref)
+	}
+	if sourceKey == "" {
+		sourceKey = key
+	}
+	return Source{Store: store, Path: path, Key: sourceKey}, nil
+}
+
+// SourceLookup returns the value a source holds, and false if the source
+// exists but no longer has the key
+type SourceLookup func(Source) (string, bool, error)
+
+// CheckSourceDrift compares each annotated value with the value at its
+// declared source and reports keys whose source lost them or whose value
+// drifted. Values are never reported.
+// refs holds the source reference of each annotated key, lines its line.
+func CheckSourceDrift(file string, env map[string]string, refs map[string]string, lines map[string]int, lookup SourceLookup, ignore []string) ([]Issue, error) {
+	ignoreSet := toSet(ignore)
+	keys := make([]string, 0, len(refs))
+	for key := range refs {
+		if !ignoreSet[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var issues []Issue
+	for _, key := range keys {
+		issue := Issue{Type: IssueSourceDrift, Key: key, File: file, Line: lines[key]}
+		source, err := ParseSource(refs[key], key)
+		if err != nil {
+			issue.Message = i18n.T("invalid source annotation %q", refs[key])
+			issues = append(issues, issue)
+			continue
+		}
+		value, found, err := lookup(source)
+		if err != nil {
+			return nil, fmt.Errorf("%s: source %s: %w", key, source, err)
+		}
+		switch {
+		case !found:
+			issue.Message = i18n.T("source %s no longer exists", source)
+		case value != env[key]:
+			issue.Message = i18n.T("value differs from its source %s", source)
+		default:
+			continue
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
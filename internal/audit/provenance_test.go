@@ -0,0 +1,66 @@
+package audit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseSource(t *testing.T) {
+	source, err := ParseSource("vault:secret/app#API_TOKEN", "API_KEY")
+	if err != nil || source != (Source{Store: "vault", Path: "secret/app", Key: "API_TOKEN"}) {
+		t.Errorf("unexpected source: %+v, %v", source, err)
+	}
+	source, err = ParseSource("ssm:/app/prod", "API_KEY")
+	if err != nil || source.Key != "API_KEY" || source.String() != "ssm:/app/prod#API_KEY" {
+		t.Errorf("expected the entry's key by default, got %+v, %v", source, err)
+	}
+	for _, ref := range []string{"vault", "vault:", ":secret/app", "vault:#KEY"} {
+		if _, err := ParseSource(ref, "KEY"); err == nil {
+			t.Errorf("%q: expected an error", ref)
+		}
+	}
+}
+
+func TestCheckSourceDrift(t *testing.T) {
+	env := map[string]string{"API_KEY": "current", "DB_PASSWORD": "old", "GONE": "x", "BAD": "y", "IGNORED": "z"}
+	refs := map[string]string{
+		"API_KEY":     "vault:secret/app",
+		"DB_PASSWORD": "vault:secret/app#DATABASE_PASSWORD",
+		"GONE":        "vault:secret/app",
+		"BAD":         "nowhere",
+		"IGNORED":     "vault:secret/app",
+	}
+	lines := map[string]int{"API_KEY": 2, "DB_PASSWORD": 4, "GONE": 6, "BAD": 8}
+	store := map[string]string{"API_KEY": "current", "DATABASE_PASSWORD": "rotated"}
+	lookup := func(s Source) (string, bool, error) {
+		value, ok := store[s.Key]
+		return value, ok, nil
+	}
+
+	issues, err := CheckSourceDrift(".env", env, refs, lines, lookup, []string{"IGNORED"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{
+		"BAD":         `invalid source annotation "nowhere"`,
+		"DB_PASSWORD": "value differs from its source vault:secret/app#DATABASE_PASSWORD",
+		"GONE":        "source vault:secret/app#GONE no longer exists",
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("expected %d issues, got %+v", len(want), issues)
+	}
+	for _, issue := range issues {
+		if issue.Type != IssueSourceDrift || issue.Message != want[issue.Key] || issue.File != ".env" || issue.Line != lines[issue.Key] {
+			t.Errorf("unexpected issue: %+v", issue)
+		}
+		if strings.Contains(issue.Message, "rotated") || strings.Contains(issue.Message, "old") {
+			t.Errorf("expected values never to be reported: %s", issue.Message)
+		}
+	}
+
+	failing := func(Source) (string, bool, error) { return "", false, errors.New("permission denied") }
+	if _, err := CheckSourceDrift(".env", env, map[string]string{"API_KEY": "vault:secret/app"}, lines, failing, nil); err == nil || !strings.Contains(err.Error(), "API_KEY: source vault:secret/app#API_KEY: permission denied") {
+		t.Errorf("expected the lookup error, got %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package audit
+
+import "strings"
+
+// PublicValue is a value that is safe to expose, such as a Stripe publishable
+// key or a referrer-restricted Google Maps browser key. Pattern is the exact
+// value or may use * as a wildcard, e.g. pk_live_*. Reason documents why the
+// value is public.
+type PublicValue struct {
+	Pattern string
+	Reason  string
+}
+
+// Match reports whether value matches the pattern
+func (p PublicValue) Match(value string) bool {
+	return wildcardMatch(p.Pattern, value)
+}
+
+// FindPublicValue returns the first entry of public matching value
+func FindPublicValue(value string, public []PublicValue) (PublicValue, bool) {
+	for _, p := range public {
+		if p.Match(value) {
+			return p, true
+		}
+	}
+	return PublicValue{}, false
+}
+
+// SuppressPublic drops leak and sensitive findings for keys whose value is
+// known to be public, so reports focus on real risk. Other findings, such as
+// an empty or malformed value, are kept.
+func SuppressPublic(issues []Issue, env map[string]string, public []PublicValue) []Issue {
+	if len(public) == 0 {
+		return issues
+	}
+	kept := issues[:0:0]
+	for _, issue := range issues {
+		if issue.Type.IsSecret() || issue.Type == IssueSensitive {
+			if value, ok := env[issue.Key]; ok && value != "" {
+				if _, public := FindPublicValue(value, public); public {
+					continue
+				}
+			}
+		}
+		kept = append(kept, issue)
+	}
+	return kept
+}
+
+// wildcardMatch matches s against pattern, where * matches any run of
+// characters, including none
+func wildcardMatch(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return len(s) >= len(last) && strings.HasSuffix(s, last)
+}
@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestWildcardMatch(t *testing.T) {
+	tests := []struct {
+		pattern, value string
+		want           bool
+	}{
+		{"pk_live_*", "pk_live_51H8abc", true},
+		{"pk_live_*", "sk_live_51H8abc", false},
+		{"pk_live_abc", "pk_live_abc", true},
+		{"pk_live_abc", "pk_live_abcd", false},
+		{"*-public", "maps-key-public", true},
+		{"AIza*maps*", "AIzaSyXmapsY", true},
+		{"a*b*a", "aba", true},
+		{"a*a", "a", false},
+		{"*", "", true},
+	}
+	for _, tt := range tests {
+		if got := wildcardMatch(tt.pattern, tt.value); got != tt.want {
+			t.Errorf("wildcardMatch(%q, %q) = %v, want %v", tt.pattern, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSuppressPublic(t *testing.T) {
+	env := map[string]string{
+		"STRIPE_PUBLISHABLE_KEY": "pk_live_51H8abcdefghijklmnop",
+		"STRIPE_SECRET_KEY":      "sk_live_51H8abcdefghijklmnop",
+	}
+	issues := []Issue{
+		{Type: IssueSensitive, Key: "STRIPE_PUBLISHABLE_KEY"},
+		{Type: IssueMalformed, Key: "STRIPE_PUBLISHABLE_KEY"},
+		{Type: IssueSensitive, Key: "STRIPE_SECRET_KEY"},
+		{Type: IssueLeak, Key: "STRIPE_SECRET_KEY"},
+	}
+	public := []PublicValue{{Pattern: "pk_live_*", Reason: "publishable keys ship to browsers"}}
+
+	kept := SuppressPublic(issues, env, public)
+	if len(kept) != 3 || kept[0].Type != IssueMalformed || kept[1].Key != "STRIPE_SECRET_KEY" {
+		t.Errorf("expected only the publishable key's sensitive finding dropped, got %+v", kept)
+	}
+	if len(issues) != 4 || issues[0].Type != IssueSensitive {
+		t.Error("SuppressPublic must not modify its input")
+	}
+}
+
+// A pattern of the value itself, or with a * in place of any substring,
+// always matches the value
+func TestProperty_WildcardMatchesItsValue(t *testing.T) {
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("value matches itself and its wildcarded forms", prop.ForAll(
+		func(prefix, middle, suffix string) bool {
+			value := prefix + middle + suffix
+			return wildcardMatch(value, value) &&
+				wildcardMatch(prefix+"*"+suffix, value) &&
+				wildcardMatch("*"+middle+"*", value)
+		},
+		gen.AlphaString(),
+		gen.AlphaString(),
+		gen.AlphaString(),
+	))
+
+	properties.TestingRun(t)
+}
@@ -0,0 +1,53 @@
+package audit
+
+import (
+	"sort"
+	"time"
+
+	"env-audit/internal/i18n"
+)
+
+// Rotation is a credential rotation policy
+type Rotation struct {
+	After       time.Duration        // how long a credential may live
+	LastRotated map[string]time.Time // when each key was last rotated
+	Now         time.Time            // reference time, defaults to time.Now()
+}
+
+// CheckRotation flags keys present in env whose last rotation is older than
+// the rotation window. Keys without a known rotation date are not reported.
+func CheckRotation(env map[string]string, rotation *Rotation, ignore []string) []Issue {
+	if rotation == nil || rotation.After <= 0 {
+		return nil
+	}
+	now := rotation.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	ignoreSet := toSet(ignore)
+
+	keys := make([]string, 0, len(rotation.LastRotated))
+	for key := range rotation.LastRotated {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var issues []Issue
+	for _, key := range keys {
+		if _, ok := env[key]; !ok || ignoreSet[key] {
+			continue
+		}
+		rotated := rotation.LastRotated[key]
+		age := now.Sub(rotated)
+		if age <= rotation.After {
+			continue
+		}
+		issues = append(issues, Issue{
+			Type: IssueRotation,
+			Key:  key,
+			Message: i18n.T("last rotated %s (%d days ago), past its %d day rotation window",
+				rotated.Format("2006-01-02"), int(age.Hours()/24), int(rotation.After.Hours()/24)),
+		})
+	}
+	return issues
+}
@@ -0,0 +1,42 @@
+package audit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckRotation(t *testing.T) {
+	now := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	rotation := &Rotation{
+		After: 90 * 24 * time.Hour,
+		LastRotated: map[string]time.Time{
+			"STRIPE_KEY":  time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+			"GITHUB_PAT":  time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+			"REMOVED_KEY": time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			"IGNORED_KEY": time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Now: now,
+	}
+	env := map[string]string{"STRIPE_KEY": "x", "GITHUB_PAT": "y", "IGNORED_KEY": "z"}
+
+	issues := CheckRotation(env, rotation, []string{"IGNORED_KEY"})
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 overdue credential, got %v", issues)
+	}
+	issue := issues[0]
+	if issue.Type != IssueRotation || issue.Key != "STRIPE_KEY" {
+		t.Errorf("unexpected issue: %+v", issue)
+	}
+	if issue.Message != "last rotated 2026-06-01 (137 days ago), past its 90 day rotation window" {
+		t.Errorf("unexpected message: %s", issue.Message)
+	}
+	if !IssueRotation.IsWarning() {
+		t.Error("expected overdue rotation to be a warning")
+	}
+}
+
+func TestCheckRotation_NoPolicy(t *testing.T) {
+	if issues := CheckRotation(map[string]string{"A": "1"}, nil, nil); len(issues) != 0 {
+		t.Errorf("expected no issues without a policy, got %v", issues)
+	}
+}
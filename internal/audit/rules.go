@@ -0,0 +1,39 @@
+package audit
+
+import (
+	"regexp"
+	"sort"
+
+	"env-audit/internal/i18n"
+)
+
+// CheckRules validates values against per-key regular expressions, e.g.
+// DATABASE_URL against ^postgres://. Absent keys are left to the required
+// checks and empty values to CheckEmpty. Messages name the rule, never the
+// value, since the value may be a secret.
+func CheckRules(env map[string]string, rules map[string]*regexp.Regexp, ignore []string) []Issue {
+	ignoreSet := toSet(ignore)
+
+	keys := make([]string, 0, len(rules))
+	for key := range rules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var issues []Issue
+	for _, key := range keys {
+		value, ok := env[key]
+		if !ok || value == "" || ignoreSet[key] {
+			continue
+		}
+		if rules[key].MatchString(value) {
+			continue
+		}
+		issues = append(issues, Issue{
+			Type:    IssueInvalid,
+			Key:     key,
+			Message: i18n.T("value does not match rule %s", rules[key].String()),
+		})
+	}
+	return issues
+}
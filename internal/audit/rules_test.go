@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestCheckRules(t *testing.T) {
+	rules := map[string]*regexp.Regexp{
+		"DATABASE_URL": regexp.MustCompile(`^postgres://`),
+		"PORT":         regexp.MustCompile(`^[0-9]+$`),
+		"LOG_LEVEL":    regexp.MustCompile(`^(debug|info)$`),
+		"ABSENT":       regexp.MustCompile(`^x$`),
+		"EMPTY":        regexp.MustCompile(`^x$`),
+	}
+	env := map[string]string{
+		"DATABASE_URL": "mysql://user:hunter2@db/app",
+		"PORT":         "8080",
+		"LOG_LEVEL":    "verbose",
+		"EMPTY":        "",
+	}
+
+	issues := CheckRules(env, rules, []string{"LOG_LEVEL"})
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 invalid value, got %v", issues)
+	}
+	issue := issues[0]
+	if issue.Type != IssueInvalid || issue.Key != "DATABASE_URL" {
+		t.Errorf("unexpected issue: %+v", issue)
+	}
+	if issue.Message != "value does not match rule ^postgres://" {
+		t.Errorf("unexpected message: %s", issue.Message)
+	}
+	if strings.Contains(issue.Message, "hunter2") {
+		t.Error("message must not reveal the value")
+	}
+	if IssueInvalid.IsWarning() || IssueInvalid.IsInfo() {
+		t.Error("expected an invalid value to be an error")
+	}
+}
+
+func TestScan_Rules(t *testing.T) {
+	result := Scan(map[string]string{"PORT": "http"}, &ScanOptions{
+		Rules: map[string]*regexp.Regexp{"PORT": regexp.MustCompile(`^[0-9]+$`)},
+	})
+	if result.Summary[IssueInvalid] != 1 || !result.HasRisks {
+		t.Errorf("expected an invalid value to be a risk, got %+v", result)
+	}
+}
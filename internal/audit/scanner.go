@@ -1,33 +1,113 @@
 package audit
 
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"env-audit/internal/i18n"
+)
+
 // Result aggregates all audit findings
 type Result struct {
 	Issues   []Issue
 	HasRisks bool
 	Summary  map[IssueType]int
+	Files    []FileStatus // per-file status for multi-file scans
+
+	// Diagnostics are the lines the parser could not read, unless
+	// --warn-parse reported them as issues
+	Diagnostics []Diagnostic
+}
+
+// FileStatus records the outcome of scanning a single input file
+type FileStatus struct {
+	Path  string
+	Error error // non-nil if the file could not be read or parsed
+}
+
+// Failed returns the number of files that could not be scanned
+func (r *Result) Failed() int {
+	failed := 0
+	for _, f := range r.Files {
+		if f.Error != nil {
+			failed++
+		}
+	}
+	return failed
 }
 
 // ScanOptions configures the scan behavior
 type ScanOptions struct {
-	Required   []string
-	Ignore     []string
-	Duplicates []string
-	Missing    []string // keys missing from target (from example comparison)
-	Extra      []string // keys extra in target (from example comparison)
-	CheckLeaks bool
-	Strict     bool
+	Required     []string
+	AllowEmpty   []string // keys that may be empty; empty required keys are otherwise errors
+	Ignore       []string
+	Duplicates   []string
+	Missing      []string // keys missing from target (from example comparison)
+	Extra        []string // keys extra in target (from example comparison)
+	CheckLeaks   bool
+	SkipFormats  bool                      // don't check credential formats implied by key names
+	Rotation     *Rotation                 // credential rotation policy, nil to skip
+	Rules        map[string]*regexp.Regexp // per-key value patterns from config rules and --rule
+	Public       []PublicValue             // known-public values exempt from leak and sensitive findings
+	Allowed      []string                  // SHA-256 digests of known-safe values exempt from leak findings
+	Unresolved   map[string][]string       // references each value failed to expand, from the parser
+	Strict       bool
+	File         string              // source file recorded on every issue
+	Locations    map[string]Location // per-key origin, takes precedence over File
+	Overrides    map[string][]Location
+	Defaults     map[string]Default
+	Order        *Ordering // env file layout, checked against ExampleOrder when both are set
+	ExampleOrder *Ordering
+	SortByKey    bool // order issues by key rather than by position in the file
+	Limits       Limits
+	Platform     *Platform // deployment platform whose constraints apply, nil to skip
+
+	// FailOn is the lowest severity that fails the audit: error by default,
+	// warning if Strict is set
+	FailOn   string
+	Severity SeverityPolicy // per-type severity overrides
 }
 
 // IsWarning returns true if the issue type is a warning (not an error)
 func (t IssueType) IsWarning() bool {
 	switch t {
-	case IssueEmpty, IssueDuplicate, IssueExtra:
+	case IssueEmpty, IssueDuplicate, IssueExtra, IssueUnsafeDefault, IssueOrder, IssueMalformed, IssueRotation, IssueUnresolved, IssueSystem, IssueDocs, IssueSourceDrift, IssueParse:
 		return true
 	default:
 		return false
 	}
 }
 
+// IsInfo returns true if the issue type is informational and never a risk
+func (t IssueType) IsInfo() bool {
+	switch t {
+	case IssueSensitive, IssueDefault, IssueOverride:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsSecret returns true if the issue type reports a value that looks like a
+// real secret, in an env file or in a committed example
+func (t IssueType) IsSecret() bool {
+	return t == IssueLeak || t == IssueExampleSecret
+}
+
+// Severity names the severity level of the issue type: "error", "warning"
+// or "info"
+func (t IssueType) Severity() string {
+	switch {
+	case t.IsInfo():
+		return SeverityInfo
+	case t.IsWarning():
+		return SeverityWarning
+	default:
+		return SeverityError
+	}
+}
+
 // Scan runs all checks and returns aggregated results
 func Scan(env map[string]string, opts *ScanOptions) *Result {
 	if opts == nil {
@@ -36,10 +116,33 @@ func Scan(env map[string]string, opts *ScanOptions) *Result {
 
 	var issues []Issue
 
+	// Absent variables with a default are reported by CheckDefaults, not as missing
+	missingIgnore := append([]string{}, opts.Ignore...)
+	for key := range opts.Defaults {
+		missingIgnore = append(missingIgnore, key)
+	}
+
 	// Run all checks
-	issues = append(issues, CheckEmpty(env, opts.Ignore)...)
-	issues = append(issues, CheckMissing(env, opts.Required, opts.Ignore)...)
+	issues = append(issues, CheckEmptyWith(env, EmptyPolicy{Required: opts.Required, AllowEmpty: opts.AllowEmpty}, opts.Ignore)...)
+	issues = append(issues, CheckMissing(env, opts.Required, missingIgnore)...)
 	issues = append(issues, CheckSensitive(env, opts.Ignore)...)
+	issues = append(issues, CheckDefaults(env, opts.Defaults, opts.Ignore)...)
+	issues = append(issues, CheckOverrides(opts.Overrides, opts.Ignore)...)
+	if !opts.SkipFormats {
+		issues = append(issues, CheckFormats(env, opts.Ignore)...)
+	}
+	issues = append(issues, CheckRotation(env, opts.Rotation, opts.Ignore)...)
+	issues = append(issues, CheckRules(env, opts.Rules, opts.Ignore)...)
+	issues = append(issues, CheckUnresolved(opts.Unresolved, opts.Ignore)...)
+	issues = append(issues, CheckLimits(env, opts.Limits, opts.Locations, opts.Ignore)...)
+	issues = append(issues, CheckPlatform(env, opts.Platform, opts.Locations, opts.Ignore)...)
+	// The process environment sets system variables itself, only files are checked
+	if opts.File != "" || len(opts.Locations) > 0 {
+		issues = append(issues, CheckSystem(env, opts.Ignore)...)
+	}
+	if opts.Order != nil && opts.ExampleOrder != nil {
+		issues = append(issues, CheckOrder(*opts.Order, *opts.ExampleOrder, opts.Ignore)...)
+	}
 
 	// Add duplicate issues
 	ignoreSet := toSet(opts.Ignore)
@@ -50,19 +153,19 @@ func Scan(env map[string]string, opts *ScanOptions) *Result {
 		issues = append(issues, Issue{
 			Type:    IssueDuplicate,
 			Key:     key,
-			Message: "duplicate key definition",
+			Message: i18n.T("duplicate key definition"),
 		})
 	}
 
 	// Add missing issues from example comparison
 	for _, key := range opts.Missing {
-		if ignoreSet[key] {
+		if _, hasDefault := opts.Defaults[key]; ignoreSet[key] || hasDefault {
 			continue
 		}
 		issues = append(issues, Issue{
 			Type:    IssueMissing,
 			Key:     key,
-			Message: "variable missing from example",
+			Message: i18n.T("variable missing from example"),
 		})
 	}
 
@@ -74,7 +177,7 @@ func Scan(env map[string]string, opts *ScanOptions) *Result {
 		issues = append(issues, Issue{
 			Type:    IssueExtra,
 			Key:     key,
-			Message: "variable not in example file",
+			Message: i18n.T("variable not in example file"),
 		})
 	}
 
@@ -83,38 +186,116 @@ func Scan(env map[string]string, opts *ScanOptions) *Result {
 		issues = append(issues, CheckLeaks(env, opts.Ignore)...)
 	}
 
-	// Build summary
-	summary := make(map[IssueType]int)
-	for _, issue := range issues {
-		summary[issue.Type]++
+	issues = SuppressPublic(issues, env, opts.Public)
+	issues = SuppressAllowed(issues, env, opts.Allowed)
+
+	// Record the source file and line on every issue
+	for i := range issues {
+		if loc, ok := opts.Locations[issues[i].Key]; ok {
+			issues[i].File = loc.File
+			issues[i].Line = loc.Line
+		} else if opts.File != "" {
+			issues[i].File = opts.File
+		}
 	}
 
-	// Determine HasRisks based on strict mode
-	hasRisks := hasRiskIssues(issues, opts.Strict)
+	if opts.CheckLeaks {
+		for _, issue := range CheckKeyLeaks(env, opts.Ignore) {
+			if loc, ok := opts.Locations[issue.Key]; ok {
+				issue.File, issue.Line = loc.File, loc.Line
+			} else {
+				issue.File = opts.File
+			}
+			issues = append(issues, issue)
+		}
+	}
+	maskSecretKeys(issues, env)
+
+	SortIssues(issues, opts.SortByKey)
 
-	return &Result{
-		Issues:   issues,
-		HasRisks: hasRisks,
-		Summary:  summary,
+	failOn := opts.FailOn
+	if failOn == "" && opts.Strict {
+		failOn = SeverityWarning
 	}
+	return NewResult(issues, opts.Severity, failOn)
 }
 
-// hasRiskIssues returns true if there are issues that should cause exit code 1
-// In strict mode, warnings are treated as errors
-func hasRiskIssues(issues []Issue, strict bool) bool {
-	for _, issue := range issues {
-		// Info-level issues (IssueSensitive) never cause risks
-		if issue.Type == IssueSensitive {
-			continue
+// maskSecretKeys masks keys that are secrets themselves in every issue,
+// whether or not leaks are checked, once its location is known. Messages
+// naming such a key, e.g. as the key an out-of-order one should follow,
+// are masked too.
+func maskSecretKeys(issues []Issue, env map[string]string) {
+	var secretKeys []string
+	for key := range env {
+		if IsSecretKey(key) {
+			secretKeys = append(secretKeys, key)
+		}
+	}
+	for i := range issues {
+		for _, key := range secretKeys {
+			issues[i].Message = strings.ReplaceAll(issues[i].Message, key, MaskKey(key))
+		}
+		if IsSecretKey(issues[i].Key) {
+			issues[i].Key = MaskKey(issues[i].Key)
+		}
+	}
+}
+
+// SortIssues orders issues by their position in the file, or by key if
+// byKey is set. Issues without a line, such as missing keys, follow those
+// with one. The sort is stable, so issues at the same position keep the
+// order of the checks that found them.
+func SortIssues(issues []Issue, byKey bool) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if byKey {
+			return a.Key < b.Key
 		}
-		// Errors always cause risks
-		if !issue.Type.IsWarning() {
-			return true
+		if (a.Line == 0) != (b.Line == 0) {
+			return b.Line == 0
 		}
-		// Warnings cause risks only in strict mode
-		if strict {
-			return true
+		if a.File != b.File {
+			return a.File < b.File
 		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Key < b.Key
+	})
+}
+
+// Merge combines per-file results into a single result.
+// File statuses are taken from files; the summary is rebuilt from the merged issues.
+func Merge(results []*Result, files []FileStatus) *Result {
+	merged := &Result{
+		Summary: make(map[IssueType]int),
+		Files:   files,
+	}
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		merged.Issues = append(merged.Issues, r.Issues...)
+		merged.Diagnostics = append(merged.Diagnostics, r.Diagnostics...)
+		if r.HasRisks {
+			merged.HasRisks = true
+		}
+	}
+	for _, issue := range merged.Issues {
+		merged.Summary[issue.Type]++
+	}
+	return merged
+}
+
+// IsRisk reports whether an issue of this type fails the audit: errors
+// always do, warnings only in strict mode, and info-level issues never
+func (t IssueType) IsRisk(strict bool) bool {
+	switch {
+	case t.IsInfo():
+		return false
+	case t.IsWarning():
+		return strict
+	default:
+		return true
 	}
-	return false
 }
@@ -1,6 +1,10 @@
 package audit
 
-import "testing"
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
 
 func TestScan_NoIssues(t *testing.T) {
 	env := map[string]string{"APP_NAME": "test"}
@@ -144,3 +148,93 @@ func TestScan_Summary(t *testing.T) {
 		t.Errorf("expected 1 sensitive in summary, got %d", result.Summary[IssueSensitive])
 	}
 }
+
+func TestScan_RecordsFile(t *testing.T) {
+	env := map[string]string{"DB_URL": ""}
+	result := Scan(env, &ScanOptions{File: "app.env"})
+
+	if len(result.Issues) != 1 || result.Issues[0].File != "app.env" {
+		t.Errorf("expected issue from app.env, got %v", result.Issues)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	r1 := Scan(map[string]string{"A": ""}, &ScanOptions{File: "a.env"})
+	r2 := Scan(map[string]string{"B": "x"}, &ScanOptions{File: "b.env", Required: []string{"C"}})
+	files := []FileStatus{{Path: "a.env"}, {Path: "b.env"}, {Path: "c.env", Error: errors.New("boom")}}
+
+	merged := Merge([]*Result{r1, nil, r2}, files)
+
+	if len(merged.Issues) != 2 {
+		t.Errorf("expected 2 issues, got %d", len(merged.Issues))
+	}
+	if !merged.HasRisks {
+		t.Error("expected HasRisks from second result")
+	}
+	if merged.Summary[IssueEmpty] != 1 || merged.Summary[IssueMissing] != 1 {
+		t.Errorf("unexpected summary: %v", merged.Summary)
+	}
+	if merged.Failed() != 1 {
+		t.Errorf("expected 1 failed file, got %d", merged.Failed())
+	}
+}
+
+func TestScan_DefaultReplacesMissing(t *testing.T) {
+	result := Scan(map[string]string{}, &ScanOptions{
+		Required: []string{"LOG_LEVEL"},
+		Missing:  []string{"LOG_LEVEL"},
+		Defaults: map[string]Default{"LOG_LEVEL": {Value: "info"}},
+	})
+
+	if result.HasRisks {
+		t.Error("expected defaulted variable not to be a risk")
+	}
+	if len(result.Issues) != 1 || result.Issues[0].Type != IssueDefault {
+		t.Errorf("expected a single default issue, got %v", result.Issues)
+	}
+}
+
+func TestScan_IssueOrder(t *testing.T) {
+	env := map[string]string{"ZONE": "", "DB_PASSWORD": "", "API_URL": ""}
+	locations := map[string]Location{
+		"ZONE":        {File: ".env", Line: 1},
+		"DB_PASSWORD": {File: ".env", Line: 2},
+		"API_URL":     {File: ".env", Line: 3},
+	}
+	type found struct {
+		Key  string
+		Type IssueType
+	}
+	order := func(result *Result) []found {
+		var order []found
+		for _, issue := range result.Issues {
+			order = append(order, found{issue.Key, issue.Type})
+		}
+		return order
+	}
+
+	byFile := Scan(env, &ScanOptions{Locations: locations, Required: []string{"SECRET_KEY"}})
+	want := []found{{"ZONE", IssueEmpty}, {"DB_PASSWORD", IssueEmpty}, {"DB_PASSWORD", IssueSensitive}, {"API_URL", IssueEmpty}, {"SECRET_KEY", IssueMissing}}
+	if got := order(byFile); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected file order %v, got %v", want, got)
+	}
+
+	byKey := Scan(env, &ScanOptions{Locations: locations, Required: []string{"SECRET_KEY"}, SortByKey: true})
+	want = []found{{"API_URL", IssueEmpty}, {"DB_PASSWORD", IssueEmpty}, {"DB_PASSWORD", IssueSensitive}, {"SECRET_KEY", IssueMissing}, {"ZONE", IssueEmpty}}
+	if got := order(byKey); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected key order %v, got %v", want, got)
+	}
+}
+
+func TestScan_UnsafeDefault_Strict(t *testing.T) {
+	opts := &ScanOptions{Defaults: map[string]Default{"SECRET_KEY": {Value: "changeme", Unsafe: true}}}
+	env := map[string]string{"SECRET_KEY": "changeme"}
+
+	if Scan(env, opts).HasRisks {
+		t.Error("unsafe default should be a warning outside strict mode")
+	}
+	opts.Strict = true
+	if !Scan(env, opts).HasRisks {
+		t.Error("unsafe default should be a risk in strict mode")
+	}
+}
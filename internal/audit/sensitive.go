@@ -0,0 +1,56 @@
+package audit
+
+import "strings"
+
+// SensitiveMatcher decides from its name whether a variable holds a secret
+type SensitiveMatcher struct {
+	Patterns []string // matched anywhere in the upper-cased key
+	Suffixes []string // matched at the end of the upper-cased key, e.g. STRIPE_KEY
+	Exclude  []string // keys that are never sensitive, compared case-insensitively
+}
+
+// DefaultSensitiveKeys is the built-in matcher
+var DefaultSensitiveKeys = SensitiveMatcher{
+	Patterns: []string{"SECRET", "PASSWORD", "TOKEN", "API_KEY", "APIKEY", "CREDENTIAL", "PRIVATE", "AUTH"},
+	Suffixes: []string{"KEY"},
+}
+
+// SensitiveKeys is the active matcher behind IsSensitiveKey; the CLI extends
+// it from the sensitive_patterns and sensitive_exclude config settings
+var SensitiveKeys = DefaultSensitiveKeys
+
+// Match reports whether key is sensitive. Exclusions win over patterns, so
+// AUTHOR can be excluded while AUTH_TOKEN stays sensitive.
+func (m SensitiveMatcher) Match(key string) bool {
+	upper := strings.ToUpper(key)
+	for _, excluded := range m.Exclude {
+		if strings.ToUpper(excluded) == upper {
+			return false
+		}
+	}
+	for _, p := range m.Patterns {
+		if strings.Contains(upper, p) {
+			return true
+		}
+	}
+	for _, s := range m.Suffixes {
+		if strings.HasSuffix(upper, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Extend returns a copy of m with extra patterns and exclusions added;
+// patterns are upper-cased to match the keys they are compared against
+func (m SensitiveMatcher) Extend(patterns, exclude []string) SensitiveMatcher {
+	extended := SensitiveMatcher{
+		Patterns: append([]string{}, m.Patterns...),
+		Suffixes: append([]string{}, m.Suffixes...),
+		Exclude:  append(append([]string{}, m.Exclude...), exclude...),
+	}
+	for _, p := range patterns {
+		extended.Patterns = append(extended.Patterns, strings.ToUpper(p))
+	}
+	return extended
+}
@@ -0,0 +1,41 @@
+package audit
+
+import "testing"
+
+func TestSensitiveMatcher_Extend(t *testing.T) {
+	m := DefaultSensitiveKeys.Extend([]string{"dsn", "SIGNING"}, []string{"author", "OAUTH_CALLBACK_URL"})
+
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"SENTRY_DSN", true},
+		{"JWT_SIGNING_SALT", true},
+		{"AUTHOR", false},
+		{"oauth_callback_url", false},
+		{"AUTH_TOKEN", true},
+		{"STRIPE_KEY", true},
+		{"APP_NAME", false},
+	}
+	for _, tt := range tests {
+		if got := m.Match(tt.key); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+
+	if DefaultSensitiveKeys.Match("SENTRY_DSN") || !DefaultSensitiveKeys.Match("AUTHOR") {
+		t.Error("Extend must not modify the default matcher")
+	}
+}
+
+func TestIsSensitiveKey_UsesActiveMatcher(t *testing.T) {
+	defer func() { SensitiveKeys = DefaultSensitiveKeys }()
+	SensitiveKeys = DefaultSensitiveKeys.Extend(nil, []string{"AUTHOR"})
+
+	if IsSensitiveKey("AUTHOR") {
+		t.Error("expected AUTHOR to be excluded")
+	}
+	if !IsSensitiveKey("AUTH_TOKEN") {
+		t.Error("expected AUTH_TOKEN to stay sensitive")
+	}
+}
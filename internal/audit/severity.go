@@ -0,0 +1,128 @@
+package audit
+
+// Severity levels of an issue, from most to least severe. SeverityIgnore
+// only appears in a SeverityPolicy, to drop a type of issue altogether.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+	SeverityIgnore  = "ignore"
+)
+
+// severityRanks orders the levels an issue can be reported at
+var severityRanks = map[string]int{
+	SeverityInfo:    1,
+	SeverityWarning: 2,
+	SeverityError:   3,
+}
+
+// IsSeverity reports whether s is a level an issue can be reported at
+func IsSeverity(s string) bool {
+	_, ok := severityRanks[s]
+	return ok
+}
+
+// issueTypeIDs are the stable names of issue types used in JSON, SARIF
+// rule ids and config files
+var issueTypeIDs = map[IssueType]string{
+	IssueEmpty:         "empty",
+	IssueMissing:       "missing",
+	IssueSensitive:     "sensitive",
+	IssueDuplicate:     "duplicate",
+	IssueLeak:          "leak",
+	IssueExtra:         "extra",
+	IssueDefault:       "default",
+	IssueUnsafeDefault: "unsafe_default",
+	IssueOverride:      "override",
+	IssueOrder:         "order",
+	IssueMalformed:     "malformed",
+	IssueRotation:      "rotation",
+	IssueInvalid:       "invalid",
+	IssueUnresolved:    "unresolved",
+	IssueExampleSecret: "example_secret",
+	IssueLimit:         "limit",
+	IssuePlatform:      "platform",
+	IssueSystem:        "system_override",
+	IssueDocs:          "docs_drift",
+	IssueSourceDrift:   "source_drift",
+	IssueParse:         "parse_error",
+}
+
+// Name returns the stable name of the issue type, e.g. "leak"
+func (t IssueType) Name() string {
+	if name, ok := issueTypeIDs[t]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseIssueType returns the issue type with the given name
+func ParseIssueType(name string) (IssueType, bool) {
+	for t, id := range issueTypeIDs {
+		if id == name {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// SeverityPolicy overrides the default severity of issue types, e.g. to
+// report duplicates as errors or to ignore sensitive key findings
+type SeverityPolicy map[IssueType]string
+
+// Apply sets the severity of each issue whose type the policy overrides and
+// drops the types it ignores
+func (p SeverityPolicy) Apply(issues []Issue) []Issue {
+	if len(p) == 0 {
+		return issues
+	}
+	kept := issues[:0:0]
+	for _, issue := range issues {
+		switch level := p[issue.Type]; level {
+		case "":
+		case SeverityIgnore:
+			continue
+		default:
+			issue.Severity = level
+		}
+		kept = append(kept, issue)
+	}
+	return kept
+}
+
+// Level returns the severity the issue is reported at: the one a policy
+// set, or its type's default
+func (i Issue) Level() string {
+	if i.Severity != "" {
+		return i.Severity
+	}
+	return i.Type.Severity()
+}
+
+// IsRisk reports whether the issue fails an audit that fails on issues at
+// or above the failOn level. An empty failOn fails on errors only.
+func (i Issue) IsRisk(failOn string) bool {
+	if failOn == "" {
+		failOn = SeverityError
+	}
+	return severityRanks[i.Level()] >= severityRanks[failOn]
+}
+
+// NewResult applies a severity policy to issues and builds their result,
+// which has risks if any issue is at or above the failOn level
+func NewResult(issues []Issue, policy SeverityPolicy, failOn string) *Result {
+	issues = policy.Apply(issues)
+	summary := make(map[IssueType]int)
+	hasRisks := false
+	for _, issue := range issues {
+		summary[issue.Type]++
+		if issue.IsRisk(failOn) {
+			hasRisks = true
+		}
+	}
+	return &Result{
+		Issues:   issues,
+		HasRisks: hasRisks,
+		Summary:  summary,
+	}
+}
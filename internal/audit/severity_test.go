@@ -0,0 +1,108 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestIssueTypeNames(t *testing.T) {
+	for id, name := range issueTypeIDs {
+		got, ok := ParseIssueType(name)
+		if !ok || got != id {
+			t.Errorf("ParseIssueType(%q) = %v, %v; want %v", name, got, ok, id)
+		}
+	}
+	if _, ok := ParseIssueType("unknown"); ok {
+		t.Error("expected unknown names to be rejected")
+	}
+	if IssuePlatform.Name() != "platform" || IssueType(-1).Name() != "unknown" {
+		t.Errorf("unexpected names: %s, %s", IssuePlatform.Name(), IssueType(-1).Name())
+	}
+}
+
+func TestSeverityPolicy_Apply(t *testing.T) {
+	issues := []Issue{
+		{Type: IssueDuplicate, Key: "PORT"},
+		{Type: IssueSensitive, Key: "API_KEY"},
+		{Type: IssueEmpty, Key: "HOST"},
+	}
+	policy := SeverityPolicy{IssueDuplicate: SeverityError, IssueSensitive: SeverityIgnore}
+
+	got := policy.Apply(issues)
+	if len(got) != 2 || got[0].Level() != SeverityError || got[1].Level() != SeverityWarning {
+		t.Errorf("unexpected issues: %+v", got)
+	}
+	if issues[0].Severity != "" {
+		t.Error("expected the input issues to be left unchanged")
+	}
+}
+
+func TestIssue_IsRisk(t *testing.T) {
+	tests := []struct {
+		level, failOn string
+		want          bool
+	}{
+		{SeverityError, "", true},
+		{SeverityWarning, "", false},
+		{SeverityWarning, SeverityWarning, true},
+		{SeverityInfo, SeverityWarning, false},
+		{SeverityInfo, SeverityInfo, true},
+		{SeverityError, SeverityInfo, true},
+	}
+	for _, tt := range tests {
+		issue := Issue{Type: IssueEmpty, Severity: tt.level}
+		if got := issue.IsRisk(tt.failOn); got != tt.want {
+			t.Errorf("%s issue, fail on %q: IsRisk = %v, want %v", tt.level, tt.failOn, got, tt.want)
+		}
+	}
+}
+
+func TestScan_SeverityPolicy(t *testing.T) {
+	env := map[string]string{"API_KEY": "x", "PORT": "3000"}
+	opts := &ScanOptions{Duplicates: []string{"PORT"}}
+
+	if result := Scan(env, opts); result.HasRisks {
+		t.Errorf("expected duplicates to be warnings by default, got %+v", result.Issues)
+	}
+
+	opts.Severity = SeverityPolicy{IssueDuplicate: SeverityError, IssueSensitive: SeverityIgnore}
+	result := Scan(env, opts)
+	if !result.HasRisks || len(result.Issues) != 1 || result.Summary[IssueSensitive] != 0 {
+		t.Errorf("expected one duplicate error and no sensitive finding, got %+v", result.Issues)
+	}
+
+	opts.Severity = nil
+	opts.FailOn = SeverityInfo
+	if result := Scan(env, opts); !result.HasRisks {
+		t.Errorf("expected info findings to fail with fail on info, got %+v", result.Issues)
+	}
+}
+
+// Property: an issue that fails the run at one --fail-on level also fails it
+// at every lower level
+func TestProperty_FailOnIsMonotonic(t *testing.T) {
+	properties := gopter.NewProperties(gopter.DefaultTestParameters())
+	levels := []string{SeverityInfo, SeverityWarning, SeverityError}
+
+	properties.Property("a risk at one level is a risk at every lower level", prop.ForAll(
+		func(level, failOn int) bool {
+			issue := Issue{Type: IssueEmpty, Severity: levels[level]}
+			if !issue.IsRisk(levels[failOn]) {
+				return true
+			}
+			for lower := 0; lower <= failOn; lower++ {
+				if !issue.IsRisk(levels[lower]) {
+					return false
+				}
+			}
+			return true
+		},
+		gen.IntRange(0, 2),
+		gen.IntRange(0, 2),
+	))
+
+	properties.TestingRun(t)
+}
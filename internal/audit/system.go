@@ -0,0 +1,56 @@
+package audit
+
+import "env-audit/internal/i18n"
+
+// systemVars are variables the system or a language runtime reads at
+// startup, by what they control. An env file that is exported blindly
+// replaces them for every process it starts.
+var systemVars = map[string]string{
+	"PATH":                  "executable lookup",
+	"LD_PRELOAD":            "shared library loading",
+	"LD_LIBRARY_PATH":       "shared library loading",
+	"LD_AUDIT":              "shared library loading",
+	"DYLD_INSERT_LIBRARIES": "shared library loading",
+	"DYLD_LIBRARY_PATH":     "shared library loading",
+	"NODE_OPTIONS":          "the Node.js runtime",
+	"NODE_PATH":             "the Node.js runtime",
+	"PYTHONPATH":            "the Python runtime",
+	"PYTHONHOME":            "the Python runtime",
+	"PYTHONSTARTUP":         "the Python runtime",
+	"RUBYOPT":               "the Ruby runtime",
+	"RUBYLIB":               "the Ruby runtime",
+	"PERL5LIB":              "the Perl runtime",
+	"PERL5OPT":              "the Perl runtime",
+	"JAVA_TOOL_OPTIONS":     "the Java runtime",
+	"_JAVA_OPTIONS":         "the Java runtime",
+	"BASH_ENV":              "the shell",
+	"ENV":                   "the shell",
+	"IFS":                   "the shell",
+	"PROMPT_COMMAND":        "the shell",
+	"SHELL":                 "the shell",
+	"SHELLOPTS":             "the shell",
+	"HOME":                  "the user environment",
+	"USER":                  "the user environment",
+	"TMPDIR":                "the user environment",
+}
+
+// CheckSystem flags keys that shadow a system or runtime variable, such as
+// PATH or LD_PRELOAD, which can hijack or break every process started with
+// the file's environment
+func CheckSystem(env map[string]string, ignore []string) []Issue {
+	ignoreSet := toSet(ignore)
+
+	var issues []Issue
+	for _, key := range sortedKeys(env) {
+		controls, ok := systemVars[key]
+		if !ok || ignoreSet[key] {
+			continue
+		}
+		issues = append(issues, Issue{
+			Type:    IssueSystem,
+			Key:     key,
+			Message: i18n.T("shadows a system variable that controls %s", i18n.T(controls)),
+		})
+	}
+	return issues
+}
@@ -0,0 +1,42 @@
+package audit
+
+import "testing"
+
+func TestCheckSystem(t *testing.T) {
+	env := map[string]string{
+		"PATH":         "/opt/app/bin",
+		"LD_PRELOAD":   "/tmp/hook.so",
+		"NODE_OPTIONS": "--require ./trace.js",
+		"HOME":         "/srv",
+		"APP_HOME":     "/srv/app",
+		"DATABASE_URL": "postgres://localhost/app",
+	}
+
+	issues := CheckSystem(env, []string{"HOME"})
+	want := map[string]string{
+		"LD_PRELOAD":   "shadows a system variable that controls shared library loading",
+		"NODE_OPTIONS": "shadows a system variable that controls the Node.js runtime",
+		"PATH":         "shadows a system variable that controls executable lookup",
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("expected %d issues, got %v", len(want), issues)
+	}
+	for _, issue := range issues {
+		if issue.Type != IssueSystem || want[issue.Key] != issue.Message {
+			t.Errorf("unexpected issue: %+v", issue)
+		}
+	}
+	if !IssueSystem.IsWarning() {
+		t.Error("expected shadowed system variables to be warnings")
+	}
+}
+
+func TestScan_SystemOnlyInFiles(t *testing.T) {
+	env := map[string]string{"PATH": "/usr/bin"}
+	if result := Scan(env, &ScanOptions{}); result.Summary[IssueSystem] != 0 {
+		t.Errorf("expected the process environment not to be checked, got %v", result.Issues)
+	}
+	if result := Scan(env, &ScanOptions{File: ".env"}); result.Summary[IssueSystem] != 1 {
+		t.Errorf("expected PATH in a file to be flagged, got %v", result.Issues)
+	}
+}
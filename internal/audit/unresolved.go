@@ -0,0 +1,37 @@
+package audit
+
+import (
+	"sort"
+
+	"env-audit/internal/i18n"
+)
+
+// CheckUnresolved reports values whose ${VAR} references could not be
+// expanded; the parser replaces them with "", which is rarely intended. The
+// names are left out of the message, which must not quote the value.
+func CheckUnresolved(unresolved map[string][]string, ignore []string) []Issue {
+	ignoreSet := toSet(ignore)
+
+	keys := make([]string, 0, len(unresolved))
+	for key := range unresolved {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var issues []Issue
+	for _, key := range keys {
+		if ignoreSet[key] || len(unresolved[key]) == 0 {
+			continue
+		}
+		message := i18n.T("references an undefined variable")
+		if n := len(unresolved[key]); n > 1 {
+			message = i18n.T("references %d undefined variables", n)
+		}
+		issues = append(issues, Issue{
+			Type:    IssueUnresolved,
+			Key:     key,
+			Message: message,
+		})
+	}
+	return issues
+}
@@ -0,0 +1,24 @@
+package audit
+
+import "testing"
+
+func TestCheckUnresolved(t *testing.T) {
+	unresolved := map[string][]string{
+		"DATABASE_URL": {"DB_HOST", "DB_PORT"},
+		"API_URL":      {"API_HOST"},
+		"IGNORED":      {"X"},
+	}
+	issues := CheckUnresolved(unresolved, []string{"IGNORED"})
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %v", issues)
+	}
+	if issues[0].Key != "API_URL" || issues[0].Message != "references an undefined variable" {
+		t.Errorf("unexpected issue: %+v", issues[0])
+	}
+	if issues[1].Message != "references 2 undefined variables" {
+		t.Errorf("unexpected message: %s", issues[1].Message)
+	}
+	if !IssueUnresolved.IsWarning() {
+		t.Error("expected unresolved references to be warnings")
+	}
+}
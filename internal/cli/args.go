@@ -1,30 +1,146 @@
 package cli
 
-import "fmt"
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"env-audit/internal/audit"
+	"env-audit/internal/config"
+	"env-audit/internal/netutil"
+	"env-audit/internal/redact"
+)
 
 // Config holds parsed CLI arguments
 type Config struct {
-	FilePath     string   // --file path to .env file
-	Required     []string // --required comma-separated required vars
-	ExampleFile  string   // --example path to .env.example file
-	DiffFile     string   // --diff path to second file for comparison
-	Ignore       []string // --ignore comma-separated keys to ignore
-	DumpMode     bool     // --dump output parsed config
-	JSONOutput   bool     // --json output results as JSON
-	GitHubOutput bool     // --github output results in GitHub Actions format
-	Quiet        bool     // --quiet/-q suppress stdout output
-	Strict       bool     // --strict treat warnings as errors
-	CheckLeaks   bool     // --check-leaks analyze values for secret patterns
-	NoColor      bool     // --no-color disable colored output
-	Watch        bool     // --watch watch file for changes
-	Init         bool     // --init generate .env.example file
-	Force        bool     // --force overwrite existing files
-	Help         bool     // --help show usage
-	Version      bool     // --version/-v show version
+	FilePath          string   // --file path to .env file
+	Files             []string // --file may be repeated to scan several files
+	FilesFrom         string   // --files-from read newline-separated file paths from a file, "-" for stdin
+	Recursive         bool     // --recursive/-R discover env files under directories
+	NoIgnore          bool     // --no-ignore walk gitignored, vendor and node_modules directories
+	ExcludePaths      []string // --exclude-path gitignore-style patterns of paths --recursive skips
+	AllProjects       bool     // --all-projects audit every project of the config file concurrently
+	ConfigFile        string   // --config path of the config file, instead of searching up from the working directory
+	Merge             bool     // --merge layer multiple --file values, later files overriding earlier
+	Flow              bool     // --flow[=env] merge the dotenv-flow cascade of --file (default .env)
+	FlowEnv           string   // environment of the --flow cascade, defaults to $NODE_ENV or development
+	NoExpand          bool     // --no-expand keep ${VAR} and $VAR references in values literally
+	ExpandEnv         bool     // --expand-env resolve references to keys the file lacks from the OS environment
+	DotenvKey         string   // --dotenv-key key URI(s) for .env.vault files, defaults to $DOTENV_KEY
+	Required          []string // --required comma-separated required vars
+	ExampleFile       string   // --example path to .env.example file
+	NoExampleLeaks    bool     // --no-example-leaks skip leak detection on the example file
+	RequireAllExample bool     // --require-all-example (or --required-from-example) treat every example key as required
+	CheckOrder        bool     // --check-order compare key order and sections with the example
+	FixOrder          bool     // --fix-order rewrite files to follow the example's key order
+	DiffFile          string   // --diff path to second file for comparison
+	SideBySide        bool     // --side-by-side show --diff as two aligned columns
+	Ignore            []string // --ignore comma-separated keys to ignore
+	AllowEmpty        []string // --allow-empty comma-separated optional keys that may be empty
+	DumpMode          bool     // --dump output parsed config
+	Shell             bool     // --shell dump as sourceable "export KEY=VALUE" lines
+	JSONOutput        bool     // --json output results as JSON
+	NDJSONOutput      bool     // --ndjson stream one JSON object per issue and per audit run
+	SARIFOutput       bool     // --sarif output results as a SARIF 2.1.0 log
+	FormatTemplate    string   // --format-template path of a Go text/template rendering the result
+	Output            string   // --output also write the report to this file
+	OutputFormat      string   // --output-format format of the --output file, inferred from its extension by default
+	GitHubOutput      bool     // --github output results in GitHub Actions format
+	CompactOutput     bool     // --format compact one file:line:col: message line per issue
+	GitLabOutput      bool     // --gitlab output GitLab CI log sections and a Code Quality report
+	GitLabReport      string   // --gitlab-report path of the Code Quality report
+	BuildkiteOutput   bool     // --buildkite output Markdown and annotate the Buildkite build
+	CircleCIOutput    bool     // --circleci write JUnit test results for CircleCI
+	CircleCIReport    string   // --circleci-report path of the JUnit report
+	AzureOutput       bool     // --azure output Azure Pipelines ##vso logging commands
+	TeamCityOutput    bool     // --teamcity output TeamCity inspection service messages
+	Summary           string   // --summary=stderr print a one-line summary to stderr
+	ExplainExit       bool     // --explain-exit print to stderr why the exit code is what it is
+	Reproducible      bool     // --reproducible byte-identical reports for identical content
+	AssertReadOnly    bool     // --assert-read-only refuse any run that would write to disk
+	Offline           bool     // --offline refuse any run that would access the network
+	MaxDisplay        int      // --max-display cap on issues listed in text and GitHub output, 0 for all
+	Quiet             bool     // --quiet/-q suppress the report
+	QuietLevel        int      // 1 for -q, 2 for -qq which also silences notices and banners
+	Strict            bool     // --strict treat warnings as errors, as --fail-on warning
+	FailOn            string   // --fail-on lowest severity that fails the run: error (default), warning or info
+	CheckLeaks        bool     // --check-leaks analyze values for secret patterns
+	CheckSources      bool     // --check-sources compare values with their "# source:" stores
+	Quarantine        bool     // --quarantine empty leaked values and keep them encrypted in a quarantine file
+	QuarantineTo      []string // --quarantine-recipient age recipients able to decrypt quarantine files
+	NoFormatCheck     bool     // --no-format-check skip provider credential format checks
+	Platform          string   // --platform deployment platform whose env constraints apply, e.g. lambda
+	Only              []string // --only issue types to report, all others are dropped
+	Exclude           []string // --exclude issue types to drop from the report
+	Patterns          string   // --patterns path or URL of a leak pattern pack
+	NoColor           bool     // --no-color disable colored output
+	NoCIDetect        bool     // --no-ci-detect ignore the CI environment when picking defaults
+	LogLevel          string   // --log-level debug, info or warn diagnostics on stderr
+	LogFormat         string   // --log-format text or json diagnostics
+	Lang              string   // --lang language of report messages, defaults to LC_ALL/LC_MESSAGES/LANG
+	Watch             bool     // --watch watch file for changes
+	Exec              string   // --exec command watch mode runs after each audit, e.g. to reload a dev server
+	ExecIfClean       bool     // --exec-if-clean run --exec only when the audit finds no risks
+	Verbose           bool     // --verbose show variable descriptions in the report
+	Init              bool     // --init generate .env.example file
+	Docs              bool     // --docs print a Markdown table documenting each variable
+	DocsFile          string   // --docs-file Markdown docs whose variables must match the example, default ENVIRONMENT.md
+	GroupBy           string   // --group-by type (default), prefix or file layout of text reports and --docs
+	Sort              string   // --sort file (default) or key order of --dump entries and reported issues
+	Force             bool     // --force overwrite existing files
+	Backup            string   // --backup[=suffix] save originals as path+suffix before rewriting
+	KeepGoing         bool     // --keep-going continue scanning after a file fails to parse
+	WarnParse         bool     // --warn-parse report lines the parser can't read as warnings
+	FailFast          bool     // --fail-fast abort the run on the first file that fails to parse, or stop scanning after the first error-severity issue, skipping the remaining files and the example leak and docs drift checks
+	Help              bool     // --help show usage
+	Version           bool     // --version/-v show version
+
+	// Redaction policy, set from the config file
+	RedactMode        string // "full" or "partial"
+	RedactPlaceholder string // replacement text for masked values
+	RedactReveal      int    // prefix characters kept in partial mode
+
+	// Sensitive key detection, set from the config file
+	SensitivePatterns []string // extra key name fragments that mark a key sensitive
+	SensitiveExclude  []string // keys never treated as sensitive, e.g. AUTHOR
+
+	Descriptions map[string]string         // per-variable documentation from the config file
+	Defaults     map[string]audit.Default  // fallback values for absent variables
+	RotateAfter  string                    // credential rotation window from the config file
+	LastRotated  map[string]string         // last rotation date of each key from the config file
+	Rules        map[string]*regexp.Regexp // --rule KEY=REGEX and config rules: patterns values must match
+	PublicValues []audit.PublicValue       // known-public values exempt from leak and sensitive findings
+	AllowHashes  []string                  // SHA-256 digests of known-safe values, from the config and allow files
+	Limits       audit.Limits              // size limits on each env file from the config file
+	Severity     audit.SeverityPolicy      // per-type severity overrides from the config file
+	HTTP         netutil.Options           // proxy, CA bundle, retries and rate limit of remote integrations
+	Projects     []config.Project          // monorepo projects from the config file, audited by --all-projects
+
+	cliArgs    *Config      // settings from CLI flags only, before any config file
+	ci         *ciProvider  // CI service detected from the environment, nil outside CI
+	ciFormat   string       // console format picked for the detected CI service, empty for the flags' choice
+	formatSet  bool         // --format was given, even if only as text
+	configPath string       // absolute path of the config file merged into this Config
+	command    string       // subcommand the arguments followed, empty for flat flags
+	log        *slog.Logger // diagnostics from --log-level, nil when not yet set up
+
+	formatTemplate *template.Template // parsed --format-template, nil when unset
+	ndjson         *ndjsonStream      // streams --ndjson events to stdout, nil when not streaming
 }
 
 // ParseArgs parses command line arguments into Config
 func ParseArgs(args []string) (*Config, error) {
+	return parseArgs(args, nil)
+}
+
+// parseArgs parses flags into Config. Arguments that are not flags are a
+// subcommand's operands and go to positional; without it they are an error.
+func parseArgs(args []string, positional func(cfg *Config, arg string) error) (*Config, error) {
 	cfg := &Config{}
 
 	for i := 0; i < len(args); i++ {
@@ -34,24 +150,104 @@ func ParseArgs(args []string) (*Config, error) {
 			cfg.Help = true
 		case "--dump", "-d":
 			cfg.DumpMode = true
+		case "--shell":
+			cfg.Shell = true
 		case "--json":
 			cfg.JSONOutput = true
+		case "--ndjson":
+			cfg.NDJSONOutput = true
+		case "--sarif":
+			cfg.SARIFOutput = true
 		case "--github":
 			cfg.GitHubOutput = true
+		case "--gitlab":
+			cfg.GitLabOutput = true
+		case "--buildkite":
+			cfg.BuildkiteOutput = true
+		case "--circleci":
+			cfg.CircleCIOutput = true
+		case "--azure":
+			cfg.AzureOutput = true
+		case "--teamcity":
+			cfg.TeamCityOutput = true
 		case "--quiet", "-q":
 			cfg.Quiet = true
+			if cfg.QuietLevel < 2 {
+				cfg.QuietLevel++
+			}
+		case "-qq":
+			cfg.Quiet = true
+			cfg.QuietLevel = 2
 		case "--strict":
 			cfg.Strict = true
 		case "--check-leaks":
 			cfg.CheckLeaks = true
+		case "--quarantine":
+			cfg.Quarantine = true
+			cfg.CheckLeaks = true
+		case "--check-sources":
+			cfg.CheckSources = true
+		case "--warn-parse":
+			cfg.WarnParse = true
+		case "--check-order":
+			cfg.CheckOrder = true
+		case "--fix-order":
+			cfg.FixOrder = true
+		case "--require-all-example", "--required-from-example":
+			cfg.RequireAllExample = true
+		case "--no-format-check":
+			cfg.NoFormatCheck = true
+		case "--no-example-leaks":
+			cfg.NoExampleLeaks = true
 		case "--init":
 			cfg.Init = true
+		case "--docs":
+			cfg.Docs = true
 		case "--force":
 			cfg.Force = true
+		case "--backup":
+			cfg.Backup = ".bak"
+		case "--side-by-side", "-y":
+			cfg.SideBySide = true
+		case "--merge":
+			cfg.Merge = true
+		case "--flow":
+			cfg.Flow = true
+		case "--no-expand":
+			cfg.NoExpand = true
+		case "--expand-env":
+			cfg.ExpandEnv = true
+		case "--recursive", "-R":
+			cfg.Recursive = true
+			// An optional directory to walk, like --file
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				i++
+				cfg.addFile(args[i])
+			}
+		case "--no-ignore":
+			cfg.NoIgnore = true
+		case "--all-projects":
+			cfg.AllProjects = true
+		case "--keep-going":
+			cfg.KeepGoing = true
+		case "--fail-fast":
+			cfg.FailFast = true
 		case "--no-color":
 			cfg.NoColor = true
+		case "--no-ci-detect":
+			cfg.NoCIDetect = true
 		case "--watch", "-w":
 			cfg.Watch = true
+		case "--verbose":
+			cfg.Verbose = true
+		case "--explain-exit":
+			cfg.ExplainExit = true
+		case "--reproducible":
+			cfg.Reproducible = true
+		case "--assert-read-only":
+			cfg.AssertReadOnly = true
+		case "--offline":
+			cfg.Offline = true
 		case "--version", "-V":
 			cfg.Version = true
 		case "--file", "-f":
@@ -59,19 +255,113 @@ func ParseArgs(args []string) (*Config, error) {
 				return nil, fmt.Errorf("missing value for %s", arg)
 			}
 			i++
-			cfg.FilePath = args[i]
+			cfg.addFile(args[i])
+		case "--quarantine-recipient":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			cfg.QuarantineTo = append(cfg.QuarantineTo, parseCommaSeparated(args[i])...)
+		case "--exclude-path":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			cfg.ExcludePaths = append(cfg.ExcludePaths, parseCommaSeparated(args[i])...)
 		case "--required", "-r":
 			if i+1 >= len(args) {
 				return nil, fmt.Errorf("missing value for %s", arg)
 			}
 			i++
 			cfg.Required = parseCommaSeparated(args[i])
+		case "--files-from":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			cfg.FilesFrom = args[i]
+		case "--format-template":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			cfg.FormatTemplate = args[i]
+			cfg.formatSet = true
+		case "--output", "-o", "--output-format":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			if arg == "--output-format" {
+				if !isReportFormat(args[i]) {
+					return nil, fmt.Errorf("unknown output format: %s (expected %s)", args[i], strings.Join(reportFormats, ", "))
+				}
+				cfg.OutputFormat = args[i]
+			} else {
+				cfg.Output = args[i]
+			}
+		case "--rule":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			if err := cfg.addRule(args[i]); err != nil {
+				return nil, err
+			}
+		case "--max-display":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid --max-display: %s (expected a non-negative number)", args[i])
+			}
+			cfg.MaxDisplay = n
+		case "--patterns":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			cfg.Patterns = args[i]
+		case "--config":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			cfg.ConfigFile = args[i]
+		case "--dotenv-key":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			cfg.DotenvKey = args[i]
 		case "--example", "-e":
 			if i+1 >= len(args) {
 				return nil, fmt.Errorf("missing value for %s", arg)
 			}
 			i++
 			cfg.ExampleFile = args[i]
+		case "--gitlab-report":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			cfg.GitLabReport = args[i]
+		case "--circleci-report":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			cfg.CircleCIReport = args[i]
+		case "--format":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			if err := cfg.setFormat(args[i]); err != nil {
+				return nil, err
+			}
 		case "--diff":
 			if i+1 >= len(args) {
 				return nil, fmt.Errorf("missing value for %s", arg)
@@ -84,14 +374,358 @@ func ParseArgs(args []string) (*Config, error) {
 			}
 			i++
 			cfg.Ignore = parseCommaSeparated(args[i])
+		case "--allow-empty":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			cfg.AllowEmpty = parseCommaSeparated(args[i])
+		case "--group-by":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			if args[i] != groupByType && args[i] != groupByPrefix && args[i] != groupByFile {
+				return nil, fmt.Errorf("--group-by must be type, prefix or file, got %q", args[i])
+			}
+			cfg.GroupBy = args[i]
+		case "--sort":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			if args[i] != sortFile && args[i] != sortKey {
+				return nil, fmt.Errorf("--sort must be file or key, got %q", args[i])
+			}
+			cfg.Sort = args[i]
+		case "--docs-file":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			cfg.DocsFile = args[i]
+		case "--platform":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			if _, ok := audit.PlatformByName(args[i]); !ok {
+				return nil, fmt.Errorf("--platform must be one of %s, got %q", strings.Join(audit.PlatformNames(), ", "), args[i])
+			}
+			cfg.Platform = args[i]
+		case "--only", "--exclude":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			types := parseCommaSeparated(args[i])
+			for _, name := range types {
+				if _, ok := audit.ParseIssueType(name); !ok {
+					return nil, fmt.Errorf("%s: unknown issue type %q", arg, name)
+				}
+			}
+			if arg == "--only" {
+				cfg.Only = types
+			} else {
+				cfg.Exclude = types
+			}
+		case "--fail-on":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			if !audit.IsSeverity(args[i]) {
+				return nil, fmt.Errorf("--fail-on must be error, warning or info, got %q", args[i])
+			}
+			cfg.FailOn = args[i]
+		case "--lang":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			cfg.Lang = args[i]
+		case "--log-level", "--log-format":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			if arg == "--log-level" {
+				cfg.LogLevel = args[i]
+			} else {
+				cfg.LogFormat = args[i]
+			}
+		case "--exec":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			cfg.Exec = args[i]
+		case "--exec-if-clean":
+			cfg.ExecIfClean = true
+		case "--summary":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			if err := cfg.setSummary(args[i]); err != nil {
+				return nil, err
+			}
 		default:
+			if suffix, ok := strings.CutPrefix(arg, "--backup="); ok {
+				if suffix == "" {
+					return nil, fmt.Errorf("empty suffix for --backup")
+				}
+				cfg.Backup = suffix
+				continue
+			}
+			if env, ok := strings.CutPrefix(arg, "--flow="); ok {
+				if env == "" {
+					return nil, fmt.Errorf("--flow= requires an environment name")
+				}
+				cfg.Flow = true
+				cfg.FlowEnv = env
+				continue
+			}
+			if root, ok := strings.CutPrefix(arg, "--recursive="); ok {
+				cfg.Recursive = true
+				cfg.addFile(root)
+				continue
+			}
+			if value, ok := strings.CutPrefix(arg, "--summary="); ok {
+				if err := cfg.setSummary(value); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if positional != nil && !strings.HasPrefix(arg, "-") {
+				if err := positional(cfg, arg); err != nil {
+					return nil, err
+				}
+				continue
+			}
 			return nil, fmt.Errorf("unknown argument: %s", arg)
 		}
 	}
 
+	if cfg.SideBySide && cfg.DiffFile == "" {
+		return nil, fmt.Errorf("--side-by-side requires --diff")
+	}
+
+	if cfg.Exec != "" && !cfg.Watch {
+		return nil, fmt.Errorf("--exec requires --watch")
+	}
+	if cfg.ExecIfClean && cfg.Exec == "" {
+		return nil, fmt.Errorf("--exec-if-clean requires --exec")
+	}
+
+	if cfg.Shell && !cfg.DumpMode {
+		return nil, fmt.Errorf("--shell requires --dump")
+	}
+
+	if cfg.OutputFormat != "" && cfg.Output == "" {
+		return nil, fmt.Errorf("--output-format requires --output")
+	}
+
+	if cfg.NoExpand && cfg.ExpandEnv {
+		return nil, fmt.Errorf("--no-expand and --expand-env cannot be used together")
+	}
+
+	if cfg.KeepGoing && cfg.FailFast {
+		return nil, fmt.Errorf("--keep-going and --fail-fast cannot be used together")
+	}
+
+	if cfg.Strict && cfg.FailOn != "" {
+		return nil, fmt.Errorf("--strict and --fail-on cannot be used together (--strict is --fail-on warning)")
+	}
+
+	if cfg.Flow && (len(cfg.Files) > 1 || cfg.FilesFrom != "" || cfg.Recursive) {
+		return nil, fmt.Errorf("--flow takes a single base --file and cannot be used with --files-from or --recursive")
+	}
+
+	if len(cfg.QuarantineTo) > 0 && !cfg.Quarantine {
+		return nil, fmt.Errorf("--quarantine-recipient requires --quarantine")
+	}
+	for _, recipient := range cfg.QuarantineTo {
+		if !config.IsAgeRecipient(recipient) {
+			return nil, fmt.Errorf("--quarantine-recipient: %q is not an age recipient (age1... or an SSH public key)", recipient)
+		}
+	}
+
+	if cfg.AllProjects {
+		if len(cfg.Files) > 0 || cfg.FilesFrom != "" || cfg.Recursive || cfg.Merge || cfg.Flow {
+			return nil, fmt.Errorf("--all-projects takes its files from the config file and cannot be used with --file, --files-from, --recursive, --merge or --flow")
+		}
+		if cfg.Watch || cfg.Init || cfg.Docs || cfg.FixOrder || cfg.DiffFile != "" || cfg.DumpMode {
+			return nil, fmt.Errorf("--all-projects only applies to audits")
+		}
+	}
+
 	return cfg, nil
 }
 
+// addFile adds a file or directory to scan, as --file does
+func (cfg *Config) addFile(path string) {
+	if cfg.FilePath == "" {
+		cfg.FilePath = path
+	}
+	cfg.Files = append(cfg.Files, path)
+}
+
+// setFormat selects an output format by name; --format json is the same as --json
+func (cfg *Config) setFormat(name string) error {
+	switch name {
+	case "text":
+	case "compact":
+		cfg.CompactOutput = true
+	case "json":
+		cfg.JSONOutput = true
+	case "ndjson":
+		cfg.NDJSONOutput = true
+	case "sarif":
+		cfg.SARIFOutput = true
+	case "github":
+		cfg.GitHubOutput = true
+	case "gitlab":
+		cfg.GitLabOutput = true
+	case "buildkite":
+		cfg.BuildkiteOutput = true
+	case "circleci":
+		cfg.CircleCIOutput = true
+	case "azure":
+		cfg.AzureOutput = true
+	case "teamcity":
+		cfg.TeamCityOutput = true
+	default:
+		return fmt.Errorf("unknown format: %s (expected text, compact, json, ndjson, sarif, github, gitlab, buildkite, circleci, azure or teamcity)", name)
+	}
+	cfg.formatSet = true
+	return nil
+}
+
+// severityPolicy is the config's severity overrides, with the issue types
+// that --only and --exclude leave out ignored
+func (cfg *Config) severityPolicy() audit.SeverityPolicy {
+	if cfg.Only == nil && cfg.Exclude == nil {
+		return cfg.Severity
+	}
+	policy := make(audit.SeverityPolicy, len(cfg.Severity))
+	for t, level := range cfg.Severity {
+		policy[t] = level
+	}
+	if cfg.Only != nil {
+		only := make(map[string]bool, len(cfg.Only))
+		for _, name := range cfg.Only {
+			only[name] = true
+		}
+		for _, t := range issueTypeOrder {
+			if !only[t.Name()] {
+				policy[t] = audit.SeverityIgnore
+			}
+		}
+	}
+	for _, name := range cfg.Exclude {
+		if t, ok := audit.ParseIssueType(name); ok {
+			policy[t] = audit.SeverityIgnore
+		}
+	}
+	return policy
+}
+
+// httpOptions are the settings of the shared HTTP client: those from the
+// config, or the defaults when the config has none. --offline disables it.
+func (cfg *Config) httpOptions() netutil.Options {
+	opts := cfg.HTTP
+	if opts == (netutil.Options{}) {
+		opts.Retries = netutil.DefaultRetries
+	}
+	opts.Offline = cfg.Offline
+	return opts
+}
+
+// failOn is the lowest severity that fails the run: --fail-on, warning
+// under --strict, or error
+func (cfg *Config) failOn() string {
+	switch {
+	case cfg.FailOn != "":
+		return cfg.FailOn
+	case cfg.Strict:
+		return audit.SeverityWarning
+	default:
+		return audit.SeverityError
+	}
+}
+
+// addRule adds a --rule KEY=REGEX value rule; the regex is everything after
+// the first "=", so it may contain "=" itself
+func (cfg *Config) addRule(spec string) error {
+	key, pattern, ok := strings.Cut(spec, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("invalid --rule %q (expected KEY=REGEX)", spec)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid --rule for %s: %w", key, err)
+	}
+	if cfg.Rules == nil {
+		cfg.Rules = make(map[string]*regexp.Regexp)
+	}
+	cfg.Rules[key] = re
+	return nil
+}
+
+// setSummary selects where the one-line summary goes; only stderr is
+// supported, so stdout stays clean for scripts
+func (cfg *Config) setSummary(dest string) error {
+	if dest != "stderr" {
+		return fmt.Errorf("unknown summary destination: %s (expected stderr)", dest)
+	}
+	cfg.Summary = dest
+	return nil
+}
+
+// Redactor builds the Redactor for the configured redaction policy
+func (cfg *Config) Redactor() (*redact.Redactor, error) {
+	mode, ok := redact.ParseMode(cfg.RedactMode)
+	if !ok {
+		return nil, fmt.Errorf("invalid redaction mode: %s (expected full or partial)", cfg.RedactMode)
+	}
+	r := redact.Default()
+	r.Mode = mode
+	if cfg.RedactPlaceholder != "" {
+		r.Placeholder = cfg.RedactPlaceholder
+	}
+	r.Reveal = cfg.RedactReveal
+	return r, nil
+}
+
+// readFileList adds the newline-separated paths in r to the input files.
+// Blank lines and surrounding whitespace are ignored.
+func (cfg *Config) readFileList(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		path := strings.TrimSpace(scanner.Text())
+		if path == "" {
+			continue
+		}
+		if cfg.FilePath == "" {
+			cfg.FilePath = path
+		}
+		cfg.Files = append(cfg.Files, path)
+	}
+	return scanner.Err()
+}
+
+// InputFiles returns every env file to scan, in the order given
+func (cfg *Config) InputFiles() []string {
+	if len(cfg.Files) > 0 {
+		return cfg.Files
+	}
+	if cfg.FilePath != "" {
+		return []string{cfg.FilePath}
+	}
+	return nil
+}
+
 func parseCommaSeparated(s string) []string {
 	if s == "" {
 		return nil
@@ -151,6 +785,9 @@ func (cfg *Config) MergeWithFileConfig(file *FileConfig) {
 	if len(cfg.Ignore) == 0 && len(file.Ignore) > 0 {
 		cfg.Ignore = file.Ignore
 	}
+	if len(cfg.AllowEmpty) == 0 && len(file.AllowEmpty) > 0 {
+		cfg.AllowEmpty = file.AllowEmpty
+	}
 
 	// Boolean flags: file config only sets if CLI didn't enable
 	if !cfg.Strict && file.Strict {
@@ -159,8 +796,15 @@ func (cfg *Config) MergeWithFileConfig(file *FileConfig) {
 	if !cfg.CheckLeaks && file.CheckLeaks {
 		cfg.CheckLeaks = true
 	}
+	if !cfg.CheckSources && file.CheckSources {
+		cfg.CheckSources = true
+	}
+	if !cfg.WarnParse && file.WarnParse {
+		cfg.WarnParse = true
+	}
 	if !cfg.Quiet && file.Quiet {
 		cfg.Quiet = true
+		cfg.QuietLevel = 1
 	}
 	if !cfg.JSONOutput && file.JSON {
 		cfg.JSONOutput = true
@@ -168,9 +812,101 @@ func (cfg *Config) MergeWithFileConfig(file *FileConfig) {
 	if !cfg.GitHubOutput && file.GitHub {
 		cfg.GitHubOutput = true
 	}
+	if !cfg.GitLabOutput && file.GitLab {
+		cfg.GitLabOutput = true
+	}
+	if !cfg.BuildkiteOutput && file.Buildkite {
+		cfg.BuildkiteOutput = true
+	}
+	if !cfg.CircleCIOutput && file.CircleCI {
+		cfg.CircleCIOutput = true
+	}
 	if !cfg.NoColor && file.NoColor {
 		cfg.NoColor = true
 	}
+	if !cfg.CheckOrder && file.CheckOrder {
+		cfg.CheckOrder = true
+	}
+	if !cfg.RequireAllExample && file.RequireAllExample {
+		cfg.RequireAllExample = true
+	}
+	if cfg.Patterns == "" {
+		cfg.Patterns = file.Patterns
+	}
+	if cfg.RedactMode == "" {
+		cfg.RedactMode = file.RedactMode
+	}
+	if cfg.RedactPlaceholder == "" {
+		cfg.RedactPlaceholder = file.RedactPlaceholder
+	}
+	if cfg.RedactReveal == 0 {
+		cfg.RedactReveal = file.RedactReveal
+	}
+	if cfg.Descriptions == nil {
+		cfg.Descriptions = file.Descriptions
+	}
+	if cfg.Defaults == nil {
+		cfg.Defaults = file.Defaults
+	}
+	if cfg.RotateAfter == "" {
+		cfg.RotateAfter = file.RotateAfter
+	}
+	if cfg.SensitivePatterns == nil {
+		cfg.SensitivePatterns = file.SensitivePatterns
+	}
+	if cfg.SensitiveExclude == nil {
+		cfg.SensitiveExclude = file.SensitiveExclude
+	}
+	if cfg.LastRotated == nil {
+		cfg.LastRotated = file.LastRotated
+	}
+	if cfg.PublicValues == nil {
+		cfg.PublicValues = file.PublicValues
+	}
+	if cfg.Platform == "" {
+		cfg.Platform = file.Platform
+	}
+	if cfg.DocsFile == "" {
+		cfg.DocsFile = file.DocsFile
+	}
+	// --strict on the CLI is a fail_on level of its own
+	if cfg.FailOn == "" && !cfg.Strict {
+		cfg.FailOn = file.FailOn
+	}
+	if cfg.Severity == nil {
+		cfg.Severity = file.Severity
+	}
+	if cfg.Only == nil {
+		cfg.Only = file.Only
+	}
+	cfg.HTTP = file.HTTP
+	cfg.Projects = file.Projects
+	if len(cfg.QuarantineTo) == 0 {
+		cfg.QuarantineTo = file.QuarantineTo
+	}
+	if cfg.Exclude == nil {
+		cfg.Exclude = file.Exclude
+	}
+	if cfg.Limits.IsZero() {
+		cfg.Limits = file.Limits
+	}
+	// The allow file is read before any config, so both lists apply
+	cfg.AllowHashes = append(cfg.AllowHashes[:len(cfg.AllowHashes):len(cfg.AllowHashes)], file.AllowHashes...)
+	// Rules combine per key, a --rule overriding the file's rule for its key
+	if len(file.Rules) > 0 {
+		rules := make(map[string]*regexp.Regexp, len(file.Rules)+len(cfg.Rules))
+		for key, pattern := range file.Rules {
+			rules[key] = pattern
+		}
+		for key, pattern := range cfg.Rules {
+			rules[key] = pattern
+		}
+		cfg.Rules = rules
+	}
+	// --keep-going on the CLI overrides fail_fast from the file
+	if !cfg.FailFast && !cfg.KeepGoing && file.FailFast {
+		cfg.FailFast = true
+	}
 }
 
 // FileConfig holds config loaded from file
@@ -179,10 +915,45 @@ type FileConfig struct {
 	Required   []string
 	Example    string
 	Ignore     []string
+	AllowEmpty []string
 	Strict     bool
 	CheckLeaks bool
 	Quiet      bool
 	JSON       bool
 	GitHub     bool
+	GitLab     bool
+	Buildkite  bool
+	CircleCI   bool
 	NoColor    bool
+	FailFast   bool
+	CheckOrder bool
+	WarnParse  bool
+
+	RequireAllExample bool
+	CheckSources      bool
+	Patterns          string
+	SensitivePatterns []string
+	SensitiveExclude  []string
+
+	RedactMode        string
+	RedactPlaceholder string
+	RedactReveal      int
+
+	Descriptions map[string]string
+	Defaults     map[string]audit.Default
+	RotateAfter  string
+	LastRotated  map[string]string
+	Rules        map[string]*regexp.Regexp
+	PublicValues []audit.PublicValue
+	AllowHashes  []string
+	Limits       audit.Limits
+	Platform     string
+	DocsFile     string
+	FailOn       string
+	Severity     audit.SeverityPolicy
+	Only         []string
+	Exclude      []string
+	HTTP         netutil.Options
+	Projects     []config.Project
+	QuarantineTo []string
 }
@@ -173,6 +173,19 @@ func TestParseArgs_InvalidArgs(t *testing.T) {
 		{name: "missing required value", args: []string{"--required"}},
 		{name: "missing required value short", args: []string{"-r"}},
 		{name: "missing diff value", args: []string{"--diff"}},
+		{name: "keep-going with fail-fast", args: []string{"--keep-going", "--fail-fast"}},
+		{name: "negative max-display", args: []string{"--max-display", "-1"}},
+		{name: "rule without regex", args: []string{"--rule", "PORT"}},
+		{name: "no-expand with expand-env", args: []string{"--no-expand", "--expand-env"}},
+		{name: "shell without dump", args: []string{"--shell"}},
+		{name: "unknown sort order", args: []string{"--sort", "size"}},
+		{name: "unknown platform", args: []string{"--platform", "fly"}},
+		{name: "unknown fail-on severity", args: []string{"--fail-on", "critical"}},
+		{name: "strict with fail-on", args: []string{"--strict", "--fail-on", "info"}},
+		{name: "unknown only type", args: []string{"--only", "empty,leaks"}},
+		{name: "missing exclude value", args: []string{"--exclude"}},
+		{name: "rule with invalid regex", args: []string{"--rule", "PORT=[0-9"}},
+		{name: "non-numeric max-display", args: []string{"--max-display", "ten"}},
 	}
 
 	for _, tt := range tests {
@@ -205,3 +218,157 @@ func TestTrimSpace_Tabs(t *testing.T) {
 		t.Errorf("expected 'value', got %q", result)
 	}
 }
+
+func TestParseArgs_RepeatedFile(t *testing.T) {
+	cfg, err := ParseArgs([]string{"-f", "a.env", "--file", "b.env", "--fail-fast"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.FilePath != "a.env" {
+		t.Errorf("FilePath: got %q, want a.env", cfg.FilePath)
+	}
+	files := cfg.InputFiles()
+	if len(files) != 2 || files[0] != "a.env" || files[1] != "b.env" {
+		t.Errorf("InputFiles: got %v, want [a.env b.env]", files)
+	}
+	if !cfg.FailFast {
+		t.Error("expected FailFast=true")
+	}
+}
+
+func TestParseArgs_QuietLevels(t *testing.T) {
+	tests := []struct {
+		args  []string
+		level int
+	}{
+		{[]string{"-q"}, 1},
+		{[]string{"--quiet"}, 1},
+		{[]string{"-qq"}, 2},
+		{[]string{"-q", "--quiet"}, 2},
+		{[]string{"-q", "-q", "-q"}, 2},
+	}
+	for _, tt := range tests {
+		cfg, err := ParseArgs(tt.args)
+		if err != nil {
+			t.Fatalf("unexpected error for %v: %v", tt.args, err)
+		}
+		if !cfg.Quiet || cfg.QuietLevel != tt.level {
+			t.Errorf("%v: got Quiet=%v QuietLevel=%d, want level %d", tt.args, cfg.Quiet, cfg.QuietLevel, tt.level)
+		}
+	}
+}
+
+func TestParseArgs_Format(t *testing.T) {
+	cfg, err := ParseArgs([]string{"--format", "compact"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.CompactOutput {
+		t.Error("expected CompactOutput=true")
+	}
+
+	cfg, err = ParseArgs([]string{"--format", "json"})
+	if err != nil || !cfg.JSONOutput {
+		t.Errorf("expected --format json to select JSON, got %v", err)
+	}
+
+	cfg, err = ParseArgs([]string{"--format", "ndjson"})
+	if err != nil || !cfg.NDJSONOutput {
+		t.Errorf("expected --format ndjson to select NDJSON, got %v", err)
+	}
+
+	if _, err := ParseArgs([]string{"--format", "xml"}); err == nil {
+		t.Error("expected error for unknown format")
+	}
+	if _, err := ParseArgs([]string{"--format"}); err == nil {
+		t.Error("expected error for missing format")
+	}
+}
+
+func TestParseArgs_Summary(t *testing.T) {
+	for _, args := range [][]string{{"--summary=stderr"}, {"--summary", "stderr"}} {
+		cfg, err := ParseArgs(args)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", args, err)
+		}
+		if cfg.Summary != "stderr" {
+			t.Errorf("%v: expected Summary=stderr, got %q", args, cfg.Summary)
+		}
+	}
+	if _, err := ParseArgs([]string{"--summary=stdout"}); err == nil {
+		t.Error("expected error for unsupported summary destination")
+	}
+}
+
+func TestParseArgs_SARIF(t *testing.T) {
+	for _, args := range [][]string{{"--sarif"}, {"--format", "sarif"}} {
+		cfg, err := ParseArgs(args)
+		if err != nil || !cfg.SARIFOutput {
+			t.Errorf("%v: expected SARIFOutput=true, got %v", args, err)
+		}
+	}
+}
+
+func TestParseArgs_Backup(t *testing.T) {
+	cfg, err := ParseArgs([]string{"--backup"})
+	if err != nil || cfg.Backup != ".bak" {
+		t.Errorf("expected default suffix .bak, got %q (%v)", cfg.Backup, err)
+	}
+	cfg, err = ParseArgs([]string{"--backup=.orig"})
+	if err != nil || cfg.Backup != ".orig" {
+		t.Errorf("expected suffix .orig, got %q (%v)", cfg.Backup, err)
+	}
+	if _, err := ParseArgs([]string{"--backup="}); err == nil {
+		t.Error("expected error for an empty suffix")
+	}
+}
+
+func TestParseArgs_MaxDisplay(t *testing.T) {
+	cfg, err := ParseArgs([]string{"--max-display", "50"})
+	if err != nil || cfg.MaxDisplay != 50 {
+		t.Errorf("expected MaxDisplay=50, got %d (%v)", cfg.MaxDisplay, err)
+	}
+}
+
+func TestParseArgs_Rule(t *testing.T) {
+	cfg, err := ParseArgs([]string{"--rule", "DATABASE_URL=^postgres://", "--rule", "MODE=^a=b$"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Rules["DATABASE_URL"].String() != "^postgres://" || cfg.Rules["MODE"].String() != "^a=b$" {
+		t.Errorf("unexpected rules: %v", cfg.Rules)
+	}
+}
+
+func TestParseArgs_Config(t *testing.T) {
+	cfg, err := ParseArgs([]string{"--config", "ci/env-audit.yaml"})
+	if err != nil || cfg.ConfigFile != "ci/env-audit.yaml" {
+		t.Errorf("expected ConfigFile=ci/env-audit.yaml, got %q (%v)", cfg.ConfigFile, err)
+	}
+	if _, err := ParseArgs([]string{"--config"}); err == nil {
+		t.Error("expected error for a missing --config value")
+	}
+}
+
+func TestParseArgs_WarnParse(t *testing.T) {
+	cfg, err := ParseArgs([]string{"--warn-parse"})
+	if err != nil || !cfg.WarnParse {
+		t.Errorf("expected WarnParse, got %v (%v)", cfg.WarnParse, err)
+	}
+}
+
+func TestParseArgs_Exec(t *testing.T) {
+	cfg, err := ParseArgs([]string{"-f", ".env", "--watch", "--exec", "npm run dev", "--exec-if-clean"})
+	if err != nil || cfg.Exec != "npm run dev" || !cfg.ExecIfClean {
+		t.Errorf("expected Exec and ExecIfClean, got %q, %v (%v)", cfg.Exec, cfg.ExecIfClean, err)
+	}
+	for _, args := range [][]string{
+		{"--exec", "make"},
+		{"--watch", "--exec-if-clean"},
+		{"--watch", "--exec"},
+	} {
+		if _, err := ParseArgs(args); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
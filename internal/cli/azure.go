@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"env-audit/internal/audit"
+)
+
+// AzureFormatter outputs results as Azure Pipelines logging commands
+// (##vso[task.logissue]), which show up as errors and warnings on the run
+type AzureFormatter struct{}
+
+// Format implements Formatter interface for AzureFormatter. Azure knows only
+// errors and warnings, so informational issues are reported as warnings.
+func (f *AzureFormatter) Format(result *audit.Result) string {
+	if result == nil || (len(result.Issues) == 0 && result.Failed() == 0) {
+		return ""
+	}
+
+	var lines []string
+	for _, file := range result.Files {
+		if file.Error != nil {
+			lines = append(lines, azureLogIssue("error", file.Path, 0, "scan_error", "failed to scan: "+file.Error.Error()))
+		}
+	}
+	for _, issue := range result.Issues {
+		level := "error"
+		if issue.Level() != audit.SeverityError {
+			level = "warning"
+		}
+		lines = append(lines, azureLogIssue(level, issue.File, issue.Line, issueTypeToString(issue.Type), issue.Key+": "+issue.Message))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// azureLogIssue builds one task.logissue command; the location is omitted
+// for issues found in the process environment
+func azureLogIssue(level, file string, line int, code, message string) string {
+	props := []string{"type=" + level}
+	if file != "" {
+		props = append(props, "sourcepath="+azureEscapeProperty(file))
+		if line > 0 {
+			props = append(props, fmt.Sprintf("linenumber=%d", line), "columnnumber=1")
+		}
+	}
+	props = append(props, "code="+azureEscapeProperty(code))
+	return "##vso[task.logissue " + strings.Join(props, ";") + ";]" + azureEscapeData(message)
+}
+
+// azureEscapeData escapes a logging command message
+func azureEscapeData(s string) string {
+	return strings.NewReplacer("%", "%AZP25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// azureEscapeProperty escapes a logging command property value
+func azureEscapeProperty(s string) string {
+	return strings.NewReplacer("%", "%AZP25", "\r", "%0D", "\n", "%0A", "]", "%5D", ";", "%3B").Replace(s)
+}
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"env-audit/internal/audit"
+)
+
+func TestAzureFormatter_Format(t *testing.T) {
+	result := &audit.Result{
+		Issues: []audit.Issue{
+			{Type: audit.IssueLeak, Key: "TOKEN", Message: "GitHub token detected", File: ".env", Line: 3},
+			{Type: audit.IssueEmpty, Key: "PORT", Message: "variable has empty value", File: "a;b.env", Line: 1},
+			{Type: audit.IssueMissing, Key: "DB_URL", Message: "100% required\nvariable"},
+		},
+		Files: []audit.FileStatus{{Path: "bad.env", Error: errors.New("unreadable")}},
+	}
+	output := (&AzureFormatter{}).Format(result)
+
+	want := []string{
+		"##vso[task.logissue type=error;sourcepath=bad.env;code=scan_error;]failed to scan: unreadable",
+		"##vso[task.logissue type=error;sourcepath=.env;linenumber=3;columnnumber=1;code=leak;]TOKEN: GitHub token detected",
+		"##vso[task.logissue type=warning;sourcepath=a%3Bb.env;linenumber=1;columnnumber=1;code=empty;]PORT: variable has empty value",
+		"##vso[task.logissue type=error;code=missing;]DB_URL: 100%AZP25 required%0Avariable",
+	}
+	if got := strings.Split(strings.TrimSuffix(output, "\n"), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", output, strings.Join(want, "\n"))
+	}
+}
+
+func TestAzureFormatter_NoIssues(t *testing.T) {
+	if output := (&AzureFormatter{}).Format(&audit.Result{}); output != "" {
+		t.Errorf("expected no output, got %q", output)
+	}
+}
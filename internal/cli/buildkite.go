@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"env-audit/internal/audit"
+	"env-audit/internal/redact"
+)
+
+// BuildkiteFormatter renders the report as Markdown for Buildkite annotations
+type BuildkiteFormatter struct {
+	Redactor *redact.Redactor // masks sensitive values, defaults to redact.Default()
+	Metadata *reportMetadata  // optional run details appended as a collapsed block
+}
+
+// Format implements Formatter interface for BuildkiteFormatter
+func (f *BuildkiteFormatter) Format(result *audit.Result) string {
+	if result == nil || (len(result.Issues) == 0 && result.Failed() == 0) {
+		return "**env-audit**: no issues found\n" + f.metadata()
+	}
+	redactor := f.Redactor
+	if redactor == nil {
+		redactor = redact.Default()
+	}
+	opts := textOptions{Redactor: redactor}
+
+	groups := make(map[audit.IssueType][]audit.Issue)
+	for _, issue := range result.Issues {
+		groups[issue.Type] = append(groups[issue.Type], issue)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("### env-audit\n")
+	for _, t := range issueTypeOrder {
+		issues := groups[t]
+		if len(issues) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n**%s (%d)**\n\n", issueTypeName(t), len(issues)))
+		for _, issue := range issues {
+			// Keys and locations are code, not prose
+			line := formatIssueLine(issue, true, opts)
+			line = strings.Replace(line, "  - "+issue.Key, "- `"+issue.Key+"`", 1)
+			sb.WriteString(line)
+		}
+	}
+	if failed := result.Failed(); failed > 0 {
+		sb.WriteString("\n**Files failed to scan**\n\n")
+		for _, file := range result.Files {
+			if file.Error != nil {
+				sb.WriteString(fmt.Sprintf("- `%s`: %v\n", file.Path, file.Error))
+			}
+		}
+	}
+	sb.WriteString(formatSummaryLine(result))
+	sb.WriteString(f.metadata())
+	return sb.String()
+}
+
+// metadata renders the metadata block, if any
+func (f *BuildkiteFormatter) metadata() string {
+	if f.Metadata == nil {
+		return ""
+	}
+	return f.Metadata.markdown()
+}
+
+// buildkiteStyle picks the annotation style for a result
+func buildkiteStyle(result *audit.Result) string {
+	switch {
+	case result.Failed() > 0 || result.HasRisks:
+		return "error"
+	case len(result.Issues) > 0:
+		return "warning"
+	default:
+		return "success"
+	}
+}
+
+// buildkiteAnnotate attaches markdown to the running Buildkite build. It is a
+// no-op outside a Buildkite job or when buildkite-agent is not installed.
+// Replaced in tests.
+var buildkiteAnnotate = func(markdown, style string) error {
+	if os.Getenv("BUILDKITE_JOB_ID") == "" {
+		return nil
+	}
+	agent, err := exec.LookPath("buildkite-agent")
+	if err != nil {
+		return nil
+	}
+	cmd := exec.Command(agent, "annotate", "--style", style, "--context", "env-audit")
+	cmd.Stdin = strings.NewReader(markdown)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("buildkite-agent annotate: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
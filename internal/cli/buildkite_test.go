@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"env-audit/internal/audit"
+)
+
+func TestBuildkiteFormatter_Markdown(t *testing.T) {
+	result := &audit.Result{
+		Issues: []audit.Issue{
+			{Type: audit.IssueMissing, Key: "API_KEY", Message: "required variable is missing", File: ".env"},
+			{Type: audit.IssueLeak, Key: "TOKEN", Message: "GitHub token detected", File: ".env", Line: 3},
+		},
+		HasRisks: true,
+	}
+	output := (&BuildkiteFormatter{}).Format(result)
+
+	for _, want := range []string{
+		"### env-audit\n",
+		"**Missing Required (1)**\n\n- `API_KEY` (.env)\n",
+		"- `TOKEN` (.env:3): GitHub token detected\n",
+		"Summary: 2 issues found",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+	if style := buildkiteStyle(result); style != "error" {
+		t.Errorf("expected error style, got %s", style)
+	}
+}
+
+func TestRun_BuildkiteAnnotates(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+	os.WriteFile(envFile, []byte("EMPTY=\n"), 0644)
+
+	var annotated, style string
+	orig := buildkiteAnnotate
+	buildkiteAnnotate = func(markdown, s string) error {
+		annotated, style = markdown, s
+		return nil
+	}
+	t.Cleanup(func() { buildkiteAnnotate = orig })
+
+	var stdout, stderr bytes.Buffer
+	exitCode := Run([]string{"-f", envFile, "--buildkite"}, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Errorf("expected exit 0, got %d: %s", exitCode, stderr.String())
+	}
+	if style != "warning" || !strings.Contains(annotated, "**Empty Values (1)**") {
+		t.Errorf("expected warning annotation, got %s: %s", style, annotated)
+	}
+	if stdout.String() != annotated {
+		t.Errorf("expected the annotation Markdown on stdout, got: %s", stdout.String())
+	}
+}
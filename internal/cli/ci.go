@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"env-audit/internal/audit"
+	"env-audit/internal/fsutil"
+	"env-audit/internal/redact"
+)
+
+// ciProvider is a CI service recognised from its environment variables
+type ciProvider struct {
+	Name   string // display name
+	Format string // console format the service renders, empty for plain text
+}
+
+// ciProviders are checked in order; most set their variable to "true"
+// (Azure Pipelines: "True"), TeamCity to its version
+var ciProviders = []struct {
+	envVar   string
+	anyValue bool // any non-empty value identifies the service
+	provider ciProvider
+}{
+	{"GITHUB_ACTIONS", false, ciProvider{Name: "GitHub Actions", Format: "github"}},
+	{"GITLAB_CI", false, ciProvider{Name: "GitLab CI", Format: "gitlab"}},
+	{"CIRCLECI", false, ciProvider{Name: "CircleCI"}},
+	{"BUILDKITE", false, ciProvider{Name: "Buildkite", Format: "markdown"}},
+	{"TF_BUILD", false, ciProvider{Name: "Azure Pipelines", Format: "azure"}},
+	{"TEAMCITY_VERSION", true, ciProvider{Name: "TeamCity", Format: "teamcity"}},
+}
+
+// detectCI returns the CI service the process runs under, or nil outside CI.
+// Unknown services that follow the CI=true convention are still detected.
+func detectCI(getenv func(string) string) *ciProvider {
+	for _, p := range ciProviders {
+		value := getenv(p.envVar)
+		if strings.EqualFold(value, "true") || (p.anyValue && value != "") {
+			provider := p.provider
+			return &provider
+		}
+	}
+	if getenv("CI") == "true" || getenv("CI") == "1" {
+		return &ciProvider{Name: "CI"}
+	}
+	return nil
+}
+
+// applyCIDefaults picks defaults suited to a pipeline: no colour, the
+// service's annotation format on stdout unless another output format was
+// chosen, and no interactive behaviour. Detection alone never has side
+// effects: the GitLab Code Quality report, CircleCI test results and
+// Buildkite annotations are written only when --gitlab, --circleci or
+// --buildkite, or the config file, asks for them.
+func (cfg *Config) applyCIDefaults(ci *ciProvider) {
+	cfg.ci = ci
+	// FORCE_COLOR is how pipelines opt in to colored logs
+	if _, forced := os.LookupEnv("FORCE_COLOR"); !forced {
+		cfg.NoColor = true
+	}
+	if cfg.formatSet || cfg.JSONOutput || cfg.NDJSONOutput || cfg.SARIFOutput || cfg.GitHubOutput || cfg.GitLabOutput || cfg.BuildkiteOutput || cfg.CircleCIOutput || cfg.AzureOutput || cfg.TeamCityOutput {
+		return
+	}
+	switch ci.Format {
+	case "github":
+		cfg.GitHubOutput = true
+	case "azure":
+		cfg.AzureOutput = true
+	case "teamcity":
+		cfg.TeamCityOutput = true
+	default:
+		// GitLab's --gitlab and Buildkite's --buildkite also write a report or
+		// annotate the build, so only their console output is picked
+		cfg.ciFormat = ci.Format
+	}
+}
+
+// writeCIArtifacts writes the reports and annotations of the selected CI format
+func writeCIArtifacts(cfg *Config, result *audit.Result, redactor *redact.Redactor) error {
+	switch {
+	case cfg.GitLabOutput:
+		report, err := (&GitLabFormatter{Redactor: redactor}).CodeQuality(result)
+		if err != nil {
+			return err
+		}
+		return writeReport(cfg.GitLabReport, defaultCodeQualityReport, append(report, '\n'))
+	case cfg.CircleCIOutput:
+		report, err := JUnitReport(result)
+		if err != nil {
+			return err
+		}
+		return writeReport(cfg.CircleCIReport, defaultJUnitReport, append(report, '\n'))
+	case cfg.BuildkiteOutput:
+		markdown := (&BuildkiteFormatter{Redactor: redactor, Metadata: newReportMetadata(cfg)}).Format(result)
+		return buildkiteAnnotate(markdown, buildkiteStyle(result))
+	}
+	return nil
+}
+
+// writeReport writes a CI report to path, or to fallback when path is empty,
+// creating parent directories as needed
+func writeReport(path, fallback string, data []byte) error {
+	if path == "" {
+		path = fallback
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(path, data, 0644)
+}
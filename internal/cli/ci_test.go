@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestMain clears CI variables so Run behaves the same locally and in pipelines
+func TestMain(m *testing.M) {
+	for _, p := range ciProviders {
+		os.Unsetenv(p.envVar)
+	}
+	os.Unsetenv("CI")
+	// Color conventions from the developer's shell would skew color tests
+	os.Unsetenv("NO_COLOR")
+	os.Unsetenv("FORCE_COLOR")
+	os.Unsetenv("CLICOLOR")
+	os.Exit(m.Run())
+}
+
+func TestDetectCI(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"none", map[string]string{}, ""},
+		{"github", map[string]string{"GITHUB_ACTIONS": "true", "CI": "true"}, "GitHub Actions"},
+		{"gitlab", map[string]string{"GITLAB_CI": "true", "CI": "true"}, "GitLab CI"},
+		{"circleci", map[string]string{"CIRCLECI": "true"}, "CircleCI"},
+		{"buildkite", map[string]string{"BUILDKITE": "true"}, "Buildkite"},
+		{"azure", map[string]string{"TF_BUILD": "True"}, "Azure Pipelines"},
+		{"teamcity", map[string]string{"TEAMCITY_VERSION": "2024.03.1 (build 156606)"}, "TeamCity"},
+		{"generic", map[string]string{"CI": "1"}, "CI"},
+		{"disabled", map[string]string{"CI": "false"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ci := detectCI(func(key string) string { return tt.env[key] })
+			got := ""
+			if ci != nil {
+				got = ci.Name
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestApplyCIDefaults_KeepsExplicitFormat(t *testing.T) {
+	cfg := &Config{JSONOutput: true}
+	cfg.applyCIDefaults(&ciProvider{Name: "GitHub Actions", Format: "github"})
+	if cfg.GitHubOutput || !cfg.JSONOutput || !cfg.NoColor {
+		t.Errorf("expected JSON kept and colour disabled, got %+v", cfg)
+	}
+}
+
+func TestRun_GitHubActionsDefaults(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+	os.WriteFile(envFile, []byte("EMPTY=\n"), 0644)
+	t.Setenv("GITHUB_ACTIONS", "true")
+
+	var stdout, stderr bytes.Buffer
+	Run([]string{"-f", envFile}, &stdout, &stderr)
+	if !strings.Contains(stdout.String(), "::warning") {
+		t.Errorf("expected GitHub annotations in Actions, got: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	Run([]string{"-f", envFile, "--no-ci-detect"}, &stdout, &stderr)
+	if strings.Contains(stdout.String(), "::warning") {
+		t.Errorf("expected text output with --no-ci-detect, got: %s", stdout.String())
+	}
+}
+
+func TestRun_WatchRefusedInCI(t *testing.T) {
+	t.Setenv("CI", "true")
+
+	var stdout, stderr bytes.Buffer
+	exitCode := Run([]string{"-f", ".env", "--watch"}, &stdout, &stderr)
+	if exitCode != 2 || !strings.Contains(stderr.String(), "--watch is interactive") {
+		t.Errorf("expected --watch to be refused in CI, got %d: %s", exitCode, stderr.String())
+	}
+}
+
+func TestApplyCIDefaults_ForceColorKeepsColor(t *testing.T) {
+	t.Setenv("FORCE_COLOR", "1")
+	cfg := &Config{}
+	cfg.applyCIDefaults(&ciProvider{Name: "GitHub Actions", Format: "github"})
+	if cfg.NoColor {
+		t.Error("expected FORCE_COLOR to keep color on in CI")
+	}
+}
+
+func TestRun_CIDetectionHasNoSideEffects(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+	os.WriteFile(".env", []byte("EMPTY=\n"), 0644)
+
+	annotated := false
+	oldAnnotate := buildkiteAnnotate
+	buildkiteAnnotate = func(markdown, style string) error {
+		annotated = true
+		return nil
+	}
+	defer func() { buildkiteAnnotate = oldAnnotate }()
+
+	tests := []struct {
+		envVar string
+		want   string // on stdout
+	}{
+		{"GITLAB_CI", "section_start:"},
+		{"CIRCLECI", "env-audit scan results"},
+		{"BUILDKITE", "### env-audit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.envVar, func(t *testing.T) {
+			t.Setenv(tt.envVar, "true")
+			var stdout, stderr bytes.Buffer
+			if code := Run([]string{"-f", ".env"}, &stdout, &stderr); code != 0 {
+				t.Fatalf("expected exit 0, got %d: %s", code, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.want) {
+				t.Errorf("expected %q on stdout, got: %s", tt.want, stdout.String())
+			}
+			if entries, _ := os.ReadDir(tmpDir); len(entries) != 1 {
+				t.Errorf("expected no report written, found %d entries", len(entries))
+			}
+			if annotated {
+				t.Error("expected no Buildkite annotation without --buildkite")
+			}
+		})
+	}
+
+	// The flag asks for the report
+	t.Setenv("GITLAB_CI", "true")
+	var stdout, stderr bytes.Buffer
+	Run([]string{"-f", ".env", "--gitlab"}, &stdout, &stderr)
+	if _, err := os.Stat(defaultCodeQualityReport); err != nil {
+		t.Errorf("expected the Code Quality report with --gitlab, got %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"env-audit/internal/audit"
+)
+
+// defaultJUnitReport is where --circleci writes its test results; CircleCI's
+// store_test_results step takes the containing directory
+const defaultJUnitReport = "test-results/env-audit/results.xml"
+
+// junitTestSuite is the JUnit XML subset CircleCI renders in its Tests tab
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+}
+
+// JUnitReport renders result as JUnit XML, one test case per issue.
+// Informational issues are reported as skipped rather than failed.
+func JUnitReport(result *audit.Result) ([]byte, error) {
+	suite := junitTestSuite{Name: "env-audit"}
+	if result != nil {
+		for _, file := range result.Files {
+			if file.Error != nil {
+				suite.TestCases = append(suite.TestCases, junitTestCase{
+					ClassName: "env-audit.scan",
+					Name:      file.Path,
+					File:      file.Path,
+					Failure:   &junitMessage{Message: "failed to scan: " + file.Error.Error(), Type: "scan_error"},
+				})
+			}
+		}
+		for _, issue := range result.Issues {
+			tc := junitTestCase{
+				ClassName: "env-audit." + issueTypeToString(issue.Type),
+				Name:      issue.Key,
+				File:      issue.File,
+			}
+			if issue.File != "" {
+				tc.Name = fmt.Sprintf("%s (%s)", issue.Key, audit.Location{File: issue.File, Line: issue.Line})
+			}
+			msg := &junitMessage{Message: issue.Message, Type: issueTypeToString(issue.Type)}
+			if issue.Level() == audit.SeverityInfo {
+				tc.Skipped = msg
+			} else {
+				tc.Failure = msg
+			}
+			suite.TestCases = append(suite.TestCases, tc)
+		}
+	}
+	for _, tc := range suite.TestCases {
+		suite.Tests++
+		if tc.Failure != nil {
+			suite.Failures++
+		} else if tc.Skipped != nil {
+			suite.Skipped++
+		}
+	}
+
+	data, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
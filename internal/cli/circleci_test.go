@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"env-audit/internal/audit"
+)
+
+func TestJUnitReport(t *testing.T) {
+	result := &audit.Result{
+		Issues: []audit.Issue{
+			{Type: audit.IssueMissing, Key: "API_KEY", Message: "required variable is missing"},
+			{Type: audit.IssueDefault, Key: "LOG_LEVEL", Message: "using default value \"info\"", File: ".env", Line: 2},
+		},
+		Files: []audit.FileStatus{{Path: "broken.env", Error: os.ErrNotExist}},
+	}
+	data, err := JUnitReport(result)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var suite junitTestSuite
+	if err := xml.Unmarshal(data, &suite); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, data)
+	}
+	if suite.Tests != 3 || suite.Failures != 2 || suite.Skipped != 1 {
+		t.Errorf("unexpected counts: tests=%d failures=%d skipped=%d", suite.Tests, suite.Failures, suite.Skipped)
+	}
+	if tc := suite.TestCases[2]; tc.Name != "LOG_LEVEL (.env:2)" || tc.Skipped == nil || tc.ClassName != "env-audit.default" {
+		t.Errorf("expected informational issue to be skipped, got %+v", tc)
+	}
+}
+
+func TestRun_CircleCIWritesJUnitReport(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+	reportFile := filepath.Join(tmpDir, "results", "env-audit.xml")
+	os.WriteFile(envFile, []byte("PORT=3000\n"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	exitCode := Run([]string{"-f", envFile, "-r", "API_KEY", "--circleci", "--circleci-report", reportFile}, &stdout, &stderr)
+
+	if exitCode != 1 {
+		t.Errorf("expected exit 1, got %d: %s", exitCode, stderr.String())
+	}
+	data, err := os.ReadFile(reportFile)
+	if err != nil {
+		t.Fatalf("expected report to be written: %v", err)
+	}
+	if !strings.Contains(string(data), `<failure message="required variable is missing" type="missing">`) {
+		t.Errorf("unexpected report: %s", data)
+	}
+	if !strings.Contains(stdout.String(), "Missing Required (1)") {
+		t.Errorf("expected the text report on stdout, got: %s", stdout.String())
+	}
+}
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// command is a subcommand that stands for a combination of the flat flags:
+// "env-audit diff a b" is "env-audit -f a --diff b". Flags of other modes
+// are rejected instead of silently winning or losing against each other.
+type command struct {
+	synopsis string                              // usage line
+	about    string                              // one-line description for help
+	flags    []string                            // flat flags the command implies
+	mode     string                              // mode flag the command replaces, empty for the audit
+	operand  func(cfg *Config, arg string) error // takes a non-flag argument
+	validate func(cfg *Config) error             // checks the operands once all are parsed, may be nil
+}
+
+// commands are the subcommands parsed into a Config; fmt, fix, merge,
+// lint, org, remote-diff, version and config have their own arguments
+var commands = map[string]*command{
+	"scan": {
+		synopsis: "env-audit scan [files...] [options]",
+		about:    "Audit env files for missing, empty and leaked values (the default)",
+		operand:  addFileOperand,
+	},
+	"diff": {
+		synopsis: "env-audit diff <file> <other> [options]",
+		about:    "Compare two env files",
+		mode:     "--diff",
+		operand:  diffOperand,
+		validate: func(cfg *Config) error {
+			if cfg.DiffFile == "" {
+				return fmt.Errorf("diff requires two files")
+			}
+			return nil
+		},
+	},
+	"init": {
+		synopsis: "env-audit init [file] [options]",
+		about:    "Generate .env.example from an env file or the environment",
+		flags:    []string{"--init"},
+		mode:     "--init",
+		operand:  addFileOperand,
+	},
+	"watch": {
+		synopsis: "env-audit watch [file] [options]",
+		about:    "Audit an env file again whenever it changes",
+		flags:    []string{"--watch"},
+		mode:     "--watch",
+		operand:  addFileOperand,
+	},
+	"docs": {
+		synopsis: "env-audit docs [file] [options]",
+		about:    "Print a Markdown table documenting each variable",
+		flags:    []string{"--docs"},
+		mode:     "--docs",
+		operand:  addFileOperand,
+	},
+	"leaks": {
+		synopsis: "env-audit leaks [files...] [options]",
+		about:    "Report only secrets in env values and in the example file",
+		flags:    []string{"--check-leaks", "--only", "leak,example_secret"},
+		operand:  addFileOperand,
+	},
+}
+
+// commandOrder is the order subcommands are listed in help
+var commandOrder = []string{"scan", "diff", "init", "watch", "docs", "leaks"}
+
+// modeFlags are the flags that select what a run does, each with the flags
+// only that mode uses. A subcommand accepts those of its own mode only.
+var modeFlags = []struct{ selects, uses []string }{
+	{[]string{"--diff"}, []string{"--side-by-side", "-y"}},
+	{[]string{"--dump", "-d"}, []string{"--shell"}},
+	{[]string{"--init"}, []string{"--force"}},
+	{[]string{"--docs"}, nil},
+	{[]string{"--watch", "-w"}, []string{"--exec", "--exec-if-clean"}},
+	{[]string{"--fix-order"}, nil},
+}
+
+// rejects reports whether the command refuses a flag: one that selects a
+// mode, which the command already decides, or one another mode uses
+func (c *command) rejects(flag string) bool {
+	for _, m := range modeFlags {
+		for _, f := range m.selects {
+			if f == flag {
+				return true
+			}
+		}
+		for _, f := range m.uses {
+			if f == flag {
+				return m.selects[0] != c.mode
+			}
+		}
+	}
+	return false
+}
+
+// addFileOperand scans an operand like --file
+func addFileOperand(cfg *Config, arg string) error {
+	cfg.addFile(arg)
+	return nil
+}
+
+// diffOperand takes the file and the file it is compared with
+func diffOperand(cfg *Config, arg string) error {
+	switch {
+	case cfg.FilePath == "":
+		cfg.addFile(arg)
+		return nil
+	case cfg.DiffFile == "":
+		cfg.DiffFile = arg
+		return nil
+	default:
+		return fmt.Errorf("diff takes two files, got extra argument %s", arg)
+	}
+}
+
+// parseCommand parses the arguments following a subcommand into the Config
+// of the equivalent flat flags
+func parseCommand(name string, args []string) (*Config, error) {
+	cmd := commands[name]
+	for _, arg := range args {
+		if cmd.rejects(arg) {
+			return nil, fmt.Errorf("%s cannot be used with env-audit %s", arg, name)
+		}
+	}
+	cfg, err := parseArgs(append(append([]string{}, cmd.flags...), args...), cmd.operand)
+	if err != nil {
+		return nil, err
+	}
+	cfg.command = name
+	if cfg.Help || cmd.validate == nil {
+		return cfg, nil
+	}
+	if err := cmd.validate(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// printCommandUsage prints the help of a subcommand: its synopsis and the
+// options of PrintUsage it accepts
+func printCommandUsage(w io.Writer, name string) {
+	cmd := commands[name]
+	fmt.Fprintln(w, cmd.synopsis)
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, cmd.about)
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Options:")
+
+	var usage bytes.Buffer
+	PrintUsage(&usage)
+	scanner := bufio.NewScanner(&usage)
+	inOptions, skipping := false, false
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !inOptions {
+			inOptions = line == "Options:"
+			continue
+		}
+		if line == "" {
+			break
+		}
+		// An option line starts with its flags; continuation lines follow it
+		if trimmed := strings.TrimLeft(line, " "); len(line)-len(trimmed) == 2 {
+			flag, _, _ := strings.Cut(trimmed, " ")
+			skipping = cmd.rejects(strings.TrimSuffix(flag, ","))
+		}
+		if !skipping {
+			fmt.Fprintln(w, line)
+		}
+	}
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Run 'env-audit --help' for all commands.")
+}
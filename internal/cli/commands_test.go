@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want Config
+	}{
+		{"scan", []string{"a.env", "b.env", "--json"}, Config{FilePath: "a.env", Files: []string{"a.env", "b.env"}, JSONOutput: true}},
+		{"diff", []string{"a.env", "b.env", "-y"}, Config{FilePath: "a.env", Files: []string{"a.env"}, DiffFile: "b.env", SideBySide: true}},
+		{"diff", []string{"-f", "a.env", "b.env"}, Config{FilePath: "a.env", Files: []string{"a.env"}, DiffFile: "b.env"}},
+		{"init", []string{"--force"}, Config{Init: true, Force: true}},
+		{"watch", []string{".env.local"}, Config{Watch: true, FilePath: ".env.local", Files: []string{".env.local"}}},
+		{"leaks", []string{".env"}, Config{CheckLeaks: true, Only: []string{"leak", "example_secret"}, FilePath: ".env", Files: []string{".env"}}},
+	}
+	for _, tt := range tests {
+		cfg, err := parseCommand(tt.name, tt.args)
+		if err != nil {
+			t.Errorf("%s %v: unexpected error: %v", tt.name, tt.args, err)
+			continue
+		}
+		tt.want.command = tt.name
+		if !reflect.DeepEqual(*cfg, tt.want) {
+			t.Errorf("%s %v: got %+v, want %+v", tt.name, tt.args, *cfg, tt.want)
+		}
+	}
+}
+
+func TestParseCommand_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"scan", []string{"--diff", "b.env"}, "--diff cannot be used with env-audit scan"},
+		{"scan", []string{"--force"}, "--force cannot be used with env-audit scan"},
+		{"diff", []string{"a.env", "b.env", "--dump"}, "--dump cannot be used with env-audit diff"},
+		{"diff", []string{"a.env", "--diff", "b.env"}, "--diff cannot be used with env-audit diff"},
+		{"diff", []string{"a.env"}, "diff requires two files"},
+		{"diff", []string{"a.env", "b.env", "c.env"}, "extra argument c.env"},
+		{"init", []string{"--watch"}, "--watch cannot be used with env-audit init"},
+		{"leaks", []string{"--bogus"}, "unknown argument: --bogus"},
+	}
+	for _, tt := range tests {
+		_, err := parseCommand(tt.name, tt.args)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s %v: expected error containing %q, got %v", tt.name, tt.args, tt.want, err)
+		}
+	}
+}
+
+func TestParseArgs_RejectsOperands(t *testing.T) {
+	if _, err := ParseArgs([]string{".env"}); err == nil {
+		t.Error("expected flat flags to reject a bare file argument")
+	}
+}
+
+func TestRun_Commands(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.env")
+	b := filepath.Join(dir, "b.env")
+	os.WriteFile(a, []byte("PORT=8080\nHOST=localhost\n"), 0644)
+	os.WriteFile(b, []byte("PORT=9090\nHOST=localhost\n"), 0644)
+
+	var flat, stdout, stderr bytes.Buffer
+	Run([]string{"-f", a, "--diff", b, "--no-color"}, &flat, &stderr)
+	Run([]string{"diff", a, b, "--no-color"}, &stdout, &stderr)
+	if stdout.String() != flat.String() || !strings.Contains(stdout.String(), "PORT") {
+		t.Errorf("expected env-audit diff to match --diff, got:\n%s\nwant:\n%s", stdout.String(), flat.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := Run([]string{"scan", a, "--dump"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit 2 for a flag of another mode, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "--dump cannot be used with env-audit scan") {
+		t.Errorf("unexpected error: %s", stderr.String())
+	}
+}
+
+func TestRun_CommandHelp(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"diff", "--help"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	out := stdout.String()
+	if !strings.HasPrefix(out, "env-audit diff <file> <other> [options]\n") {
+		t.Errorf("expected the diff synopsis first, got:\n%s", out)
+	}
+	if !strings.Contains(out, "--side-by-side") || !strings.Contains(out, "--format <name>") || !strings.Contains(out, "github, gitlab, buildkite") {
+		t.Errorf("expected diff and shared options with their continuation lines, got:\n%s", out)
+	}
+	for _, flag := range []string{"--diff <path>", "--init", "--dump", "--shell", "--watch", "--force"} {
+		if strings.Contains(out, "\n  "+flag) {
+			t.Errorf("expected %s to be left out of the diff help, got:\n%s", flag, out)
+		}
+	}
+}
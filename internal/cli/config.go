@@ -0,0 +1,279 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"env-audit/internal/config"
+	"env-audit/internal/netutil"
+)
+
+// configSetting is a setting of the effective configuration, named as in the
+// config file, and how to print its value
+type configSetting struct {
+	name  string
+	value func(cfg *Config) string
+}
+
+// configSettings are the settings config show prints, in config file order.
+// Maps whose values may be secret, such as defaults, are printed as keys.
+var configSettings = []configSetting{
+	{"file", func(c *Config) string { return showString(c.FilePath) }},
+	{"example", func(c *Config) string { return showString(c.ExampleFile) }},
+	{"required", func(c *Config) string { return showList(c.Required) }},
+	{"require_all_example", func(c *Config) string { return strconv.FormatBool(c.RequireAllExample) }},
+	{"ignore", func(c *Config) string { return showList(c.Ignore) }},
+	{"allow_empty", func(c *Config) string { return showList(c.AllowEmpty) }},
+	{"strict", func(c *Config) string { return strconv.FormatBool(c.Strict) }},
+	{"fail_on", func(c *Config) string { return c.failOn() }},
+	{"fail_fast", func(c *Config) string { return strconv.FormatBool(c.FailFast) }},
+	{"check_leaks", func(c *Config) string { return strconv.FormatBool(c.CheckLeaks) }},
+	{"check_sources", func(c *Config) string { return strconv.FormatBool(c.CheckSources) }},
+	{"check_order", func(c *Config) string { return strconv.FormatBool(c.CheckOrder) }},
+	{"warn_parse", func(c *Config) string { return strconv.FormatBool(c.WarnParse) }},
+	{"quiet", func(c *Config) string { return strconv.FormatBool(c.Quiet) }},
+	{"json", func(c *Config) string { return strconv.FormatBool(c.JSONOutput) }},
+	{"github", func(c *Config) string { return strconv.FormatBool(c.GitHubOutput) }},
+	{"gitlab", func(c *Config) string { return strconv.FormatBool(c.GitLabOutput) }},
+	{"buildkite", func(c *Config) string { return strconv.FormatBool(c.BuildkiteOutput) }},
+	{"circleci", func(c *Config) string { return strconv.FormatBool(c.CircleCIOutput) }},
+	{"no_color", func(c *Config) string { return strconv.FormatBool(c.NoColor) }},
+	{"only", func(c *Config) string { return showList(c.Only) }},
+	{"exclude", func(c *Config) string { return showList(c.Exclude) }},
+	{"severity", func(c *Config) string {
+		levels := make(map[string]string, len(c.Severity))
+		for t, level := range c.Severity {
+			levels[t.Name()] = level
+		}
+		return showMap(levels)
+	}},
+	{"rules", func(c *Config) string {
+		patterns := make(map[string]string, len(c.Rules))
+		for key, re := range c.Rules {
+			patterns[key] = re.String()
+		}
+		return showMap(patterns)
+	}},
+	{"patterns", func(c *Config) string { return showString(c.Patterns) }},
+	{"sensitive_patterns", func(c *Config) string { return showList(c.SensitivePatterns) }},
+	{"sensitive_exclude", func(c *Config) string { return showList(c.SensitiveExclude) }},
+	{"redaction.mode", func(c *Config) string { return showString(c.RedactMode) }},
+	{"redaction.placeholder", func(c *Config) string { return showString(c.RedactPlaceholder) }},
+	{"redaction.reveal", func(c *Config) string { return strconv.Itoa(c.RedactReveal) }},
+	{"descriptions", func(c *Config) string { return showList(sortedKeys(c.Descriptions)) }},
+	{"defaults", func(c *Config) string {
+		keys := make([]string, 0, len(c.Defaults))
+		for key := range c.Defaults {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return showList(keys)
+	}},
+	{"public_values", func(c *Config) string { return pluralize(len(c.PublicValues), "value") }},
+	{"rotate_after", func(c *Config) string { return showString(c.RotateAfter) }},
+	{"last_rotated", func(c *Config) string { return showList(sortedKeys(c.LastRotated)) }},
+	{"limits.max_vars", func(c *Config) string { return strconv.Itoa(c.Limits.MaxVars) }},
+	{"limits.max_value_length", func(c *Config) string { return strconv.Itoa(c.Limits.MaxValueLength) }},
+	{"limits.max_total_size", func(c *Config) string { return strconv.Itoa(c.Limits.MaxTotalSize) }},
+	{"platform", func(c *Config) string { return showString(c.Platform) }},
+	{"docs_file", func(c *Config) string { return showString(c.DocsFile) }},
+	{"quarantine_recipients", func(c *Config) string { return showList(c.QuarantineTo) }},
+	{"http.proxy", func(c *Config) string { return showString(c.HTTP.Proxy) }},
+	{"http.ca_bundle", func(c *Config) string { return showString(c.HTTP.CABundle) }},
+	{"http.retries", func(c *Config) string { return strconv.Itoa(c.HTTP.Retries) }},
+	{"http.rate_limit", func(c *Config) string { return strconv.FormatFloat(c.HTTP.RateLimit, 'g', -1, 64) }},
+	{"projects", func(c *Config) string {
+		var names []string
+		for _, project := range c.Projects {
+			names = append(names, project.Name)
+		}
+		return showList(names)
+	}},
+}
+
+// showString prints a string setting, quoting it when empty
+func showString(s string) string {
+	if s == "" {
+		return `""`
+	}
+	return s
+}
+
+// showList prints a list setting in YAML flow style
+func showList(items []string) string {
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// showMap prints a map setting in YAML flow style, sorted by key
+func showMap(m map[string]string) string {
+	var pairs []string
+	for _, key := range sortedKeys(m) {
+		pairs = append(pairs, key+": "+m[key])
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// configLayers is the configuration built up one source at a time, so the
+// source of each setting is the highest layer that changed it
+type configLayers struct {
+	defaults  *Config // no flags and no config file
+	flags     *Config // the flags alone
+	own       *Config // the config file without its preset or base, without flags
+	preset    *Config // the config file with its preset, without flags
+	file      *Config // the config file with its preset and base, without flags
+	env       *Config // ENV_AUDIT_* variables over the config file, without flags
+	merged    *Config // the flags over the variables and the config file
+	effective *Config // merged, with the defaults of a detected CI service
+
+	configPath string // config file, empty when there is none
+	presetName string // preset the config file names
+	extends    string // config file the config file extends
+}
+
+// loadConfigLayers builds each layer of the configuration the flags in args
+// would audit with
+func loadConfigLayers(args []string) (*configLayers, error) {
+	layer := func(args []string, file *config.FileConfig, env bool) (*Config, error) {
+		cfg, err := ParseArgs(args)
+		if err != nil {
+			return nil, err
+		}
+		flags := *cfg
+		if file == nil {
+			file = &config.FileConfig{}
+		}
+		cfg.MergeWithFileConfig(toFileConfig(file))
+		if env {
+			if err := cfg.applyEnv(&flags, os.Getenv); err != nil {
+				return nil, err
+			}
+		}
+		return cfg, nil
+	}
+
+	flags, err := ParseArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	// A config file's preset may be a URL, fetched while the file loads
+	if flags.Offline {
+		netutil.Configure(netutil.Options{Offline: true})
+	}
+	layers := &configLayers{configPath: flags.findConfigFile()}
+	var own, preset, file *config.FileConfig
+	if layers.configPath != "" {
+		if file, err = loadConfigFile(layers.configPath, config.LoadFile); err != nil {
+			return nil, err
+		}
+		if preset, err = loadConfigFile(layers.configPath, config.LoadFileWithoutExtends); err != nil {
+			return nil, err
+		}
+		if own, err = loadConfigFile(layers.configPath, config.LoadFileWithoutPreset); err != nil {
+			return nil, err
+		}
+		layers.presetName = own.Preset
+		layers.extends = own.Extends
+	}
+
+	if layers.defaults, err = layer(nil, nil, false); err != nil {
+		return nil, err
+	}
+	if layers.flags, err = layer(args, nil, false); err != nil {
+		return nil, err
+	}
+	if layers.own, err = layer(nil, own, false); err != nil {
+		return nil, err
+	}
+	if layers.preset, err = layer(nil, preset, false); err != nil {
+		return nil, err
+	}
+	if layers.file, err = layer(nil, file, false); err != nil {
+		return nil, err
+	}
+	if layers.env, err = layer(nil, file, true); err != nil {
+		return nil, err
+	}
+	if layers.merged, err = layer(args, file, true); err != nil {
+		return nil, err
+	}
+	if layers.effective, err = layer(args, file, true); err != nil {
+		return nil, err
+	}
+	if !layers.effective.NoCIDetect {
+		if ci := detectCI(os.Getenv); ci != nil {
+			layers.effective.applyCIDefaults(ci)
+		}
+	}
+	return layers, nil
+}
+
+// origin names the source of a setting's effective value
+func (l *configLayers) origin(s configSetting) string {
+	switch {
+	case s.value(l.effective) != s.value(l.merged):
+		return "ci " + l.effective.ci.Name
+	case s.value(l.flags) != s.value(l.defaults):
+		return "flag"
+	case s.value(l.env) != s.value(l.file):
+		return "env " + envPrefix + strings.ToUpper(s.name)
+	case s.value(l.own) != s.value(l.defaults):
+		return "config " + l.configPath
+	case s.value(l.preset) != s.value(l.defaults):
+		return "preset " + l.presetName
+	case s.value(l.file) != s.value(l.defaults):
+		return "extends " + l.extends
+	default:
+		return "default"
+	}
+}
+
+// runConfig implements "config show": it prints the effective configuration
+// the given flags and the config file add up to, and with --origin where
+// each setting came from
+func runConfig(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "show" {
+		fmt.Fprintln(stderr, "Error: config requires a subcommand: show")
+		return 2
+	}
+	origin := false
+	var flags []string
+	for _, arg := range args[1:] {
+		if arg == "--origin" {
+			origin = true
+			continue
+		}
+		flags = append(flags, arg)
+	}
+
+	layers, err := loadConfigLayers(flags)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+
+	if layers.configPath == "" {
+		fmt.Fprintln(stdout, "# no config file up to the repository root")
+	} else {
+		fmt.Fprintln(stdout, "# config file:", layers.configPath)
+	}
+	lines := make([]string, len(configSettings))
+	width := 0
+	for i, s := range configSettings {
+		lines[i] = s.name + ": " + s.value(layers.effective)
+		if len(lines[i]) > width {
+			width = len(lines[i])
+		}
+	}
+	for i, s := range configSettings {
+		if origin {
+			fmt.Fprintf(stdout, "%-*s  # %s\n", width, lines[i], layers.origin(s))
+		} else {
+			fmt.Fprintln(stdout, lines[i])
+		}
+	}
+	return 0
+}
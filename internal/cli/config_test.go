@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRun_ConfigShowOrigin(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tmpDir, ".git"), 0755)
+	os.MkdirAll(filepath.Join(tmpDir, "sub"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, ".env-audit.yaml"), []byte("preset: ci-minimal\nrequired: [API_KEY]\nfile: .env\n"), 0644)
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(filepath.Join(tmpDir, "sub"))
+	defer os.Chdir(oldWd)
+
+	var stdout, stderr bytes.Buffer
+	exitCode := Run([]string{"config", "show", "--origin", "--strict", "--no-ci-detect"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr: %s", exitCode, stderr.String())
+	}
+	output := stdout.String()
+	for _, want := range []string{
+		`(?m)^file: \.\./\.env +# config .+\.env-audit\.yaml$`,
+		`(?m)^required: \[API_KEY\] +# config `,
+		`(?m)^strict: true +# flag$`,
+		`(?m)^fail_on: warning +# flag$`,
+		`(?m)^check_leaks: true +# preset ci-minimal$`,
+		`(?m)^check_order: false +# default$`,
+	} {
+		if !regexp.MustCompile(want).MatchString(output) {
+			t.Errorf("expected a line matching %s, got:\n%s", want, output)
+		}
+	}
+
+	stdout.Reset()
+	Run([]string{"config", "show", "--no-ci-detect"}, &stdout, &stderr)
+	if strings.Contains(stdout.String(), "# default") || !strings.Contains(stdout.String(), "check_leaks: true\n") {
+		t.Errorf("expected values without origins, got:\n%s", stdout.String())
+	}
+}
+
+func TestRun_ConfigShowCIOrigin(t *testing.T) {
+	t.Setenv("GITHUB_ACTIONS", "true")
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := Run([]string{"config", "show", "--origin"}, &stdout, &stderr); exitCode != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr: %s", exitCode, stderr.String())
+	}
+	if !regexp.MustCompile(`(?m)^github: true +# ci GitHub Actions$`).MatchString(stdout.String()) {
+		t.Errorf("expected the CI default traced, got:\n%s", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "# no config file") {
+		t.Errorf("expected a note that no config file was found, got:\n%s", stdout.String())
+	}
+}
+
+func TestRun_ConfigRequiresShow(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if exitCode := Run([]string{"config"}, &stdout, &stderr); exitCode != 2 {
+		t.Errorf("expected exit 2, got %d", exitCode)
+	}
+}
+
+func TestRun_ConfigShowExtendsOrigin(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmpDir, "org.yaml"), []byte("check_order: true\nignore: [CI_TOKEN]\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, ".env-audit.yaml"), []byte("extends: org.yaml\nrequired: [API_KEY]\n"), 0644)
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := Run([]string{"config", "show", "--origin", "--no-ci-detect"}, &stdout, &stderr); exitCode != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr: %s", exitCode, stderr.String())
+	}
+	for _, want := range []string{
+		`(?m)^check_order: true +# extends org\.yaml$`,
+		`(?m)^ignore: \[CI_TOKEN\] +# extends org\.yaml$`,
+		`(?m)^required: \[API_KEY\] +# config \.env-audit\.yaml$`,
+	} {
+		if !regexp.MustCompile(want).MatchString(stdout.String()) {
+			t.Errorf("expected a line matching %s, got:\n%s", want, stdout.String())
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package cli
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"env-audit/internal/redact"
+)
+
+// defaultDiffWidth is the side-by-side width when $COLUMNS is not set
+const defaultDiffWidth = 120
+
+// sideBySideOptions controls the side-by-side diff rendering
+type sideBySideOptions struct {
+	Left, Right string           // column headers, usually the file names
+	Redactor    *redact.Redactor // masks sensitive values and keys that are secrets
+	Width       int              // total line width
+	Color       bool             // highlight changed, removed and added rows
+}
+
+// formatSideBySide renders every key of both environments in two aligned
+// columns, sorted by key. The gutter marks each row like sdiff: "|" changed,
+// "<" only on the left, ">" only on the right, blank when equal.
+func formatSideBySide(left, right map[string]string, opts sideBySideOptions) string {
+	keys := make([]string, 0, len(left)+len(right))
+	for key := range left {
+		keys = append(keys, key)
+	}
+	for key := range right {
+		if _, ok := left[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	type row struct {
+		left, right, marker string
+	}
+	rows := make([]row, 0, len(keys))
+	column := utf8.RuneCountInString(opts.Left)
+	rightColumn := utf8.RuneCountInString(opts.Right)
+	for _, key := range keys {
+		l, inLeft := left[key]
+		r, inRight := right[key]
+		var rw row
+		if inLeft {
+			rw.left = opts.Redactor.Key(key) + "=" + opts.Redactor.Value(key, l)
+		}
+		if inRight {
+			rw.right = opts.Redactor.Key(key) + "=" + opts.Redactor.Value(key, r)
+		}
+		switch {
+		case !inRight:
+			rw.marker = "<"
+		case !inLeft:
+			rw.marker = ">"
+		case l != r:
+			rw.marker = "|"
+		default:
+			rw.marker = " "
+		}
+		if n := utf8.RuneCountInString(rw.left); n > column {
+			column = n
+		}
+		if n := utf8.RuneCountInString(rw.right); n > rightColumn {
+			rightColumn = n
+		}
+		rows = append(rows, rw)
+	}
+
+	// The left column gets at most half of the width beside the " x "
+	// gutter, the right column whatever remains
+	if limit := (opts.Width - 3) / 2; limit > 0 && column > limit {
+		column = limit
+	}
+	if limit := opts.Width - 3 - column; limit > 0 && rightColumn > limit {
+		rightColumn = limit
+	}
+
+	var sb strings.Builder
+	sb.WriteString(pad(truncate(opts.Left, column), column) + "   " + truncate(opts.Right, rightColumn) + "\n")
+	sb.WriteString(strings.Repeat("-", column) + "   " + strings.Repeat("-", rightColumn) + "\n")
+	for _, rw := range rows {
+		line := pad(truncate(rw.left, column), column) + " " + rw.marker + " " + truncate(rw.right, rightColumn)
+		line = strings.TrimRight(line, " ")
+		if opts.Color {
+			if color := diffMarkerColor(rw.marker); color != "" {
+				line = color + line + colorReset
+			}
+		}
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
+
+// diffMarkerColor highlights a side-by-side row by its gutter marker
+func diffMarkerColor(marker string) string {
+	switch marker {
+	case "|":
+		return colorYellow
+	case "<":
+		return colorRed
+	case ">":
+		return colorGreen
+	default:
+		return ""
+	}
+}
+
+// diffWidth is the terminal width from $COLUMNS, or defaultDiffWidth
+func diffWidth() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return defaultDiffWidth
+}
+
+// truncate shortens s to width runes, marking the cut with "…"
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	if width < 1 {
+		return ""
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + "…"
+}
+
+// pad right-pads s with spaces to width runes
+func pad(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// diffContext is the number of unchanged lines shown around each change of
+// a unified diff
+const diffContext = 3
+
+// lineEdit is one line of a line diff: ' ' kept, '-' removed or '+' added,
+// with its index in before and in after, -1 on the side it is missing from
+type lineEdit struct {
+	op       byte
+	old, new int
+}
+
+// diffLines is the shortest edit turning before into after, from their
+// longest common subsequence
+func diffLines(before, after []string) []lineEdit {
+	// common[i][j] is the LCS length of before[i:] and after[j:]
+	common := make([][]int, len(before)+1)
+	for i := range common {
+		common[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var edits []lineEdit
+	i, j := 0, 0
+	for i < len(before) || j < len(after) {
+		switch {
+		case i < len(before) && j < len(after) && before[i] == after[j]:
+			edits = append(edits, lineEdit{' ', i, j})
+			i++
+			j++
+		case j < len(after) && (i == len(before) || common[i][j+1] > common[i+1][j]):
+			edits = append(edits, lineEdit{'+', -1, j})
+			j++
+		default:
+			edits = append(edits, lineEdit{'-', i, -1})
+			i++
+		}
+	}
+	return edits
+}
+
+// formatUnifiedDiff renders the change from before to after as a unified
+// diff of path, or empty string if there is none. Values of sensitive keys
+// are masked.
+func formatUnifiedDiff(path, before, after string, redactor *redact.Redactor) string {
+	oldLines, newLines := splitLines(before), splitLines(after)
+	edits := diffLines(oldLines, newLines)
+	oldShown, newShown := redactLines(oldLines, redactor), redactLines(newLines, redactor)
+
+	var sb strings.Builder
+	for start := 0; start < len(edits); {
+		if edits[start].op == ' ' {
+			start++
+			continue
+		}
+		// A hunk takes in the next change while the lines between fit in
+		// the context of both
+		end := start
+		for k := start; k < len(edits) && k-end <= 2*diffContext+1; k++ {
+			if edits[k].op != ' ' {
+				end = k
+			}
+		}
+		first, last := max(start-diffContext, 0), min(end+diffContext+1, len(edits))
+
+		oldStart, newStart := 1, 1
+		for _, e := range edits[:first] {
+			if e.old >= 0 {
+				oldStart++
+			}
+			if e.new >= 0 {
+				newStart++
+			}
+		}
+		oldLen, newLen := 0, 0
+		for _, e := range edits[first:last] {
+			if e.old >= 0 {
+				oldLen++
+			}
+			if e.new >= 0 {
+				newLen++
+			}
+		}
+		if sb.Len() == 0 {
+			sb.WriteString("--- " + path + "\n+++ " + path + "\n")
+		}
+		sb.WriteString("@@ -" + hunkRange(oldStart, oldLen) + " +" + hunkRange(newStart, newLen) + " @@\n")
+		for _, e := range edits[first:last] {
+			if e.old >= 0 {
+				sb.WriteString(string(e.op) + oldShown[e.old] + "\n")
+			} else {
+				sb.WriteString(string(e.op) + newShown[e.new] + "\n")
+			}
+		}
+		start = last
+	}
+	return sb.String()
+}
+
+// hunkRange formats the start and length of one side of a hunk; an empty
+// side starts at the line before it
+func hunkRange(start, length int) string {
+	if length == 0 {
+		start--
+	}
+	if length == 1 {
+		return strconv.Itoa(start)
+	}
+	return strconv.Itoa(start) + "," + strconv.Itoa(length)
+}
+
+// splitLines splits content into lines without their line endings
+func splitLines(content string) []string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// redactLines masks secret values in env file lines, including the
+// continuation lines of a multiline value, and keys that are secrets
+func redactLines(lines []string, redactor *redact.Redactor) []string {
+	shown := make([]string, len(lines))
+	for i := 0; i < len(lines); i++ {
+		shown[i] = lines[i]
+		name, value, ok := strings.Cut(lines[i], "=")
+		if !ok || strings.HasPrefix(strings.TrimSpace(name), "#") {
+			continue
+		}
+		key := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(name), "export "))
+		name = strings.Replace(name, key, redactor.Key(key), 1)
+		shown[i] = name + "=" + value
+		value = strings.TrimSpace(value)
+		if !redactor.Secret(key, value) {
+			continue
+		}
+		shown[i] = name + "=" + redactor.Mask(value)
+		if !strings.HasPrefix(value, `"`) || (len(value) > 1 && strings.HasSuffix(value, `"`)) {
+			continue
+		}
+		for i+1 < len(lines) {
+			i++
+			shown[i] = redactor.Mask(lines[i])
+			if strings.HasSuffix(strings.TrimSpace(lines[i]), `"`) {
+				break
+			}
+		}
+	}
+	return shown
+}
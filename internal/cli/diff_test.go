@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"env-audit/internal/redact"
+)
+
+func TestFormatSideBySide(t *testing.T) {
+	left := map[string]string{"A": "1", "B": "old", "GONE": "x"}
+	right := map[string]string{"A": "1", "B": "new", "NEW": "y"}
+
+	output := formatSideBySide(left, right, sideBySideOptions{Left: ".env", Right: ".env.prod", Width: 80})
+
+	want := strings.Join([]string{
+		".env     .env.prod",
+		"------   ---------",
+		"A=1      A=1",
+		"B=old  | B=new",
+		"GONE=x <",
+		"       > NEW=y",
+	}, "\n") + "\n"
+	if output != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", output, want)
+	}
+}
+
+func TestFormatSideBySide_RedactsAndTruncates(t *testing.T) {
+	left := map[string]string{"API_SECRET": "s3cr3t-value", "LONG": strings.Repeat("x", 50)}
+	right := map[string]string{"API_SECRET": "other-value", "LONG": strings.Repeat("x", 50)}
+
+	output := formatSideBySide(left, right, sideBySideOptions{Left: "a", Right: "b", Redactor: redact.Default(), Width: 43})
+
+	if strings.Contains(output, "s3cr3t") || strings.Contains(output, "other-value") {
+		t.Errorf("expected secrets masked, got:\n%s", output)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\n"), "\n") {
+		if n := len([]rune(line)); n > 43 {
+			t.Errorf("line exceeds width (%d): %q", n, line)
+		}
+	}
+	if !strings.Contains(output, "…") {
+		t.Errorf("expected long values to be truncated, got:\n%s", output)
+	}
+}
+
+func TestFormatSideBySide_Color(t *testing.T) {
+	output := formatSideBySide(map[string]string{"A": "1"}, map[string]string{"A": "2", "B": "3"}, sideBySideOptions{Width: 80, Color: true})
+	if !strings.Contains(output, colorYellow+"A=1 | A=2"+colorReset) {
+		t.Errorf("expected the changed row highlighted, got %q", output)
+	}
+	if !strings.Contains(output, colorGreen) {
+		t.Errorf("expected the added row highlighted, got %q", output)
+	}
+}
+
+func TestRun_DiffSideBySide(t *testing.T) {
+	dir := t.TempDir()
+	file1 := filepath.Join(dir, "a.env")
+	file2 := filepath.Join(dir, "b.env")
+	os.WriteFile(file1, []byte("PORT=3000\nHOST=a\n"), 0644)
+	os.WriteFile(file2, []byte("PORT=3000\nHOST=b\n"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := Run([]string{"-f", file1, "--diff", file2, "--side-by-side", "--no-color"}, &stdout, &stderr); exitCode != 0 {
+		t.Fatalf("expected exit 0, got %d: %s", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), " | HOST=b") || !strings.Contains(stdout.String(), "   PORT=3000") {
+		t.Errorf("unexpected side-by-side output:\n%s", stdout.String())
+	}
+}
+
+func TestParseArgs_SideBySideRequiresDiff(t *testing.T) {
+	if _, err := ParseArgs([]string{"--side-by-side"}); err == nil {
+		t.Error("expected --side-by-side without --diff to fail")
+	}
+}
+
+func TestRun_DiffReportsRenames(t *testing.T) {
+	dir := t.TempDir()
+	file1 := filepath.Join(dir, "a.env")
+	file2 := filepath.Join(dir, "b.env")
+	os.WriteFile(file1, []byte("DB_PASS=correct-horse-battery\n"), 0644)
+	os.WriteFile(file2, []byte("DATABASE_PASSWORD=correct-horse-battery\n"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := Run([]string{"-f", file1, "--diff", file2}, &stdout, &stderr); exitCode != 0 {
+		t.Fatalf("expected exit 0, got %d: %s", exitCode, stderr.String())
+	}
+	if got := stdout.String(); got != "> DB_PASS -> DATABASE_PASSWORD (renamed)" {
+		t.Errorf("unexpected diff output: %q", got)
+	}
+}
+
+func TestFormatUnifiedDiff(t *testing.T) {
+	before := "A=1\nB=2\nC=3\nD=4\nE=5\nF=6\nG=7\nH=8\nI=9\nJ=10\nK=11\nL=12\n"
+	after := "A=1\nB=two\nC=3\nD=4\nE=5\nF=6\nG=7\nH=8\nI=9\nJ=10\nL=12\n"
+	want := "--- .env\n+++ .env\n" +
+		"@@ -1,5 +1,5 @@\n A=1\n-B=2\n+B=two\n C=3\n D=4\n E=5\n" +
+		"@@ -8,5 +8,4 @@\n H=8\n I=9\n J=10\n-K=11\n L=12\n"
+	if got := formatUnifiedDiff(".env", before, after, redact.Default()); got != want {
+		t.Errorf("unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+	if got := formatUnifiedDiff(".env", before, before, redact.Default()); got != "" {
+		t.Errorf("expected no diff for equal content, got %q", got)
+	}
+}
+
+func TestFormatUnifiedDiff_Redacts(t *testing.T) {
+	before := "API_KEY = sk_live_123\nPRIVATE_KEY=\"-----BEGIN KEY-----\nMIIB\n-----END KEY-----\"\n"
+	after := "API_KEY=sk_live_123\n"
+	got := formatUnifiedDiff(".env", before, after, redact.Default())
+	for _, secret := range []string{"sk_live_123", "MIIB", "BEGIN"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("expected %q masked, got:\n%s", secret, got)
+		}
+	}
+	if !strings.Contains(got, "@@ -1,4 +1 @@\n-API_KEY =[REDACTED]\n-PRIVATE_KEY=[REDACTED]\n-[REDACTED]\n-[REDACTED]\n+API_KEY=[REDACTED]\n") {
+		t.Errorf("expected the masked lines in the diff, got:\n%s", got)
+	}
+}
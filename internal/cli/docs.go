@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"env-audit/internal/parser"
+	"env-audit/internal/redact"
+)
+
+// defaultDocsFile is compared with the example when it exists and no docs
+// file is configured
+const defaultDocsFile = "ENVIRONMENT.md"
+
+// docsFile is the docs file compared with the example: --docs-file, else
+// ENVIRONMENT.md if there is one, else none
+func (cfg *Config) docsFile() string {
+	if cfg.DocsFile != "" {
+		return cfg.DocsFile
+	}
+	if _, err := os.Stat(defaultDocsFile); err == nil {
+		return defaultDocsFile
+	}
+	return ""
+}
+
+// loadDocumented reads the entries of the env file at path, as written, and
+// their descriptions: the comments above each key, overridden by
+// descriptions from the config file. An empty path reads the environment.
+func loadDocumented(cfg *Config, path string) (map[string]string, map[string]string, error) {
+	descriptions := make(map[string]string)
+	var env map[string]string
+	if path == "" {
+		env = parser.ReadOSEnv()
+	} else {
+		result, err := parser.ParseEnvFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		env = result.Entries
+		descriptions = result.Descriptions()
+	}
+	for key, desc := range cfg.Descriptions {
+		descriptions[key] = desc
+	}
+	return env, descriptions, nil
+}
+
+// docsInfo is what the docs table says about each variable besides its
+// description
+type docsInfo struct {
+	descriptions map[string]string
+	examples     map[string]string // values of the example file, masked if sensitive
+	required     map[string]bool
+	sensitive    map[string]bool
+	redactor     *redact.Redactor // masks keys that are secrets themselves
+}
+
+// loadDocsInfo gathers the docs table of env, the entries of the file at
+// path. Example values come from the example file, which also describes the
+// keys the file doesn't, or from the file itself if it is an example such
+// as .env.example; never from an env file that may hold real values. They
+// are masked for sensitive keys.
+func loadDocsInfo(cfg *Config, path string, env, descriptions map[string]string, redactor *redact.Redactor) (*docsInfo, error) {
+	info := &docsInfo{
+		descriptions: descriptions,
+		examples:     make(map[string]string),
+		required:     make(map[string]bool),
+		sensitive:    make(map[string]bool),
+		redactor:     redactor,
+	}
+	var example map[string]string
+	if cfg.ExampleFile != "" {
+		// The example only adds a column, so it may not exist yet
+		result, err := parser.ParseEnvFile(cfg.ExampleFile)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		if err == nil {
+			example = result.Entries
+			// The example's comments describe keys the file doesn't
+			for key, desc := range result.Descriptions() {
+				if _, ok := info.descriptions[key]; !ok {
+					info.descriptions[key] = desc
+				}
+			}
+		}
+	} else if strings.Contains(filepath.Base(path), "example") {
+		example = env
+	}
+	for _, key := range cfg.Required {
+		info.required[key] = true
+	}
+	for key := range env {
+		info.sensitive[key] = redactor.Sensitive(key)
+		if value, ok := example[key]; ok {
+			if value != "" {
+				info.examples[key] = redactor.Value(key, value)
+			}
+			info.required[key] = info.required[key] || cfg.RequireAllExample
+		}
+	}
+	return info, nil
+}
+
+// runDocs prints a Markdown table documenting the variables of the --file,
+// or else the --example file: their descriptions, whether they are required
+// or sensitive, and their example values
+func runDocs(cfg *Config, redactor *redact.Redactor, stdout, stderr io.Writer) int {
+	path := cfg.FilePath
+	if path == "" {
+		path = cfg.ExampleFile
+	}
+	if path == "" {
+		fmt.Fprintln(stderr, "Error: --docs requires --file or --example")
+		return 2
+	}
+	env, descriptions, err := loadDocumented(cfg, path)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	info, err := loadDocsInfo(cfg, path, env, descriptions, redactor)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	if cfg.Quiet {
+		return 0
+	}
+	if cfg.GroupBy != groupByPrefix {
+		fmt.Fprint(stdout, formatDocs(sortedKeys(env), info))
+		return 0
+	}
+	// One section per namespace
+	for i, group := range groupKeysByPrefix(sortedKeys(env)) {
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		fmt.Fprintf(stdout, "### %s\n\n%s", group.label(), formatDocs(group.Keys, info))
+	}
+	return 0
+}
+
+// formatDocs renders a Markdown table of keys and what info says about
+// them. Example values are plain text rather than code, so an upper-case
+// value isn't mistaken for a documented variable by the drift check.
+func formatDocs(keys []string, info *docsInfo) string {
+	yes := func(b bool) string {
+		if b {
+			return "yes"
+		}
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("| Variable | Description | Required | Sensitive | Example |\n| --- | --- | --- | --- | --- |\n")
+	for _, key := range keys {
+		sb.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s | %s |\n", info.redactor.Key(key), markdownCell(info.descriptions[key]),
+			yes(info.required[key]), yes(info.sensitive[key]), markdownCell(info.examples[key])))
+	}
+	return sb.String()
+}
+
+// markdownCell escapes text for a single Markdown table cell
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
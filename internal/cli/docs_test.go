@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatDocs(t *testing.T) {
+	output := formatDocs([]string{"API_KEY", "DATABASE_URL", "MODE", "PORT"}, &docsInfo{
+		descriptions: map[string]string{
+			"DATABASE_URL": "Primary database\nUse a read-write user",
+			"MODE":         "dev | prod",
+		},
+		examples:  map[string]string{"API_KEY": "[REDACTED]", "PORT": "3000"},
+		required:  map[string]bool{"DATABASE_URL": true},
+		sensitive: map[string]bool{"API_KEY": true},
+	})
+	want := "| Variable | Description | Required | Sensitive | Example |\n| --- | --- | --- | --- | --- |\n" +
+		"| `API_KEY` |  |  | yes | [REDACTED] |\n" +
+		"| `DATABASE_URL` | Primary database<br>Use a read-write user | yes |  |  |\n" +
+		"| `MODE` | dev \\| prod |  |  |  |\n" +
+		"| `PORT` |  |  |  | 3000 |\n"
+	if output != want {
+		t.Errorf("unexpected table:\n%s\nwant:\n%s", output, want)
+	}
+}
+
+func TestRun_DocsFromComments(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	os.WriteFile(".env-audit.yaml", []byte("descriptions:\n  PORT: HTTP port\n"), 0644)
+	os.WriteFile(".env.example", []byte("# Primary database\n# last_rotated: 2026-01-01\nDATABASE_URL=\n# stale\nPORT=\n"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"--example", ".env.example", "--docs"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d: %s", code, stderr.String())
+	}
+	output := stdout.String()
+	if !strings.Contains(output, "| `DATABASE_URL` | Primary database |") {
+		t.Errorf("expected the comment as description, got:\n%s", output)
+	}
+	if !strings.Contains(output, "| `PORT` | HTTP port |") {
+		t.Errorf("expected the config description to win, got:\n%s", output)
+	}
+
+	if code := Run([]string{"--docs"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit 2 without a file, got %d", code)
+	}
+}
+
+func TestRun_DocsCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	os.WriteFile(".env-audit.yaml", []byte("example: .env.example\nrequired: [DATABASE_URL]\n"), 0644)
+	os.WriteFile(".env.example", []byte("# Primary database\nDATABASE_URL=postgres://localhost/app\nSTRIPE_SECRET=sk_test_example\nLOG_LEVEL=INFO\n"), 0644)
+	os.WriteFile(".env", []byte("DATABASE_URL=postgres://prod.internal/app\nSTRIPE_SECRET=sk_live_123\nLOG_LEVEL=warn\n"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"docs", ".env"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d: %s", code, stderr.String())
+	}
+	output := stdout.String()
+	for _, want := range []string{
+		"| `DATABASE_URL` | Primary database | yes |  | postgres://localhost/app |\n",
+		"| `LOG_LEVEL` |  |  |  | INFO |\n",
+		"| `STRIPE_SECRET` |  |  | yes | [REDACTED] |\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "prod.internal") || strings.Contains(output, "sk_") {
+		t.Errorf("expected values from the example only, masked if sensitive, got:\n%s", output)
+	}
+
+	if code := Run([]string{"docs", ".env", "--dump"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected another mode to be rejected, got %d", code)
+	}
+}
+
+func TestRun_InitKeepsComments(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	envFile := filepath.Join(tmpDir, ".env")
+	os.WriteFile(envFile, []byte("# Public site URL\n# without a trailing slash\nSITE_URL=https://example.com\n"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"-f", envFile, "--init"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d: %s", code, stderr.String())
+	}
+	data, _ := os.ReadFile(".env.example")
+	if want := "# Public site URL\n# without a trailing slash\nSITE_URL=your_site_url_here\n"; string(data) != want {
+		t.Errorf("expected comments in the template, got:\n%s", data)
+	}
+}
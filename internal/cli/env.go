@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"env-audit/internal/audit"
+)
+
+// envPrefix starts the environment variables overriding config settings:
+// ENV_AUDIT_ and the setting's config file name in upper case
+const envPrefix = "ENV_AUDIT_"
+
+// envSetting is a config setting an environment variable overrides
+type envSetting struct {
+	name string                                // config file name, e.g. check_leaks
+	set  func(cfg *Config, value string) error // applies the variable's value
+	cli  func(cfg *Config) bool                // whether the flags set it, so the variable is ignored
+}
+
+// envVar is the name of the environment variable overriding the setting
+func (s envSetting) envVar() string {
+	return envPrefix + strings.ToUpper(s.name)
+}
+
+// envBool is a boolean setting; the variable takes 1, true, 0, false and
+// the other values strconv.ParseBool accepts
+func envBool(name string, field func(cfg *Config) *bool) envSetting {
+	return envSetting{
+		name: name,
+		set: func(cfg *Config, value string) error {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("%s must be true or false, got %q", envPrefix+strings.ToUpper(name), value)
+			}
+			*field(cfg) = b
+			return nil
+		},
+		cli: func(cfg *Config) bool { return *field(cfg) },
+	}
+}
+
+// envString is a string setting
+func envString(name string, field func(cfg *Config) *string) envSetting {
+	return envSetting{
+		name: name,
+		set: func(cfg *Config, value string) error {
+			*field(cfg) = value
+			return nil
+		},
+		cli: func(cfg *Config) bool { return *field(cfg) != "" },
+	}
+}
+
+// envList is a comma-separated list setting
+func envList(name string, field func(cfg *Config) *[]string) envSetting {
+	return envSetting{
+		name: name,
+		set: func(cfg *Config, value string) error {
+			*field(cfg) = parseCommaSeparated(value)
+			return nil
+		},
+		cli: func(cfg *Config) bool { return len(*field(cfg)) > 0 },
+	}
+}
+
+// envIssueTypes is a comma-separated list of issue type names
+func envIssueTypes(name string, field func(cfg *Config) *[]string) envSetting {
+	setting := envList(name, field)
+	setList := setting.set
+	setting.set = func(cfg *Config, value string) error {
+		for _, t := range parseCommaSeparated(value) {
+			if _, ok := audit.ParseIssueType(t); !ok {
+				return fmt.Errorf("%s: unknown issue type %q", envPrefix+strings.ToUpper(name), t)
+			}
+		}
+		return setList(cfg, value)
+	}
+	return setting
+}
+
+// envSettings are the settings ENV_AUDIT_* variables override, applied in
+// this order
+var envSettings = []envSetting{
+	envString("file", func(c *Config) *string { return &c.FilePath }),
+	envString("example", func(c *Config) *string { return &c.ExampleFile }),
+	envList("required", func(c *Config) *[]string { return &c.Required }),
+	envBool("require_all_example", func(c *Config) *bool { return &c.RequireAllExample }),
+	envList("ignore", func(c *Config) *[]string { return &c.Ignore }),
+	envList("allow_empty", func(c *Config) *[]string { return &c.AllowEmpty }),
+	{
+		name: "strict",
+		set: func(cfg *Config, value string) error {
+			if err := envBool("strict", func(c *Config) *bool { return &c.Strict }).set(cfg, value); err != nil {
+				return err
+			}
+			// As with --strict, the config file's fail_on gives way
+			if cfg.Strict {
+				cfg.FailOn = ""
+			}
+			return nil
+		},
+		cli: func(c *Config) bool { return c.Strict || c.FailOn != "" },
+	},
+	{
+		name: "fail_on",
+		set: func(cfg *Config, value string) error {
+			if !audit.IsSeverity(value) {
+				return fmt.Errorf("%sFAIL_ON must be error, warning or info, got %q", envPrefix, value)
+			}
+			cfg.FailOn = value
+			return nil
+		},
+		cli: func(c *Config) bool { return c.Strict || c.FailOn != "" },
+	},
+	envBool("fail_fast", func(c *Config) *bool { return &c.FailFast }),
+	envBool("check_leaks", func(c *Config) *bool { return &c.CheckLeaks }),
+	envBool("check_sources", func(c *Config) *bool { return &c.CheckSources }),
+	envBool("check_order", func(c *Config) *bool { return &c.CheckOrder }),
+	envBool("warn_parse", func(c *Config) *bool { return &c.WarnParse }),
+	{
+		name: "quiet",
+		set: func(cfg *Config, value string) error {
+			if err := envBool("quiet", func(c *Config) *bool { return &c.Quiet }).set(cfg, value); err != nil {
+				return err
+			}
+			cfg.QuietLevel = 0
+			if cfg.Quiet {
+				cfg.QuietLevel = 1
+			}
+			return nil
+		},
+		cli: func(c *Config) bool { return c.Quiet },
+	},
+	envBool("json", func(c *Config) *bool { return &c.JSONOutput }),
+	envBool("github", func(c *Config) *bool { return &c.GitHubOutput }),
+	envBool("gitlab", func(c *Config) *bool { return &c.GitLabOutput }),
+	envBool("buildkite", func(c *Config) *bool { return &c.BuildkiteOutput }),
+	envBool("circleci", func(c *Config) *bool { return &c.CircleCIOutput }),
+	envBool("no_color", func(c *Config) *bool { return &c.NoColor }),
+	envIssueTypes("only", func(c *Config) *[]string { return &c.Only }),
+	envIssueTypes("exclude", func(c *Config) *[]string { return &c.Exclude }),
+	envString("patterns", func(c *Config) *string { return &c.Patterns }),
+	{
+		name: "platform",
+		set: func(cfg *Config, value string) error {
+			if _, ok := audit.PlatformByName(value); !ok {
+				return fmt.Errorf("%sPLATFORM must be one of %s, got %q", envPrefix, strings.Join(audit.PlatformNames(), ", "), value)
+			}
+			cfg.Platform = value
+			return nil
+		},
+		cli: func(c *Config) bool { return c.Platform != "" },
+	},
+	envString("docs_file", func(c *Config) *string { return &c.DocsFile }),
+}
+
+// applyEnv overrides settings with the ENV_AUDIT_* variables that are set
+// and not empty, except those flags set: flags win over the environment,
+// which wins over the config file. It runs after the config file is merged.
+func (cfg *Config) applyEnv(flags *Config, getenv func(string) string) error {
+	if flags == nil {
+		flags = &Config{}
+	}
+	for _, s := range envSettings {
+		value := getenv(s.envVar())
+		if value == "" || s.cli(flags) {
+			continue
+		}
+		if err := s.set(cfg, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// envConfigFile is the config file ENV_AUDIT_CONFIG names, used when
+// --config is not given
+func envConfigFile() string {
+	return os.Getenv(envPrefix + "CONFIG")
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRun_EnvOverridesPrecedence(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmpDir, ".env"), []byte("APP=web\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, ".env-audit.yaml"), []byte("file: .env\nrequired: [FROM_CONFIG]\n"), 0644)
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+	t.Setenv("ENV_AUDIT_REQUIRED", "FROM_ENV,OTHER_ENV")
+
+	var stdout, stderr bytes.Buffer
+	Run([]string{"--json"}, &stdout, &stderr)
+	output := stdout.String()
+	if !strings.Contains(output, `"key":"FROM_ENV"`) || !strings.Contains(output, `"key":"OTHER_ENV"`) || strings.Contains(output, `"key":"FROM_CONFIG"`) {
+		t.Errorf("expected the environment to win over the config file, got: %s", output)
+	}
+
+	stdout.Reset()
+	Run([]string{"--json", "--required", "FROM_FLAG"}, &stdout, &stderr)
+	output = stdout.String()
+	if !strings.Contains(output, `"key":"FROM_FLAG"`) || strings.Contains(output, `"key":"FROM_ENV"`) {
+		t.Errorf("expected the flag to win over the environment, got: %s", output)
+	}
+}
+
+func TestRun_EnvOverridesBooleans(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmpDir, ".env"), []byte("APP=\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, ".env-audit.yaml"), []byte("file: .env\nstrict: true\n"), 0644)
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := Run([]string{"-q"}, &stdout, &stderr); exitCode != 1 {
+		t.Fatalf("expected the config's strict mode to fail on the empty value, got exit %d", exitCode)
+	}
+
+	t.Setenv("ENV_AUDIT_STRICT", "false")
+	if exitCode := Run([]string{"-q"}, &stdout, &stderr); exitCode != 0 {
+		t.Errorf("expected ENV_AUDIT_STRICT=false to turn strict mode off, got exit %d", exitCode)
+	}
+	if exitCode := Run([]string{"-q", "--strict"}, &stdout, &stderr); exitCode != 1 {
+		t.Errorf("expected --strict to win over the environment, got exit %d", exitCode)
+	}
+
+	t.Setenv("ENV_AUDIT_STRICT", "sometimes")
+	stderr.Reset()
+	if exitCode := Run([]string{"-q"}, &stdout, &stderr); exitCode != 2 || !strings.Contains(stderr.String(), "ENV_AUDIT_STRICT must be true or false") {
+		t.Errorf("expected exit 2 for an invalid boolean, got %d: %s", exitCode, stderr.String())
+	}
+}
+
+func TestRun_EnvConfigFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmpDir, ".env"), []byte("APP=web\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "ci.yaml"), []byte("required: [CI_ONLY]\n"), 0644)
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+	t.Setenv("ENV_AUDIT_CONFIG", "ci.yaml")
+	t.Setenv("ENV_AUDIT_FILE", ".env")
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := Run([]string{"--json"}, &stdout, &stderr); exitCode != 1 || !strings.Contains(stdout.String(), `"key":"CI_ONLY"`) {
+		t.Errorf("expected the config named by ENV_AUDIT_CONFIG, got exit %d: %s", exitCode, stdout.String())
+	}
+}
+
+func TestRun_ConfigShowEnvOrigin(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmpDir, ".env-audit.yaml"), []byte("check_leaks: true\n"), 0644)
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+	t.Setenv("ENV_AUDIT_CHECK_LEAKS", "0")
+
+	var stdout, stderr bytes.Buffer
+	Run([]string{"config", "show", "--origin", "--no-ci-detect"}, &stdout, &stderr)
+	if !regexp.MustCompile(`(?m)^check_leaks: false +# env ENV_AUDIT_CHECK_LEAKS$`).MatchString(stdout.String()) {
+		t.Errorf("expected the environment traced, got:\n%s", stdout.String())
+	}
+}
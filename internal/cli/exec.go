@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// stopTimeout is how long a stopped --exec command may take to exit before
+// it is killed
+const stopTimeout = 5 * time.Second
+
+// watchCommand is the --exec command of watch mode. Each audit stops the
+// instance the previous one started, so a long-running command such as a
+// dev server restarts with the changed file.
+type watchCommand struct {
+	command string
+	ifClean bool // --exec-if-clean skips it after an audit finding risks
+	stdout  io.Writer
+	stderr  io.Writer
+	running *exec.Cmd
+	done    chan struct{} // closed once running has exited
+}
+
+// rerun stops the running instance and, unless the audit that exited with
+// code failed or found risks the command skips, starts a new one. It
+// reports whether it did.
+func (w *watchCommand) rerun(code int) (bool, error) {
+	w.stop()
+	if code == 2 || code == 1 && w.ifClean {
+		return false, nil
+	}
+	return true, w.start()
+}
+
+// start runs the command through the shell without waiting for it
+func (w *watchCommand) start() error {
+	cmd := shellCommand(w.command)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = w.stdout
+	cmd.Stderr = w.stderr
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("--exec: %w", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(done)
+	}()
+	w.running, w.done = cmd, done
+	return nil
+}
+
+// stop terminates the instance started last, with the processes it
+// started, and waits for it to exit. It is a no-op if none is running.
+func (w *watchCommand) stop() {
+	if w == nil || w.running == nil {
+		return
+	}
+	select {
+	case <-w.done:
+	default:
+		// Give it the chance to shut down cleanly, as a dev server should
+		terminateProcessGroup(w.running)
+		select {
+		case <-w.done:
+		case <-time.After(stopTimeout):
+			killProcessGroup(w.running)
+			<-w.done
+		}
+	}
+	w.running, w.done = nil, nil
+}
@@ -0,0 +1,21 @@
+//go:build !unix
+
+package cli
+
+import "os/exec"
+
+// shellCommand runs command with cmd.exe
+func shellCommand(command string) *exec.Cmd {
+	return exec.Command("cmd", "/C", command)
+}
+
+// terminateProcessGroup stops the shell running the command; there is no
+// signal to ask it to exit, so it is killed
+func terminateProcessGroup(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
+
+// killProcessGroup kills the shell running the command
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
@@ -0,0 +1,76 @@
+//go:build unix
+
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond until it holds or a second has passed
+func waitFor(cond func() bool) bool {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if cond() {
+			return true
+		}
+	}
+	return cond()
+}
+
+func TestWatchCommand_Rerun(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	command := &watchCommand{command: "echo run", ifClean: true, stdout: &stdout, stderr: &stderr}
+
+	for _, tt := range []struct {
+		code    int
+		started bool
+	}{{0, true}, {1, false}, {2, false}} {
+		started, err := command.rerun(tt.code)
+		if err != nil || started != tt.started {
+			t.Errorf("code %d: expected started=%v, got %v (%v)", tt.code, tt.started, started, err)
+		}
+		if started {
+			<-command.done
+		}
+	}
+	if stdout.String() != "run\n" {
+		t.Errorf("expected one run, got %q", stdout.String())
+	}
+
+	command.ifClean = false
+	if started, _ := command.rerun(1); !started {
+		t.Error("expected risks to start the command without --exec-if-clean")
+	}
+	command.stop()
+}
+
+func TestWatchCommand_StopsTheProcessGroup(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "alive")
+	var stdout, stderr bytes.Buffer
+	// The child keeps touching the marker until it is stopped
+	command := &watchCommand{
+		command: "(while true; do touch " + marker + "; sleep 0.05; done) & wait",
+		stdout:  &stdout,
+		stderr:  &stderr,
+	}
+	if _, err := command.rerun(0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !waitFor(func() bool { _, err := os.Stat(marker); return err == nil }) {
+		t.Fatal("expected the command to start")
+	}
+
+	command.stop()
+	os.Remove(marker)
+	time.Sleep(200 * time.Millisecond)
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("expected the command's child process stopped too")
+	}
+	if strings.TrimSpace(stderr.String()) != "" {
+		t.Errorf("unexpected stderr %q", stderr.String())
+	}
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"env-audit/internal/audit"
+)
+
+// exitCode is the exit code of an audit: 2 if any file could not be scanned,
+// 1 if any issue counts as a risk, 0 otherwise
+func exitCode(result *audit.Result) int {
+	if result.Failed() > 0 {
+		return 2
+	}
+	if result.HasRisks {
+		return 1
+	}
+	return 0
+}
+
+// explainExit describes why an audit ends with code for --explain-exit: the
+// files that failed, the issues counted as risks at the --fail-on level, and
+// a tally of those that were not
+func explainExit(cfg *Config, result *audit.Result, code int) string {
+	var sb strings.Builder
+	switch code {
+	case 2:
+		sb.WriteString(fmt.Sprintf("Exit code 2: %d of %d files could not be scanned\n", result.Failed(), len(result.Files)))
+	case 1:
+		sb.WriteString("Exit code 1: issues count as risks\n")
+	default:
+		sb.WriteString("Exit code 0: no issue counts as a risk\n")
+	}
+
+	failOn := cfg.failOn()
+	switch failOn {
+	case audit.SeverityInfo:
+		sb.WriteString("  Failing on info: every finding counts\n")
+	case audit.SeverityWarning:
+		sb.WriteString("  Strict mode is on: errors and warnings count, info findings never do\n")
+	default:
+		sb.WriteString("  Strict mode is off: errors count, warnings and info findings do not\n")
+	}
+	if len(cfg.Severity) > 0 {
+		sb.WriteString("  Severity overrides from the config apply\n")
+	}
+	if cfg.Only != nil {
+		sb.WriteString(fmt.Sprintf("  Only %s issues are reported\n", strings.Join(cfg.Only, ", ")))
+	}
+	if cfg.Exclude != nil {
+		sb.WriteString(fmt.Sprintf("  %s issues are excluded\n", strings.Join(cfg.Exclude, ", ")))
+	}
+
+	for _, file := range result.Files {
+		if file.Error != nil {
+			sb.WriteString(fmt.Sprintf("  Failed: %s: %v\n", file.Path, file.Error))
+		}
+	}
+
+	var risks []audit.Issue
+	ignored := make(map[audit.IssueType]int)
+	levels := make(map[audit.IssueType]string)
+	for _, issue := range result.Issues {
+		if issue.IsRisk(failOn) {
+			risks = append(risks, issue)
+		} else {
+			ignored[issue.Type]++
+			levels[issue.Type] = issue.Level()
+		}
+	}
+
+	if len(risks) > 0 {
+		sb.WriteString(fmt.Sprintf("  Counted as risks (%d):\n", len(risks)))
+		for _, issue := range risks {
+			sb.WriteString("    - " + explainIssue(issue) + "\n")
+		}
+	} else if result.HasRisks {
+		// Each file is judged by its own config, which may fail on lower levels
+		sb.WriteString("  Risks come from a file whose nested config fails on lower severities\n")
+	}
+
+	if len(ignored) > 0 {
+		var types []audit.IssueType
+		for t := range ignored {
+			types = append(types, t)
+		}
+		sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+		sb.WriteString("  Not counted:\n")
+		for _, t := range types {
+			reason := "info, counted with --fail-on info"
+			if levels[t] == audit.SeverityWarning {
+				reason = "warning, counted with --strict"
+			}
+			sb.WriteString(fmt.Sprintf("    - %s: %d (%s)\n", issueTypeToString(t), ignored[t], reason))
+		}
+	}
+	return sb.String()
+}
+
+// explainIssue labels an issue as "type KEY (file:line)"
+func explainIssue(issue audit.Issue) string {
+	label := issueTypeToString(issue.Type) + " " + issue.Key
+	if issue.File != "" {
+		label += " (" + audit.Location{File: issue.File, Line: issue.Line}.String() + ")"
+	}
+	return label
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"env-audit/internal/audit"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *audit.Result
+		want   int
+	}{
+		{"clean", &audit.Result{}, 0},
+		{"risks", &audit.Result{HasRisks: true}, 1},
+		{"failed file", &audit.Result{HasRisks: true, Files: []audit.FileStatus{{Path: "a.env", Error: errors.New("boom")}}}, 2},
+	}
+	for _, tt := range tests {
+		if got := exitCode(tt.result); got != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestExplainExit(t *testing.T) {
+	result := &audit.Result{
+		Issues: []audit.Issue{
+			{Type: audit.IssueMissing, Key: "API_SECRET", File: ".env"},
+			{Type: audit.IssueLeak, Key: "GITHUB_TOKEN", File: ".env", Line: 9},
+			{Type: audit.IssueEmpty, Key: "PORT", File: ".env", Line: 2},
+			{Type: audit.IssueSensitive, Key: "GITHUB_TOKEN", File: ".env", Line: 9},
+		},
+		HasRisks: true,
+	}
+
+	got := explainExit(&Config{}, result, 1)
+	want := "Exit code 1: issues count as risks\n" +
+		"  Strict mode is off: errors count, warnings and info findings do not\n" +
+		"  Counted as risks (2):\n" +
+		"    - missing API_SECRET (.env)\n" +
+		"    - leak GITHUB_TOKEN (.env:9)\n" +
+		"  Not counted:\n" +
+		"    - empty: 1 (warning, counted with --strict)\n" +
+		"    - sensitive: 1 (info, counted with --fail-on info)\n"
+	if got != want {
+		t.Errorf("unexpected explanation:\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := explainExit(&Config{Strict: true}, result, 1); !strings.Contains(got, "Counted as risks (3):") || !strings.Contains(got, "empty PORT (.env:2)") {
+		t.Errorf("expected warnings to count in strict mode, got:\n%s", got)
+	}
+	if got := explainExit(&Config{FailOn: "info"}, result, 1); !strings.Contains(got, "Failing on info") || !strings.Contains(got, "Counted as risks (4):") {
+		t.Errorf("expected every finding to count with --fail-on info, got:\n%s", got)
+	}
+
+	result.Issues[1].Severity = audit.SeverityWarning
+	if got := explainExit(&Config{}, result, 1); !strings.Contains(got, "Counted as risks (1):") || !strings.Contains(got, "leak: 1 (warning, counted with --strict)") {
+		t.Errorf("expected a lowered leak to be tallied as a warning, got:\n%s", got)
+	}
+}
+
+func TestRun_ExplainExit(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+	os.WriteFile(envFile, []byte("EMPTY=\n"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	exitCode := Run([]string{"-f", envFile, "--explain-exit", "-q"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Errorf("expected exit 0, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "Exit code 0: no issue counts as a risk") || !strings.Contains(stderr.String(), "empty: 1 (warning, counted with --strict)") {
+		t.Errorf("unexpected explanation: %s", stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("expected the explanation on stderr only, got stdout: %s", stdout.String())
+	}
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"env-audit/internal/fsutil"
+	"env-audit/internal/parser"
+	"env-audit/internal/redact"
+)
+
+// fixConfig holds the arguments of the fix command
+type fixConfig struct {
+	Files          []string // files to fix, defaults to .env
+	DryRun         bool     // --dry-run print the changes as a diff instead of writing them
+	Sort           bool     // --sort sort keys within each blank-line separated block
+	AssertReadOnly bool     // --assert-read-only refuse to rewrite files
+	Backup         string   // --backup[=suffix] save originals as path+suffix before rewriting
+}
+
+// parseFixArgs parses the arguments following "fix"
+func parseFixArgs(args []string) (*fixConfig, error) {
+	cfg := &fixConfig{}
+	for _, arg := range args {
+		switch {
+		case arg == "--dry-run":
+			cfg.DryRun = true
+		case arg == "--sort":
+			cfg.Sort = true
+		case arg == "--assert-read-only":
+			cfg.AssertReadOnly = true
+		case arg == "--backup":
+			cfg.Backup = ".bak"
+		case strings.HasPrefix(arg, "--backup="):
+			if cfg.Backup = strings.TrimPrefix(arg, "--backup="); cfg.Backup == "" {
+				return nil, fmt.Errorf("empty suffix for --backup")
+			}
+		case strings.HasPrefix(arg, "-"):
+			return nil, fmt.Errorf("unknown argument: %s", arg)
+		default:
+			cfg.Files = append(cfg.Files, arg)
+		}
+	}
+	if len(cfg.Files) == 0 {
+		cfg.Files = []string{".env"}
+	}
+	if cfg.AssertReadOnly && !cfg.DryRun {
+		return nil, fmt.Errorf("--assert-read-only: fix would write to disk, use fix --dry-run")
+	}
+	return cfg, nil
+}
+
+// duplicateEntries returns the assignments fix drops: every one of a key
+// but the last, which is the one that takes effect
+func duplicateEntries(doc *parser.Document) []parser.DocEntry {
+	count := make(map[string]int)
+	for _, entry := range doc.Entries {
+		count[entry.Key]++
+	}
+	var dropped []parser.DocEntry
+	for _, entry := range doc.Entries {
+		if count[entry.Key] > 1 {
+			count[entry.Key]--
+			dropped = append(dropped, entry)
+		}
+	}
+	return dropped
+}
+
+// runFix repairs env files: duplicate keys are removed keeping the last
+// value, and the file is rewritten in the canonical style of fmt, which
+// trims whitespace and normalizes quoting. With --dry-run the changes are
+// printed as a unified diff, with sensitive values masked, and nothing is
+// written.
+func runFix(args []string, stdout, stderr io.Writer) int {
+	fcfg, err := parseFixArgs(args)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	opts := parser.CanonicalOptions{SortKeys: fcfg.Sort, Dedupe: true}
+	redactor := redact.Default()
+
+	for _, path := range fcfg.Files {
+		original, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+		doc, err := parser.ParseDocument(path)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+		fixed, err := parser.FormatCanonical(bytes.NewReader(original), opts)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+		if fixed == string(original) {
+			continue
+		}
+
+		for _, entry := range duplicateEntries(doc) {
+			fmt.Fprintf(stderr, "%s:%d: removing duplicate %s, a later assignment wins\n", path, entry.Line, redactor.Key(entry.Key))
+		}
+		if fcfg.DryRun {
+			fmt.Fprint(stdout, formatUnifiedDiff(path, string(original), fixed, redactor))
+			continue
+		}
+		if fcfg.Backup != "" && !backupFile(path, fcfg.Backup, stdout, stderr) {
+			return 2
+		}
+		if err := fsutil.WriteFileAtomic(path, []byte(fixed), 0644); err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+		fmt.Fprintln(stdout, "fixed", path)
+	}
+	return 0
+}
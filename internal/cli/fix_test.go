@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunFix(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, ".env")
+	original := "PORT=3000   \nNAME='demo'\n# production port\nPORT = 8080\n"
+	os.WriteFile(path, []byte(original), 0600)
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"fix", "--dry-run", path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "-PORT=3000   \n") || !strings.Contains(stdout.String(), "+PORT=8080\n") {
+		t.Errorf("expected a diff of the changes, got:\n%s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), path+":1: removing duplicate PORT") {
+		t.Errorf("expected the dropped duplicate reported, got %q", stderr.String())
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Errorf("expected --dry-run to leave the file alone, got %q", data)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := Run([]string{"fix", "--sort", path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if data, _ := os.ReadFile(path); string(data) != "NAME=demo\n# production port\nPORT=8080\n" {
+		t.Errorf("unexpected fixed file %q", data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("expected the file to keep mode 0600, got %o", info.Mode().Perm())
+	}
+	if stdout.String() != "fixed "+path+"\n" {
+		t.Errorf("unexpected output %q", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := Run([]string{"fix", path}, &stdout, &stderr); code != 0 || stdout.Len() != 0 {
+		t.Errorf("expected a fixed file to be left alone, got %d: %q", code, stdout.String())
+	}
+}
+
+func TestRunFix_Backup(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, ".env")
+	original := "PORT=3000\nPORT=8080\n"
+	os.WriteFile(path, []byte(original), 0600)
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"fix", "--backup", path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if data, _ := os.ReadFile(path + ".bak"); string(data) != original {
+		t.Errorf("expected the original saved as .bak, got %q", data)
+	}
+	if info, err := os.Stat(path + ".bak"); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("expected the backup to keep mode 0600, got %v, %v", info, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "PORT=8080\n" {
+		t.Errorf("unexpected fixed file %q", data)
+	}
+	if !strings.Contains(stdout.String(), "Backed up "+path+" to "+path+".bak") {
+		t.Errorf("expected the backup reported, got %q", stdout.String())
+	}
+
+	os.WriteFile(path, []byte(original), 0600)
+	if code := Run([]string{"fix", "--backup=.orig", path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if data, _ := os.ReadFile(path + ".orig"); string(data) != original {
+		t.Errorf("expected the original saved with the custom suffix, got %q", data)
+	}
+}
+
+func TestParseFixArgs_Invalid(t *testing.T) {
+	for _, args := range [][]string{{"--bogus"}, {"--assert-read-only", ".env"}, {"--backup="}} {
+		if _, err := parseFixArgs(args); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+	if _, err := parseFixArgs([]string{"--assert-read-only", "--dry-run"}); err != nil {
+		t.Errorf("expected --dry-run to be allowed read-only, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"env-audit/internal/parser"
+	"env-audit/internal/redact"
+)
+
+// defaultFlowEnv is the --flow environment when neither the flag nor
+// NODE_ENV names one, as in dotenv-flow
+const defaultFlowEnv = "development"
+
+// flowFiles returns the dotenv-flow cascade of base for env, lowest
+// precedence first: .env, .env.local, .env.<env>, .env.<env>.local. Tests
+// must be reproducible, so the test environment skips .env.local.
+func flowFiles(base, env string) []string {
+	files := []string{base}
+	if env != "test" {
+		files = append(files, base+".local")
+	}
+	return append(files, base+"."+env, base+"."+env+".local")
+}
+
+// resolveFlow replaces the input file with the files of its dotenv-flow
+// cascade that exist, merged like --merge
+func resolveFlow(cfg *Config) error {
+	base := cfg.FilePath
+	if base == "" {
+		base = ".env"
+	}
+	env := cfg.FlowEnv
+	if env == "" {
+		env = os.Getenv("NODE_ENV")
+	}
+	if env == "" {
+		env = defaultFlowEnv
+	}
+
+	candidates := flowFiles(base, env)
+	var files []string
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("--flow: none of %s exist", strings.Join(candidates, ", "))
+	}
+	cfg.FlowEnv = env
+	cfg.Files = files
+	cfg.FilePath = files[0]
+	cfg.Merge = true
+	return nil
+}
+
+// runMergedDump prints the effective entries of layered files, each with
+// the file and line its value came from
+func runMergedDump(cfg *Config, redactor *redact.Redactor, stdout, stderr io.Writer) int {
+	merged, err := parser.MergeFilesWith(cfg.InputFiles(), cfg.parseOptions())
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	if cfg.Quiet {
+		return 0
+	}
+	for _, entry := range parser.SortedEntries(merged.Entries) {
+		line := redactor.Key(entry.Key) + "=" + redactor.Value(entry.Key, entry.Value)
+		if cfg.Shell {
+			line = "export " + redactor.Key(entry.Key) + "=" + parser.ShellQuote(redactor.Value(entry.Key, entry.Value))
+		}
+		fmt.Fprintf(stdout, "%s  # %s\n", line, merged.Locations[entry.Key])
+	}
+	return 0
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFlowFiles(t *testing.T) {
+	got := flowFiles(".env", "production")
+	want := []string{".env", ".env.local", ".env.production", ".env.production.local"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("flowFiles = %v, want %v", got, want)
+	}
+	if got := flowFiles("app.env", "test"); !reflect.DeepEqual(got, []string{"app.env", "app.env.test", "app.env.test.local"}) {
+		t.Errorf("expected the test environment to skip .local, got %v", got)
+	}
+}
+
+func writeFlow(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmpDir, ".env"), []byte("PORT=3000\nAPI_URL=http://localhost\nDEBUG=\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, ".env.local"), []byte("DEBUG=1\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, ".env.production"), []byte("API_URL=https://api.example.com\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, ".env.production.local"), []byte("PORT=8080\n"), 0644)
+	return tmpDir
+}
+
+func TestResolveFlow(t *testing.T) {
+	dir := writeFlow(t)
+	base := filepath.Join(dir, ".env")
+	t.Setenv("NODE_ENV", "production")
+
+	cfg := &Config{FilePath: base, Files: []string{base}, Flow: true}
+	if err := resolveFlow(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{base, base + ".local", base + ".production", base + ".production.local"}
+	if !reflect.DeepEqual(cfg.Files, want) || !cfg.Merge || cfg.FlowEnv != "production" {
+		t.Errorf("unexpected cascade: %+v", cfg)
+	}
+
+	// Files that don't exist are skipped
+	cfg = &Config{FilePath: base, Files: []string{base}, Flow: true, FlowEnv: "staging"}
+	resolveFlow(cfg)
+	if !reflect.DeepEqual(cfg.Files, []string{base, base + ".local"}) {
+		t.Errorf("expected only existing files, got %v", cfg.Files)
+	}
+
+	t.Setenv("NODE_ENV", "")
+	cfg = &Config{FilePath: filepath.Join(dir, "missing.env"), Flow: true}
+	if err := resolveFlow(cfg); err == nil || !strings.Contains(err.Error(), "missing.env.development.local") {
+		t.Errorf("expected an error naming the development cascade, got %v", err)
+	}
+}
+
+func TestRun_Flow(t *testing.T) {
+	dir := writeFlow(t)
+	base := filepath.Join(dir, ".env")
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"-f", base, "--flow=production", "--dump", "--shell"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d: %s", code, stderr.String())
+	}
+	want := "export API_URL=https://api.example.com  # " + base + ".production:1\n" +
+		"export DEBUG=1  # " + base + ".local:1\n" +
+		"export PORT=8080  # " + base + ".production.local:1\n"
+	if stdout.String() != want {
+		t.Errorf("unexpected dump:\n%s\nwant\n%s", stdout.String(), want)
+	}
+
+	stdout.Reset()
+	if code := Run([]string{"-f", base, "--flow=production", "--json", "--required", "SECRET"}, &stdout, &stderr); code != 1 {
+		t.Errorf("expected the merged cascade to be audited, got exit %d", code)
+	}
+	if strings.Contains(stdout.String(), `"type":"empty"`) {
+		t.Errorf("expected DEBUG to be set by .env.local, got:\n%s", stdout.String())
+	}
+
+	if _, err := ParseArgs([]string{"--flow", "-f", "a", "-f", "b"}); err == nil {
+		t.Error("expected --flow to take a single base file")
+	}
+}
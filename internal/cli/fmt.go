@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"env-audit/internal/fsutil"
+	"env-audit/internal/parser"
+)
+
+// fmtConfig holds the arguments of the fmt command
+type fmtConfig struct {
+	Files          []string // files to format, defaults to .env; "-" formats stdin to stdout
+	Check          bool     // --check list unformatted files and exit 1 instead of rewriting them
+	Sort           bool     // --sort sort keys within each blank-line separated block
+	AssertReadOnly bool     // --assert-read-only refuse to rewrite files
+	Backup         string   // --backup[=suffix] save originals as path+suffix before rewriting
+}
+
+// parseFmtArgs parses the arguments following "fmt"
+func parseFmtArgs(args []string) (*fmtConfig, error) {
+	cfg := &fmtConfig{}
+	for _, arg := range args {
+		switch {
+		case arg == "--check":
+			cfg.Check = true
+		case arg == "--sort":
+			cfg.Sort = true
+		case arg == "--assert-read-only":
+			cfg.AssertReadOnly = true
+		case arg == "--backup":
+			cfg.Backup = ".bak"
+		case strings.HasPrefix(arg, "--backup="):
+			if cfg.Backup = strings.TrimPrefix(arg, "--backup="); cfg.Backup == "" {
+				return nil, fmt.Errorf("empty suffix for --backup")
+			}
+		case strings.HasPrefix(arg, "-") && arg != "-":
+			return nil, fmt.Errorf("unknown argument: %s", arg)
+		default:
+			cfg.Files = append(cfg.Files, arg)
+		}
+	}
+	if len(cfg.Files) == 0 {
+		cfg.Files = []string{".env"}
+	}
+	if cfg.AssertReadOnly && !cfg.Check {
+		return nil, fmt.Errorf("--assert-read-only: fmt would write to disk, use fmt --check")
+	}
+	return cfg, nil
+}
+
+// runFmt rewrites env files in the canonical style of parser.FormatCanonical.
+// With --check nothing is written: files that would change are listed and
+// the exit code is 1.
+func runFmt(args []string, stdout, stderr io.Writer) int {
+	fcfg, err := parseFmtArgs(args)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	opts := parser.CanonicalOptions{SortKeys: fcfg.Sort}
+
+	code := 0
+	for _, path := range fcfg.Files {
+		if path == "-" {
+			formatted, err := parser.FormatCanonical(stdin, opts)
+			if err != nil {
+				fmt.Fprintln(stderr, "Error:", err)
+				return 2
+			}
+			fmt.Fprint(stdout, formatted)
+			continue
+		}
+
+		original, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+		formatted, err := parser.FormatCanonical(bytes.NewReader(original), opts)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+		if formatted == string(original) {
+			continue
+		}
+		if fcfg.Check {
+			fmt.Fprintln(stdout, path)
+			code = 1
+			continue
+		}
+		if fcfg.Backup != "" && !backupFile(path, fcfg.Backup, stdout, stderr) {
+			return 2
+		}
+		if err := fsutil.WriteFileAtomic(path, []byte(formatted), 0644); err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+	}
+	return code
+}
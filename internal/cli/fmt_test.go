@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunFmt(t *testing.T) {
+	tmpDir := t.TempDir()
+	messy := filepath.Join(tmpDir, ".env")
+	os.WriteFile(messy, []byte("PORT = 8080\n\n\nNAME='demo'"), 0600)
+	tidy := filepath.Join(tmpDir, ".env.example")
+	os.WriteFile(tidy, []byte("PORT=\n"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"fmt", "--check", messy, tidy}, &stdout, &stderr); code != 1 {
+		t.Errorf("expected --check to exit 1, got %d (stderr: %s)", code, stderr.String())
+	}
+	if stdout.String() != messy+"\n" {
+		t.Errorf("expected only the unformatted file to be listed, got %q", stdout.String())
+	}
+	if data, _ := os.ReadFile(messy); string(data) != "PORT = 8080\n\n\nNAME='demo'" {
+		t.Errorf("expected --check to leave the file alone, got %q", data)
+	}
+
+	stdout.Reset()
+	if code := Run([]string{"fmt", messy, tidy}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if data, _ := os.ReadFile(messy); string(data) != "PORT=8080\n\nNAME=demo\n" {
+		t.Errorf("unexpected formatted file %q", data)
+	}
+	if info, _ := os.Stat(messy); info.Mode().Perm() != 0600 {
+		t.Errorf("expected the file to keep mode 0600, got %o", info.Mode().Perm())
+	}
+
+	if code := Run([]string{"fmt", "--check", messy, tidy}, &stdout, &stderr); code != 0 {
+		t.Errorf("expected formatted files to pass --check, got %d", code)
+	}
+}
+
+func TestRunFmt_Stdin(t *testing.T) {
+	orig := stdin
+	stdin = strings.NewReader("B=2\nA = 1\n")
+	t.Cleanup(func() { stdin = orig })
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"fmt", "--sort", "-"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if stdout.String() != "A=1\nB=2\n" {
+		t.Errorf("unexpected output %q", stdout.String())
+	}
+}
+
+func TestRunFmt_Backup(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, ".env")
+	os.WriteFile(path, []byte("PORT = 3000\n"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"fmt", "--backup", path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if data, _ := os.ReadFile(path + ".bak"); string(data) != "PORT = 3000\n" {
+		t.Errorf("expected the original saved as .bak, got %q", data)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "PORT=3000\n" {
+		t.Errorf("unexpected formatted file %q", data)
+	}
+}
+
+func TestParseFmtArgs_Invalid(t *testing.T) {
+	for _, args := range [][]string{{"--bogus"}, {"--assert-read-only", ".env"}, {"--backup="}} {
+		if _, err := parseFmtArgs(args); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+	if _, err := parseFmtArgs([]string{"--assert-read-only", "--check"}); err != nil {
+		t.Errorf("expected --check to be allowed under --assert-read-only, got %v", err)
+	}
+}
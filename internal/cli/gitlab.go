@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"env-audit/internal/audit"
+	"env-audit/internal/redact"
+)
+
+// defaultCodeQualityReport is where --gitlab writes its Code Quality report
+const defaultCodeQualityReport = "gl-code-quality-report.json"
+
+// GitLabFormatter renders the report as collapsible GitLab CI job log
+// sections, one per issue group
+type GitLabFormatter struct {
+	Redactor *redact.Redactor // masks sensitive values, defaults to redact.Default()
+	Now      func() time.Time // section timestamps, defaults to time.Now
+}
+
+// codeQualityIssue is one entry of a GitLab Code Quality report
+// (artifacts:reports:codequality), a subset of the Code Climate format
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string `json:"path"`
+	Lines struct {
+		Begin int `json:"begin"`
+	} `json:"lines"`
+}
+
+// Format implements Formatter interface for GitLabFormatter
+func (f *GitLabFormatter) Format(result *audit.Result) string {
+	if result == nil || (len(result.Issues) == 0 && result.Failed() == 0) {
+		return "env-audit: no issues found\n"
+	}
+
+	now := time.Now
+	if f.Now != nil {
+		now = f.Now
+	}
+	redactor := f.Redactor
+	if redactor == nil {
+		redactor = redact.Default()
+	}
+	opts := textOptions{Redactor: redactor}
+
+	groups := make(map[audit.IssueType][]audit.Issue)
+	for _, issue := range result.Issues {
+		groups[issue.Type] = append(groups[issue.Type], issue)
+	}
+
+	var sb strings.Builder
+	for _, t := range issueTypeOrder {
+		issues := groups[t]
+		if len(issues) == 0 {
+			continue
+		}
+		name := "env_audit_" + issueTypeToString(t)
+		// Informational groups start collapsed so risks stand out
+		collapsed := ""
+		if t.IsInfo() {
+			collapsed = "[collapsed=true]"
+		}
+		sb.WriteString(fmt.Sprintf("\033[0Ksection_start:%d:%s%s\r\033[0K%s (%d)\n", now().Unix(), name, collapsed, issueTypeName(t), len(issues)))
+		for _, issue := range issues {
+			sb.WriteString(formatIssueLine(issue, true, opts))
+		}
+		sb.WriteString(fmt.Sprintf("\033[0Ksection_end:%d:%s\r\033[0K\n", now().Unix(), name))
+	}
+
+	sb.WriteString(formatFileStatuses(result))
+	sb.WriteString(formatSummaryLine(result))
+	return sb.String()
+}
+
+// CodeQuality renders result as a GitLab Code Quality report
+func (f *GitLabFormatter) CodeQuality(result *audit.Result) ([]byte, error) {
+	issues := []codeQualityIssue{}
+	if result != nil {
+		for _, file := range result.Files {
+			if file.Error != nil {
+				issues = append(issues, newCodeQualityIssue("scan_error", "", "failed to scan: "+file.Error.Error(), "blocker", file.Path, 1))
+			}
+		}
+		for _, issue := range result.Issues {
+			description := issue.Key + ": " + issue.Message
+			issues = append(issues, newCodeQualityIssue(issueTypeToString(issue.Type), issue.Key, description, codeQualitySeverity(issue), issue.File, issue.Line))
+		}
+	}
+	return json.MarshalIndent(issues, "", "  ")
+}
+
+// newCodeQualityIssue builds a report entry. GitLab requires a path and a
+// line, so issues without a file point at the environment as a whole.
+func newCodeQualityIssue(check, key, description, severity, path string, line int) codeQualityIssue {
+	if path == "" {
+		path = "environment"
+	}
+	if line < 1 {
+		line = 1
+	}
+	issue := codeQualityIssue{
+		Description: description,
+		CheckName:   "env-audit/" + check,
+		Fingerprint: audit.Fingerprint(check, key, path),
+		Severity:    severity,
+		Location:    codeQualityLocation{Path: path},
+	}
+	issue.Location.Lines.Begin = line
+	return issue
+}
+
+// codeQualitySeverity maps issues onto Code Quality severities, by the level
+// a severity policy set or else by type
+func codeQualitySeverity(issue audit.Issue) string {
+	t := issue.Type
+	switch {
+	case issue.Severity != "":
+		return map[string]string{audit.SeverityError: "major", audit.SeverityWarning: "minor", audit.SeverityInfo: "info"}[issue.Severity]
+	case t.IsSecret():
+		return "critical"
+	case t == audit.IssueMissing || t == audit.IssueDuplicate || t == audit.IssueInvalid || t == audit.IssueLimit || t == audit.IssuePlatform:
+		return "major"
+	case t.IsInfo():
+		return "info"
+	default:
+		return "minor"
+	}
+}
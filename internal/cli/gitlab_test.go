@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"env-audit/internal/audit"
+)
+
+func TestGitLabFormatter_Sections(t *testing.T) {
+	result := &audit.Result{
+		Issues: []audit.Issue{
+			{Type: audit.IssueMissing, Key: "API_KEY", Message: "required variable is missing", File: ".env"},
+			{Type: audit.IssueSensitive, Key: "DB_PASSWORD", Message: "sensitive key detected", File: ".env", Line: 2},
+		},
+		HasRisks: true,
+	}
+	f := &GitLabFormatter{Now: func() time.Time { return time.Unix(1700000000, 0) }}
+	output := f.Format(result)
+
+	for _, want := range []string{
+		"\033[0Ksection_start:1700000000:env_audit_missing\r\033[0KMissing Required (1)\n  - API_KEY (.env)\n",
+		"\033[0Ksection_end:1700000000:env_audit_missing\r\033[0K\n",
+		"section_start:1700000000:env_audit_sensitive[collapsed=true]",
+		"Summary: 2 issues found",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%q", want, output)
+		}
+	}
+}
+
+func TestGitLabFormatter_CodeQuality(t *testing.T) {
+	result := &audit.Result{
+		Issues: []audit.Issue{
+			{Type: audit.IssueLeak, Key: "TOKEN", Message: "GitHub token detected", File: "app/.env", Line: 4},
+			{Type: audit.IssueMissing, Key: "API_KEY", Message: "required variable is missing"},
+		},
+	}
+	data, err := (&GitLabFormatter{}).CodeQuality(result)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var issues []codeQualityIssue
+	if err := json.Unmarshal(data, &issues); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d", len(issues))
+	}
+	leak := issues[0]
+	if leak.CheckName != "env-audit/leak" || leak.Severity != "critical" || leak.Location.Path != "app/.env" || leak.Location.Lines.Begin != 4 {
+		t.Errorf("unexpected leak entry: %+v", leak)
+	}
+	if issues[1].Location.Path != "environment" || issues[1].Location.Lines.Begin != 1 || issues[1].Severity != "major" {
+		t.Errorf("expected file-less issue to point at the environment, got %+v", issues[1])
+	}
+
+	again, _ := (&GitLabFormatter{}).CodeQuality(result)
+	if !bytes.Equal(data, again) {
+		t.Error("expected stable fingerprints across runs")
+	}
+}
+
+func TestRun_GitLabWritesCodeQualityReport(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+	reportFile := filepath.Join(tmpDir, "cq.json")
+	os.WriteFile(envFile, []byte("EMPTY=\n"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	exitCode := Run([]string{"-f", envFile, "--gitlab", "--gitlab-report", reportFile, "-q"}, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Errorf("expected exit 0, got %d: %s", exitCode, stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("expected no stdout with -q, got: %s", stdout.String())
+	}
+	data, err := os.ReadFile(reportFile)
+	if err != nil {
+		t.Fatalf("expected report to be written: %v", err)
+	}
+	if !strings.Contains(string(data), `"check_name": "env-audit/empty"`) {
+		t.Errorf("unexpected report: %s", data)
+	}
+}
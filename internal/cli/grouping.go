@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"env-audit/internal/audit"
+	"env-audit/internal/i18n"
+)
+
+// Report layouts selectable with --group-by
+const (
+	groupByType   = "type"
+	groupByPrefix = "prefix"
+	groupByFile   = "file"
+)
+
+// prefixGroup is a cluster of keys sharing a namespace prefix such as
+// "AWS_". The group with an empty prefix collects the remaining keys.
+type prefixGroup struct {
+	Prefix string
+	Keys   []string
+}
+
+// label names the group in reports, e.g. "AWS_*"
+func (g prefixGroup) label() string {
+	if g.Prefix == "" {
+		return i18n.T("Other")
+	}
+	return g.Prefix + "*"
+}
+
+// keyPrefix returns the namespace of key: everything up to and including
+// its first underscore, or "" if it has none
+func keyPrefix(key string) string {
+	if i := strings.Index(key, "_"); i > 0 {
+		return key[:i+1]
+	}
+	return ""
+}
+
+// groupKeysByPrefix clusters keys by prefix, sorted by prefix with the other
+// group last; keys keep their order within a group. A prefix used by a
+// single key is no namespace, so that key joins the other group.
+func groupKeysByPrefix(keys []string) []prefixGroup {
+	byPrefix := make(map[string][]string)
+	for _, key := range keys {
+		prefix := keyPrefix(key)
+		byPrefix[prefix] = append(byPrefix[prefix], key)
+	}
+	var other []string
+	var groups []prefixGroup
+	for prefix, members := range byPrefix {
+		if prefix == "" || len(members) < 2 {
+			other = append(other, members...)
+			continue
+		}
+		groups = append(groups, prefixGroup{Prefix: prefix, Keys: members})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Prefix < groups[j].Prefix })
+	if len(other) > 0 {
+		// Restore the original order of keys merged from several prefixes
+		rank := make(map[string]int, len(keys))
+		for i, key := range keys {
+			rank[key] = i
+		}
+		sort.SliceStable(other, func(i, j int) bool { return rank[other[i]] < rank[other[j]] })
+		groups = append(groups, prefixGroup{Keys: other})
+	}
+	return groups
+}
+
+// writePrefixGroups writes issues clustered by key prefix, each cluster
+// listing its issues under their type headings, until --max-display issues
+// are listed. It returns the number of issues listed.
+func writePrefixGroups(sb *strings.Builder, issues []audit.Issue, multiFile bool, opts textOptions) int {
+	byKey := make(map[string][]audit.Issue)
+	var keys []string
+	for _, issue := range issues {
+		if _, seen := byKey[issue.Key]; !seen {
+			keys = append(keys, issue.Key)
+		}
+		byKey[issue.Key] = append(byKey[issue.Key], issue)
+	}
+
+	shown := 0
+	for _, group := range groupKeysByPrefix(keys) {
+		if opts.MaxDisplay > 0 && shown >= opts.MaxDisplay {
+			break
+		}
+		var grouped []audit.Issue
+		for _, key := range group.Keys {
+			grouped = append(grouped, byKey[key]...)
+		}
+		sb.WriteString(fmt.Sprintf("\n%s (%d):\n", group.label(), len(grouped)))
+		limit := 0
+		if opts.MaxDisplay > 0 {
+			limit = opts.MaxDisplay - shown
+		}
+		shown += writeTypeGroups(sb, grouped, "  ", multiFile, limit, opts)
+	}
+	return shown
+}
+
+// writeFileGroups writes one section per file, each listing the file's
+// issues under their type headings, until --max-display issues are listed.
+// Files come in scan order; issues of no file, such as those of the process
+// environment, come last. It returns the number of issues listed.
+func writeFileGroups(sb *strings.Builder, result *audit.Result, opts textOptions) int {
+	byFile := make(map[string][]audit.Issue)
+	var files []string
+	for _, file := range result.Files {
+		files = append(files, file.Path)
+		byFile[file.Path] = nil
+	}
+	for _, issue := range result.Issues {
+		if _, seen := byFile[issue.File]; !seen {
+			files = append(files, issue.File)
+		}
+		byFile[issue.File] = append(byFile[issue.File], issue)
+	}
+	// The environment has no path, so its section goes last
+	sort.SliceStable(files, func(i, j int) bool { return files[i] != "" && files[j] == "" })
+
+	shown := 0
+	for _, file := range files {
+		issues := byFile[file]
+		if len(issues) == 0 {
+			continue
+		}
+		if opts.MaxDisplay > 0 && shown >= opts.MaxDisplay {
+			break
+		}
+		label := file
+		if label == "" {
+			label = i18n.T("Environment")
+		}
+		sb.WriteString(fmt.Sprintf("\n%s (%d):\n", label, len(issues)))
+		limit := 0
+		if opts.MaxDisplay > 0 {
+			limit = opts.MaxDisplay - shown
+		}
+		shown += writeTypeGroups(sb, issues, "  ", false, limit, opts)
+	}
+	return shown
+}
+
+// indentLines prefixes every line of text with indent
+func indentLines(text, indent string) string {
+	if indent == "" {
+		return text
+	}
+	lines := strings.SplitAfter(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+	return strings.Join(lines, "")
+}
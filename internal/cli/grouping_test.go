@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"env-audit/internal/audit"
+	"env-audit/internal/redact"
+)
+
+func TestGroupKeysByPrefix(t *testing.T) {
+	groups := groupKeysByPrefix([]string{"DB_USER", "PORT", "AWS_SECRET", "DB_HOST", "AWS_REGION", "SMTP_HOST", "_HIDDEN"})
+
+	want := []prefixGroup{
+		{Prefix: "AWS_", Keys: []string{"AWS_SECRET", "AWS_REGION"}},
+		{Prefix: "DB_", Keys: []string{"DB_USER", "DB_HOST"}},
+		{Keys: []string{"PORT", "SMTP_HOST", "_HIDDEN"}},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("unexpected groups:\n%+v\nwant:\n%+v", groups, want)
+	}
+	if groups[0].label() != "AWS_*" || groups[2].label() != "Other" {
+		t.Errorf("unexpected labels %q, %q", groups[0].label(), groups[2].label())
+	}
+}
+
+func TestFormatSummary_GroupByPrefix(t *testing.T) {
+	result := &audit.Result{Issues: []audit.Issue{
+		{Type: audit.IssueEmpty, Key: "DB_HOST", File: ".env", Line: 2},
+		{Type: audit.IssueLeak, Key: "AWS_SECRET", Message: "potential secret detected (high entropy)", File: ".env", Line: 5},
+		{Type: audit.IssueEmpty, Key: "AWS_REGION", File: ".env", Line: 4},
+		{Type: audit.IssueEmpty, Key: "DB_USER", File: ".env", Line: 1},
+		{Type: audit.IssueEmpty, Key: "PORT", File: ".env", Line: 3},
+	}}
+
+	output := formatSummary(result, textOptions{Redactor: redact.Default(), GroupBy: groupByPrefix})
+
+	want := "\nAWS_* (2):\n" +
+		"\n  Empty Values (1):\n    - AWS_REGION (line 4)\n" +
+		"\n  Potential Leaks (1):\n    - AWS_SECRET (line 5): potential secret detected (high entropy)\n" +
+		"\nDB_* (2):\n" +
+		"\n  Empty Values (2):\n    - DB_HOST (line 2)\n    - DB_USER (line 1)\n" +
+		"\nOther (1):\n" +
+		"\n  Empty Values (1):\n    - PORT (line 3)\n"
+	if !strings.Contains(output, want) {
+		t.Errorf("unexpected report:\n%s\nwant to contain:\n%s", output, want)
+	}
+
+	limited := formatSummary(result, textOptions{Redactor: redact.Default(), GroupBy: groupByPrefix, MaxDisplay: 3})
+	if strings.Contains(limited, "Other") || !strings.Contains(limited, "and 2 more…") {
+		t.Errorf("expected --max-display to cut across groups, got:\n%s", limited)
+	}
+}
+
+func TestRun_DocsGroupByPrefix(t *testing.T) {
+	exampleFile := filepath.Join(t.TempDir(), ".env.example")
+	os.WriteFile(exampleFile, []byte("# Region\nAWS_REGION=\nAWS_SECRET=\nPORT=\n"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"-f", exampleFile, "--docs", "--group-by", "prefix"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d: %s", code, stderr.String())
+	}
+	header := "| Variable | Description | Required | Sensitive | Example |\n| --- | --- | --- | --- | --- |\n"
+	want := "### AWS_*\n\n" + header + "| `AWS_REGION` | Region |  |  |  |\n| `AWS_SECRET` |  |  | yes |  |\n" +
+		"\n### Other\n\n" + header + "| `PORT` |  |  |  |  |\n"
+	if stdout.String() != want {
+		t.Errorf("unexpected docs:\n%s\nwant:\n%s", stdout.String(), want)
+	}
+
+	if code := Run([]string{"-f", exampleFile, "--group-by", "size"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected an unknown layout to be rejected, got %d", code)
+	}
+}
+
+func TestFormatSummary_GroupByFile(t *testing.T) {
+	result := &audit.Result{
+		Issues: []audit.Issue{
+			{Type: audit.IssueEmpty, Key: "API_URL", File: "api/.env", Line: 2},
+			{Type: audit.IssueMissing, Key: "DEBUG"},
+			{Type: audit.IssueEmpty, Key: "QUEUE", File: "worker/.env", Line: 1},
+			{Type: audit.IssueLeak, Key: "TOKEN", Message: "potential secret detected (high entropy)", File: "api/.env", Line: 3},
+		},
+		Files: []audit.FileStatus{{Path: "worker/.env"}, {Path: "api/.env"}, {Path: "web/.env"}},
+	}
+
+	output := formatSummary(result, textOptions{Redactor: redact.Default(), GroupBy: groupByFile})
+
+	want := "\nworker/.env (1):\n" +
+		"\n  Empty Values (1):\n    - QUEUE (line 1)\n" +
+		"\napi/.env (2):\n" +
+		"\n  Empty Values (1):\n    - API_URL (line 2)\n" +
+		"\n  Potential Leaks (1):\n    - TOKEN (line 3): potential secret detected (high entropy)\n" +
+		"\nEnvironment (1):\n" +
+		"\n  Missing Required (1):\n    - DEBUG\n"
+	if !strings.Contains(output, want) {
+		t.Errorf("unexpected report:\n%s\nwant to contain:\n%s", output, want)
+	}
+	if strings.Contains(output, "web/.env (") {
+		t.Errorf("expected files without issues to have no section, got:\n%s", output)
+	}
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"env-audit/internal/parser"
+	"env-audit/internal/redact"
+)
+
+// lintConfig holds the arguments of the lint command
+type lintConfig struct {
+	Files    []string // files to lint, defaults to .env
+	Dialects []string // --dialects dotenv implementations to compare, defaults to all
+}
+
+// parseLintArgs parses the arguments following "lint"
+func parseLintArgs(args []string) (*lintConfig, error) {
+	cfg := &lintConfig{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--dialects":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("--dialects requires a value")
+			}
+			i++
+			cfg.Dialects = parseCommaSeparated(args[i])
+		case strings.HasPrefix(arg, "--dialects="):
+			cfg.Dialects = parseCommaSeparated(strings.TrimPrefix(arg, "--dialects="))
+		case strings.HasPrefix(arg, "-"):
+			return nil, fmt.Errorf("unknown argument: %s", arg)
+		default:
+			cfg.Files = append(cfg.Files, arg)
+		}
+	}
+	if len(cfg.Files) == 0 {
+		cfg.Files = []string{".env"}
+	}
+	if cfg.Dialects == nil {
+		cfg.Dialects = parser.Dialects
+	}
+	for _, d := range cfg.Dialects {
+		if !parser.IsDialect(d) {
+			return nil, fmt.Errorf("--dialects: unknown dialect %q (use %s)", d, strings.Join(parser.Dialects, ", "))
+		}
+	}
+	if len(cfg.Dialects) < 2 {
+		return nil, fmt.Errorf("--dialects needs at least two dialects to compare")
+	}
+	return cfg, nil
+}
+
+// runLint reports entries whose value loads differently under the dotenv
+// implementations of different languages. The exit code is 1 if any does.
+func runLint(args []string, stdout, stderr io.Writer) int {
+	lcfg, err := parseLintArgs(args)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	redactor := redact.Default()
+
+	code := 0
+	for _, path := range lcfg.Files {
+		doc, err := parser.ParseDocument(path)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+		values := make([][]string, len(lcfg.Dialects))
+		for i, d := range lcfg.Dialects {
+			values[i] = doc.DialectValues(d)
+		}
+
+		for e, entry := range doc.Entries {
+			// Group the dialects by the value they load, in first-seen order
+			var distinct []string
+			byValue := make(map[string][]string)
+			for i, d := range lcfg.Dialects {
+				v := values[i][e]
+				if _, ok := byValue[v]; !ok {
+					distinct = append(distinct, v)
+				}
+				byValue[v] = append(byValue[v], d)
+			}
+			if len(distinct) == 1 {
+				continue
+			}
+
+			code = 1
+			fmt.Fprintf(stdout, "%s:%d %s: value differs between dotenv dialects\n", path, entry.Line, redactor.Key(entry.Key))
+			for _, v := range distinct {
+				fmt.Fprintf(stdout, "  %s: %s\n", strings.Join(byValue[v], ", "), strconv.Quote(redactor.Value(entry.Key, v)))
+			}
+		}
+	}
+	return code
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunLint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	os.WriteFile(path, []byte("PORT=8080\nCOLOR=blue#1\nAPI_KEY=abc#def\n"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"lint", path}, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected exit 1, got %d (stderr: %s)", code, stderr.String())
+	}
+	out := stdout.String()
+	if !strings.Contains(out, path+":2 COLOR: value differs between dotenv dialects\n  node, ruby: \"blue\"\n  python, go: \"blue#1\"\n") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if strings.Contains(out, "PORT") || strings.Contains(out, "abc") {
+		t.Errorf("expected only differing entries with redacted values, got:\n%s", out)
+	}
+
+	stdout.Reset()
+	if code := Run([]string{"lint", "--dialects", "node,ruby", path}, &stdout, &stderr); code != 0 {
+		t.Errorf("expected node and ruby to agree, got %d:\n%s", code, stdout.String())
+	}
+}
+
+func TestParseLintArgs_Invalid(t *testing.T) {
+	for _, args := range [][]string{{"--bogus"}, {"--dialects"}, {"--dialects", "node,php"}, {"--dialects=go"}} {
+		if _, err := parseLintArgs(args); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// newLogger builds the diagnostic logger for --log-level and --log-format.
+// Logs go to w (stderr), never to the report stream. The default level,
+// warn, keeps normal runs silent.
+func newLogger(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	switch level {
+	case "", "warn":
+		lvl = slog.LevelWarn
+	case "info":
+		lvl = slog.LevelInfo
+	case "debug":
+		lvl = slog.LevelDebug
+	default:
+		return nil, fmt.Errorf("unknown log level: %s (expected debug, info or warn)", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch format {
+	case "", "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format: %s (expected text or json)", format)
+	}
+}
+
+// discardLogger drops every record; used when no logger was configured
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError + 1}))
+
+// logger returns the run's diagnostic logger, or one that discards everything
+func (cfg *Config) logger() *slog.Logger {
+	if cfg.log == nil {
+		return discardLogger
+	}
+	return cfg.log
+}
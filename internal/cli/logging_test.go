@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewLogger_Levels(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := newLogger(&buf, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Info("hidden")
+	log.Warn("shown")
+	if strings.Contains(buf.String(), "hidden") || !strings.Contains(buf.String(), "shown") {
+		t.Errorf("expected warn as the default level, got: %s", buf.String())
+	}
+
+	if _, err := newLogger(&buf, "trace", ""); err == nil {
+		t.Error("expected error for unknown level")
+	}
+	if _, err := newLogger(&buf, "info", "xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestConfigLogger_DefaultsToDiscard(t *testing.T) {
+	cfg := &Config{}
+	cfg.logger().Error("nowhere") // must not panic
+}
+
+func TestRun_LogLevelDebugJSON(t *testing.T) {
+	envPath := filepath.Join(t.TempDir(), ".env")
+	os.WriteFile(envPath, []byte("PORT=3000\n"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	exitCode := Run([]string{"-f", envPath, "--json", "--log-level", "debug", "--log-format", "json"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit 0, got %d: %s", exitCode, stderr.String())
+	}
+
+	var report map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("logs polluted the report: %v\n%s", err, stdout.String())
+	}
+
+	var sawChecks bool
+	for _, line := range strings.Split(strings.TrimSpace(stderr.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("expected JSON log lines, got %q", line)
+		}
+		if record["msg"] == "running checks" && record["file"] == envPath {
+			sawChecks = true
+		}
+	}
+	if !sawChecks {
+		t.Errorf("expected a debug record of the checks run, got: %s", stderr.String())
+	}
+}
+
+func TestRun_InvalidLogLevel(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if exitCode := Run([]string{"--log-level", "loud"}, &stdout, &stderr); exitCode != 2 {
+		t.Errorf("expected exit 2, got %d", exitCode)
+	}
+}
@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"env-audit/internal/audit"
+	"env-audit/internal/fsutil"
+	"env-audit/internal/parser"
+	"env-audit/internal/redact"
+)
+
+// Strategies of the merge command for a key the files give different values
+const (
+	strategyLastWins        = "last-wins"         // the last file's value, as with --merge
+	strategyFirstWins       = "first-wins"        // the first file's value
+	strategyErrorOnConflict = "error-on-conflict" // write nothing and exit 1
+	strategyInteractive     = "interactive"       // ask which value to keep
+)
+
+// mergeStrategies lists the strategies in the order usage shows them
+var mergeStrategies = []string{strategyLastWins, strategyFirstWins, strategyErrorOnConflict, strategyInteractive}
+
+// mergeConfig holds the arguments of the merge command
+type mergeConfig struct {
+	Files    []string // files to merge, lowest precedence first
+	Output   string   // -o/--output file to write, stdout when empty or "-"
+	Strategy string   // --strategy for conflicting keys, default last-wins
+}
+
+// parseMergeArgs parses the arguments following "merge"
+func parseMergeArgs(args []string) (*mergeConfig, error) {
+	cfg := &mergeConfig{Strategy: strategyLastWins}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-o" || arg == "--output" || arg == "--strategy":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("%s requires a value", arg)
+			}
+			i++
+			if arg == "--strategy" {
+				cfg.Strategy = args[i]
+			} else {
+				cfg.Output = args[i]
+			}
+		case strings.HasPrefix(arg, "--output="):
+			cfg.Output = strings.TrimPrefix(arg, "--output=")
+		case strings.HasPrefix(arg, "--strategy="):
+			cfg.Strategy = strings.TrimPrefix(arg, "--strategy=")
+		case strings.HasPrefix(arg, "-"):
+			return nil, fmt.Errorf("unknown argument: %s", arg)
+		default:
+			cfg.Files = append(cfg.Files, arg)
+		}
+	}
+	if len(cfg.Files) < 2 {
+		return nil, fmt.Errorf("merge requires at least two files")
+	}
+	valid := false
+	for _, s := range mergeStrategies {
+		valid = valid || cfg.Strategy == s
+	}
+	if !valid {
+		return nil, fmt.Errorf("--strategy: unknown strategy %q (use %s)", cfg.Strategy, strings.Join(mergeStrategies, ", "))
+	}
+	return cfg, nil
+}
+
+// mergeCandidate is one file's definition of a key: its last assignment
+// there, as written, and the value it loads
+type mergeCandidate struct {
+	file  string
+	entry parser.DocEntry
+	value string
+}
+
+// location is where the candidate is defined, as file:line
+func (c mergeCandidate) location() string {
+	return audit.Location{File: c.file, Line: c.entry.Line}.String()
+}
+
+// conflicting reports whether the candidates load different values
+func conflicting(candidates []mergeCandidate) bool {
+	for _, c := range candidates[1:] {
+		if c.value != candidates[0].value {
+			return true
+		}
+	}
+	return false
+}
+
+// runMerge composes env files into one, e.g. a base and its overrides into
+// a deployable artifact. Keys keep the order they are first defined in, and
+// each is written as its chosen definition was, with its comments. A key the
+// files give different values is a conflict, reported on stderr and
+// resolved by the strategy.
+func runMerge(args []string, stdout, stderr io.Writer) int {
+	mcfg, err := parseMergeArgs(args)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+
+	var keys []string
+	candidates := make(map[string][]mergeCandidate)
+	for _, path := range mcfg.Files {
+		doc, err := parser.ParseDocument(path)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+		result, err := parser.ParseEnvFile(path)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+		// Within a file, the last assignment of a key is the one that loads
+		last := make(map[string]parser.DocEntry)
+		for _, entry := range doc.Entries {
+			last[entry.Key] = entry
+		}
+		for _, key := range result.Keys {
+			entry, ok := last[key]
+			if !ok {
+				continue
+			}
+			if candidates[key] == nil {
+				keys = append(keys, key)
+			}
+			candidates[key] = append(candidates[key], mergeCandidate{file: path, entry: entry, value: result.Entries[key]})
+		}
+	}
+
+	var answers *bufio.Scanner
+	if mcfg.Strategy == strategyInteractive {
+		answers = bufio.NewScanner(stdin)
+	}
+	redactor := redact.Default()
+	conflicts := 0
+	var out strings.Builder
+	for _, key := range keys {
+		defined := candidates[key]
+		chosen := defined[len(defined)-1]
+		if conflicting(defined) {
+			conflicts++
+			locations := make([]string, len(defined))
+			for i, c := range defined {
+				locations[i] = c.location()
+			}
+			switch mcfg.Strategy {
+			case strategyFirstWins:
+				chosen = defined[0]
+			case strategyErrorOnConflict:
+				fmt.Fprintf(stderr, "conflict: %s differs in %s\n", key, strings.Join(locations, ", "))
+				continue
+			case strategyInteractive:
+				if chosen, err = askCandidate(key, defined, answers, redactor, stderr); err != nil {
+					fmt.Fprintln(stderr, "Error:", err)
+					return 2
+				}
+			}
+			fmt.Fprintf(stderr, "conflict: %s differs in %s, keeping %s\n", key, strings.Join(locations, ", "), chosen.location())
+		}
+		for _, line := range chosen.entry.Comments {
+			out.WriteString(strings.TrimSpace(line) + "\n")
+		}
+		out.WriteString(strings.TrimSpace(chosen.entry.Raw) + "\n")
+	}
+
+	if mcfg.Strategy == strategyErrorOnConflict && conflicts > 0 {
+		fmt.Fprintf(stderr, "Error: %s, nothing written\n", pluralize(conflicts, "conflicting key"))
+		return 1
+	}
+	if mcfg.Output == "" || mcfg.Output == "-" {
+		fmt.Fprint(stdout, out.String())
+		return 0
+	}
+	// The merged file holds the secrets of all its inputs
+	if err := fsutil.WriteFileAtomic(mcfg.Output, []byte(out.String()), 0600); err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	fmt.Fprintf(stderr, "merged %s into %s\n", pluralize(len(mcfg.Files), "file"), mcfg.Output)
+	return 0
+}
+
+// askCandidate prompts on w for which definition of a conflicting key to
+// keep, reading the answer from answers. Sensitive values are masked.
+func askCandidate(key string, defined []mergeCandidate, answers *bufio.Scanner, redactor *redact.Redactor, w io.Writer) (mergeCandidate, error) {
+	fmt.Fprintf(w, "%s is defined differently:\n", redactor.Key(key))
+	for i, c := range defined {
+		fmt.Fprintf(w, "  %d) %s  %s\n", i+1, c.location(), strconv.Quote(redactor.Value(key, c.value)))
+	}
+	for {
+		fmt.Fprintf(w, "Keep [1-%d, default %d]: ", len(defined), len(defined))
+		if !answers.Scan() {
+			return mergeCandidate{}, fmt.Errorf("no answer for %s", key)
+		}
+		answer := strings.TrimSpace(answers.Text())
+		if answer == "" {
+			return defined[len(defined)-1], nil
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(defined) {
+			return defined[n-1], nil
+		}
+		fmt.Fprintf(w, "Please answer a number from 1 to %d\n", len(defined))
+	}
+}
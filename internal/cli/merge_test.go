@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeMergeFiles writes a base and an override env file sharing two keys,
+// only one of them with a different value
+func writeMergeFiles(t *testing.T) (base, override string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	base = filepath.Join(tmpDir, ".env")
+	override = filepath.Join(tmpDir, ".env.production")
+	os.WriteFile(base, []byte("# listening port\nPORT=3000\nNAME=\"demo app\"\nAPI_KEY=sk_dev\n"), 0644)
+	os.WriteFile(override, []byte("NAME='demo app'\nAPI_KEY=sk_live\nREGION=eu\n"), 0644)
+	return base, override
+}
+
+func TestRunMerge_Strategies(t *testing.T) {
+	base, override := writeMergeFiles(t)
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"merge", base, override}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if want := "# listening port\nPORT=3000\nNAME='demo app'\nAPI_KEY=sk_live\nREGION=eu\n"; stdout.String() != want {
+		t.Errorf("unexpected last-wins output:\n%s\nwant:\n%s", stdout.String(), want)
+	}
+	if want := "conflict: API_KEY differs in " + base + ":4, " + override + ":2, keeping " + override + ":2\n"; stderr.String() != want {
+		t.Errorf("expected only the differing key reported, got %q", stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := Run([]string{"merge", "--strategy", "first-wins", base, override}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "API_KEY=sk_dev\n") {
+		t.Errorf("expected the first value kept, got:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	out := filepath.Join(filepath.Dir(base), "deploy.env")
+	if code := Run([]string{"merge", "--strategy=error-on-conflict", base, override, "-o", out}, &stdout, &stderr); code != 1 {
+		t.Errorf("expected exit 1 on a conflict, got %d (stderr: %s)", code, stderr.String())
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("expected nothing written on a conflict, got %v", err)
+	}
+	if !strings.Contains(stderr.String(), "Error: 1 conflicting key, nothing written") || strings.Contains(stderr.String(), "sk_") {
+		t.Errorf("expected the conflict reported without values, got %q", stderr.String())
+	}
+}
+
+func TestRunMerge_Output(t *testing.T) {
+	base, override := writeMergeFiles(t)
+	out := filepath.Join(filepath.Dir(base), "deploy.env")
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"merge", base, override, "--output", out}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("expected nothing on stdout, got %q", stdout.String())
+	}
+	if data, _ := os.ReadFile(out); !strings.Contains(string(data), "API_KEY=sk_live\n") {
+		t.Errorf("unexpected merged file %q", data)
+	}
+	if info, _ := os.Stat(out); info.Mode().Perm() != 0600 {
+		t.Errorf("expected the merged file private, got %o", info.Mode().Perm())
+	}
+}
+
+func TestRunMerge_Interactive(t *testing.T) {
+	base, override := writeMergeFiles(t)
+	orig := stdin
+	stdin = strings.NewReader("3\n1\n")
+	t.Cleanup(func() { stdin = orig })
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"merge", "--strategy", "interactive", base, override}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "API_KEY=sk_dev\n") {
+		t.Errorf("expected the chosen value kept, got:\n%s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "Please answer a number from 1 to 2") || strings.Contains(stderr.String(), "sk_") {
+		t.Errorf("expected a re-prompt and masked values, got %q", stderr.String())
+	}
+
+	stdin = strings.NewReader("")
+	if code := Run([]string{"merge", "--strategy", "interactive", base, override}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit 2 without an answer, got %d", code)
+	}
+}
+
+func TestParseMergeArgs_Invalid(t *testing.T) {
+	for _, args := range [][]string{{".env"}, {"a.env", "b.env", "--strategy", "newest"}, {"a.env", "b.env", "-o"}, {"a.env", "b.env", "--bogus"}} {
+		if _, err := parseMergeArgs(args); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"env-audit/internal/audit"
+	"env-audit/internal/fsutil"
+)
+
+// reportMetadata describes how a report was produced, so it can be
+// reproduced and attached to compliance evidence
+type reportMetadata struct {
+	Tool            string      `json:"tool"`
+	Version         string      `json:"version"`
+	PatternsVersion string      `json:"patternsVersion"`
+	Config          string      `json:"config,omitempty"`
+	Rules           []string    `json:"rules"`
+	Inputs          []inputFile `json:"inputs,omitempty"`
+}
+
+// inputFile is a scanned file and the SHA-256 of its contents at scan time
+type inputFile struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// newReportMetadata collects the metadata of the run configured by cfg
+func newReportMetadata(cfg *Config) *reportMetadata {
+	meta := &reportMetadata{
+		Tool:            "env-audit",
+		Version:         Version,
+		PatternsVersion: audit.PatternsVersion,
+		Config:          displayPath(cfg.configPath),
+		Rules:           enabledRules(cfg),
+	}
+	if cfg.Reproducible {
+		meta.Config = reproduciblePath(cfg.configPath)
+	}
+	paths := append([]string{}, cfg.InputFiles()...)
+	if cfg.ExampleFile != "" {
+		paths = append(paths, cfg.ExampleFile)
+	}
+	for _, path := range paths {
+		// A file that cannot be read is reported by the scan itself
+		sum, _ := fileSHA256(path)
+		if cfg.Reproducible {
+			path = reproduciblePath(path)
+		}
+		meta.Inputs = append(meta.Inputs, inputFile{Path: path, SHA256: sum})
+	}
+	return meta
+}
+
+// enabledRules lists the checks a run performs, by issue type name
+func enabledRules(cfg *Config) []string {
+	rules := []string{"empty", "duplicate", "sensitive", "default", "override", "system_override"}
+	if len(cfg.Required) > 0 || cfg.ExampleFile != "" {
+		rules = append(rules, "missing")
+	}
+	if cfg.ExampleFile != "" {
+		rules = append(rules, "extra")
+	}
+	if cfg.CheckLeaks {
+		rules = append(rules, "leak")
+	}
+	if cfg.ExampleFile != "" && !cfg.NoExampleLeaks {
+		rules = append(rules, "example_secret")
+	}
+	if !cfg.NoFormatCheck {
+		rules = append(rules, "malformed")
+	}
+	if !cfg.NoExpand {
+		rules = append(rules, "unresolved")
+	}
+	if len(cfg.Rules) > 0 {
+		rules = append(rules, "invalid")
+	}
+	if cfg.ExampleFile != "" && cfg.docsFile() != "" {
+		rules = append(rules, "docs_drift")
+	}
+	if cfg.Platform != "" {
+		rules = append(rules, "platform")
+	}
+	if !cfg.Limits.IsZero() {
+		rules = append(rules, "limit")
+	}
+	if cfg.RotateAfter != "" {
+		rules = append(rules, "rotation")
+	}
+	if cfg.CheckSources {
+		rules = append(rules, "source_drift")
+	}
+	if cfg.WarnParse {
+		rules = append(rules, "parse_error")
+	}
+	if cfg.CheckOrder && cfg.ExampleFile != "" {
+		rules = append(rules, "order")
+	}
+	// Types dropped by --only, --exclude or an ignore severity did not run
+	policy := cfg.severityPolicy()
+	kept := rules[:0]
+	for _, rule := range rules {
+		if t, ok := audit.ParseIssueType(rule); !ok || policy[t] != audit.SeverityIgnore {
+			kept = append(kept, rule)
+		}
+	}
+	rules = kept
+	switch cfg.failOn() {
+	case audit.SeverityWarning:
+		rules = append(rules, "strict")
+	case audit.SeverityInfo:
+		rules = append(rules, "fail-on-info")
+	}
+	return rules
+}
+
+// fileSHA256 returns the hex SHA-256 digest of the file at path
+func fileSHA256(path string) (string, error) {
+	f, err := fsutil.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// displayPath shortens an absolute path to one relative to the working
+// directory, so reports do not carry home directories
+func displayPath(path string) string {
+	if path == "" || !filepath.IsAbs(path) {
+		return path
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+// markdown renders the metadata as a collapsed Markdown block
+func (m *reportMetadata) markdown() string {
+	var sb strings.Builder
+	sb.WriteString("\n<details><summary>Report metadata</summary>\n\n")
+	sb.WriteString(fmt.Sprintf("- Tool: `%s %s` (leak patterns `%s`)\n", m.Tool, m.Version, m.PatternsVersion))
+	if m.Config != "" {
+		sb.WriteString(fmt.Sprintf("- Config: `%s`\n", m.Config))
+	}
+	sb.WriteString(fmt.Sprintf("- Rules: %s\n", strings.Join(m.Rules, ", ")))
+	if len(m.Inputs) > 0 {
+		sb.WriteString("\n| Input | SHA-256 |\n| --- | --- |\n")
+		for _, input := range m.Inputs {
+			sum := input.SHA256
+			if sum == "" {
+				sum = "unreadable"
+			}
+			sb.WriteString(fmt.Sprintf("| `%s` | `%s` |\n", input.Path, sum))
+		}
+	}
+	sb.WriteString("\n</details>\n")
+	return sb.String()
+}
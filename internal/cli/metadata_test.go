@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewReportMetadata_HashesInputs(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, ".env")
+	os.WriteFile(envPath, []byte("test"), 0644)
+	missing := filepath.Join(dir, "missing.env")
+
+	meta := newReportMetadata(&Config{Files: []string{envPath, missing}})
+
+	want := []inputFile{
+		// sha256("test")
+		{Path: envPath, SHA256: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"},
+		{Path: missing},
+	}
+	if !reflect.DeepEqual(meta.Inputs, want) {
+		t.Errorf("unexpected inputs: %+v", meta.Inputs)
+	}
+	if meta.Version != Version || meta.PatternsVersion == "" {
+		t.Errorf("expected tool and pattern versions, got %+v", meta)
+	}
+}
+
+func TestEnabledRules(t *testing.T) {
+	got := enabledRules(&Config{ExampleFile: ".env.example", CheckLeaks: true, NoFormatCheck: true, Strict: true})
+	want := []string{"empty", "duplicate", "sensitive", "default", "override", "system_override", "missing", "extra", "leak", "example_secret", "unresolved", "strict"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("enabledRules = %v, want %v", got, want)
+	}
+}
+
+func TestReportMetadata_Markdown(t *testing.T) {
+	meta := &reportMetadata{Tool: "env-audit", Version: "1.0.0", PatternsVersion: "p1", Rules: []string{"empty"}, Inputs: []inputFile{{Path: ".env", SHA256: "abc"}, {Path: "gone.env"}}}
+	md := meta.markdown()
+	for _, want := range []string{"<details>", "`env-audit 1.0.0`", "| `.env` | `abc` |", "| `gone.env` | `unreadable` |"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected %q in markdown:\n%s", want, md)
+		}
+	}
+}
+
+func TestRun_JSONIncludesMetadata(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	os.WriteFile(".env-audit.yaml", []byte("check_leaks: true\n"), 0644)
+	os.WriteFile(".env", []byte("PORT=3000\n"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := Run([]string{"-f", ".env", "--json"}, &stdout, &stderr); exitCode != 0 {
+		t.Fatalf("expected exit 0, got %d: %s", exitCode, stderr.String())
+	}
+	var report struct {
+		Metadata reportMetadata `json:"metadata"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if report.Metadata.Config != ".env-audit.yaml" {
+		t.Errorf("expected the config file relative to the working directory, got %q", report.Metadata.Config)
+	}
+	if len(report.Metadata.Inputs) != 1 || report.Metadata.Inputs[0].SHA256 == "" {
+		t.Errorf("expected a hashed input, got %+v", report.Metadata.Inputs)
+	}
+	if !strings.Contains(strings.Join(report.Metadata.Rules, ","), "leak") {
+		t.Errorf("expected leak checks listed, got %v", report.Metadata.Rules)
+	}
+}
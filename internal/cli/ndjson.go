@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"env-audit/internal/audit"
+)
+
+// NDJSONFormatter outputs newline-delimited JSON: one "issue" event per
+// issue followed by one "summary" event, so log pipelines (fluentd, Loki)
+// can ingest findings line by line
+type NDJSONFormatter struct{}
+
+// ndjsonIssue is an "issue" event: a jsonIssue tagged with its audit run
+type ndjsonIssue struct {
+	Event string `json:"event"`
+	Run   int    `json:"run"`
+	jsonIssue
+}
+
+// ndjsonSummary is the "summary" event closing an audit run
+type ndjsonSummary struct {
+	Event    string         `json:"event"`
+	Run      int            `json:"run"`
+	HasRisks bool           `json:"hasRisks"`
+	Summary  map[string]int `json:"summary"`
+	Stats    *jsonStats     `json:"stats"`
+	Files    []jsonFile     `json:"files,omitempty"`
+}
+
+// Format implements Formatter interface for NDJSONFormatter
+func (f *NDJSONFormatter) Format(result *audit.Result) string {
+	if result == nil {
+		result = &audit.Result{Summary: map[audit.IssueType]int{}}
+	}
+	var sb strings.Builder
+	stream := &ndjsonStream{w: &sb}
+	stream.begin()
+	stream.issues(result.Issues)
+	stream.summary(result)
+	return sb.String()
+}
+
+// ndjsonStream writes NDJSON events as the audit finds issues, instead of
+// one report at the end. Each audit run, one per change in watch mode, gets
+// the next run number.
+type ndjsonStream struct {
+	w   io.Writer
+	run int
+}
+
+// begin starts the next audit run
+func (s *ndjsonStream) begin() {
+	if s != nil {
+		s.run++
+	}
+}
+
+// issues writes an "issue" event per issue; a nil stream writes nothing
+func (s *ndjsonStream) issues(issues []audit.Issue) {
+	if s == nil {
+		return
+	}
+	for _, issue := range issues {
+		s.write(ndjsonIssue{Event: "issue", Run: s.run, jsonIssue: newJSONIssue(issue)})
+	}
+}
+
+// summary writes the "summary" event closing the current run
+func (s *ndjsonStream) summary(result *audit.Result) {
+	if s == nil {
+		return
+	}
+	s.write(ndjsonSummary{
+		Event:    "summary",
+		Run:      s.run,
+		HasRisks: result.HasRisks,
+		Summary:  jsonSummary(result.Summary),
+		Stats:    newJSONStats(result),
+		Files:    newJSONFiles(result.Files),
+	})
+}
+
+// write encodes one event as a line
+func (s *ndjsonStream) write(event interface{}) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	s.w.Write(append(data, '\n'))
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"env-audit/internal/audit"
+)
+
+func TestNDJSONFormatter_Format(t *testing.T) {
+	result := &audit.Result{
+		Issues: []audit.Issue{
+			{Type: audit.IssueLeak, Key: "TOKEN", Message: "GitHub token detected", File: ".env", Line: 3},
+			{Type: audit.IssueEmpty, Key: "PORT", Message: "variable has empty value", File: ".env", Line: 1},
+		},
+		Summary:  map[audit.IssueType]int{audit.IssueLeak: 1, audit.IssueEmpty: 1},
+		Files:    []audit.FileStatus{{Path: ".env"}, {Path: "bad.env", Error: errors.New("unreadable")}},
+		HasRisks: true,
+	}
+	output := (&NDJSONFormatter{}).Format(result)
+
+	want := []string{
+		`{"event":"issue","run":1,"id":"33427cf00e7a2da01a0b6b96a71e6464","type":"leak","key":"TOKEN","message":"GitHub token detected","file":".env","line":3,"remediation":{"action":"rotate_secret","key":"TOKEN","file":".env"}}`,
+		`{"event":"issue","run":1,"id":"a0f8cd7b773ef0cfb88d98110e02ee59","type":"empty","key":"PORT","message":"variable has empty value","file":".env","line":1,"remediation":{"action":"set_value","key":"PORT","file":".env"}}`,
+		`{"event":"summary","run":1,"hasRisks":true,"summary":{"empty":1,"leak":1},"stats":{"bySeverity":{"error":1,"warning":1},"byRule":{"empty":1,"leak":1},"byFile":{".env":2,"bad.env":0}},"files":[{"path":".env","status":"ok"},{"path":"bad.env","status":"error","error":"unreadable"}]}`,
+	}
+	if got := strings.TrimSuffix(output, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, strings.Join(want, "\n"))
+	}
+}
+
+func TestNDJSONStream_NilIsNoop(t *testing.T) {
+	var stream *ndjsonStream
+	stream.begin()
+	stream.issues([]audit.Issue{{Type: audit.IssueEmpty, Key: "A"}})
+	stream.summary(&audit.Result{})
+}
+
+func TestRun_NDJSONStreamsEachFile(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "a.env")
+	second := filepath.Join(dir, "b.env")
+	os.WriteFile(first, []byte("EMPTY=\n"), 0644)
+	os.WriteFile(second, []byte("PORT=8080\nBLANK=\n"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	Run([]string{"-f", first, "-f", second, "--ndjson"}, &stdout, &stderr)
+
+	lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected two issues and a summary, got:\n%s", stdout.String())
+	}
+	var events []map[string]interface{}
+	for _, line := range lines {
+		var event map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("line is not JSON: %q: %v", line, err)
+		}
+		events = append(events, event)
+	}
+	if events[0]["file"] != first || events[1]["file"] != second {
+		t.Errorf("expected issues in scan order, got %v", events)
+	}
+	if events[2]["event"] != "summary" {
+		t.Errorf("expected the summary last, got %v", events[2])
+	}
+}
+
+func TestRun_NDJSONQuiet(t *testing.T) {
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, ".env")
+	os.WriteFile(envFile, []byte("EMPTY=\n"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	Run([]string{"-f", envFile, "--ndjson", "-q"}, &stdout, &stderr)
+	if stdout.Len() != 0 {
+		t.Errorf("expected no output with --quiet, got: %s", stdout.String())
+	}
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// networkFlags lists the requested features that would access the network,
+// which --offline refuses: downloading a pattern pack, annotating a
+// Buildkite build through its agent and checking values against a secret
+// store
+func (cfg *Config) networkFlags() []string {
+	var flags []string
+	if strings.HasPrefix(cfg.Patterns, "https://") || strings.HasPrefix(cfg.Patterns, "http://") {
+		flags = append(flags, "--patterns "+cfg.Patterns)
+	}
+	if cfg.BuildkiteOutput {
+		flags = append(flags, "--buildkite")
+	}
+	if cfg.CheckSources {
+		if stores := remoteSourceStores(cfg.InputFiles(), cfg.parseOptions()); len(stores) > 0 {
+			flags = append(flags, "--check-sources with "+strings.Join(stores, ", ")+" sources")
+		}
+	}
+	return flags
+}
+
+// checkOffline fails if --offline is set and the run would access the
+// network
+func (cfg *Config) checkOffline() error {
+	if !cfg.Offline {
+		return nil
+	}
+	if flags := cfg.networkFlags(); len(flags) > 0 {
+		return fmt.Errorf("--offline: %s would access the network", strings.Join(flags, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"env-audit/internal/netutil"
+	"env-audit/internal/remote"
+)
+
+// restoreNetwork undoes the offline transport an --offline run leaves behind
+func restoreNetwork() {
+	netutil.Configure(netutil.Options{Retries: netutil.DefaultRetries})
+}
+
+func TestRun_Offline(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+	defer restoreNetwork()
+	os.WriteFile(".env", []byte("PORT=8080\n"), 0644)
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	refused := [][]string{
+		{"-f", ".env", "--patterns", server.URL + "/pack.json"},
+		{"-f", ".env", "--buildkite"},
+		{"org", "--repos", "repos.txt", "--offline"},
+		{"version", "--check", "--offline"},
+		{"remote-diff", "--provider", "heroku", "--app", "web", "--offline"},
+	}
+	for _, args := range refused {
+		if args[0] == "-f" {
+			args = append(args, "--offline")
+		}
+		var stdout, stderr bytes.Buffer
+		if code := Run(args, &stdout, &stderr); code != 2 {
+			t.Errorf("%v: expected exit 2, got %d", args, code)
+		}
+		if !strings.Contains(stderr.String(), "--offline:") {
+			t.Errorf("%v: expected an offline error, got %q", args, stderr.String())
+		}
+	}
+	if calls != 0 {
+		t.Errorf("expected no request to be sent, got %d", calls)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"-f", ".env", "--offline", "--json"}, &stdout, &stderr); code != 0 {
+		t.Errorf("expected a plain audit to run, got exit %d: %s", code, stderr.String())
+	}
+}
+
+func TestRun_OfflineSkipsBuildkite(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+	defer restoreNetwork()
+	os.WriteFile(".env", []byte("PORT=8080\n"), 0644)
+	t.Setenv("BUILDKITE", "true")
+
+	annotated := false
+	oldAnnotate := buildkiteAnnotate
+	buildkiteAnnotate = func(markdown, style string) error {
+		annotated = true
+		return nil
+	}
+	defer func() { buildkiteAnnotate = oldAnnotate }()
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"-f", ".env", "--offline"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d: %s", code, stderr.String())
+	}
+	if annotated {
+		t.Error("expected CI detection not to pick Buildkite annotations under --offline")
+	}
+}
+
+func TestRun_OfflinePreset(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+	defer restoreNetwork()
+	os.WriteFile(".env", []byte("PORT=8080\n"), 0644)
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte("fail_on: warning\n"))
+	}))
+	defer server.Close()
+	os.WriteFile(".env-audit.yaml", []byte("preset: "+server.URL+"/policy.yaml\n"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"-f", ".env", "--offline"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit 2, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "offline mode") || calls != 0 {
+		t.Errorf("expected the remote preset to be refused without a request, got %d calls: %q", calls, stderr.String())
+	}
+
+	restoreNetwork()
+	stderr.Reset()
+	if code := Run([]string{"-f", ".env"}, &stdout, &stderr); code != 0 || calls != 1 {
+		t.Errorf("expected the preset to be fetched online, got exit %d and %d calls: %s", code, calls, stderr.String())
+	}
+}
+
+func TestRun_OfflineCheckSources(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+	defer restoreNetwork()
+	os.WriteFile("shared.env", []byte("REGION=eu-west-1\n"), 0644)
+	os.WriteFile(".env", []byte("# source: file:shared.env\nREGION=eu-west-1\n"), 0644)
+	os.WriteFile("remote.env", []byte("# source: ssm:/app/prod\nAPI_KEY=a\n# source: vault:secret/app\nTOKEN=b\n"), 0644)
+
+	opened := false
+	oldOpen := openSource
+	openSource = func(name, path string) (remote.Store, string, error) {
+		opened = true
+		return &fakeStore{}, path, nil
+	}
+	defer func() { openSource = oldOpen }()
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"-f", "remote.env", "--check-sources", "--offline"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit 2, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "--offline: --check-sources with ssm, vault sources would access the network") {
+		t.Errorf("expected the remote stores named, got %q", stderr.String())
+	}
+	if opened {
+		t.Error("expected no store to be opened")
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := Run([]string{"-f", ".env", "--check-sources", "--offline"}, &stdout, &stderr); code != 0 {
+		t.Errorf("expected file sources to be checked offline, got exit %d: %s", code, stderr.String())
+	}
+}
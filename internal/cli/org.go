@@ -0,0 +1,284 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"env-audit/internal/audit"
+	"env-audit/internal/discover"
+)
+
+// syncRepo shallow-clones url into dir, or updates an existing clone to the
+// remote's latest commit; replaced in tests
+var syncRepo = gitSync
+
+// orgConfig holds the arguments of the org command
+type orgConfig struct {
+	Repos   string   // --repos file listing one repository URL per line
+	Workdir string   // --workdir directory holding the clones, defaults to the user cache dir
+	Args    []string // remaining audit flags, e.g. --json or --strict
+}
+
+// parseOrgArgs parses the arguments following "org"; flags it does not own
+// are passed through to the audit
+func parseOrgArgs(args []string) (*orgConfig, error) {
+	cfg := &orgConfig{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--repos", "--workdir":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			if arg == "--repos" {
+				cfg.Repos = args[i]
+			} else {
+				cfg.Workdir = args[i]
+			}
+		default:
+			cfg.Args = append(cfg.Args, arg)
+		}
+	}
+	if cfg.Repos == "" {
+		return nil, fmt.Errorf("org requires --repos")
+	}
+	if cfg.Workdir == "" {
+		cache, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("no cache directory, use --workdir: %w", err)
+		}
+		cfg.Workdir = filepath.Join(cache, "env-audit", "repos")
+	}
+	return cfg, nil
+}
+
+// readRepoList reads repository URLs, one per line; blank lines and
+// # comments are skipped
+func readRepoList(r io.Reader) ([]string, error) {
+	var repos []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		repos = append(repos, line)
+	}
+	return repos, scanner.Err()
+}
+
+// repoName derives a short owner/name label from a repository URL, e.g.
+// git@github.com:acme/api.git -> acme/api
+func repoName(url string) string {
+	name := strings.TrimSuffix(strings.TrimRight(url, "/"), ".git")
+	name = strings.ReplaceAll(name, ":", "/")
+	parts := strings.FieldsFunc(name, func(r rune) bool { return r == '/' || r == '\\' })
+	if len(parts) > 2 {
+		parts = parts[len(parts)-2:]
+	}
+	return strings.Join(parts, "/")
+}
+
+// repoDir returns the clone directory of url under workdir, one per host
+// and path, so the same owner/name on two hosts never share a clone.
+// Credentials in the URL are left out, and . and .. are refused so a list
+// entry can't point the clone, and its reset, outside workdir.
+func repoDir(workdir, url string) (string, error) {
+	rest := url
+	if i := strings.Index(rest, "://"); i >= 0 {
+		rest = rest[i+3:]
+	}
+	if at := strings.Index(rest, "@"); at >= 0 && !strings.ContainsAny(rest[:at], "/") {
+		rest = rest[at+1:]
+	}
+	rest = strings.TrimSuffix(strings.TrimRight(rest, "/"), ".git")
+	parts := strings.FieldsFunc(rest, func(r rune) bool { return r == '/' || r == '\\' || r == ':' })
+	if len(parts) == 0 {
+		return "", fmt.Errorf("%s: no repository path", url)
+	}
+	for _, part := range parts {
+		if part == "." || part == ".." {
+			return "", fmt.Errorf("%s: . and .. are not allowed in a repository path", url)
+		}
+	}
+	dir := filepath.Join(append([]string{workdir}, parts...)...)
+	if rel, err := filepath.Rel(workdir, dir); err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("%s: clone directory would be outside --workdir", url)
+	}
+	return dir, nil
+}
+
+// gitSync clones url into dir with history depth 1, or fetches url into an
+// existing clone and resets it, so local edits never mask what is on the
+// remote and a changed URL in the list is followed
+func gitSync(url, dir string) error {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		if err := runGit("-C", dir, "fetch", "--quiet", "--depth", "1", "--", url); err != nil {
+			return err
+		}
+		return runGit("-C", dir, "reset", "--quiet", "--hard", "FETCH_HEAD")
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return err
+	}
+	return runGit("clone", "--quiet", "--depth", "1", "--", url, dir)
+}
+
+// runGit runs git, folding its stderr into the returned error
+func runGit(args ...string) error {
+	cmd := exec.Command("git", args...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return nil
+}
+
+// runOrg implements "env-audit org --repos repos.txt": every listed
+// repository is synced and audited recursively, and the findings are merged
+// into one report with paths labelled owner/name/file. Leak detection is on,
+// since the point is an organization-wide secrets assessment.
+func runOrg(args []string, stdout, stderr io.Writer) int {
+	ocfg, err := parseOrgArgs(args)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	cfg, err := ParseArgs(ocfg.Args)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	if cfg.Offline {
+		fmt.Fprintln(stderr, "Error: --offline: org would clone repositories over the network")
+		return 2
+	}
+	if cfg.AssertReadOnly {
+		fmt.Fprintln(stderr, "Error: --assert-read-only: org would write to disk, cloning repositories into --workdir")
+		return 2
+	}
+	if err := cfg.setLocale(); err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	if cfg.log, err = newLogger(stderr, cfg.LogLevel, cfg.LogFormat); err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	if cfg.FormatTemplate != "" {
+		if cfg.formatTemplate, err = loadFormatTemplate(cfg.FormatTemplate); err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+	}
+	cfg.CheckLeaks = true
+	cfg.startNDJSON(stdout)
+	cfg.ndjson.begin()
+	// Each repository's own config file applies to its files
+	cliArgs := *cfg
+	cfg.cliArgs = &cliArgs
+
+	f, err := os.Open(ocfg.Repos)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	repos, err := readRepoList(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	if len(repos) == 0 {
+		cfg.notify(stdout, "No repositories to scan")
+		return 0
+	}
+
+	redactor, err := cfg.Redactor()
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+
+	var results []*audit.Result
+	var statuses []audit.FileStatus
+	for _, url := range repos {
+		name := repoName(url)
+		dir, err := repoDir(ocfg.Workdir, url)
+		if err == nil {
+			err = syncRepo(url, dir)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %s: %v\n", name, err)
+			statuses = append(statuses, audit.FileStatus{Path: name, Error: err})
+			continue
+		}
+		cfg.logger().Info("synced repository", "repo", name, "dir", dir)
+		repoResults, repoStatuses := scanRepo(cfg, name, dir, stderr)
+		for _, result := range repoResults {
+			cfg.ndjson.issues(result.Issues)
+		}
+		results = append(results, repoResults...)
+		statuses = append(statuses, repoStatuses...)
+	}
+
+	return reportResult(cfg, audit.Merge(results, statuses), redactor, stdout, stderr)
+}
+
+// scanRepo audits the env files of one cloned repository, relabelling paths
+// from the clone directory to name/relative-path
+func scanRepo(cfg *Config, name, dir string, stderr io.Writer) ([]*audit.Result, []audit.FileStatus) {
+	label := func(path string) string {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return path
+		}
+		return name + "/" + filepath.ToSlash(rel)
+	}
+
+	files, err := discover.Find([]string{dir}, discover.Options{NoIgnore: cfg.NoIgnore})
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s: %v\n", name, err)
+		return nil, []audit.FileStatus{{Path: name, Error: err}}
+	}
+
+	examples := &exampleSet{}
+	var results []*audit.Result
+	var statuses []audit.FileStatus
+	for _, path := range files {
+		result, err := scanFile(cfg, path, examples.load)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %s: %v\n", label(path), err)
+			statuses = append(statuses, audit.FileStatus{Path: label(path), Error: err})
+			continue
+		}
+		for i := range result.Issues {
+			if result.Issues[i].File != "" {
+				result.Issues[i].File = label(result.Issues[i].File)
+			}
+		}
+		results = append(results, result)
+		statuses = append(statuses, audit.FileStatus{Path: label(path)})
+	}
+
+	// Committed examples are the likeliest place for a real secret
+	if !cfg.NoExampleLeaks {
+		leaks := audit.NewResult(examples.leaks(cfg.Ignore, cfg.PublicValues, cfg.AllowHashes), cfg.severityPolicy(), cfg.failOn())
+		if len(leaks.Issues) > 0 {
+			for i := range leaks.Issues {
+				leaks.Issues[i].File = label(leaks.Issues[i].File)
+			}
+			results = append(results, leaks)
+		}
+	}
+	return results, statuses
+}
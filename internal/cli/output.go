@@ -6,8 +6,11 @@ import (
 	"io"
 	"os"
 	"strings"
+	"unicode/utf8"
 
 	"env-audit/internal/audit"
+	"env-audit/internal/i18n"
+	"env-audit/internal/redact"
 )
 
 // Formatter defines the interface for output formatting
@@ -16,14 +19,33 @@ type Formatter interface {
 }
 
 // JSONFormatter outputs results as JSON
-type JSONFormatter struct{}
+type JSONFormatter struct {
+	Metadata *reportMetadata // optional header describing the run
+}
 
 // GitHubFormatter outputs results in GitHub Actions workflow command format
-type GitHubFormatter struct{}
+type GitHubFormatter struct {
+	MaxDisplay int // annotations emitted before an "and N more…" notice, 0 for all
+}
+
+// CompactFormatter outputs one gcc-style "file:line:col: severity: message"
+// line per issue, for editor quickfix lists and problem matchers
+type CompactFormatter struct{}
 
 // TextFormatter outputs results with optional color support
 type TextFormatter struct {
-	UseColor bool
+	UseColor     bool
+	Redactor     *redact.Redactor  // masks sensitive values, defaults to redact.Default()
+	Descriptions map[string]string // variable descriptions shown under each issue
+}
+
+// textOptions controls rendering of the plain text report
+type textOptions struct {
+	Redactor     *redact.Redactor
+	Descriptions map[string]string // shown under each issue in verbose mode
+	Color        bool              // color issue groups and the all-clear message
+	MaxDisplay   int               // issues listed before an "and N more…" footer, 0 for all
+	GroupBy      string            // "prefix" or "file" clusters issues by key prefix or file before type
 }
 
 // ANSI color codes
@@ -36,47 +58,123 @@ const (
 
 // jsonIssue represents an issue in JSON output
 type jsonIssue struct {
+	ID      string `json:"id"`
 	Type    string `json:"type"`
 	Key     string `json:"key"`
 	Message string `json:"message"`
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Rule    string `json:"rule,omitempty"`
+	Docs    string `json:"docs,omitempty"`
+
+	Remediation *jsonRemediation `json:"remediation,omitempty"`
+}
+
+// jsonFile represents the scan status of one input file in JSON output
+type jsonFile struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
 }
 
 // jsonOutput represents the complete JSON output structure
 type jsonOutput struct {
-	HasRisks bool           `json:"hasRisks"`
-	Issues   []jsonIssue    `json:"issues"`
-	Summary  map[string]int `json:"summary"`
+	HasRisks bool            `json:"hasRisks"`
+	Issues   []jsonIssue     `json:"issues"`
+	Summary  map[string]int  `json:"summary"`
+	Stats    *jsonStats      `json:"stats,omitempty"`
+	Files    []jsonFile      `json:"files,omitempty"`
+	Metadata *reportMetadata `json:"metadata,omitempty"`
+
+	ParseErrors []jsonParseError `json:"parseErrors,omitempty"`
+}
+
+// jsonParseError is a line the parser skipped or read as written; like the
+// text report, it never includes the line itself
+type jsonParseError struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+	Length int    `json:"length"` // bytes in the line
+}
+
+// jsonStats breaks issue counts down beyond the by-type summary, so
+// dashboards need not re-aggregate the issues array
+type jsonStats struct {
+	BySeverity map[string]int `json:"bySeverity"` // error, warning and info
+	ByRule     map[string]int `json:"byRule"`     // leak pattern id, else issue type
+	ByFile     map[string]int `json:"byFile"`     // every scanned file, including those without issues
+}
+
+// issueTypeOrder is the order in which issue groups appear in text reports
+var issueTypeOrder = []audit.IssueType{
+	audit.IssueParse,
+	audit.IssueEmpty,
+	audit.IssueMissing,
+	audit.IssueSensitive,
+	audit.IssueDuplicate,
+	audit.IssueExtra,
+	audit.IssueLeak,
+	audit.IssueExampleSecret,
+	audit.IssueUnsafeDefault,
+	audit.IssueDefault,
+	audit.IssueOverride,
+	audit.IssueOrder,
+	audit.IssueMalformed,
+	audit.IssueInvalid,
+	audit.IssueLimit,
+	audit.IssuePlatform,
+	audit.IssueUnresolved,
+	audit.IssueSystem,
+	audit.IssueDocs,
+	audit.IssueSourceDrift,
+	audit.IssueRotation,
+}
+
+// issueTypeNames are the section headings used in text reports
+var issueTypeNames = map[audit.IssueType]string{
+	audit.IssueEmpty:         "Empty Values",
+	audit.IssueMissing:       "Missing Required",
+	audit.IssueSensitive:     "Sensitive Keys Detected",
+	audit.IssueDuplicate:     "Duplicate Keys",
+	audit.IssueExtra:         "Extra Variables",
+	audit.IssueLeak:          "Potential Leaks",
+	audit.IssueExampleSecret: "Example Secrets",
+	audit.IssueUnsafeDefault: "Unsafe Defaults",
+	audit.IssueDefault:       "Using Defaults",
+	audit.IssueOverride:      "Overridden Values",
+	audit.IssueOrder:         "Key Order",
+	audit.IssueMalformed:     "Malformed Credentials",
+	audit.IssueInvalid:       "Invalid Values",
+	audit.IssueLimit:         "Limits Exceeded",
+	audit.IssuePlatform:      "Platform Constraints",
+	audit.IssueUnresolved:    "Unresolved References",
+	audit.IssueSystem:        "System Variables",
+	audit.IssueDocs:          "Documentation Drift",
+	audit.IssueSourceDrift:   "Source Drift",
+	audit.IssueParse:         "Parse Errors",
+	audit.IssueRotation:      "Rotation Overdue",
+}
+
+// issueTypeName is the section heading of an issue type in the active language
+func issueTypeName(t audit.IssueType) string {
+	return i18n.T(issueTypeNames[t])
 }
 
 // issueTypeToString converts IssueType to string for JSON
 func issueTypeToString(t audit.IssueType) string {
-	switch t {
-	case audit.IssueEmpty:
-		return "empty"
-	case audit.IssueMissing:
-		return "missing"
-	case audit.IssueSensitive:
-		return "sensitive"
-	case audit.IssueDuplicate:
-		return "duplicate"
-	case audit.IssueLeak:
-		return "leak"
-	case audit.IssueExtra:
-		return "extra"
-	default:
-		return "unknown"
-	}
+	return t.Name()
 }
 
 // Format implements Formatter interface for TextFormatter
 // Uses colors for errors (red), warnings (yellow), and success (green)
 func (f *TextFormatter) Format(result *audit.Result) string {
-	if result == nil || len(result.Issues) == 0 {
-		msg := "env-audit scan results\n======================\n\nNo issues found."
+	if result == nil || (len(result.Issues) == 0 && result.Failed() == 0) {
+		msg := reportTitle() + "\n" + i18n.T("No issues found.")
 		if f.UseColor {
-			return colorGreen + msg + colorReset
+			msg = colorGreen + msg + colorReset
 		}
-		return msg
+		return msg + strings.TrimRight(formatDiagnostics(result), "\n")
 	}
 
 	// Group issues by type
@@ -85,22 +183,13 @@ func (f *TextFormatter) Format(result *audit.Result) string {
 		groups[issue.Type] = append(groups[issue.Type], issue)
 	}
 
+	multiFile := len(result.Files) > 1
+
 	var sb strings.Builder
-	sb.WriteString("env-audit scan results\n")
-	sb.WriteString("======================\n")
+	sb.WriteString(reportTitle())
 
 	// Output each group in order
-	typeOrder := []audit.IssueType{audit.IssueEmpty, audit.IssueMissing, audit.IssueSensitive, audit.IssueDuplicate, audit.IssueExtra, audit.IssueLeak}
-	typeNames := map[audit.IssueType]string{
-		audit.IssueEmpty:     "Empty Values",
-		audit.IssueMissing:   "Missing Required",
-		audit.IssueSensitive: "Sensitive Keys Detected",
-		audit.IssueDuplicate: "Duplicate Keys",
-		audit.IssueExtra:     "Extra Variables",
-		audit.IssueLeak:      "Potential Leaks",
-	}
-
-	for _, t := range typeOrder {
+	for _, t := range issueTypeOrder {
 		issues := groups[t]
 		if len(issues) == 0 {
 			continue
@@ -109,77 +198,143 @@ func (f *TextFormatter) Format(result *audit.Result) string {
 		// Determine color based on issue type
 		color := ""
 		if f.UseColor {
-			if t == audit.IssueMissing || t == audit.IssueLeak {
-				color = colorRed
-			} else {
-				color = colorYellow
-			}
+			color = groupColor(t)
 		}
 
 		if color != "" {
 			sb.WriteString(color)
 		}
-		sb.WriteString(fmt.Sprintf("\n%s (%d):\n", typeNames[t], len(issues)))
+		sb.WriteString(fmt.Sprintf("\n%s (%d):\n", issueTypeName(t), len(issues)))
 		for _, issue := range issues {
-			if t == audit.IssueSensitive {
-				sb.WriteString(fmt.Sprintf("  - %s: [REDACTED]\n", issue.Key))
-			} else if t == audit.IssueLeak {
-				sb.WriteString(fmt.Sprintf("  - %s: %s\n", issue.Key, issue.Message))
-			} else {
-				sb.WriteString(fmt.Sprintf("  - %s\n", issue.Key))
-			}
+			sb.WriteString(formatIssueLine(issue, multiFile, textOptions{Redactor: f.redactor(), Descriptions: f.Descriptions}))
 		}
 		if color != "" {
 			sb.WriteString(colorReset)
 		}
 	}
 
-	sb.WriteString(fmt.Sprintf("\nSummary: %d issues found\n", len(result.Issues)))
+	sb.WriteString(formatDiagnostics(result))
+	sb.WriteString(formatFileStatuses(result))
+	sb.WriteString(formatSummaryLine(result))
 	return sb.String()
 }
 
+// groupColor is the color of an issue group: red for risks, yellow otherwise
+func groupColor(t audit.IssueType) string {
+	if t == audit.IssueMissing || t.IsSecret() || t == audit.IssueInvalid || t == audit.IssueLimit || t == audit.IssuePlatform {
+		return colorRed
+	}
+	return colorYellow
+}
+
 // Format implements Formatter interface for GitHubFormatter
 // Uses ::error:: for critical issues (missing, leak, duplicate)
 // Uses ::warning:: for non-critical issues (empty, sensitive, extra)
 func (f *GitHubFormatter) Format(result *audit.Result) string {
-	if result == nil || len(result.Issues) == 0 {
+	if result == nil || (len(result.Issues) == 0 && result.Failed() == 0) {
 		return ""
 	}
 
 	var lines []string
-	for _, issue := range result.Issues {
-		prefix := "::warning::"
-		// Critical issues get error level
-		if issue.Type == audit.IssueMissing || issue.Type == audit.IssueLeak || issue.Type == audit.IssueDuplicate {
-			prefix = "::error::"
+	for _, file := range result.Files {
+		if file.Error != nil {
+			lines = append(lines, fmt.Sprintf("::error file=%s::failed to scan: %v", file.Path, file.Error))
+		}
+	}
+	issues := result.Issues
+	if f.MaxDisplay > 0 && len(issues) > f.MaxDisplay {
+		issues = issues[:f.MaxDisplay]
+	}
+	for _, issue := range issues {
+		command := "warning"
+		switch {
+		case issue.Severity != "":
+			// A severity policy decides the level of the types it overrides
+			command = map[string]string{audit.SeverityError: "error", audit.SeverityWarning: "warning", audit.SeverityInfo: "notice"}[issue.Severity]
+		case issue.Type == audit.IssueMissing || issue.Type.IsSecret() || issue.Type == audit.IssueDuplicate || issue.Type == audit.IssueInvalid || issue.Type == audit.IssueLimit || issue.Type == audit.IssuePlatform:
+			// Critical issues get error level
+			command = "error"
 		}
-		lines = append(lines, fmt.Sprintf("%s%s: %s", prefix, issue.Key, issue.Message))
+		lines = append(lines, fmt.Sprintf("::%s%s::%s: %s", command, githubLocation(issue), issue.Key, issue.Message))
+	}
+	if hidden := len(result.Issues) - len(issues); hidden > 0 {
+		lines = append(lines, fmt.Sprintf("::notice::%s", moreIssues(hidden)))
 	}
 	return strings.Join(lines, "\n")
 }
 
+// githubLocation renders the " file=...,line=..." properties that attach an
+// annotation to its position in the diff, or "" for issues without a file
+func githubLocation(issue audit.Issue) string {
+	if issue.File == "" {
+		return ""
+	}
+	location := " file=" + githubEscapeProperty(issue.File)
+	if issue.Line > 0 {
+		location += fmt.Sprintf(",line=%d", issue.Line)
+	}
+	return location
+}
+
+// githubEscapeProperty escapes a workflow command property value
+func githubEscapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// Format implements Formatter interface for CompactFormatter.
+// Issues without a known line point at the start of their file.
+func (f *CompactFormatter) Format(result *audit.Result) string {
+	if result == nil {
+		return ""
+	}
+	var lines []string
+	for _, file := range result.Files {
+		if file.Error != nil {
+			lines = append(lines, fmt.Sprintf("%s:1:1: error: failed to scan: %v [scan_error]", file.Path, file.Error))
+		}
+	}
+	for _, issue := range result.Issues {
+		file := issue.File
+		if file == "" {
+			file = "environment"
+		}
+		line := issue.Line
+		if line < 1 {
+			line = 1
+		}
+		severity := issue.Level()
+		if severity == audit.SeverityInfo {
+			severity = "note"
+		}
+		lines = append(lines, fmt.Sprintf("%s:%d:1: %s: %s: %s [%s]", file, line, severity, issue.Key, issue.Message, issueTypeToString(issue.Type)))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
 // Format implements Formatter interface for JSONFormatter
 func (f *JSONFormatter) Format(result *audit.Result) string {
 	output := jsonOutput{
 		HasRisks: false,
 		Issues:   []jsonIssue{},
 		Summary:  make(map[string]int),
+		Metadata: f.Metadata,
 	}
 
 	if result != nil {
 		output.HasRisks = result.HasRisks
 
 		for _, issue := range result.Issues {
-			output.Issues = append(output.Issues, jsonIssue{
-				Type:    issueTypeToString(issue.Type),
-				Key:     issue.Key,
-				Message: issue.Message,
-			})
+			output.Issues = append(output.Issues, newJSONIssue(issue))
 		}
-
-		for issueType, count := range result.Summary {
-			output.Summary[issueTypeToString(issueType)] = count
+		output.Files = newJSONFiles(result.Files)
+		for _, d := range result.Diagnostics {
+			output.ParseErrors = append(output.ParseErrors, jsonParseError{File: d.File, Line: d.Line, Reason: d.Reason, Length: d.Length})
 		}
+		output.Summary = jsonSummary(result.Summary)
+		output.Stats = newJSONStats(result)
 	}
 
 	data, err := json.Marshal(output)
@@ -189,74 +344,392 @@ func (f *JSONFormatter) Format(result *audit.Result) string {
 	return string(data)
 }
 
-// FormatSummary produces human-readable output grouped by issue type
-func FormatSummary(result *audit.Result) string {
-	if result == nil || len(result.Issues) == 0 {
-		return "env-audit scan results\n======================\n\nNo issues found.\n"
+// newJSONIssue converts an issue to its JSON representation
+func newJSONIssue(issue audit.Issue) jsonIssue {
+	entry := jsonIssue{
+		ID:      issue.ID(),
+		Type:    issueTypeToString(issue.Type),
+		Key:     issue.Key,
+		Message: issue.Message,
+		File:    issue.File,
+		Line:    issue.Line,
+		Rule:    issue.Rule,
+
+		Remediation: newRemediation(issue),
+	}
+	if pattern, ok := audit.PatternByID(issue.Rule); ok {
+		entry.Docs = pattern.DocsURL
 	}
+	return entry
+}
 
-	// Group issues by type
-	groups := make(map[audit.IssueType][]audit.Issue)
+// newJSONFiles converts per-file scan statuses to their JSON representation
+func newJSONFiles(files []audit.FileStatus) []jsonFile {
+	var entries []jsonFile
+	for _, file := range files {
+		entry := jsonFile{Path: file.Path, Status: "ok"}
+		if file.Error != nil {
+			entry.Status = "error"
+			entry.Error = file.Error.Error()
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// jsonSummary keys issue counts by issue type name
+func jsonSummary(summary map[audit.IssueType]int) map[string]int {
+	counts := make(map[string]int, len(summary))
+	for issueType, count := range summary {
+		counts[issueTypeToString(issueType)] = count
+	}
+	return counts
+}
+
+// newJSONStats counts the issues of result by severity, rule and file.
+// Issues not tied to a file are counted under "environment".
+func newJSONStats(result *audit.Result) *jsonStats {
+	stats := &jsonStats{
+		BySeverity: make(map[string]int),
+		ByRule:     make(map[string]int),
+		ByFile:     make(map[string]int),
+	}
+	for _, file := range result.Files {
+		stats.ByFile[file.Path] += 0
+	}
 	for _, issue := range result.Issues {
-		groups[issue.Type] = append(groups[issue.Type], issue)
+		stats.BySeverity[issue.Level()]++
+		rule := issue.Rule
+		if rule == "" {
+			rule = issueTypeToString(issue.Type)
+		}
+		stats.ByRule[rule]++
+		file := issue.File
+		if file == "" {
+			file = "environment"
+		}
+		stats.ByFile[file]++
+	}
+	return stats
+}
+
+// redactor returns the formatter's Redactor or the default policy
+func (f *TextFormatter) redactor() *redact.Redactor {
+	if f.Redactor != nil {
+		return f.Redactor
+	}
+	return redact.Default()
+}
+
+// FormatSummary produces human-readable output grouped by issue type
+func FormatSummary(result *audit.Result) string {
+	return formatSummary(result, textOptions{Redactor: redact.Default()})
+}
+
+// formatSummary produces the plain text report
+func formatSummary(result *audit.Result, opts textOptions) string {
+	if result == nil || (len(result.Issues) == 0 && result.Failed() == 0) {
+		if opts.Color {
+			return reportTitle() + "\n" + colorGreen + i18n.T("No issues found.") + colorReset + "\n" + formatDiagnostics(result)
+		}
+		return reportTitle() + "\n" + i18n.T("No issues found.") + "\n" + formatDiagnostics(result)
 	}
 
+	multiFile := len(result.Files) > 1
+
 	var sb strings.Builder
-	sb.WriteString("env-audit scan results\n")
-	sb.WriteString("======================\n")
+	sb.WriteString(reportTitle())
+
+	var shown int
+	switch opts.GroupBy {
+	case groupByPrefix:
+		shown = writePrefixGroups(&sb, result.Issues, multiFile, opts)
+	case groupByFile:
+		shown = writeFileGroups(&sb, result, opts)
+	default:
+		shown = writeTypeGroups(&sb, result.Issues, "", multiFile, opts.MaxDisplay, opts)
+	}
+	if hidden := len(result.Issues) - shown; hidden > 0 {
+		sb.WriteString("\n" + moreIssues(hidden) + "\n")
+	}
 
-	// Output each group in order
-	typeOrder := []audit.IssueType{audit.IssueEmpty, audit.IssueMissing, audit.IssueSensitive, audit.IssueDuplicate, audit.IssueExtra, audit.IssueLeak}
-	typeNames := map[audit.IssueType]string{
-		audit.IssueEmpty:     "Empty Values",
-		audit.IssueMissing:   "Missing Required",
-		audit.IssueSensitive: "Sensitive Keys Detected",
-		audit.IssueDuplicate: "Duplicate Keys",
-		audit.IssueExtra:     "Extra Variables",
-		audit.IssueLeak:      "Potential Leaks",
+	sb.WriteString(formatDiagnostics(result))
+	sb.WriteString(formatFileStatuses(result))
+	sb.WriteString(formatSummaryLine(result))
+	return sb.String()
+}
+
+// writeTypeGroups writes issues grouped under their type headings, in
+// report order and indented by indent, until limit issues are listed (0 for
+// all). It returns the number of issues listed.
+func writeTypeGroups(sb *strings.Builder, issues []audit.Issue, indent string, multiFile bool, limit int, opts textOptions) int {
+	groups := make(map[audit.IssueType][]audit.Issue)
+	for _, issue := range issues {
+		groups[issue.Type] = append(groups[issue.Type], issue)
 	}
 
-	for _, t := range typeOrder {
-		issues := groups[t]
-		if len(issues) == 0 {
+	shown := 0
+	for _, t := range issueTypeOrder {
+		listed := groups[t]
+		if len(listed) == 0 {
 			continue
 		}
-		sb.WriteString(fmt.Sprintf("\n%s (%d):\n", typeNames[t], len(issues)))
-		for _, issue := range issues {
-			if t == audit.IssueSensitive {
-				sb.WriteString(fmt.Sprintf("  - %s: [REDACTED]\n", issue.Key))
-			} else if t == audit.IssueLeak {
-				sb.WriteString(fmt.Sprintf("  - %s: %s\n", issue.Key, issue.Message))
-			} else {
-				sb.WriteString(fmt.Sprintf("  - %s\n", issue.Key))
+		if limit > 0 {
+			if shown >= limit {
+				break
 			}
+			if remaining := limit - shown; len(listed) > remaining {
+				listed = listed[:remaining]
+			}
+		}
+		color := ""
+		if opts.Color {
+			color = groupColor(t)
+			sb.WriteString(color)
+		}
+		sb.WriteString(fmt.Sprintf("\n%s%s (%d):\n", indent, issueTypeName(t), len(groups[t])))
+		for _, issue := range listed {
+			sb.WriteString(indentLines(formatIssueLine(issue, multiFile, opts), indent))
 		}
+		if color != "" {
+			sb.WriteString(colorReset)
+		}
+		shown += len(listed)
+	}
+	return shown
+}
+
+// formatIssueLine renders a single issue as a list item in the text report.
+// Multi-file reports name the file; single-file reports only give the line.
+func formatIssueLine(issue audit.Issue, showFile bool, opts textOptions) string {
+	line := "  - " + issue.Key
+	if issue.Key == "" {
+		// A parse error's line has no key; its location stands in
+		line = "  -"
+	}
+	switch {
+	case showFile && issue.File != "":
+		line += " (" + audit.Location{File: issue.File, Line: issue.Line}.String() + ")"
+	case issue.File != "" && issue.Line > 0:
+		line += i18n.T(" (line %d)", issue.Line)
+	}
+	switch issue.Type {
+	case audit.IssueSensitive:
+		line += ": " + opts.Redactor.Mask("")
+	case audit.IssueEmpty, audit.IssueMissing, audit.IssueDuplicate, audit.IssueExtra:
+		// The section heading already says everything
+	default:
+		line += ": " + issue.Message
 	}
+	line += "\n"
+	if desc := opts.Descriptions[issue.Key]; desc != "" {
+		line += "      " + strings.ReplaceAll(desc, "\n", "\n      ") + "\n"
+	}
+	return line
+}
+
+// moreIssues is the footer replacing the issues cut by --max-display
+func moreIssues(hidden int) string {
+	return i18n.T("and %d more… (raise --max-display or use --json for the full list)", hidden)
+}
+
+// formatDiagnostics lists the lines the parser could not read, which are
+// not issues unless --warn-parse makes them so
+func formatDiagnostics(result *audit.Result) string {
+	if result == nil || len(result.Diagnostics) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("\n" + i18n.T("Unparsed Lines (%d):", len(result.Diagnostics)) + "\n")
+	for _, d := range result.Diagnostics {
+		sb.WriteString("  - " + d.String() + "\n")
+	}
+	return sb.String()
+}
 
-	sb.WriteString(fmt.Sprintf("\nSummary: %d issues found\n", len(result.Issues)))
+// formatFileStatuses lists per-file scan status for multi-file runs
+func formatFileStatuses(result *audit.Result) string {
+	if len(result.Files) < 2 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("\n" + i18n.T("Files (%d):", len(result.Files)) + "\n")
+	for _, file := range result.Files {
+		if file.Error != nil {
+			sb.WriteString(fmt.Sprintf("  - %s: %s: %v\n", file.Path, i18n.T("error"), file.Error))
+		} else {
+			sb.WriteString(fmt.Sprintf("  - %s: ok\n", file.Path))
+		}
+	}
 	return sb.String()
 }
 
+// formatSummaryLine renders the closing summary line of the text report
+func formatSummaryLine(result *audit.Result) string {
+	if failed := result.Failed(); failed > 0 {
+		return "\n" + i18n.T("Summary: %d issues found, %d of %d files failed", len(result.Issues), failed, len(result.Files)) + "\n"
+	}
+	return "\n" + i18n.T("Summary: %d issues found", len(result.Issues)) + "\n"
+}
+
+// reportTitle is the underlined heading of the text report
+func reportTitle() string {
+	title := i18n.T("env-audit scan results")
+	return title + "\n" + strings.Repeat("=", utf8.RuneCountInString(title)) + "\n"
+}
+
+// formatOneLineSummary renders the --summary line, e.g. "3 issues (1 error) in .env".
+// Info-level findings are not counted as issues.
+func formatOneLineSummary(result *audit.Result, files []string) string {
+	var errs, warnings int
+	for _, issue := range result.Issues {
+		switch issue.Level() {
+		case audit.SeverityInfo:
+		case audit.SeverityWarning:
+			warnings++
+		default:
+			errs++
+		}
+	}
+
+	line := pluralize(errs+warnings, "issue")
+	var parts []string
+	if errs > 0 {
+		parts = append(parts, pluralize(errs, "error"))
+	}
+	if warnings > 0 {
+		parts = append(parts, pluralize(warnings, "warning"))
+	}
+	if len(parts) > 0 {
+		line += " (" + strings.Join(parts, ", ") + ")"
+	}
+
+	switch {
+	case len(result.Files) > 1:
+		line += " in " + pluralize(len(result.Files), "file")
+	case len(files) == 1:
+		line += " in " + files[0]
+	case len(files) > 1:
+		line += " in " + pluralize(len(files), "file")
+	default:
+		line += " in environment"
+	}
+	if failed := result.Failed(); failed > 0 {
+		line += fmt.Sprintf(", %d failed", failed)
+	}
+	return line
+}
+
+// pluralize formats a count with an English noun, e.g. "1 error", "2 errors"
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
 // PrintUsage outputs help text
 func PrintUsage(w io.Writer) {
 	fmt.Fprintln(w, "env-audit [options]")
+	for _, name := range commandOrder {
+		fmt.Fprintln(w, commands[name].synopsis)
+	}
+	fmt.Fprintln(w, "env-audit version [--check]")
+	fmt.Fprintln(w, "env-audit remote-diff --provider <name> --app <app> [--target <targets>] [--file <path>] [--keys-only] [--concurrency <n>] [--timeout <duration>]")
+	fmt.Fprintln(w, "env-audit org --repos <path> [--workdir <dir>] [options]")
+	fmt.Fprintln(w, "env-audit fmt [--check] [--sort] [--backup[=suffix]] [files...]")
+	fmt.Fprintln(w, "env-audit fix [--dry-run] [--sort] [--backup[=suffix]] [files...]")
+	fmt.Fprintln(w, "env-audit merge file1 file2 [...] [-o out.env] [--strategy last-wins|first-wins|error-on-conflict|interactive]")
+	fmt.Fprintln(w, "env-audit lint [--dialects node,python,ruby,go] [files...]")
+	fmt.Fprintln(w, "env-audit usage [--json] [--no-ignore] [dir]")
+	fmt.Fprintln(w, "env-audit push --provider vault|ssm --prefix <path> [--file <path>] [--all] [--dry-run]")
+	fmt.Fprintln(w, "env-audit config show [--origin] [options]")
+	fmt.Fprintln(w, "env-audit serve [--addr host:port]")
 	fmt.Fprintln(w, "")
 	fmt.Fprintln(w, "Options:")
-	fmt.Fprintln(w, "  --file, -f <path>     Path to .env file to scan")
+	fmt.Fprintln(w, "  --file, -f <path>     Path to .env file to scan (repeatable)")
+	fmt.Fprintln(w, "  --recursive, -R [dir] Scan env files under dir and --file directories (default: .)")
+	fmt.Fprintln(w, "  --no-ignore           With -R, also walk gitignored, vendor and node_modules dirs")
+	fmt.Fprintln(w, "  --exclude-path <globs> With -R, skip paths matching these .gitignore-style patterns")
+	fmt.Fprintln(w, "  --all-projects        Audit every project of the config file, concurrently")
+	fmt.Fprintln(w, "  --config <path>       Use this config file instead of searching up to the repo root")
+	fmt.Fprintln(w, "  --files-from <path>   Scan the files listed one per line in path (- for stdin)")
 	fmt.Fprintln(w, "  --required, -r <vars> Comma-separated list of required variables")
+	fmt.Fprintln(w, "  --rule KEY=REGEX      Require KEY's value to match REGEX (repeatable)")
 	fmt.Fprintln(w, "  --example, -e <path>  Path to .env.example file for comparison")
+	fmt.Fprintln(w, "  --require-all-example Treat every key in the example file as required")
+	fmt.Fprintln(w, "                        (alias: --required-from-example)")
+	fmt.Fprintln(w, "  --no-example-leaks    Skip secret detection on the example file")
+	fmt.Fprintln(w, "  --check-order         Check key order and sections match the example")
+	fmt.Fprintln(w, "  --fix-order           Reorder --file entries to match the example")
 	fmt.Fprintln(w, "  --ignore, -i <keys>   Comma-separated list of keys to ignore")
+	fmt.Fprintln(w, "  --allow-empty <keys>  Comma-separated optional keys that may be empty")
 	fmt.Fprintln(w, "  --diff <path>         Compare with another env file")
+	fmt.Fprintln(w, "  --side-by-side, -y    Show --diff as two aligned columns")
 	fmt.Fprintln(w, "  --dump, -d            Output parsed configuration (with redaction)")
+	fmt.Fprintln(w, "  --shell               With --dump, print sourceable export KEY=VALUE lines")
+	fmt.Fprintln(w, "  --sort <order>        Order --dump entries and issues by file position (default) or key")
 	fmt.Fprintln(w, "  --init                Generate .env.example from current env")
+	fmt.Fprintln(w, "  --docs                Print a Markdown table of variables and their descriptions")
+	fmt.Fprintln(w, "  --group-by <layout>   Group text reports and --docs by type (default), prefix or")
+	fmt.Fprintln(w, "                        file (default with -R)")
 	fmt.Fprintln(w, "  --force               Overwrite existing files")
+	fmt.Fprintln(w, "  --backup[=suffix]     Save files as path+suffix (default .bak) before rewriting")
+	fmt.Fprintln(w, "  --dotenv-key <uri>    Key for .env.vault files (default: $DOTENV_KEY)")
+	fmt.Fprintln(w, "  --merge               Layer multiple --file values, later files win")
+	fmt.Fprintln(w, "  --flow[=env]          Merge the dotenv-flow cascade of --file (default .env) for env")
+	fmt.Fprintln(w, "                        (default: $NODE_ENV or development)")
+	fmt.Fprintln(w, "  --no-expand           Keep ${VAR} and $VAR references in values literally")
+	fmt.Fprintln(w, "  --expand-env          Resolve references the file cannot from the OS environment")
+	fmt.Fprintln(w, "  --keep-going          Keep scanning other files when one fails (default)")
+	fmt.Fprintln(w, "  --warn-parse          Report lines the parser can't read as warnings")
+	fmt.Fprintln(w, "  --fail-fast           Stop at the first file that fails to parse or has errors")
+	fmt.Fprintln(w, "  --format <name>       Output format: text, compact, json, ndjson, sarif,")
+	fmt.Fprintln(w, "                        github, gitlab, buildkite, circleci, azure or teamcity")
 	fmt.Fprintln(w, "  --json                Output results as JSON")
+	fmt.Fprintln(w, "  --ndjson              Stream one JSON object per issue and per audit run")
+	fmt.Fprintln(w, "  --sarif               Output results as SARIF 2.1.0 for code scanning")
+	fmt.Fprintln(w, "  --output, -o <path>   Also write the report to path (.json, .ndjson, .sarif, .md or text)")
+	fmt.Fprintln(w, "  --output-format <fmt> Format of the --output file: text, compact, json, ndjson,")
+	fmt.Fprintln(w, "                        sarif, github, gitlab, azure, teamcity or markdown")
+	fmt.Fprintln(w, "  --format-template <path>")
+	fmt.Fprintln(w, "                        Render results with a Go text/template file")
 	fmt.Fprintln(w, "  --github              Output results in GitHub Actions format")
-	fmt.Fprintln(w, "  --quiet, -q           Suppress stdout output")
-	fmt.Fprintln(w, "  --strict              Treat warnings as errors")
+	fmt.Fprintln(w, "  --azure               Output Azure Pipelines ##vso logging commands")
+	fmt.Fprintln(w, "  --teamcity            Output TeamCity inspection service messages")
+	fmt.Fprintln(w, "  --gitlab              Output GitLab CI sections and a Code Quality report")
+	fmt.Fprintln(w, "  --gitlab-report <path> Code Quality report path (default gl-code-quality-report.json)")
+	fmt.Fprintln(w, "  --buildkite           Output Markdown and annotate the Buildkite build")
+	fmt.Fprintln(w, "  --circleci            Write JUnit results for CircleCI's Tests tab")
+	fmt.Fprintln(w, "  --circleci-report <path> JUnit report path (default test-results/env-audit/results.xml)")
+	fmt.Fprintln(w, "  --lang <lang>         Language of report messages: en or de (default: from LANG)")
+	fmt.Fprintln(w, "  --log-level <level>   Diagnostics on stderr: debug, info or warn (default)")
+	fmt.Fprintln(w, "  --log-format <fmt>    Diagnostics format: text (default) or json")
+	fmt.Fprintln(w, "  --max-display <n>     List at most n issues in text and GitHub output")
+	fmt.Fprintln(w, "  --summary=stderr      Print a one-line summary to stderr, e.g. with --quiet")
+	fmt.Fprintln(w, "  --quiet, -q           Suppress the report (-qq also hides notices)")
+	fmt.Fprintln(w, "  --strict              Treat warnings as errors (same as --fail-on warning)")
+	fmt.Fprintln(w, "  --fail-on <severity>  Exit 1 on issues at or above error (default), warning or info")
+	fmt.Fprintln(w, "  --explain-exit        Explain on stderr which issues decided the exit code")
+	fmt.Fprintln(w, "  --reproducible        Relative paths, fixed order and no timestamps in reports")
+	fmt.Fprintln(w, "  --assert-read-only    Refuse to run if anything would be written to disk (exit 2)")
+	fmt.Fprintln(w, "  --offline             Refuse to run if anything would access the network (exit 2)")
 	fmt.Fprintln(w, "  --check-leaks         Analyze values for secret patterns")
+	fmt.Fprintln(w, "  --check-sources       Compare values with the stores their # source: comments name")
+	fmt.Fprintln(w, "  --quarantine          Empty leaked values, keeping them age-encrypted beside the file")
+	fmt.Fprintln(w, "  --quarantine-recipient <key> age recipient of quarantine files (repeatable)")
+	fmt.Fprintln(w, "  --no-format-check     Don't check provider credential formats")
+	fmt.Fprintln(w, "  --platform <name>     Check env constraints of lambda, cloudrun or heroku")
+	fmt.Fprintln(w, "  --docs-file <path>    Check the variables in Markdown docs match the example (default: ENVIRONMENT.md)")
+	fmt.Fprintln(w, "  --only <types>        Report only these issue types, e.g. empty,missing")
+	fmt.Fprintln(w, "  --exclude <types>     Drop these issue types from the report, e.g. leak,sensitive")
+	fmt.Fprintln(w, "  --patterns <path|url> Load an updated leak pattern pack")
 	fmt.Fprintln(w, "  --no-color            Disable colored output")
-	fmt.Fprintln(w, "  --watch, -w           Watch file for changes")
+	fmt.Fprintln(w, "  --no-ci-detect        Ignore CI environment defaults")
+	fmt.Fprintln(w, "  --watch, -w           Watch the file, example and config for changes")
+	fmt.Fprintln(w, "  --exec <command>      With --watch, run a command after each audit, restarting it")
+	fmt.Fprintln(w, "  --exec-if-clean       Run --exec only when the audit finds no risks")
+	fmt.Fprintln(w, "  --verbose             Show variable descriptions from config")
 	fmt.Fprintln(w, "  --version, -V         Show version")
 	fmt.Fprintln(w, "  --help, -h            Show this help message")
 	fmt.Fprintln(w, "")
@@ -265,28 +738,72 @@ func PrintUsage(w io.Writer) {
 	fmt.Fprintln(w, "  1  Risks detected")
 	fmt.Fprintln(w, "  2  Fatal error (invalid arguments, file not found)")
 	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Commands:")
+	fmt.Fprintln(w, "  scan, diff, init, watch, docs and leaks stand for the matching flags, which still work;")
+	fmt.Fprintln(w, "  a command rejects the flags of other modes (env-audit <command> --help)")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Remote Diff:")
+	fmt.Fprintln(w, "  Compares an app's live config vars with --file (default .env), values redacted")
+	fmt.Fprintln(w, "  Providers: heroku ($HEROKU_API_KEY), vercel ($VERCEL_TOKEN, $VERCEL_TEAM_ID),")
+	fmt.Fprintln(w, "             netlify ($NETLIFY_AUTH_TOKEN)")
+	fmt.Fprintln(w, "  --target takes production, preview or development (default production)")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Push:")
+	fmt.Fprintln(w, "  Uploads the sensitive keys of --file (default .env), or --all keys, to a secret")
+	fmt.Fprintln(w, "  store and lists those added or changed. Empty values are skipped.")
+	fmt.Fprintln(w, "  Stores: vault ($VAULT_ADDR, $VAULT_TOKEN, $VAULT_MOUNT), ssm ($AWS_REGION and")
+	fmt.Fprintln(w, "          AWS credentials)")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Org Scan:")
+	fmt.Fprintln(w, "  Shallow-clones or updates each repository URL listed in --repos into --workdir")
+	fmt.Fprintln(w, "  (default: user cache dir), audits its env files with leak checks, and merges")
+	fmt.Fprintln(w, "  the findings into one report labelled owner/repo/file")
+	fmt.Fprintln(w, "")
 	fmt.Fprintln(w, "Config File:")
 	fmt.Fprintln(w, "  Create .env-audit.yaml or .env-audit.yml in your project root")
 	fmt.Fprintln(w, "  CLI flags take precedence over config file values")
+	fmt.Fprintln(w, "  projects: lists the name, file, example, required keys and strict setting of")
+	fmt.Fprintln(w, "  each project of a monorepo, audited together with --all-projects")
 }
 
 // Redact returns "[REDACTED]" placeholder
 func Redact(value string) string {
-	return "[REDACTED]"
+	return redact.Default().Mask(value)
 }
 
-// ShouldUseColor determines if colored output should be used
-// Returns false if:
-// - noColor flag is true (--no-color)
-// - NO_COLOR env var is set (any value)
-// - stdout is not a TTY
+// ShouldUseColor determines if colored output should be used, in order:
+// - noColor flag (--no-color) disables color
+// - NO_COLOR env var set (any value) disables color (https://no-color.org/)
+// - FORCE_COLOR env var enables color even without a TTY, unless it is "0" or "false"
+// - CLICOLOR=0 disables color
+// - otherwise color is used only when stdout is a TTY
 func ShouldUseColor(noColor bool, isTTY bool) bool {
 	if noColor {
 		return false
 	}
-	// Check NO_COLOR environment variable (https://no-color.org/)
 	if _, exists := os.LookupEnv("NO_COLOR"); exists {
 		return false
 	}
+	if force, exists := os.LookupEnv("FORCE_COLOR"); exists {
+		return force != "0" && force != "false"
+	}
+	if os.Getenv("CLICOLOR") == "0" {
+		return false
+	}
 	return isTTY
 }
+
+// isTerminal reports whether w is a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// useColor is the single color decision for everything written to w
+func (cfg *Config) useColor(w io.Writer) bool {
+	return ShouldUseColor(cfg.NoColor, isTerminal(w))
+}
@@ -2,11 +2,13 @@ package cli
 
 import (
 	"encoding/json"
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
 
 	"env-audit/internal/audit"
+	"env-audit/internal/redact"
 
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
@@ -173,7 +175,7 @@ func TestJSONFormatter_EmptyIssues(t *testing.T) {
 		HasRisks: false,
 		Summary:  map[audit.IssueType]int{},
 	})
-	expected := `{"hasRisks":false,"issues":[],"summary":{}}`
+	expected := `{"hasRisks":false,"issues":[],"summary":{},"stats":{"bySeverity":{},"byRule":{},"byFile":{}}}`
 	if result != expected {
 		t.Errorf("expected %s, got %s", expected, result)
 	}
@@ -202,6 +204,10 @@ func TestJSONFormatter_WithIssues(t *testing.T) {
 	if !strings.Contains(result, `"message":"variable has empty value"`) {
 		t.Error("expected message")
 	}
+	id := audit.Issue{Type: audit.IssueEmpty, Key: "DATABASE_URL"}.ID()
+	if !strings.Contains(result, `"id":"`+id+`"`) {
+		t.Errorf("expected the issue ID %s", id)
+	}
 }
 
 // **Feature: env-audit-v2, Property 11: GitHub Actions format**
@@ -474,4 +480,193 @@ func TestTextFormatter_WarningsYellow(t *testing.T) {
 	if !strings.Contains(result, "\033[33m") {
 		t.Error("expected yellow color code for warnings")
 	}
-}
\ No newline at end of file
+}
+func TestCompactFormatter(t *testing.T) {
+	f := &CompactFormatter{}
+	result := f.Format(&audit.Result{
+		Issues: []audit.Issue{
+			{Type: audit.IssueEmpty, Key: "DB_URL", Message: "variable has empty value", File: ".env", Line: 3},
+			{Type: audit.IssueMissing, Key: "API_KEY", Message: "required variable is missing", File: ".env"},
+			{Type: audit.IssueSensitive, Key: "TOKEN", Message: "sensitive key detected"},
+		},
+		Files: []audit.FileStatus{{Path: "bad.env", Error: errors.New("file does not exist")}},
+	})
+
+	expected := "bad.env:1:1: error: failed to scan: file does not exist [scan_error]\n" +
+		".env:3:1: warning: DB_URL: variable has empty value [empty]\n" +
+		".env:1:1: error: API_KEY: required variable is missing [missing]\n" +
+		"environment:1:1: note: TOKEN: sensitive key detected [sensitive]\n"
+	if result != expected {
+		t.Errorf("unexpected output:\n%s", result)
+	}
+
+	if f.Format(&audit.Result{}) != "" {
+		t.Error("expected no output without issues")
+	}
+}
+
+func TestFormatOneLineSummary(t *testing.T) {
+	result := &audit.Result{Issues: []audit.Issue{
+		{Type: audit.IssueMissing, Key: "A"},
+		{Type: audit.IssueEmpty, Key: "B"},
+		{Type: audit.IssueDuplicate, Key: "C"},
+		{Type: audit.IssueSensitive, Key: "D"},
+	}}
+	if got := formatOneLineSummary(result, []string{".env"}); got != "3 issues (1 error, 2 warnings) in .env" {
+		t.Errorf("unexpected summary: %q", got)
+	}
+
+	clean := &audit.Result{}
+	if got := formatOneLineSummary(clean, nil); got != "0 issues in environment" {
+		t.Errorf("unexpected summary: %q", got)
+	}
+
+	multi := &audit.Result{
+		Issues: []audit.Issue{{Type: audit.IssueLeak, Key: "T"}},
+		Files:  []audit.FileStatus{{Path: "a"}, {Path: "b", Error: errors.New("boom")}},
+	}
+	if got := formatOneLineSummary(multi, []string{"a", "b"}); got != "1 issue (1 error) in 2 files, 1 failed" {
+		t.Errorf("unexpected summary: %q", got)
+	}
+}
+
+func TestShouldUseColor_Environment(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		noColor bool
+		isTTY   bool
+		want    bool
+	}{
+		{"FORCE_COLOR overrides non-TTY", map[string]string{"FORCE_COLOR": "1"}, false, false, true},
+		{"FORCE_COLOR=0 disables", map[string]string{"FORCE_COLOR": "0"}, false, true, false},
+		{"FORCE_COLOR=false disables", map[string]string{"FORCE_COLOR": "false"}, false, true, false},
+		{"NO_COLOR beats FORCE_COLOR", map[string]string{"NO_COLOR": "", "FORCE_COLOR": "1"}, false, true, false},
+		{"--no-color beats FORCE_COLOR", map[string]string{"FORCE_COLOR": "1"}, true, true, false},
+		{"CLICOLOR=0 disables on TTY", map[string]string{"CLICOLOR": "0"}, false, true, false},
+		{"CLICOLOR=1 keeps TTY default", map[string]string{"CLICOLOR": "1"}, false, false, false},
+		{"FORCE_COLOR beats CLICOLOR=0", map[string]string{"CLICOLOR": "0", "FORCE_COLOR": "1"}, false, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			if got := ShouldUseColor(tt.noColor, tt.isTTY); got != tt.want {
+				t.Errorf("ShouldUseColor(%v, %v) = %v, want %v", tt.noColor, tt.isTTY, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatSummary_Color(t *testing.T) {
+	result := &audit.Result{Issues: []audit.Issue{{Type: audit.IssueLeak, Key: "TOKEN", Message: "leak"}}}
+	if out := formatSummary(result, textOptions{Color: true}); !strings.Contains(out, colorRed) || !strings.Contains(out, colorReset) {
+		t.Errorf("expected colored leak group, got %q", out)
+	}
+	if out := formatSummary(result, textOptions{}); strings.Contains(out, "\033[") {
+		t.Errorf("expected no escape codes without color, got %q", out)
+	}
+}
+
+func TestFormatSummary_MaxDisplay(t *testing.T) {
+	result := &audit.Result{
+		Issues: []audit.Issue{
+			{Type: audit.IssueEmpty, Key: "A"},
+			{Type: audit.IssueEmpty, Key: "B"},
+			{Type: audit.IssueMissing, Key: "C"},
+			{Type: audit.IssueMissing, Key: "D"},
+		},
+	}
+	output := formatSummary(result, textOptions{Redactor: redact.Default(), MaxDisplay: 3})
+	if !strings.Contains(output, "  - C\n") || strings.Contains(output, "  - D\n") {
+		t.Errorf("expected the first 3 issues only, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Missing Required (2):") {
+		t.Errorf("expected group headings to keep their full count, got:\n%s", output)
+	}
+	if !strings.Contains(output, "and 1 more…") || !strings.Contains(output, "Summary: 4 issues found") {
+		t.Errorf("expected a footer and the full summary, got:\n%s", output)
+	}
+
+	if output := formatSummary(result, textOptions{Redactor: redact.Default(), MaxDisplay: 4}); strings.Contains(output, "more…") {
+		t.Errorf("expected no footer when nothing is cut, got:\n%s", output)
+	}
+}
+
+func TestGitHubFormatter_MaxDisplay(t *testing.T) {
+	result := &audit.Result{
+		Issues: []audit.Issue{
+			{Type: audit.IssueEmpty, Key: "A", Message: "variable has empty value"},
+			{Type: audit.IssueEmpty, Key: "B", Message: "variable has empty value"},
+			{Type: audit.IssueEmpty, Key: "C", Message: "variable has empty value"},
+		},
+	}
+	lines := strings.Split((&GitHubFormatter{MaxDisplay: 1}).Format(result), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], "::notice::and 2 more…") {
+		t.Errorf("expected one annotation and a notice, got %q", lines)
+	}
+}
+
+func TestGitHubFormatter_Location(t *testing.T) {
+	output := (&GitHubFormatter{}).Format(&audit.Result{
+		Issues: []audit.Issue{
+			{Type: audit.IssueLeak, Key: "TOKEN", Message: "GitHub token detected", File: "config/app,prod.env", Line: 7},
+			{Type: audit.IssueEmpty, Key: "PORT", Message: "variable has empty value", File: ".env"},
+			{Type: audit.IssueMissing, Key: "DB_URL", Message: "required variable is missing"},
+		},
+	})
+	want := "::error file=config/app%2Cprod.env,line=7::TOKEN: GitHub token detected\n" +
+		"::warning file=.env::PORT: variable has empty value\n" +
+		"::error::DB_URL: required variable is missing"
+	if output != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", output, want)
+	}
+}
+
+func TestFormatSummary_SingleFileLines(t *testing.T) {
+	result := &audit.Result{
+		Issues: []audit.Issue{
+			{Type: audit.IssueEmpty, Key: "PORT", File: ".env", Line: 3},
+			{Type: audit.IssueMissing, Key: "DB_URL", File: ".env"},
+		},
+	}
+	output := formatSummary(result, textOptions{Redactor: redact.Default()})
+	if !strings.Contains(output, "  - PORT (line 3)\n") || !strings.Contains(output, "  - DB_URL\n") {
+		t.Errorf("expected line numbers where known, got:\n%s", output)
+	}
+}
+
+func TestJSONFormatter_Stats(t *testing.T) {
+	result := &audit.Result{
+		Issues: []audit.Issue{
+			{Type: audit.IssueLeak, Key: "TOKEN", File: ".env", Rule: "github-pat"},
+			{Type: audit.IssueLeak, Key: "AWS_KEY", File: ".env", Rule: "aws-access-key"},
+			{Type: audit.IssueEmpty, Key: "PORT", File: ".env.local"},
+			{Type: audit.IssueSensitive, Key: "PASSWORD", File: ".env.local"},
+			{Type: audit.IssueMissing, Key: "API_URL"},
+		},
+		Files: []audit.FileStatus{{Path: ".env"}, {Path: ".env.local"}, {Path: "clean.env"}},
+	}
+
+	var output jsonOutput
+	if err := json.Unmarshal([]byte((&JSONFormatter{}).Format(result)), &output); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	stats := output.Stats
+	if stats == nil {
+		t.Fatal("expected stats in the JSON output")
+	}
+	if stats.BySeverity["error"] != 3 || stats.BySeverity["warning"] != 1 || stats.BySeverity["info"] != 1 {
+		t.Errorf("unexpected severity counts: %v", stats.BySeverity)
+	}
+	if stats.ByRule["github-pat"] != 1 || stats.ByRule["aws-access-key"] != 1 || stats.ByRule["empty"] != 1 || stats.ByRule["missing"] != 1 {
+		t.Errorf("unexpected rule counts: %v", stats.ByRule)
+	}
+	if stats.ByFile[".env"] != 2 || stats.ByFile[".env.local"] != 2 || stats.ByFile["environment"] != 1 {
+		t.Errorf("unexpected file counts: %v", stats.ByFile)
+	}
+	if count, ok := stats.ByFile["clean.env"]; !ok || count != 0 {
+		t.Errorf("expected clean files to be listed with 0, got %v", stats.ByFile)
+	}
+}
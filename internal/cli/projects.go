@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+
+	"env-audit/internal/audit"
+	"env-audit/internal/config"
+	"env-audit/internal/redact"
+)
+
+// projectScan is the outcome of auditing one project
+type projectScan struct {
+	result *audit.Result
+	err    error
+}
+
+// projectConfig returns the configuration of one project: the shared
+// settings with the project's file, example, required keys and strictness.
+// --strict and --fail-on on the command line still apply to every project.
+func (cfg *Config) projectConfig(project config.Project) *Config {
+	effective := *cfg
+	effective.FilePath = project.File
+	effective.Files = nil
+	effective.ExampleFile = project.Example
+	effective.Required = project.Required
+	effective.Strict = project.Strict
+	if cfg.cliArgs != nil {
+		effective.Strict = effective.Strict || cfg.cliArgs.Strict
+		if project.Strict {
+			effective.FailOn = cfg.cliArgs.FailOn
+		}
+	}
+	// The project declaration is its policy, not a config file near its env file
+	effective.cliArgs = nil
+	return &effective
+}
+
+// scanProject audits a project's env file, and its example for leaks
+func scanProject(cfg *Config) (*audit.Result, error) {
+	examples := &exampleSet{}
+	result, err := scanFile(cfg, cfg.FilePath, examples.load)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.NoExampleLeaks {
+		return result, nil
+	}
+	leaks := audit.NewResult(examples.leaks(cfg.Ignore, cfg.PublicValues, cfg.AllowHashes), cfg.severityPolicy(), cfg.failOn())
+	return audit.Merge([]*audit.Result{result, leaks}, nil), nil
+}
+
+// runProjects implements --all-projects: every project of the config file is
+// audited concurrently with its own settings, and the results are reported
+// as one run in the order the projects are declared. A project whose file
+// can't be read is recorded in the report and the others still count.
+func runProjects(cfg *Config, redactor *redact.Redactor, stdout, stderr io.Writer) int {
+	if len(cfg.Projects) == 0 {
+		fmt.Fprintln(stderr, "Error: --all-projects: the config file declares no projects")
+		return 2
+	}
+	cfg.ndjson.begin()
+
+	scans := make([]projectScan, len(cfg.Projects))
+	done := make(chan int, len(cfg.Projects))
+	slots := make(chan struct{}, runtime.GOMAXPROCS(0))
+	for i, project := range cfg.Projects {
+		go func(i int, projectCfg *Config) {
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			result, err := scanProject(projectCfg)
+			// Scans are only written here before signalling done
+			scans[i] = projectScan{result: result, err: err}
+			done <- i
+		}(i, cfg.projectConfig(project))
+	}
+	for range cfg.Projects {
+		<-done
+	}
+
+	var results []*audit.Result
+	var statuses []audit.FileStatus
+	for i, project := range cfg.Projects {
+		scan := scans[i]
+		if scan.err != nil {
+			fmt.Fprintf(stderr, "Error: %s: %v\n", project.Name, scan.err)
+			statuses = append(statuses, audit.FileStatus{Path: project.File, Error: scan.err})
+			continue
+		}
+		cfg.logger().Info("audited project", "project", project.Name, "file", project.File, "issues", len(scan.result.Issues))
+		cfg.ndjson.issues(scan.result.Issues)
+		results = append(results, scan.result)
+		statuses = append(statuses, audit.FileStatus{Path: project.File})
+	}
+	return reportResult(cfg, audit.Merge(results, statuses), redactor, stdout, stderr)
+}
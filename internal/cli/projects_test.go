@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"env-audit/internal/config"
+)
+
+func writeProjects(t *testing.T, webStrict bool) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tmpDir, "api"), 0755)
+	os.MkdirAll(filepath.Join(tmpDir, "web"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "api", ".env"), []byte("DATABASE_URL=postgres://db\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "api", ".env.example"), []byte("DATABASE_URL=\nREDIS_URL=\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "web", ".env"), []byte("PUBLIC_URL=\n"), 0644)
+	strict := "false"
+	if webStrict {
+		strict = "true"
+	}
+	os.WriteFile(filepath.Join(tmpDir, ".env-audit.yaml"), []byte(`projects:
+  - name: api
+    file: api/.env
+    example: api/.env.example
+    required: [DATABASE_URL]
+  - name: web
+    file: web/.env
+    strict: `+strict+`
+`), 0644)
+	return tmpDir
+}
+
+func TestRun_AllProjects(t *testing.T) {
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+
+	os.Chdir(writeProjects(t, false))
+	var stdout, stderr bytes.Buffer
+	exitCode := Run([]string{"scan", "--all-projects", "--no-color"}, &stdout, &stderr)
+	if exitCode != 1 {
+		t.Errorf("expected the api's missing example key to fail the run, got %d: %s", exitCode, stderr.String())
+	}
+	output := stdout.String()
+	for _, want := range []string{
+		filepath.Join("api", ".env") + " (1):\n\n  Missing Required (1):\n    - REDIS_URL\n",
+		filepath.Join("web", ".env") + " (1):\n\n  Empty Values (1):\n    - PUBLIC_URL (line 1)\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected a section per project file, got:\n%s", output)
+		}
+	}
+}
+
+func TestRun_AllProjectsStrictness(t *testing.T) {
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+
+	dir := writeProjects(t, true)
+	os.WriteFile(filepath.Join(dir, "api", ".env"), []byte("DATABASE_URL=postgres://db\nREDIS_URL=redis://cache\n"), 0644)
+	os.Chdir(dir)
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := Run([]string{"--all-projects", "--quiet"}, &stdout, &stderr); exitCode != 1 {
+		t.Errorf("expected web's empty value to fail the strict project, got %d: %s", exitCode, stderr.String())
+	}
+
+	os.WriteFile(".env-audit.yaml", []byte("projects:\n  - name: web\n    file: web/.env\n"), 0644)
+	if exitCode := Run([]string{"--all-projects", "--quiet"}, &stdout, &stderr); exitCode != 0 {
+		t.Errorf("expected an empty value to pass a lenient project, got %d: %s", exitCode, stderr.String())
+	}
+	if exitCode := Run([]string{"--all-projects", "--quiet", "--strict"}, &stdout, &stderr); exitCode != 1 {
+		t.Errorf("expected --strict to apply to every project, got %d", exitCode)
+	}
+}
+
+func TestRun_AllProjectsErrors(t *testing.T) {
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+
+	dir := writeProjects(t, false)
+	os.Chdir(dir)
+	os.Remove(filepath.Join("web", ".env"))
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := Run([]string{"--all-projects", "--no-color"}, &stdout, &stderr); exitCode != 2 {
+		t.Errorf("expected exit 2 for a project that can't be read, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "Error: web:") {
+		t.Errorf("expected the failing project to be named, got %q", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "REDIS_URL") {
+		t.Errorf("expected the other projects to still be reported, got:\n%s", stdout.String())
+	}
+
+	os.WriteFile(".env-audit.yaml", []byte("strict: true\n"), 0644)
+	stderr.Reset()
+	if exitCode := Run([]string{"--all-projects"}, &stdout, &stderr); exitCode != 2 || !strings.Contains(stderr.String(), "declares no projects") {
+		t.Errorf("expected an error without projects, got %d: %s", exitCode, stderr.String())
+	}
+}
+
+func TestParseArgs_AllProjectsConflicts(t *testing.T) {
+	for _, args := range [][]string{
+		{"--all-projects", "--file", ".env"},
+		{"--all-projects", "-R"},
+		{"--all-projects", "--watch"},
+		{"--all-projects", "--dump"},
+	} {
+		if _, err := ParseArgs(args); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
+
+func TestProjectConfig(t *testing.T) {
+	cfg := &Config{FilePath: ".env", Files: []string{".env"}, ExampleFile: ".env.example", Required: []string{"PORT"}, FailOn: "info"}
+	cfg.cliArgs = &Config{}
+
+	projectCfg := cfg.projectConfig(config.Project{Name: "api", File: "api/.env", Strict: true})
+	if projectCfg.FilePath != "api/.env" || projectCfg.Files != nil || projectCfg.ExampleFile != "" || projectCfg.Required != nil {
+		t.Errorf("expected the project's own files and keys, got %+v", projectCfg)
+	}
+	if projectCfg.failOn() != "warning" {
+		t.Errorf("expected a strict project to fail on warnings, got %s", projectCfg.failOn())
+	}
+	if projectCfg.cliArgs != nil {
+		t.Error("expected nested config files not to apply to projects")
+	}
+	if lenient := cfg.projectConfig(config.Project{Name: "web", File: "web/.env"}); lenient.failOn() != "info" {
+		t.Errorf("expected a lenient project to keep the config's fail_on, got %s", lenient.failOn())
+	}
+}
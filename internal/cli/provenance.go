@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"env-audit/internal/audit"
+	"env-audit/internal/parser"
+	"env-audit/internal/remote"
+)
+
+// openSource opens the secret store of a declared source; replaced in tests
+var openSource = remote.OpenSource
+
+// sourceResolver looks up the values at the sources an env file declares,
+// reading each store path or file once
+type sourceResolver struct {
+	dir   string              // directory of the env file, which file sources are relative to
+	opts  parser.ParseOptions // how file sources are parsed
+	cache map[string]map[string]string
+}
+
+// lookup implements audit.SourceLookup
+func (r *sourceResolver) lookup(source audit.Source) (string, bool, error) {
+	id := source.Store + ":" + source.Path
+	values, ok := r.cache[id]
+	if !ok {
+		var err error
+		if values, err = r.fetch(source); err != nil {
+			return "", false, err
+		}
+		r.cache[id] = values
+	}
+	value, found := values[source.Key]
+	return value, found, nil
+}
+
+// fetch reads every value at a source's path. A source file that is gone
+// holds nothing, so its keys are reported as no longer existing.
+func (r *sourceResolver) fetch(source audit.Source) (map[string]string, error) {
+	if source.Store == "file" {
+		path := source.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(r.dir, path)
+		}
+		result, err := parser.ParseEnvFileWith(path, r.opts)
+		if os.IsNotExist(err) {
+			return map[string]string{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return result.Entries, nil
+	}
+	store, prefix, err := openSource(source.Store, source.Path)
+	if err != nil {
+		return nil, err
+	}
+	return store.Secrets(prefix)
+}
+
+// remoteSourceStores returns the stores other than file that the
+// "# source:" comments of files name, sorted. Files that fail to parse are
+// skipped; the audit reports them.
+func remoteSourceStores(files []string, opts parser.ParseOptions) []string {
+	seen := make(map[string]bool)
+	for _, path := range files {
+		result, err := parser.ParseEnvFileWith(path, opts)
+		if err != nil {
+			continue
+		}
+		for key := range result.Entries {
+			ref, ok := result.Annotation(key, "source")
+			if !ok {
+				continue
+			}
+			if source, err := audit.ParseSource(ref, key); err == nil && source.Store != "file" {
+				seen[source.Store] = true
+			}
+		}
+	}
+	stores := make([]string, 0, len(seen))
+	for store := range seen {
+		stores = append(stores, store)
+	}
+	sort.Strings(stores)
+	return stores
+}
+
+// checkSources compares the values of an env file annotated with
+// "# source: store:path#KEY" against their sources
+func checkSources(cfg *Config, path string, result *parser.ParseResult) (*audit.Result, error) {
+	refs := make(map[string]string)
+	for key := range result.Entries {
+		if ref, ok := result.Annotation(key, "source"); ok {
+			refs[key] = ref
+		}
+	}
+	resolver := &sourceResolver{dir: filepath.Dir(path), opts: cfg.parseOptions(), cache: make(map[string]map[string]string)}
+	issues, err := audit.CheckSourceDrift(path, result.Entries, refs, result.Lines, resolver.lookup, cfg.Ignore)
+	if err != nil {
+		return nil, err
+	}
+	return audit.NewResult(issues, cfg.severityPolicy(), cfg.failOn()), nil
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"env-audit/internal/remote"
+)
+
+func TestRun_CheckSources(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tmpDir, "shared"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "shared", ".env"), []byte("REGION=eu-west-1\n"), 0644)
+	envFile := filepath.Join(tmpDir, ".env")
+	os.WriteFile(envFile, []byte(`# source: vault:secret/app
+API_KEY=current
+# source: vault:secret/app#DATABASE_PASSWORD
+DB_PASSWORD=old
+# source: file:shared/.env
+REGION=us-east-1
+PORT=8080
+`), 0644)
+
+	var opened []string
+	store := &fakeStore{secrets: map[string]string{"API_KEY": "current", "DATABASE_PASSWORD": "rotated"}}
+	oldOpen := openSource
+	openSource = func(name, path string) (remote.Store, string, error) {
+		opened = append(opened, name+":"+path)
+		return store, path, nil
+	}
+	defer func() { openSource = oldOpen }()
+
+	var stdout, stderr bytes.Buffer
+	exitCode := Run([]string{"-f", envFile, "--check-sources", "--no-color"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Errorf("expected drift to be a warning, got exit %d: %s", exitCode, stderr.String())
+	}
+	output := stdout.String()
+	for _, want := range []string{
+		"Source Drift (2):",
+		"DB_PASSWORD (line 4): value differs from its source vault:secret/app#DATABASE_PASSWORD",
+		"REGION (line 6): value differs from its source file:shared/.env#REGION",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "rotated") || strings.Contains(output, "eu-west-1") {
+		t.Errorf("expected source values never to be printed:\n%s", output)
+	}
+	if len(opened) != 1 {
+		t.Errorf("expected the vault path to be read once, got %v", opened)
+	}
+
+	stdout.Reset()
+	Run([]string{"-f", envFile, "--no-color"}, &stdout, &stderr)
+	if strings.Contains(stdout.String(), "Source Drift") {
+		t.Errorf("expected sources to be checked only with --check-sources, got:\n%s", stdout.String())
+	}
+}
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"env-audit/internal/audit"
+	"env-audit/internal/config"
+	"env-audit/internal/netutil"
+	"env-audit/internal/parser"
+	"env-audit/internal/redact"
+	"env-audit/internal/remote"
+)
+
+// newStore builds the secret store; replaced in tests
+var newStore = remote.NewStore
+
+// pushConfig holds the arguments of the push command
+type pushConfig struct {
+	Provider string // --provider secret store name
+	Prefix   string // --prefix path of the secrets in the store, e.g. app/prod
+	File     string // --file env file to push, defaults to .env
+	All      bool   // --all push every variable, not only sensitive keys
+	DryRun   bool   // --dry-run print what would change without pushing
+	Quiet    bool   // --quiet/-q print nothing but errors
+	Offline  bool   // --offline refuse to run, as the store is on the network
+}
+
+// parsePushArgs parses the arguments following "push"
+func parsePushArgs(args []string) (*pushConfig, error) {
+	cfg := &pushConfig{File: ".env"}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--all":
+			cfg.All = true
+		case "--dry-run":
+			cfg.DryRun = true
+		case "--quiet", "-q":
+			cfg.Quiet = true
+		case "--offline":
+			cfg.Offline = true
+		case "--provider", "--prefix", "--file", "-f":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			switch arg {
+			case "--provider":
+				cfg.Provider = args[i]
+			case "--prefix":
+				cfg.Prefix = args[i]
+			default:
+				cfg.File = args[i]
+			}
+		default:
+			return nil, fmt.Errorf("unknown argument: %s", arg)
+		}
+	}
+	if cfg.Provider == "" || cfg.Prefix == "" {
+		return nil, fmt.Errorf("push requires --provider and --prefix")
+	}
+	if cfg.Offline {
+		return nil, fmt.Errorf("--offline: push would write secrets to %s", cfg.Provider)
+	}
+	return cfg, nil
+}
+
+// runPush uploads the sensitive variables of an env file, or all of them,
+// to a secret store and prints which keys it added or changed. Values are
+// never printed. Keys already stored with the same value are left alone.
+func runPush(args []string, stdout, stderr io.Writer) int {
+	pcfg, err := parsePushArgs(args)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+
+	// The project's sensitive key patterns and expansion settings decide
+	// what is pushed and as which value
+	cfg := &Config{}
+	if configPath := config.FindConfigFile(); configPath != "" {
+		fileCfg, err := config.LoadFile(configPath)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+		cfg.MergeWithFileConfig(toFileConfig(fileCfg))
+	}
+	if err := netutil.Configure(cfg.httpOptions()); err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	audit.SensitiveKeys = audit.DefaultSensitiveKeys.Extend(cfg.SensitivePatterns, cfg.SensitiveExclude)
+
+	local, err := parser.ParseEnvFileWith(pcfg.File, cfg.parseOptions())
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	secrets := make(map[string]string)
+	empty := 0
+	for key, value := range local.Entries {
+		if !pcfg.All && !audit.IsSensitiveKey(key) {
+			continue
+		}
+		if value == "" {
+			empty++
+			continue
+		}
+		secrets[key] = value
+	}
+
+	store, err := newStore(pcfg.Provider)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	stored, err := store.Secrets(pcfg.Prefix)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+
+	changes := make(map[string]string)
+	// Keys that are secrets themselves are masked in the listing
+	redactor := redact.Default()
+	var lines []string
+	for _, key := range sortedKeys(secrets) {
+		old, ok := stored[key]
+		switch {
+		case !ok:
+			lines = append(lines, "+ "+redactor.Key(key)+" (added)")
+		case old != secrets[key]:
+			lines = append(lines, "~ "+redactor.Key(key)+" (changed)")
+		default:
+			continue
+		}
+		changes[key] = secrets[key]
+	}
+
+	name := pcfg.Provider + " " + pcfg.Prefix
+	if len(changes) > 0 && !pcfg.DryRun {
+		if err := store.PutSecrets(pcfg.Prefix, changes); err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+	}
+	if pcfg.Quiet {
+		return 0
+	}
+
+	switch {
+	case len(changes) == 0:
+		fmt.Fprintf(stdout, "%s is up to date with %s\n", name, pcfg.File)
+	case pcfg.DryRun:
+		fmt.Fprintf(stdout, "Would push to %s (dry run):\n", name)
+	default:
+		fmt.Fprintf(stdout, "Pushed to %s:\n", name)
+	}
+	for _, line := range lines {
+		fmt.Fprintln(stdout, line)
+	}
+	if unchanged := len(secrets) - len(changes); unchanged > 0 && len(changes) > 0 {
+		fmt.Fprintf(stdout, "%d unchanged\n", unchanged)
+	}
+	if empty > 0 {
+		fmt.Fprintf(stdout, "Skipped %s\n", pluralize(empty, "empty value"))
+	}
+	return 0
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"env-audit/internal/remote"
+)
+
+// fakeStore holds secrets in memory for push tests
+type fakeStore struct {
+	secrets map[string]string
+	puts    int
+}
+
+func (f *fakeStore) Secrets(prefix string) (map[string]string, error) {
+	secrets := make(map[string]string, len(f.secrets))
+	for key, value := range f.secrets {
+		secrets[key] = value
+	}
+	return secrets, nil
+}
+
+func (f *fakeStore) PutSecrets(prefix string, secrets map[string]string) error {
+	f.puts++
+	for key, value := range secrets {
+		f.secrets[key] = value
+	}
+	return nil
+}
+
+func withStore(t *testing.T, s remote.Store) {
+	t.Helper()
+	orig := newStore
+	newStore = func(name string) (remote.Store, error) { return s, nil }
+	t.Cleanup(func() { newStore = orig })
+}
+
+func TestRunPush(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), ".env")
+	os.WriteFile(envFile, []byte("PORT=3000\nAPI_KEY=sk-new\nDB_PASSWORD=hunter2\nSTRIPE_SECRET=\nAUTH_TOKEN=same\n"), 0644)
+	store := &fakeStore{secrets: map[string]string{"API_KEY": "sk-old", "AUTH_TOKEN": "same"}}
+	withStore(t, store)
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"push", "--provider", "vault", "--prefix", "app/prod", "-f", envFile}
+	if code := Run(append(args, "--dry-run"), &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	want := "Would push to vault app/prod (dry run):\n~ API_KEY (changed)\n+ DB_PASSWORD (added)\n1 unchanged\nSkipped 1 empty value\n"
+	if stdout.String() != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", stdout.String(), want)
+	}
+	if store.puts != 0 {
+		t.Error("expected a dry run not to write to the store")
+	}
+
+	stdout.Reset()
+	if code := Run(args, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if store.secrets["API_KEY"] != "sk-new" || store.secrets["DB_PASSWORD"] != "hunter2" || store.secrets["PORT"] != "" {
+		t.Errorf("expected only sensitive keys to be pushed, got %v", store.secrets)
+	}
+	if !strings.HasPrefix(stdout.String(), "Pushed to vault app/prod:\n") || strings.Contains(stdout.String(), "hunter2") {
+		t.Errorf("expected the changed keys without values, got:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	Run(args, &stdout, &stderr)
+	if !strings.HasPrefix(stdout.String(), "vault app/prod is up to date with "+envFile) || store.puts != 1 {
+		t.Errorf("expected nothing left to push, got:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	Run(append(args, "--all"), &stdout, &stderr)
+	if store.secrets["PORT"] != "3000" || !strings.Contains(stdout.String(), "+ PORT (added)") {
+		t.Errorf("expected --all to push every key, got %v", store.secrets)
+	}
+}
+
+func TestParsePushArgs_Invalid(t *testing.T) {
+	for _, args := range [][]string{
+		{"--provider", "vault"},
+		{"--prefix", "app/prod"},
+		{"--provider", "vault", "--prefix"},
+		{"--provider", "vault", "--prefix", "app/prod", "--bogus"},
+		{"--provider", "vault", "--prefix", "app/prod", "--offline"},
+	} {
+		if _, err := parsePushArgs(args); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
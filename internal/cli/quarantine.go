@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"env-audit/internal/audit"
+	"env-audit/internal/fsutil"
+	"env-audit/internal/parser"
+)
+
+// encryptAge encrypts plaintext to age recipients as an armored age file;
+// replaced in tests
+var encryptAge = ageEncrypt
+
+// quarantineNow stamps quarantine file names; replaced in tests
+var quarantineNow = time.Now
+
+// ageEncrypt runs the age command, folding its stderr into the returned error
+func ageEncrypt(plaintext []byte, recipients []string) ([]byte, error) {
+	args := []string{"--encrypt", "--armor"}
+	for _, recipient := range recipients {
+		args = append(args, "--recipient", recipient)
+	}
+	cmd := exec.Command("age", args...)
+	cmd.Stdin = bytes.NewReader(plaintext)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("age: %s", msg)
+		}
+		return nil, fmt.Errorf("age: %w (install it from https://age-encryption.org)", err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// quarantinePath names the quarantine file of an env file, e.g.
+// .env.20260102T150405Z.quarantine.age next to .env. Every run gets its own
+// file, so an earlier quarantine is never overwritten.
+func quarantinePath(path string, now time.Time) string {
+	return path + "." + now.UTC().Format("20060102T150405Z") + ".quarantine.age"
+}
+
+// quarantineLeaks contains the secrets the audit found: in each file with a
+// leak, the leaked values are emptied and the original assignments written,
+// encrypted to the quarantine recipients, to a quarantine file beside it.
+// The quarantine file is written before the env file is rewritten, so a
+// failure never loses a value. Files that can't be rewritten in place, such
+// as archive members and .env.vault files, are reported and left alone.
+func quarantineLeaks(cfg *Config, result *audit.Result, stderr io.Writer) error {
+	if len(cfg.QuarantineTo) == 0 {
+		return fmt.Errorf("--quarantine requires --quarantine-recipient or quarantine_recipients in the config file")
+	}
+	leaked := make(map[string]map[string]bool)
+	var files []string
+	for _, issue := range result.Issues {
+		if !issue.Type.IsSecret() || issue.File == "" {
+			continue
+		}
+		if leaked[issue.File] == nil {
+			leaked[issue.File] = make(map[string]bool)
+			files = append(files, issue.File)
+		}
+		leaked[issue.File][issue.Key] = true
+	}
+
+	now := quarantineNow()
+	for _, path := range files {
+		if _, _, ok := fsutil.SplitArchivePath(path); ok || parser.IsVaultFile(path) {
+			fmt.Fprintf(stderr, "Warning: %s can't be rewritten, its leaks were not quarantined\n", path)
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		doc, err := parser.ParseDocument(path)
+		if err != nil {
+			return err
+		}
+		rewritten, blanked := doc.BlankValues(string(content), leaked[path])
+		if len(blanked) == 0 {
+			continue
+		}
+
+		sealed, err := encryptAge([]byte(strings.Join(blanked, "\n")+"\n"), cfg.QuarantineTo)
+		if err != nil {
+			return err
+		}
+		target := quarantinePath(path, now)
+		if err := fsutil.WriteFileAtomic(target, sealed, 0o600); err != nil {
+			return err
+		}
+		if err := fsutil.WriteFileAtomic(path, []byte(rewritten), info.Mode().Perm()); err != nil {
+			return err
+		}
+		var keys []string
+		seen := make(map[string]bool)
+		for _, entry := range doc.Entries {
+			if leaked[path][entry.Key] && !seen[entry.Key] {
+				keys = append(keys, entry.Key)
+				seen[entry.Key] = true
+			}
+		}
+		cfg.notify(stderr, fmt.Sprintf("Quarantined %s from %s to %s", strings.Join(keys, ", "), path, filepath.Base(target)))
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// writingFlags lists the requested flags that would write to disk, which
+// --assert-read-only refuses: generating or rewriting env files, quarantining leaks and writing
+// reports or CI artifacts
+func (cfg *Config) writingFlags() []string {
+	var flags []string
+	if cfg.Init {
+		flags = append(flags, "--init")
+	}
+	if cfg.FixOrder {
+		flags = append(flags, "--fix-order")
+	}
+	if cfg.Quarantine {
+		flags = append(flags, "--quarantine")
+	}
+	if cfg.Output != "" {
+		flags = append(flags, "--output")
+	}
+	if cfg.GitLabOutput {
+		flags = append(flags, "--gitlab")
+	}
+	if cfg.CircleCIOutput {
+		flags = append(flags, "--circleci")
+	}
+	return flags
+}
+
+// checkReadOnly fails if --assert-read-only is set and the run would write
+// to disk
+func (cfg *Config) checkReadOnly() error {
+	if !cfg.AssertReadOnly {
+		return nil
+	}
+	if flags := cfg.writingFlags(); len(flags) > 0 {
+		return fmt.Errorf("--assert-read-only: %s would write to disk", strings.Join(flags, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_AssertReadOnly(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+	os.WriteFile(".env", []byte("PORT=8080\n"), 0644)
+	os.WriteFile(".env.example", []byte("PORT=\n"), 0644)
+
+	refused := [][]string{
+		{"-f", ".env", "--init", "--force"},
+		{"-f", ".env", "-e", ".env.example", "--fix-order"},
+		{"-f", ".env", "--output", "report.json"},
+		{"-f", ".env", "--format", "gitlab"},
+		{"-f", ".env", "--format", "circleci"},
+	}
+	for _, args := range refused {
+		var stdout, stderr bytes.Buffer
+		code := Run(append(args, "--assert-read-only"), &stdout, &stderr)
+		if code != 2 {
+			t.Errorf("%v: expected exit 2, got %d", args, code)
+		}
+		if !strings.Contains(stderr.String(), "would write to disk") {
+			t.Errorf("%v: expected a read-only error, got %q", args, stderr.String())
+		}
+	}
+
+	entries, _ := os.ReadDir(tmpDir)
+	if len(entries) != 2 {
+		t.Errorf("expected no files to be written, found %d entries", len(entries))
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"-f", ".env", "--assert-read-only", "--json"}, &stdout, &stderr); code != 0 {
+		t.Errorf("expected a plain audit to run, got exit %d: %s", code, stderr.String())
+	}
+}
+
+func TestRun_AssertReadOnlySkipsCIReports(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+	os.WriteFile(".env", []byte("PORT=\n"), 0644)
+	t.Setenv("GITLAB_CI", "true")
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"-f", ".env", "--assert-read-only"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d: %s", code, stderr.String())
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, defaultCodeQualityReport)); !os.IsNotExist(err) {
+		t.Errorf("expected no Code Quality report under --assert-read-only, got %v", err)
+	}
+}
+
+func TestRun_AssertReadOnlyRefusesOrg(t *testing.T) {
+	withRepos(t, map[string]map[string]string{
+		"https://github.com/acme/api": {".env": "PORT=3000\n"},
+	})
+	repos := writeRepoList(t, "https://github.com/acme/api")
+	workdir := t.TempDir()
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"org", "--repos", repos, "--workdir", workdir, "--assert-read-only"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit 2, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "would write to disk") {
+		t.Errorf("expected a read-only error, got %q", stderr.String())
+	}
+	if entries, _ := os.ReadDir(workdir); len(entries) != 0 {
+		t.Errorf("expected nothing cloned, found %d entries", len(entries))
+	}
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"env-audit/internal/audit"
+	"env-audit/internal/parser"
+)
+
+// jsonRemediation is a machine-readable fix for an issue, so bots and editor
+// quick-fixes can act on it without parsing the message
+type jsonRemediation struct {
+	Action  string `json:"action"`            // what to do, e.g. set_value or rotate_secret
+	Key     string `json:"key"`               // the variable to change
+	File    string `json:"file,omitempty"`    // the file to change, empty for the environment
+	Command string `json:"command,omitempty"` // a shell command applying the fix, when one is safe to suggest
+}
+
+// remediationActions is the fix for each issue type
+var remediationActions = map[audit.IssueType]string{
+	audit.IssueEmpty:         "set_value",
+	audit.IssueMissing:       "add_key",
+	audit.IssueSensitive:     "move_to_secret_store",
+	audit.IssueDuplicate:     "remove_duplicate",
+	audit.IssueLeak:          "rotate_secret",
+	audit.IssueExtra:         "document_key",
+	audit.IssueDefault:       "set_value",
+	audit.IssueUnsafeDefault: "set_value",
+	audit.IssueOverride:      "review_override",
+	audit.IssueOrder:         "reorder",
+	audit.IssueMalformed:     "set_value",
+	audit.IssueRotation:      "rotate_secret",
+	audit.IssueInvalid:       "set_value",
+	audit.IssueUnresolved:    "define_reference",
+	audit.IssueExampleSecret: "clear_value",
+	audit.IssueLimit:         "shrink",
+	audit.IssuePlatform:      "rename_key",
+	audit.IssueSystem:        "rename_key",
+	audit.IssueDocs:          "sync_docs",
+	audit.IssueSourceDrift:   "sync_from_source",
+	audit.IssueParse:         "fix_syntax",
+}
+
+// newRemediation describes how to fix an issue. A command is only
+// suggested when it follows from the issue alone, such as appending a
+// missing key to the file it is missing from.
+func newRemediation(issue audit.Issue) *jsonRemediation {
+	action, ok := remediationActions[issue.Type]
+	if !ok {
+		return nil
+	}
+	fix := &jsonRemediation{Action: action, Key: issue.Key, File: issue.File}
+	// A masked key can't be written back, so no command names it
+	if issue.File == "" || issue.Key == "" || audit.IsMaskedKey(issue.Key) {
+		return fix
+	}
+	switch issue.Type {
+	case audit.IssueMissing:
+		fix.Command = "printf " + parser.ShellQuote(issue.Key+`=\n`) + " >> " + parser.ShellQuote(issue.File)
+	case audit.IssueExampleSecret:
+		fix.Command = "sed -i.bak " + parser.ShellQuote("s/^"+issue.Key+"=.*/"+issue.Key+"=/") + " " + parser.ShellQuote(issue.File)
+	}
+	return fix
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"env-audit/internal/audit"
+)
+
+func TestNewRemediation(t *testing.T) {
+	tests := []struct {
+		issue audit.Issue
+		want  jsonRemediation
+	}{
+		{
+			audit.Issue{Type: audit.IssueLeak, Key: "TOKEN", File: ".env"},
+			jsonRemediation{Action: "rotate_secret", Key: "TOKEN", File: ".env"},
+		},
+		{
+			audit.Issue{Type: audit.IssueMissing, Key: "API_SECRET", File: "config/my app.env"},
+			jsonRemediation{Action: "add_key", Key: "API_SECRET", File: "config/my app.env", Command: `printf 'API_SECRET=\n' >> 'config/my app.env'`},
+		},
+		{
+			audit.Issue{Type: audit.IssueExampleSecret, Key: "STRIPE_KEY", File: ".env.example"},
+			jsonRemediation{Action: "clear_value", Key: "STRIPE_KEY", File: ".env.example", Command: `sed -i.bak 's/^STRIPE_KEY=.*/STRIPE_KEY=/' .env.example`},
+		},
+		{
+			audit.Issue{Type: audit.IssueMissing, Key: "API_SECRET"},
+			jsonRemediation{Action: "add_key", Key: "API_SECRET"},
+		},
+	}
+	for _, tt := range tests {
+		if got := newRemediation(tt.issue); got == nil || *got != tt.want {
+			t.Errorf("newRemediation(%+v) = %+v, want %+v", tt.issue, got, tt.want)
+		}
+	}
+
+	for _, typ := range issueTypeOrder {
+		if _, ok := remediationActions[typ]; !ok {
+			t.Errorf("no remediation action for %s", typ.Name())
+		}
+	}
+}
+
+func TestRemediation_CommandFixesIssue(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no shell available")
+	}
+	envPath := filepath.Join(t.TempDir(), ".env")
+	os.WriteFile(envPath, []byte("PORT=3000\n"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	Run([]string{"-f", envPath, "-r", "API_SECRET", "--json"}, &stdout, &stderr)
+	var output struct {
+		Issues []jsonIssue `json:"issues"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil || len(output.Issues) != 1 {
+		t.Fatalf("unexpected output %s (%v)", stdout.String(), err)
+	}
+	fix := output.Issues[0].Remediation
+	if fix == nil || fix.Command == "" {
+		t.Fatalf("expected a command for the missing key, got %+v", fix)
+	}
+	if out, err := exec.Command("sh", "-c", fix.Command).CombinedOutput(); err != nil {
+		t.Fatalf("command %q failed: %v: %s", fix.Command, err, out)
+	}
+	if data, _ := os.ReadFile(envPath); string(data) != "PORT=3000\nAPI_SECRET=\n" {
+		t.Errorf("unexpected file after the fix: %q", data)
+	}
+}
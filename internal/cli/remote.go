@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"env-audit/internal/config"
+	"env-audit/internal/netutil"
+	"env-audit/internal/parser"
+	"env-audit/internal/remote"
+)
+
+// newProvider builds the remote provider; replaced in tests
+var newProvider = remote.New
+
+// defaultRemoteTimeout bounds how long remote-diff waits for all targets
+const defaultRemoteTimeout = 2 * time.Minute
+
+// remoteDiffConfig holds the arguments of the remote-diff command
+type remoteDiffConfig struct {
+	Provider string   // --provider hosting platform name
+	App      string   // --app application, project or site to read config vars from
+	Targets  []string // --target comma-separated deployment targets, e.g. preview,production
+	File     string   // --file local env or example file, defaults to .env
+	KeysOnly bool     // --keys-only report missing and extra keys, not changed values
+	Quiet    bool     // --quiet/-q only set the exit code
+	Offline  bool     // --offline refuse to run, as every fetch needs the network
+
+	Concurrency int           // --concurrency number of targets fetched at once
+	Timeout     time.Duration // --timeout limit on fetching all targets, 0 for none
+}
+
+// parseRemoteDiffArgs parses the arguments following "remote-diff"
+func parseRemoteDiffArgs(args []string) (*remoteDiffConfig, error) {
+	cfg := &remoteDiffConfig{File: ".env", Concurrency: remote.DefaultWorkers, Timeout: defaultRemoteTimeout}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--keys-only":
+			cfg.KeysOnly = true
+		case "--quiet", "-q":
+			cfg.Quiet = true
+		case "--offline":
+			cfg.Offline = true
+		case "--concurrency", "--timeout":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			if arg == "--concurrency" {
+				n, err := strconv.Atoi(args[i])
+				if err != nil || n < 1 {
+					return nil, fmt.Errorf("--concurrency must be a positive number, got %q", args[i])
+				}
+				cfg.Concurrency = n
+			} else {
+				d, err := time.ParseDuration(args[i])
+				if err != nil || d < 0 {
+					return nil, fmt.Errorf("--timeout must be a duration such as 30s or 2m, got %q", args[i])
+				}
+				cfg.Timeout = d
+			}
+		case "--provider", "--app", "--target", "--file", "-f":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			switch arg {
+			case "--provider":
+				cfg.Provider = args[i]
+			case "--app":
+				cfg.App = args[i]
+			case "--target":
+				cfg.Targets = parseCommaSeparated(args[i])
+			default:
+				cfg.File = args[i]
+			}
+		default:
+			return nil, fmt.Errorf("unknown argument: %s", arg)
+		}
+	}
+	if cfg.Provider == "" || cfg.App == "" {
+		return nil, fmt.Errorf("remote-diff requires --provider and --app")
+	}
+	if cfg.Offline {
+		return nil, fmt.Errorf("--offline: remote-diff would fetch config vars from %s", cfg.Provider)
+	}
+	return cfg, nil
+}
+
+// runRemoteDiff compares an app's live config vars with a local env file.
+// Every value is redacted, since remote values are live credentials.
+// Exits 1 when the two have drifted apart.
+func runRemoteDiff(args []string, stdout, stderr io.Writer) int {
+	rcfg, err := parseRemoteDiffArgs(args)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+
+	// Honour the project's redaction policy from the config file
+	cfg := &Config{}
+	if configPath := config.FindConfigFile(); configPath != "" {
+		fileCfg, err := config.LoadFile(configPath)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+		cfg.MergeWithFileConfig(toFileConfig(fileCfg))
+	}
+	if err := netutil.Configure(cfg.httpOptions()); err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	redactor, err := cfg.Redactor()
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	redactor.IsSensitive = func(string) bool { return true }
+
+	local, err := parser.ParseEnvFile(rcfg.File)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	provider, err := newProvider(rcfg.Provider)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	// No --target means the provider's default (production) environment
+	targets := rcfg.Targets
+	if len(targets) == 0 {
+		targets = []string{""}
+	}
+
+	// Targets are fetched concurrently and reported in the order given
+	drifted := false
+	for _, fetch := range remote.FetchAll(context.Background(), provider, rcfg.App, targets, rcfg.Concurrency, rcfg.Timeout) {
+		target := fetch.Target
+		if fetch.Err != nil {
+			fmt.Fprintln(stderr, "Error:", fetch.Err)
+			return 2
+		}
+
+		diffResult := parser.Diff(local.Entries, fetch.Vars)
+		if rcfg.KeysOnly {
+			diffResult.Changed = map[string][2]string{}
+		}
+		diffResult.DetectRenames()
+		targetDrifted := len(diffResult.Added)+len(diffResult.Removed)+len(diffResult.Changed)+len(diffResult.Renamed) > 0
+		drifted = drifted || targetDrifted
+
+		if !rcfg.Quiet {
+			name := fmt.Sprintf("%s app %s", rcfg.Provider, rcfg.App)
+			if target != "" {
+				name += " [" + target + "]"
+			}
+			if !targetDrifted {
+				fmt.Fprintf(stdout, "No drift between %s and %s\n", rcfg.File, name)
+			} else {
+				fmt.Fprintf(stdout, "Drift between %s (-) and %s (+):\n", rcfg.File, name)
+				fmt.Fprintln(stdout, parser.FormatDiffWith(diffResult, redactor))
+			}
+		}
+	}
+
+	if drifted {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"env-audit/internal/audit"
+	"env-audit/internal/redact"
+)
+
+// reportFormats are the formats an --output file can be written in
+var reportFormats = []string{"text", "compact", "json", "ndjson", "sarif", "github", "gitlab", "azure", "teamcity", "markdown"}
+
+// isReportFormat reports whether name is one of reportFormats
+func isReportFormat(name string) bool {
+	for _, format := range reportFormats {
+		if format == name {
+			return true
+		}
+	}
+	return false
+}
+
+// outputFormat picks the format of the --output file: --output-format if
+// given, else the file extension, else plain text
+func outputFormat(path, override string) string {
+	if override != "" {
+		return override
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json"
+	case ".ndjson", ".jsonl":
+		return "ndjson"
+	case ".sarif":
+		return "sarif"
+	case ".md", ".markdown":
+		return "markdown"
+	default:
+		return "text"
+	}
+}
+
+// consoleFormat is the report format selected for stdout by the output flags
+func (cfg *Config) consoleFormat() string {
+	switch {
+	case cfg.JSONOutput:
+		return "json"
+	case cfg.NDJSONOutput:
+		return "ndjson"
+	case cfg.SARIFOutput:
+		return "sarif"
+	case cfg.GitHubOutput:
+		return "github"
+	case cfg.CompactOutput:
+		return "compact"
+	case cfg.GitLabOutput:
+		return "gitlab"
+	case cfg.AzureOutput:
+		return "azure"
+	case cfg.TeamCityOutput:
+		return "teamcity"
+	case cfg.BuildkiteOutput:
+		return "markdown"
+	case cfg.ciFormat != "":
+		return cfg.ciFormat
+	default:
+		return "text"
+	}
+}
+
+// reportView holds the presentation settings of a console report; files are
+// always written in full and without color
+type reportView struct {
+	Color      bool // ANSI colors, text format only
+	MaxDisplay int  // issues listed before an "and N more…" footer, text and GitHub formats only; 0 lists all
+}
+
+// renderReport formats result in the named report format
+func renderReport(cfg *Config, format string, result *audit.Result, redactor *redact.Redactor, view reportView) string {
+	var formatter Formatter
+	switch format {
+	case "json":
+		formatter = &JSONFormatter{Metadata: newReportMetadata(cfg)}
+	case "ndjson":
+		formatter = &NDJSONFormatter{}
+	case "sarif":
+		formatter = &SARIFFormatter{Metadata: newReportMetadata(cfg)}
+	case "github":
+		formatter = &GitHubFormatter{MaxDisplay: view.MaxDisplay}
+	case "compact":
+		formatter = &CompactFormatter{}
+	case "gitlab":
+		formatter = &GitLabFormatter{Redactor: redactor, Now: cfg.clock()}
+	case "azure":
+		formatter = &AzureFormatter{}
+	case "teamcity":
+		formatter = &TeamCityFormatter{}
+	case "markdown":
+		formatter = &BuildkiteFormatter{Redactor: redactor, Metadata: newReportMetadata(cfg)}
+	default:
+		opts := textOptions{Redactor: redactor, Color: view.Color, MaxDisplay: view.MaxDisplay, GroupBy: cfg.GroupBy}
+		if cfg.Verbose {
+			opts.Descriptions = cfg.Descriptions
+		}
+		return formatSummary(result, opts)
+	}
+	return formatter.Format(result)
+}
+
+// clock is the time source for report timestamps: nil for the current time,
+// or a fixed epoch with --reproducible
+func (cfg *Config) clock() func() time.Time {
+	if !cfg.Reproducible {
+		return nil
+	}
+	return func() time.Time { return reproducibleEpoch }
+}
+
+// writeOutputFile writes the report to --output, creating parent
+// directories. Files are never colored.
+func writeOutputFile(cfg *Config, result *audit.Result, redactor *redact.Redactor) error {
+	output := renderReport(cfg, outputFormat(cfg.Output, cfg.OutputFormat), result, redactor, reportView{})
+	if output != "" && !strings.HasSuffix(output, "\n") {
+		output += "\n"
+	}
+	return writeReport(cfg.Output, "", []byte(output))
+}
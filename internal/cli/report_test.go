@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOutputFormat(t *testing.T) {
+	tests := []struct {
+		path, override, want string
+	}{
+		{"report.json", "", "json"},
+		{"events.ndjson", "", "ndjson"},
+		{"events.jsonl", "", "ndjson"},
+		{"scan.SARIF", "", "sarif"},
+		{"docs/report.md", "", "markdown"},
+		{"report.txt", "", "text"},
+		{"report", "", "text"},
+		{"report.json", "compact", "compact"},
+	}
+	for _, tt := range tests {
+		if got := outputFormat(tt.path, tt.override); got != tt.want {
+			t.Errorf("outputFormat(%q, %q) = %q, want %q", tt.path, tt.override, got, tt.want)
+		}
+	}
+}
+
+func TestParseArgs_OutputFormat(t *testing.T) {
+	if _, err := ParseArgs([]string{"--output-format", "json"}); err == nil {
+		t.Error("expected --output-format without --output to fail")
+	}
+	if _, err := ParseArgs([]string{"--output", "r.xml", "--output-format", "xml"}); err == nil {
+		t.Error("expected unknown output format to fail")
+	}
+	cfg, err := ParseArgs([]string{"-o", "r.txt", "--output-format", "sarif"})
+	if err != nil || cfg.Output != "r.txt" || cfg.OutputFormat != "sarif" {
+		t.Errorf("unexpected config %+v (%v)", cfg, err)
+	}
+}
+
+func TestRun_OutputFileAndConsole(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, ".env")
+	reportPath := filepath.Join(dir, "reports", "env-audit.json")
+	os.WriteFile(envPath, []byte("EMPTY=\n"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	exitCode := Run([]string{"-f", envPath, "--output", reportPath, "--no-ci-detect"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit 0, got %d: %s", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Summary: 1 issues found") {
+		t.Errorf("expected the text report on the console, got: %s", stdout.String())
+	}
+
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("expected report file: %v", err)
+	}
+	var report struct {
+		Issues []struct {
+			Key string `json:"key"`
+		} `json:"issues"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("expected JSON inferred from the extension: %v\n%s", err, data)
+	}
+	if len(report.Issues) != 1 || report.Issues[0].Key != "EMPTY" {
+		t.Errorf("unexpected report: %s", data)
+	}
+}
+
+func TestRun_OutputFileWrittenWhenQuiet(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, ".env")
+	reportPath := filepath.Join(dir, "report.md")
+	os.WriteFile(envPath, []byte("EMPTY=\n"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	Run([]string{"-f", envPath, "-o", reportPath, "-q", "--no-ci-detect"}, &stdout, &stderr)
+	if stdout.Len() != 0 {
+		t.Errorf("expected a quiet console, got: %s", stdout.String())
+	}
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("expected report file: %v", err)
+	}
+	if !strings.Contains(string(data), "- `EMPTY`") {
+		t.Errorf("expected a Markdown report, got: %s", data)
+	}
+}
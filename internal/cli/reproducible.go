@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"env-audit/internal/audit"
+)
+
+// reproducibleEpoch replaces the current time in --reproducible reports
+var reproducibleEpoch = time.Unix(0, 0).UTC()
+
+// reproducibleResult returns a copy of result for --reproducible: paths are
+// relative to the working directory with forward slashes, also inside
+// messages and errors, and issues and files are in a fixed order, so
+// identical content always renders the same report
+func reproducibleResult(result *audit.Result) *audit.Result {
+	// Longest paths first, so no path is rewritten inside a longer one
+	var paths []string
+	seen := make(map[string]bool)
+	addPath := func(path string) {
+		if path != "" && !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	for _, issue := range result.Issues {
+		addPath(issue.File)
+	}
+	for _, file := range result.Files {
+		addPath(file.Path)
+	}
+	sort.Slice(paths, func(i, j int) bool { return len(paths[i]) > len(paths[j]) })
+	var pairs []string
+	for _, path := range paths {
+		if rel := reproduciblePath(path); rel != path {
+			pairs = append(pairs, path, rel)
+		}
+	}
+	relabel := strings.NewReplacer(pairs...)
+
+	out := &audit.Result{Summary: result.Summary, HasRisks: result.HasRisks}
+	for _, issue := range result.Issues {
+		issue.File = reproduciblePath(issue.File)
+		issue.Message = relabel.Replace(issue.Message)
+		out.Issues = append(out.Issues, issue)
+	}
+	for _, file := range result.Files {
+		file.Path = reproduciblePath(file.Path)
+		if file.Error != nil {
+			file.Error = errors.New(relabel.Replace(file.Error.Error()))
+		}
+		out.Files = append(out.Files, file)
+	}
+
+	sort.SliceStable(out.Issues, func(i, j int) bool {
+		a, b := out.Issues[i], out.Issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		return a.Message < b.Message
+	})
+	sort.SliceStable(out.Files, func(i, j int) bool { return out.Files[i].Path < out.Files[j].Path })
+	return out
+}
+
+// reproduciblePath rewrites path relative to the working directory with
+// forward slashes. A path that cannot be made relative is reduced to its
+// base name rather than leak the machine's directory layout.
+func reproduciblePath(path string) string {
+	if path == "" {
+		return path
+	}
+	if !filepath.IsAbs(path) {
+		return filepath.ToSlash(filepath.Clean(path))
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return filepath.Base(path)
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil {
+		return filepath.Base(path)
+	}
+	return filepath.ToSlash(rel)
+}
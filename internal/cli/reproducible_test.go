@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"env-audit/internal/audit"
+)
+
+func TestReproducibleResult_RelativePathsAndOrder(t *testing.T) {
+	wd, _ := os.Getwd()
+	abs := filepath.Join(wd, "config", ".env")
+	result := &audit.Result{
+		Issues: []audit.Issue{
+			{Type: audit.IssueOverride, Key: "PORT", Message: abs + ":2 overrides .env:1", File: abs, Line: 2},
+			{Type: audit.IssueEmpty, Key: "B", File: ".env", Line: 3},
+			{Type: audit.IssueEmpty, Key: "A", File: ".env", Line: 1},
+		},
+		Files: []audit.FileStatus{{Path: abs, Error: errors.New("open " + abs + ": denied")}, {Path: "./.env"}},
+	}
+
+	out := reproducibleResult(result)
+
+	var keys []string
+	for _, issue := range out.Issues {
+		keys = append(keys, issue.Key)
+	}
+	if strings.Join(keys, ",") != "A,B,PORT" {
+		t.Errorf("expected issues ordered by file and line, got %v", keys)
+	}
+	if out.Issues[2].File != "config/.env" || out.Issues[2].Message != "config/.env:2 overrides .env:1" {
+		t.Errorf("expected relative paths in the issue, got %+v", out.Issues[2])
+	}
+	if out.Files[0].Path != ".env" || out.Files[1].Path != "config/.env" || out.Files[1].Error.Error() != "open config/.env: denied" {
+		t.Errorf("expected relative, sorted files, got %+v", out.Files)
+	}
+	if result.Issues[0].File != abs {
+		t.Error("expected the original result to be left untouched")
+	}
+}
+
+func TestRun_ReproducibleReportsAreIdentical(t *testing.T) {
+	dir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(oldWd)
+	envFile := filepath.Join(dir, ".env")
+	os.WriteFile(envFile, []byte("A=\nB=\nC=\nD=\nPASSWORD=secret\n"), 0644)
+
+	for _, format := range []string{"json", "sarif", "gitlab", "ndjson"} {
+		var first, second, stderr bytes.Buffer
+		Run([]string{"-f", envFile, "--format", format, "--reproducible"}, &first, &stderr)
+		Run([]string{"-f", envFile, "--format", format, "--reproducible"}, &second, &stderr)
+
+		if first.String() != second.String() {
+			t.Errorf("%s: expected identical reports, got:\n%s\nand:\n%s", format, first.String(), second.String())
+		}
+		if strings.Contains(first.String(), dir) {
+			t.Errorf("%s: expected no absolute paths, got:\n%s", format, first.String())
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	Run([]string{"-f", envFile, "--format", "gitlab", "--reproducible"}, &stdout, &stderr)
+	if !strings.Contains(stdout.String(), "section_start:0:") {
+		t.Errorf("expected fixed section timestamps, got:\n%s", stdout.String())
+	}
+}
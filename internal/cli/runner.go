@@ -3,30 +3,79 @@ package cli
 import (
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 	"syscall"
+	"time"
 
 	"env-audit/internal/audit"
 	"env-audit/internal/config"
+	"env-audit/internal/discover"
+	"env-audit/internal/fsutil"
+	"env-audit/internal/i18n"
+	"env-audit/internal/netutil"
 	"env-audit/internal/parser"
-
-	"github.com/fsnotify/fsnotify"
+	"env-audit/internal/redact"
 )
 
 // Version is the current version of env-audit
 const Version = "0.2.0"
 
+// stdin is read by --files-from -; replaced in tests
+var stdin io.Reader = os.Stdin
+
 // Run executes the main logic and returns the exit code
 func Run(args []string, stdout, stderr io.Writer) int {
-	cfg, err := ParseArgs(args)
+	if len(args) > 0 {
+		switch args[0] {
+		case "remote-diff":
+			return runRemoteDiff(args[1:], stdout, stderr)
+		case "version":
+			return runVersion(args[1:], stdout, stderr)
+		case "org":
+			return runOrg(args[1:], stdout, stderr)
+		case "fmt":
+			return runFmt(args[1:], stdout, stderr)
+		case "fix":
+			return runFix(args[1:], stdout, stderr)
+		case "merge":
+			return runMerge(args[1:], stdout, stderr)
+		case "lint":
+			return runLint(args[1:], stdout, stderr)
+		case "push":
+			return runPush(args[1:], stdout, stderr)
+		case "usage":
+			return runUsage(args[1:], stdout, stderr)
+		case "config":
+			return runConfig(args[1:], stdout, stderr)
+		case "serve":
+			return runServe(args[1:], stdout, stderr)
+		}
+	}
+
+	var cfg *Config
+	var err error
+	if len(args) > 0 && commands[args[0]] != nil {
+		cfg, err = parseCommand(args[0], args[1:])
+	} else {
+		cfg, err = ParseArgs(args)
+	}
 	if err != nil {
 		fmt.Fprintln(stderr, "Error:", err)
 		return 2
 	}
 
 	if cfg.Help {
-		PrintUsage(stdout)
+		if cfg.command != "" {
+			printCommandUsage(stdout, cfg.command)
+		} else {
+			PrintUsage(stdout)
+		}
 		return 0
 	}
 
@@ -35,50 +84,184 @@ func Run(args []string, stdout, stderr io.Writer) int {
 		return 0
 	}
 
-	// Load and merge config file if present
-	if configPath := config.FindConfigFile(); configPath != "" {
-		fileCfg, err := config.LoadFile(configPath)
-		if err != nil {
+	if err := cfg.setLocale(); err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	if cfg.log, err = newLogger(stderr, cfg.LogLevel, cfg.LogFormat); err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	log := cfg.logger()
+
+	// Scan exactly the listed files, e.g. from git diff --name-only
+	if cfg.FilesFrom != "" {
+		if err := loadFileList(cfg); err != nil {
 			fmt.Fprintln(stderr, "Error:", err)
 			return 2
 		}
-		cfg.MergeWithFileConfig(&FileConfig{
-			File:       fileCfg.File,
-			Required:   fileCfg.Required,
-			Example:    fileCfg.Example,
-			Ignore:     fileCfg.Ignore,
-			Strict:     fileCfg.Strict,
-			CheckLeaks: fileCfg.CheckLeaks,
-			Quiet:      fileCfg.Quiet,
-			JSON:       fileCfg.JSON,
-			GitHub:     fileCfg.GitHub,
-			NoColor:    fileCfg.NoColor,
-		})
+		log.Info("read file list", "source", cfg.FilesFrom, "files", len(cfg.Files))
+		if len(cfg.Files) == 0 {
+			cfg.notify(stdout, "No files to scan")
+			return 0
+		}
 	}
 
-	// Handle watch mode - continuous file watching
-	if cfg.Watch {
-		return runWatch(cfg, stdout, stderr)
+	// Expand directories (default: the working directory) into the env files below them
+	if cfg.Recursive {
+		if err := discoverFiles(cfg); err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+		log.Info("discovered env files", "files", len(cfg.Files))
+		for _, path := range cfg.Files {
+			log.Debug("discovered env file", "file", path)
+		}
+		if len(cfg.Files) == 0 {
+			cfg.notify(stdout, "No files to scan")
+			return 0
+		}
+		// Each discovered file gets its own section of the text report
+		if cfg.GroupBy == "" {
+			cfg.GroupBy = groupByFile
+		}
+	}
+
+	// Allowlisted values apply whichever config governs a file
+	if hashes, err := config.LoadAllowFile(config.AllowFileName); err == nil {
+		cfg.AllowHashes = hashes
+		log.Info("loaded allowlist", "path", config.AllowFileName, "hashes", len(hashes))
+	} else if !os.IsNotExist(err) {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
 	}
 
-	var env map[string]string
-	var duplicates []string
+	// Keep the CLI-only settings so per-file configs can be layered on them
+	cliArgs := *cfg
+	cfg.cliArgs = &cliArgs
 
-	if cfg.FilePath != "" {
-		result, err := parser.ParseEnvFile(cfg.FilePath)
+	// A config file's preset may be a URL, fetched while the file loads
+	if cfg.Offline {
+		netutil.Configure(netutil.Options{Offline: true})
+	}
+
+	// Load and merge config file if present
+	if configPath := cfg.findConfigFile(); configPath != "" {
+		fileCfg, err := loadConfigFile(configPath, config.LoadFile)
 		if err != nil {
 			fmt.Fprintln(stderr, "Error:", err)
 			return 2
 		}
-		env = result.Entries
-		duplicates = result.Duplicates
+		cfg.MergeWithFileConfig(toFileConfig(fileCfg))
+		if abs, err := filepath.Abs(configPath); err == nil {
+			cfg.configPath = abs
+		}
+		log.Info("loaded config file", "path", configPath)
 	} else {
-		env = parser.ReadOSEnv()
+		log.Info("no config file up to the repository root", "searched", ".env-audit.yaml, .env-audit.yml")
+	}
+	if err := cfg.applyEnv(cfg.cliArgs, os.Getenv); err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+
+	// The cascade builds on the base file, which the config file may name
+	if cfg.Flow {
+		if err := resolveFlow(cfg); err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+		log.Info("resolved dotenv-flow cascade", "env", cfg.FlowEnv, "files", cfg.Files)
+	}
+
+	if err := netutil.Configure(cfg.httpOptions()); err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	if err := cfg.checkOffline(); err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+
+	// An updated pattern pack rolls out new token formats without a release
+	if cfg.Patterns != "" {
+		if err := loadPatternPack(cfg.Patterns); err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+		log.Info("loaded pattern pack", "source", cfg.Patterns, "version", audit.PatternsVersion, "patterns", len(audit.KnownPatterns))
+	}
+
+	// Redaction and the sensitive check share one matcher, so an exclusion
+	// also stops the key's value from being masked
+	audit.SensitiveKeys = audit.DefaultSensitiveKeys.Extend(cfg.SensitivePatterns, cfg.SensitiveExclude)
+	if len(cfg.SensitivePatterns) > 0 || len(cfg.SensitiveExclude) > 0 {
+		log.Info("customized sensitive keys", "patterns", cfg.SensitivePatterns, "exclude", cfg.SensitiveExclude)
+	}
+
+	// A broken template should fail before any scanning
+	if cfg.FormatTemplate != "" {
+		if cfg.formatTemplate, err = loadFormatTemplate(cfg.FormatTemplate); err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+	}
+
+	// Pipelines get CI-friendly defaults without extra flags
+	if !cfg.NoCIDetect {
+		if ci := detectCI(os.Getenv); ci != nil {
+			cfg.applyCIDefaults(ci)
+			log.Info("detected CI", "provider", ci.Name)
+		}
+	}
+	// Checked once CI defaults have picked the output format
+	if err := cfg.checkReadOnly(); err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	cfg.startNDJSON(stdout)
+
+	redactor, err := cfg.Redactor()
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+
+	// Each project of a monorepo config is audited with its own settings
+	if cfg.AllProjects {
+		if cfg.GroupBy == "" {
+			cfg.GroupBy = groupByFile
+		}
+		return runProjects(cfg, redactor, stdout, stderr)
+	}
+
+	// Handle watch mode - continuous file watching
+	if cfg.Watch {
+		if cfg.ci != nil {
+			fmt.Fprintf(stderr, "Error: --watch is interactive and disabled in %s (use --no-ci-detect to force)\n", cfg.ci.Name)
+			return 2
+		}
+		return runWatch(cfg, redactor, stdout, stderr)
 	}
 
 	// Handle init mode - generate .env.example
 	if cfg.Init {
-		return runInit(env, cfg.Force, stdout, stderr)
+		// The example is generated from the values as written
+		env, descriptions, err := loadDocumented(cfg, cfg.FilePath)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+		return runInit(cfg, env, descriptions, stdout, stderr)
+	}
+
+	if cfg.Docs {
+		return runDocs(cfg, redactor, stdout, stderr)
+	}
+
+	// Handle fix-order mode - rewrite files to follow the example's key order
+	if cfg.FixOrder {
+		return runFixOrder(cfg, stdout, stderr)
 	}
 
 	// Handle diff mode - compare two env files
@@ -87,207 +270,1038 @@ func Run(args []string, stdout, stderr io.Writer) int {
 			fmt.Fprintln(stderr, "Error: --diff requires --file to specify the first file")
 			return 2
 		}
-		return runDiff(cfg.FilePath, cfg.DiffFile, cfg.Quiet, stdout, stderr)
+		return runDiff(cfg, redactor, stdout, stderr)
 	}
 
 	if cfg.DumpMode {
-		if !cfg.Quiet {
-			fmt.Fprintln(stdout, parser.FormatEnv(env, true))
+		if cfg.Merge && len(cfg.InputFiles()) > 1 {
+			return runMergedDump(cfg, redactor, stdout, stderr)
 		}
-		return 0
-	}
-
-	// Handle example file comparison
-	var missing, extra []string
-	if cfg.ExampleFile != "" {
-		exampleResult, err := parser.ParseEnvFile(cfg.ExampleFile)
+		entries, err := loadEntries(cfg.FilePath, cfg.parseOptions())
 		if err != nil {
 			fmt.Fprintln(stderr, "Error:", err)
 			return 2
 		}
-		compareResult := parser.Compare(env, exampleResult.Entries)
-		missing = compareResult.Missing
-		extra = compareResult.Extra
+		if cfg.Sort == sortKey {
+			sort.SliceStable(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+		}
+		if !cfg.Quiet {
+			if cfg.Shell {
+				fmt.Fprintln(stdout, parser.FormatShellEntries(entries, redactor))
+			} else {
+				fmt.Fprintln(stdout, parser.FormatEntries(entries, redactor))
+			}
+		}
+		return 0
 	}
 
-	scanResult := audit.Scan(env, &audit.ScanOptions{
-		Required:   cfg.Required,
-		Ignore:     cfg.Ignore,
-		Duplicates: duplicates,
-		Missing:    missing,
-		Extra:      extra,
-		CheckLeaks: cfg.CheckLeaks,
-		Strict:     cfg.Strict,
-	})
+	return runAudit(cfg, redactor, stdout, stderr)
+}
 
-	if !cfg.Quiet {
-		var output string
-		if cfg.JSONOutput {
-			formatter := &JSONFormatter{}
-			output = formatter.Format(scanResult)
-		} else if cfg.GitHubOutput {
-			formatter := &GitHubFormatter{}
-			output = formatter.Format(scanResult)
-		} else {
-			output = FormatSummary(scanResult)
+// loadFileList reads the --files-from list from stdin or a file
+func loadFileList(cfg *Config) error {
+	if cfg.FilesFrom == "-" {
+		return cfg.readFileList(stdin)
+	}
+	f, err := os.Open(cfg.FilesFrom)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return cfg.readFileList(f)
+}
+
+// discoverFiles replaces the input files with the env files found under them
+func discoverFiles(cfg *Config) error {
+	roots := cfg.InputFiles()
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+	files, err := discover.Find(roots, discover.Options{NoIgnore: cfg.NoIgnore, Exclude: cfg.ExcludePaths})
+	if err != nil {
+		return err
+	}
+	cfg.Files = files
+	cfg.FilePath = ""
+	if len(files) > 0 {
+		cfg.FilePath = files[0]
+	}
+	return nil
+}
+
+// loadPatternPack reads a leak pattern pack from a file or http(s) URL and
+// layers it over the embedded patterns
+func loadPatternPack(source string) error {
+	var r io.Reader
+	if strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://") {
+		resp, err := netutil.Client(30 * time.Second).Get(source)
+		if err != nil {
+			return err
 		}
-		if output != "" {
-			fmt.Fprint(stdout, output)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("pattern pack %s: HTTP %d", source, resp.StatusCode)
+		}
+		r = resp.Body
+	} else {
+		f, err := os.Open(source)
+		if err != nil {
+			return err
 		}
+		defer f.Close()
+		r = f
 	}
 
-	if scanResult.HasRisks {
-		return 1
+	pack, err := audit.ReadPatternPack(r)
+	if err != nil {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+	audit.ApplyPatternPack(pack)
+	return nil
+}
+
+// parseOptions are the parser settings for env files: references are
+// expanded unless --no-expand, falling back to the OS environment with
+// --expand-env
+func (cfg *Config) parseOptions() parser.ParseOptions {
+	opts := parser.ParseOptions{Expand: !cfg.NoExpand}
+	if cfg.ExpandEnv {
+		opts.LookupEnv = os.LookupEnv
+	}
+	return opts
+}
+
+// setLocale selects the language of report messages: --lang, else the
+// locale environment variables, else English
+func (cfg *Config) setLocale() error {
+	lang := cfg.Lang
+	if lang == "" {
+		lang = i18n.FromEnv()
+	}
+	return i18n.SetLocale(lang)
+}
+
+// startNDJSON sets up streaming of --ndjson events to w, unless the report
+// is suppressed or rendered by a --format-template. --reproducible reports
+// are written at the end instead, once issues can be put in order.
+func (cfg *Config) startNDJSON(w io.Writer) {
+	if cfg.consoleFormat() == "ndjson" && cfg.formatTemplate == nil && !cfg.Quiet && !cfg.Reproducible {
+		cfg.ndjson = &ndjsonStream{w: w}
+	}
+}
+
+// notify prints an informational message (banners, progress, files written).
+// Notices survive -q, which only hides the report, and are silenced by -qq.
+func (cfg *Config) notify(w io.Writer, a ...interface{}) {
+	if cfg.QuietLevel >= 2 {
+		return
+	}
+	fmt.Fprintln(w, a...)
+}
+
+// toFileConfig converts a loaded config file into the CLI merge representation
+func toFileConfig(fileCfg *config.FileConfig) *FileConfig {
+	return &FileConfig{
+		File:       fileCfg.File,
+		Required:   fileCfg.Required,
+		Example:    fileCfg.Example,
+		Ignore:     fileCfg.Ignore,
+		AllowEmpty: fileCfg.AllowEmpty,
+		Strict:     fileCfg.Strict,
+		CheckLeaks: fileCfg.CheckLeaks,
+		Quiet:      fileCfg.Quiet,
+		JSON:       fileCfg.JSON,
+		GitHub:     fileCfg.GitHub,
+		GitLab:     fileCfg.GitLab,
+		Buildkite:  fileCfg.Buildkite,
+		CircleCI:   fileCfg.CircleCI,
+		NoColor:    fileCfg.NoColor,
+		FailFast:   fileCfg.FailFast,
+		CheckOrder: fileCfg.CheckOrder,
+		WarnParse:  fileCfg.WarnParse,
+
+		RequireAllExample: fileCfg.RequireAllExample || fileCfg.RequiredFromExample,
+		CheckSources:      fileCfg.CheckSources,
+		Patterns:          fileCfg.Patterns,
+		SensitivePatterns: fileCfg.SensitivePatterns,
+		SensitiveExclude:  fileCfg.SensitiveExclude,
+
+		RedactMode:        fileCfg.Redaction.Mode,
+		RedactPlaceholder: fileCfg.Redaction.Placeholder,
+		RedactReveal:      fileCfg.Redaction.Reveal,
+
+		Descriptions: fileCfg.Descriptions,
+		Defaults:     convertDefaults(fileCfg.Defaults),
+		RotateAfter:  fileCfg.RotateAfter,
+		LastRotated:  fileCfg.LastRotated,
+		Rules:        compileRules(fileCfg.Rules),
+		PublicValues: convertPublicValues(fileCfg.PublicValues),
+		AllowHashes:  fileCfg.AllowHashes,
+		Limits:       convertLimits(fileCfg.Limits),
+		Platform:     fileCfg.Platform,
+		DocsFile:     fileCfg.DocsFile,
+		FailOn:       fileCfg.FailOn,
+		Severity:     convertSeverity(fileCfg.Severity),
+		Only:         fileCfg.Only,
+		Exclude:      fileCfg.Exclude,
+		HTTP:         convertHTTP(fileCfg.HTTP),
+		Projects:     fileCfg.Projects,
+		QuarantineTo: fileCfg.QuarantineRecipients,
 	}
-	return 0
+}
+
+// findConfigFile returns the --config path, or else $ENV_AUDIT_CONFIG, or
+// else the config file found from the working directory up, or empty string
+// if there is none
+func (cfg *Config) findConfigFile() string {
+	if cfg.ConfigFile != "" {
+		return cfg.ConfigFile
+	}
+	if path := envConfigFile(); path != "" {
+		return path
+	}
+	return config.FindConfigFile()
+}
+
+// loadConfigFile loads the config file at configPath with load, making the
+// paths in a config outside the working directory relative to it
+func loadConfigFile(configPath string, load func(string) (*config.FileConfig, error)) (*config.FileConfig, error) {
+	fileCfg, err := load(configPath)
+	if err != nil {
+		return nil, err
+	}
+	if dir := configDir(configPath); dir != "." {
+		fileCfg.ResolvePaths(dir)
+	}
+	return fileCfg, nil
+}
+
+// configDir returns the directory of a config file relative to the working
+// directory, or absolute when it can't be made relative
+func configDir(configPath string) string {
+	dir := filepath.Dir(configPath)
+	if !filepath.IsAbs(dir) {
+		return dir
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return dir
+	}
+	if rel, err := filepath.Rel(wd, dir); err == nil {
+		return rel
+	}
+	return dir
+}
+
+// configFor returns the effective configuration for an input file. If the
+// nearest config file up the file's own path differs from the one loaded for
+// the working directory, it is layered on the CLI flags instead, so each
+// service in a monorepo is audited with its own policy.
+func (cfg *Config) configFor(path string) (*Config, error) {
+	// --config names the one config file for every input
+	if cfg.cliArgs == nil || cfg.ConfigFile != "" {
+		return cfg, nil
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return cfg, nil
+	}
+	nearest := config.FindNearestConfigFile(filepath.Dir(abs))
+	if nearest == "" || nearest == cfg.configPath {
+		return cfg, nil
+	}
+
+	fileCfg, err := config.LoadFile(nearest)
+	if err != nil {
+		return nil, err
+	}
+	cfg.logger().Info("using nested config file", "file", path, "config", nearest)
+	// Example paths in a nested config are relative to that config
+	if fileCfg.Example != "" && !filepath.IsAbs(fileCfg.Example) {
+		fileCfg.Example = filepath.Join(filepath.Dir(nearest), fileCfg.Example)
+	}
+
+	effective := *cfg.cliArgs
+	effective.MergeWithFileConfig(toFileConfig(fileCfg))
+	// Validated when the working directory's config was merged
+	effective.applyEnv(cfg.cliArgs, os.Getenv)
+	effective.configPath = nearest
+	return &effective, nil
+}
+
+// Orders selectable with --sort
+const (
+	sortFile = "file"
+	sortKey  = "key"
+)
+
+// loadEntries parses the env file at path into entries in file order, or
+// reads the OS environment sorted by key if path is empty
+func loadEntries(path string, opts parser.ParseOptions) ([]parser.Entry, error) {
+	if path == "" {
+		return parser.SortedEntries(parser.ReadOSEnv()), nil
+	}
+	result, err := parser.ParseEnvFileWith(path, opts)
+	if err != nil {
+		return nil, err
+	}
+	return result.Ordered(), nil
 }
 
 // runWatch starts file watching mode
-func runWatch(cfg *Config, stdout, stderr io.Writer) int {
+func runWatch(cfg *Config, redactor *redact.Redactor, stdout, stderr io.Writer) int {
 	if cfg.FilePath == "" {
 		fmt.Fprintln(stderr, "Error: --watch requires --file to specify a file to watch")
 		return 2
 	}
 
-	watcher, err := fsnotify.NewWatcher()
+	paths := cfg.watchedFiles()
+	watcher, err := newFileWatcher(paths)
 	if err != nil {
 		fmt.Fprintln(stderr, "Error:", err)
 		return 2
 	}
 	defer watcher.Close()
 
-	if err := watcher.Add(cfg.FilePath); err != nil {
-		fmt.Fprintln(stderr, "Error:", err)
-		return 2
-	}
-
 	// Set up signal handling for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-	fmt.Fprintln(stdout, "Watching", cfg.FilePath, "for changes... (Ctrl+C to stop)")
+	// Notices would break an NDJSON stream, so they go to stderr instead
+	notices := stdout
+	if cfg.ndjson != nil {
+		notices = stderr
+	}
+	cfg.notify(notices, "Watching", strings.Join(paths, ", "), "for changes... (Ctrl+C to stop)")
+
+	var command *watchCommand
+	if cfg.Exec != "" {
+		// Its output shares the stream of the notices
+		command = &watchCommand{command: cfg.Exec, ifClean: cfg.ExecIfClean, stdout: notices, stderr: stderr}
+		defer command.stop()
+	}
+	reaudit := func() {
+		code := runAudit(cfg, redactor, stdout, stderr)
+		if command == nil {
+			return
+		}
+		started, err := command.rerun(code)
+		switch {
+		case err != nil:
+			fmt.Fprintln(stderr, "Error:", err)
+		case started:
+			cfg.notify(notices, "Running", cfg.Exec)
+		default:
+			cfg.notify(notices, "Skipping", cfg.Exec, "until the audit passes")
+		}
+	}
 
 	// Run initial audit
-	runAudit(cfg, stdout, stderr)
+	reaudit()
 
-	for {
-		select {
-		case event, ok := <-watcher.Events:
-			if !ok {
-				return 0
+	configFile := displayPath(cfg.configPath)
+	watcher.run(sigChan, func(changed []string) {
+		for _, path := range changed {
+			if _, err := os.Stat(path); err != nil {
+				// Deleted rather than replaced; it is audited when it is back
+				cfg.notify(notices, "\n---", path, "removed, waiting for it to come back ---")
+				return
 			}
-			if event.Op&fsnotify.Write == fsnotify.Write {
-				fmt.Fprintln(stdout, "\n--- File changed ---")
-				runAudit(cfg, stdout, stderr)
+		}
+		cfg.notify(notices, "\n---", strings.Join(changed, ", "), "changed ---")
+		reload := false
+		for _, path := range changed {
+			reload = reload || path == configFile
+		}
+		if reload {
+			reloaded, reloadedRedactor, err := cfg.reloadConfig()
+			if err != nil {
+				// The audit waits for a config it can load
+				fmt.Fprintln(stderr, "Error:", err)
+				return
 			}
-		case err, ok := <-watcher.Errors:
-			if !ok {
-				return 0
+			cfg, redactor = reloaded, reloadedRedactor
+			cfg.logger().Info("reloaded config file", "path", configFile)
+		}
+		reaudit()
+	}, stderr)
+	cfg.notify(notices, "\nStopping watch mode...")
+	return 0
+}
+
+// runAudit scans every input file (or the OS environment) and prints the report.
+// A file that fails to parse is recorded in the report and the remaining files
+// are still scanned, unless --fail-fast is set, which also stops scanning after
+// the first file with an error-severity issue and skips the example checks. With --ndjson, each file's
+// issues are streamed as soon as it has been scanned.
+func runAudit(cfg *Config, redactor *redact.Redactor, stdout, stderr io.Writer) int {
+	cfg.ndjson.begin()
+	examples := &exampleSet{}
+	loadExample := examples.load
+
+	files := cfg.InputFiles()
+
+	var scanResult *audit.Result
+	stopped := false
+	if len(files) == 0 {
+		example, err := loadExample(cfg.ExampleFile)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+		scanResult = scanEntries(cfg, scanInput{Entries: parser.ReadOSEnv()}, example)
+		cfg.ndjson.issues(scanResult.Issues)
+	} else if cfg.Merge && len(files) > 1 {
+		merged, err := parser.MergeFilesWith(files, cfg.parseOptions())
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+		example, err := loadExample(cfg.ExampleFile)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+		scanResult = scanEntries(cfg, scanInput{
+			Entries:    merged.Entries,
+			Duplicates: merged.Duplicates,
+			Locations:  merged.Locations,
+			Overrides:  merged.Overrides,
+			Unresolved: merged.Unresolved,
+		}, example)
+		cfg.ndjson.issues(scanResult.Issues)
+		for _, path := range files {
+			scanResult.Files = append(scanResult.Files, audit.FileStatus{Path: path})
+		}
+	} else {
+		var results []*audit.Result
+		var statuses []audit.FileStatus
+		for i, path := range files {
+			result, err := scanFile(cfg, path, loadExample)
+			if err != nil {
+				fmt.Fprintln(stderr, "Error:", err)
+				if cfg.FailFast || len(files) == 1 {
+					return 2
+				}
+				statuses = append(statuses, audit.FileStatus{Path: path, Error: err})
+				continue
+			}
+			cfg.ndjson.issues(result.Issues)
+			results = append(results, result)
+			statuses = append(statuses, audit.FileStatus{Path: path})
+			// The run fails either way, so the remaining files can't change the answer
+			if cfg.FailFast && hasErrors(result.Issues) {
+				cfg.logger().Info("stopping at the first error-severity issue", "file", path)
+				if skipped := cfg.skippedChecks(len(files) - i - 1); skipped != "" {
+					cfg.notify(stderr, "--fail-fast: stopped at the first error in "+path+", skipped "+skipped)
+				}
+				stopped = true
+				break
 			}
+		}
+		// Per-file status is only reported when several files were scanned
+		if len(files) == 1 {
+			statuses = nil
+		}
+		scanResult = audit.Merge(results, statuses)
+	}
+
+	// Examples are committed, so check them for pasted-in real secrets
+	if !cfg.NoExampleLeaks && !stopped {
+		leaks := audit.NewResult(examples.leaks(cfg.Ignore, cfg.PublicValues, cfg.AllowHashes), cfg.severityPolicy(), cfg.failOn())
+		if len(leaks.Issues) > 0 {
+			cfg.ndjson.issues(leaks.Issues)
+			scanResult = audit.Merge([]*audit.Result{scanResult, leaks}, scanResult.Files)
+		}
+	}
+
+	// Human docs should describe exactly the keys of the example
+	if docsFile := cfg.docsFile(); docsFile != "" && !stopped {
+		issues, err := examples.docsDrift(docsFile, cfg.Ignore)
+		if err != nil {
 			fmt.Fprintln(stderr, "Error:", err)
-		case <-sigChan:
-			fmt.Fprintln(stdout, "\nStopping watch mode...")
-			return 0
+			return 2
+		}
+		drift := audit.NewResult(issues, cfg.severityPolicy(), cfg.failOn())
+		if len(drift.Issues) > 0 {
+			cfg.ndjson.issues(drift.Issues)
+			scanResult = audit.Merge([]*audit.Result{scanResult, drift}, scanResult.Files)
+		}
+	}
+
+	// Contain what was found before reporting it
+	if cfg.Quarantine {
+		if err := quarantineLeaks(cfg, scanResult, stderr); err != nil {
+			fmt.Fprintln(stderr, "Error: quarantine failed:", err)
+			return 2
+		}
+	}
+
+	return reportResult(cfg, scanResult, redactor, stdout, stderr)
+}
+
+// hasErrors reports whether any of the issues is reported at error severity
+func hasErrors(issues []audit.Issue) bool {
+	for _, issue := range issues {
+		if issue.Level() == audit.SeverityError {
+			return true
 		}
 	}
+	return false
 }
 
-// runAudit performs a single audit run (used by watch mode)
-func runAudit(cfg *Config, stdout, stderr io.Writer) int {
-	result, err := parser.ParseEnvFile(cfg.FilePath)
-	if err != nil {
+// skippedChecks describes what a --fail-fast stop leaves unchecked: the
+// files after the one it stopped at, and the checks that run after all
+// files are scanned. Empty when nothing is left.
+func (cfg *Config) skippedChecks(files int) string {
+	var skipped []string
+	if files > 0 {
+		skipped = append(skipped, pluralize(files, "more file"))
+	}
+	if !cfg.NoExampleLeaks {
+		skipped = append(skipped, "the example leak check")
+	}
+	if cfg.docsFile() != "" {
+		skipped = append(skipped, "the docs drift check")
+	}
+	if len(skipped) < 2 {
+		return strings.Join(skipped, "")
+	}
+	return strings.Join(skipped[:len(skipped)-1], ", ") + " and " + skipped[len(skipped)-1]
+}
+
+// reportResult writes the audit result in the configured format and returns
+// the exit code
+func reportResult(cfg *Config, scanResult *audit.Result, redactor *redact.Redactor, stdout, stderr io.Writer) int {
+	if cfg.Reproducible {
+		scanResult = reproducibleResult(scanResult)
+	}
+	// CI reports and annotations are artifacts, so they are written even with --quiet
+	if err := writeCIArtifacts(cfg, scanResult, redactor); err != nil {
 		fmt.Fprintln(stderr, "Error:", err)
 		return 2
 	}
-
-	var missing, extra []string
-	if cfg.ExampleFile != "" {
-		exampleResult, err := parser.ParseEnvFile(cfg.ExampleFile)
-		if err != nil {
+	if cfg.Output != "" {
+		if err := writeOutputFile(cfg, scanResult, redactor); err != nil {
 			fmt.Fprintln(stderr, "Error:", err)
 			return 2
 		}
-		compareResult := parser.Compare(result.Entries, exampleResult.Entries)
+	}
+
+	if !cfg.Quiet {
+		var output string
+		if cfg.formatTemplate != nil {
+			formatter := &TemplateFormatter{Template: cfg.formatTemplate, Metadata: newReportMetadata(cfg)}
+			var err error
+			if output, err = formatter.Render(scanResult); err != nil {
+				fmt.Fprintln(stderr, "Error:", err)
+				return 2
+			}
+		} else if cfg.ndjson != nil {
+			// The issues were streamed during the scan
+			cfg.ndjson.summary(scanResult)
+		} else {
+			view := reportView{Color: cfg.useColor(stdout), MaxDisplay: cfg.MaxDisplay}
+			output = renderReport(cfg, cfg.consoleFormat(), scanResult, redactor, view)
+		}
+		if output != "" {
+			fmt.Fprint(stdout, output)
+		}
+	}
+
+	if cfg.Summary == "stderr" {
+		fmt.Fprintln(stderr, formatOneLineSummary(scanResult, cfg.InputFiles()))
+	}
+
+	code := exitCode(scanResult)
+	if cfg.ExplainExit {
+		fmt.Fprint(stderr, explainExit(cfg, scanResult, code))
+	}
+	return code
+}
+
+// exampleSet loads each example file once per run, remembering load order
+type exampleSet struct {
+	paths   []string
+	results map[string]*parser.ParseResult
+}
+
+// load returns the entries of the example file at path, or nil if path is empty
+func (e *exampleSet) load(path string) (map[string]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if result, ok := e.results[path]; ok {
+		return result.Entries, nil
+	}
+	result, err := parser.ParseEnvFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if e.results == nil {
+		e.results = make(map[string]*parser.ParseResult)
+	}
+	e.results[path] = result
+	e.paths = append(e.paths, path)
+	return result.Entries, nil
+}
+
+// leaks runs leak detection over every loaded example file
+func (e *exampleSet) leaks(ignore []string, public []audit.PublicValue, allowed []string) []audit.Issue {
+	var issues []audit.Issue
+	for _, path := range e.paths {
+		result := e.results[path]
+		leaks := audit.SuppressPublic(audit.CheckExampleLeaks(result.Entries, ignore), result.Entries, public)
+		leaks = audit.SuppressAllowed(leaks, result.Entries, allowed)
+		for _, issue := range leaks {
+			issue.File = path
+			issue.Line = result.Lines[issue.Key]
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// docsDrift compares the keys documented in docsFile with those of every
+// loaded example file
+func (e *exampleSet) docsDrift(docsFile string, ignore []string) ([]audit.Issue, error) {
+	if len(e.paths) == 0 {
+		return nil, nil
+	}
+	documented, err := parser.ParseDocsKeys(docsFile)
+	if err != nil {
+		return nil, err
+	}
+	var issues []audit.Issue
+	for _, path := range e.paths {
+		issues = append(issues, audit.CheckDocs(docsFile, documented, path, e.results[path].Lines, ignore)...)
+	}
+	return issues, nil
+}
+
+// scanFile parses and audits one env file using the config that applies to it
+func scanFile(cfg *Config, path string, loadExample func(string) (map[string]string, error)) (*audit.Result, error) {
+	fileCfg, err := cfg.configFor(path)
+	if err != nil {
+		return nil, err
+	}
+	example, err := loadExample(fileCfg.ExampleFile)
+	if err != nil {
+		return nil, err
+	}
+	if parser.IsVaultFile(path) {
+		return scanVault(fileCfg, path, example)
+	}
+	result, err := parser.ParseEnvFileWith(path, fileCfg.parseOptions())
+	if err != nil {
+		return nil, err
+	}
+	input := scanInput{
+		Entries:    result.Entries,
+		Duplicates: result.Duplicates,
+		File:       path,
+		Locations:  result.Locations(path),
+		Rotated:    rotationAnnotations(result),
+		Unresolved: result.Unresolved,
+	}
+	if fileCfg.CheckOrder && fileCfg.ExampleFile != "" {
+		if input.Order, input.ExampleOrder, err = loadOrderings(path, fileCfg.ExampleFile); err != nil {
+			return nil, err
+		}
+	}
+	scanned := scanEntries(fileCfg, input, example)
+	if len(result.Errors) > 0 {
+		diagnostics := result.Diagnostics(path)
+		cfg.logger().Info("skipped unparsable lines", "file", path, "lines", len(diagnostics))
+		if fileCfg.WarnParse {
+			parseIssues := audit.NewResult(audit.DiagnosticIssues(diagnostics), fileCfg.severityPolicy(), fileCfg.failOn())
+			scanned = audit.Merge([]*audit.Result{parseIssues, scanned}, nil)
+		} else {
+			scanned.Diagnostics = diagnostics
+		}
+	}
+	if fileCfg.CheckSources {
+		drift, err := checkSources(fileCfg, path, result)
+		if err != nil {
+			return nil, err
+		}
+		scanned = audit.Merge([]*audit.Result{scanned, drift}, nil)
+	}
+	return scanned, nil
+}
+
+// rotationAnnotations collects "# last_rotated: YYYY-MM-DD" comments
+func rotationAnnotations(result *parser.ParseResult) map[string]string {
+	annotations := make(map[string]string)
+	for key := range result.Entries {
+		if date, ok := result.Annotation(key, "last_rotated"); ok {
+			annotations[key] = date
+		}
+	}
+	return annotations
+}
+
+// scanVault decrypts each environment of a .env.vault file in memory and
+// audits it as if it were its own file, labelled path#environment
+func scanVault(cfg *Config, path string, example map[string]string) (*audit.Result, error) {
+	dotenvKey := cfg.DotenvKey
+	if dotenvKey == "" {
+		dotenvKey = os.Getenv("DOTENV_KEY")
+	}
+	environments, err := parser.ParseVaultFile(path, dotenvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*audit.Result
+	for _, env := range environments {
+		label := path + "#" + env.Name
+		results = append(results, scanEntries(cfg, scanInput{
+			Entries:    env.Result.Entries,
+			Duplicates: env.Result.Duplicates,
+			File:       label,
+			Locations:  env.Result.Locations(label),
+		}, example))
+	}
+	return audit.Merge(results, nil), nil
+}
+
+// loadOrderings reads the key layout of an env file and its example
+func loadOrderings(path, examplePath string) (*audit.Ordering, *audit.Ordering, error) {
+	doc, err := parser.ParseDocument(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	exampleDoc, err := parser.ParseDocument(examplePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	order, exampleOrder := doc.Ordering(), exampleDoc.Ordering()
+	return &order, &exampleOrder, nil
+}
+
+// scanInput is a parsed environment ready to be audited
+type scanInput struct {
+	Entries    map[string]string
+	Duplicates []string
+	File       string                      // source file, empty for the OS environment
+	Locations  map[string]audit.Location   // where each key was defined
+	Overrides  map[string][]audit.Location // override chains in merged scans
+	Rotated    map[string]string           // "# last_rotated:" annotations in the env file
+	Unresolved map[string][]string         // references the parser could not expand
+
+	Order        *audit.Ordering // key layout, set when --check-order is enabled
+	ExampleOrder *audit.Ordering
+}
+
+// scanEntries audits parsed entries, comparing against the example entries if given
+func scanEntries(cfg *Config, input scanInput, example map[string]string) *audit.Result {
+	var missing, extra []string
+	required := cfg.Required
+	if example != nil {
+		compareResult := parser.Compare(input.Entries, example)
 		missing = compareResult.Missing
 		extra = compareResult.Extra
+
+		// Every example key is required, so missing ones are reported once, as required
+		if cfg.RequireAllExample {
+			required = append(append([]string{}, required...), sortedKeys(example)...)
+			missing = nil
+		}
 	}
 
-	scanResult := audit.Scan(result.Entries, &audit.ScanOptions{
-		Required:   cfg.Required,
+	cfg.logger().Debug("running checks",
+		"file", input.File,
+		"required", len(required),
+		"example", example != nil,
+		"leaks", cfg.CheckLeaks,
+		"formats", !cfg.NoFormatCheck,
+		"rotation", cfg.RotateAfter != "",
+		"order", input.ExampleOrder != nil,
+		"fail_on", cfg.failOn(),
+	)
+	// --platform and the config were validated when parsed
+	platform, _ := audit.PlatformByName(cfg.Platform)
+	return audit.Scan(input.Entries, &audit.ScanOptions{
+		Required:   required,
+		AllowEmpty: cfg.AllowEmpty,
 		Ignore:     cfg.Ignore,
-		Duplicates: result.Duplicates,
+		Duplicates: input.Duplicates,
 		Missing:    missing,
 		Extra:      extra,
 		CheckLeaks: cfg.CheckLeaks,
-		Strict:     cfg.Strict,
+		FailOn:     cfg.failOn(),
+		File:       input.File,
+		Locations:  input.Locations,
+		Overrides:  input.Overrides,
+		Defaults:   cfg.Defaults,
+
+		SkipFormats: cfg.NoFormatCheck,
+		Rotation:    rotationPolicy(cfg, input.Rotated),
+		Rules:       cfg.Rules,
+		Public:      cfg.PublicValues,
+		Allowed:     cfg.AllowHashes,
+		Unresolved:  input.Unresolved,
+
+		Order:        input.Order,
+		ExampleOrder: input.ExampleOrder,
+		SortByKey:    cfg.Sort == sortKey,
+		Limits:       cfg.Limits,
+		Platform:     platform,
+		Severity:     cfg.severityPolicy(),
 	})
+}
 
-	if !cfg.Quiet {
-		var output string
-		if cfg.JSONOutput {
-			formatter := &JSONFormatter{}
-			output = formatter.Format(scanResult)
-		} else if cfg.GitHubOutput {
-			formatter := &GitHubFormatter{}
-			output = formatter.Format(scanResult)
-		} else {
-			output = FormatSummary(scanResult)
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// rotationPolicy builds the rotation policy from the config, using env file
+// annotations for keys the config has no date for. Both were validated when
+// loaded, except annotations, which are skipped if they aren't dates.
+func rotationPolicy(cfg *Config, annotated map[string]string) *audit.Rotation {
+	if cfg.RotateAfter == "" {
+		return nil
+	}
+	window, err := config.ParseWindow(cfg.RotateAfter)
+	if err != nil {
+		return nil
+	}
+	rotation := &audit.Rotation{After: window, LastRotated: make(map[string]time.Time)}
+	for _, dates := range []map[string]string{annotated, cfg.LastRotated} {
+		for key, date := range dates {
+			if t, err := time.Parse(config.DateLayout, date); err == nil {
+				rotation.LastRotated[key] = t
+			}
 		}
-		if output != "" {
-			fmt.Fprint(stdout, output)
+	}
+	return rotation
+}
+
+// convertDefaults maps config defaults onto the audit representation
+func convertDefaults(defaults map[string]config.Default) map[string]audit.Default {
+	if defaults == nil {
+		return nil
+	}
+	result := make(map[string]audit.Default, len(defaults))
+	for key, def := range defaults {
+		result[key] = audit.Default{Value: def.Value, Unsafe: def.Unsafe}
+	}
+	return result
+}
+
+// convertPublicValues maps config public values onto the audit representation
+func convertPublicValues(values []config.PublicValue) []audit.PublicValue {
+	var result []audit.PublicValue
+	for _, v := range values {
+		result = append(result, audit.PublicValue{Pattern: v.Value, Reason: v.Reason})
+	}
+	return result
+}
+
+// convertLimits maps config limits onto the audit representation;
+// config.LoadFile has already rejected invalid sizes
+func convertLimits(limits config.Limits) audit.Limits {
+	result := audit.Limits{MaxVars: limits.MaxVars, MaxValueLength: limits.MaxValueLength}
+	if limits.MaxTotalSize != "" {
+		result.MaxTotalSize, _ = config.ParseSize(limits.MaxTotalSize)
+	}
+	return result
+}
+
+// convertHTTP maps the config's HTTP settings onto client options
+func convertHTTP(h config.HTTP) netutil.Options {
+	opts := netutil.Options{Proxy: h.Proxy, CABundle: h.CABundle, Retries: netutil.DefaultRetries, RateLimit: h.RateLimit}
+	if h.Retries != nil {
+		opts.Retries = *h.Retries
+	}
+	return opts
+}
+
+// convertSeverity maps config severity overrides, keyed by issue type name,
+// onto a policy; config.LoadFile has already rejected unknown names
+func convertSeverity(severity map[string]string) audit.SeverityPolicy {
+	if severity == nil {
+		return nil
+	}
+	policy := make(audit.SeverityPolicy, len(severity))
+	for name, level := range severity {
+		if t, ok := audit.ParseIssueType(name); ok {
+			policy[t] = level
 		}
 	}
+	return policy
+}
 
-	if scanResult.HasRisks {
-		return 1
+// compileRules compiles config value rules; config.LoadFile has already
+// rejected invalid patterns
+func compileRules(rules map[string]string) map[string]*regexp.Regexp {
+	if rules == nil {
+		return nil
 	}
-	return 0
+	result := make(map[string]*regexp.Regexp, len(rules))
+	for key, pattern := range rules {
+		if re, err := regexp.Compile(pattern); err == nil {
+			result[key] = re
+		}
+	}
+	return result
 }
 
-// runInit generates a .env.example file from the current environment
-func runInit(env map[string]string, force bool, stdout, stderr io.Writer) int {
+// runInit generates a .env.example file from the current environment,
+// writing each description as a comment above its key
+func runInit(cfg *Config, env, descriptions map[string]string, stdout, stderr io.Writer) int {
 	const outputFile = ".env.example"
 
 	// Check if file already exists
 	if _, err := os.Stat(outputFile); err == nil {
-		if !force {
+		if !cfg.Force {
 			fmt.Fprintln(stderr, "Error:", outputFile, "already exists (use --force to overwrite)")
 			return 2
 		}
 	}
 
-	template := parser.GenerateTemplate(env)
-	if err := os.WriteFile(outputFile, []byte(template+"\n"), 0644); err != nil {
+	template := parser.GenerateDocumentedTemplate(env, descriptions)
+	if !cfg.backup(outputFile, stdout, stderr) {
+		return 2
+	}
+	if err := fsutil.WriteFileAtomic(outputFile, []byte(template+"\n"), 0644); err != nil {
 		fmt.Fprintln(stderr, "Error:", err)
 		return 2
 	}
 
-	fmt.Fprintln(stdout, "Generated", outputFile)
+	cfg.notify(stdout, "Generated", outputFile)
+	secretKeys := 0
+	for key := range env {
+		if audit.IsSecretKey(key) {
+			secretKeys++
+		}
+	}
+	if secretKeys > 0 {
+		fmt.Fprintf(stderr, "Warning: left out %s that look like secrets\n", pluralize(secretKeys, "key"))
+	}
+	return 0
+}
+
+// backup saves path before it is rewritten when --backup is set; new files
+// have nothing to back up. Returns false after reporting an error.
+func (cfg *Config) backup(path string, stdout, stderr io.Writer) bool {
+	if cfg.Backup == "" {
+		return true
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return true
+	}
+	saved, err := fsutil.Backup(path, cfg.Backup)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error: backup failed, file left unchanged:", err)
+		return false
+	}
+	cfg.notify(stdout, "Backed up", path, "to", saved)
+	return true
+}
+
+// backupFile saves path as path+suffix before a subcommand rewrites it,
+// reporting the backup on stdout. Returns false after reporting an error.
+func backupFile(path, suffix string, stdout, stderr io.Writer) bool {
+	saved, err := fsutil.Backup(path, suffix)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error: backup failed, file left unchanged:", err)
+		return false
+	}
+	fmt.Fprintln(stdout, "Backed up", path, "to", saved)
+	return true
+}
+
+// runFixOrder rewrites each input file so its keys follow the example's order and sections
+func runFixOrder(cfg *Config, stdout, stderr io.Writer) int {
+	files := cfg.InputFiles()
+	if len(files) == 0 || cfg.ExampleFile == "" {
+		fmt.Fprintln(stderr, "Error: --fix-order requires --file and --example")
+		return 2
+	}
+
+	exampleDoc, err := parser.ParseDocument(cfg.ExampleFile)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	reference := exampleDoc.Ordering()
+
+	for _, path := range files {
+		original, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+		doc, err := parser.ParseDocument(path)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+
+		reordered := doc.ReorderLike(reference)
+		if reordered == string(original) {
+			cfg.notify(stdout, path, "already follows", cfg.ExampleFile)
+			continue
+		}
+
+		if !cfg.backup(path, stdout, stderr) {
+			return 2
+		}
+		if err := fsutil.WriteFileAtomic(path, []byte(reordered), 0644); err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+		cfg.notify(stdout, "Reordered", path)
+	}
 	return 0
 }
 
 // runDiff compares two env files and outputs the differences
-func runDiff(file1, file2 string, quiet bool, stdout, stderr io.Writer) int {
+func runDiff(cfg *Config, redactor *redact.Redactor, stdout, stderr io.Writer) int {
+	file1, file2 := cfg.FilePath, cfg.DiffFile
+
 	// Parse first file
-	result1, err := parser.ParseEnvFile(file1)
+	result1, err := parser.ParseEnvFileWith(file1, cfg.parseOptions())
 	if err != nil {
 		fmt.Fprintln(stderr, "Error:", err)
 		return 2
 	}
 
 	// Parse second file
-	result2, err := parser.ParseEnvFile(file2)
+	result2, err := parser.ParseEnvFileWith(file2, cfg.parseOptions())
 	if err != nil {
 		fmt.Fprintln(stderr, "Error:", err)
 		return 2
 	}
 
+	if cfg.SideBySide {
+		if !cfg.Quiet {
+			fmt.Fprint(stdout, formatSideBySide(result1.Entries, result2.Entries, sideBySideOptions{
+				Left:     file1,
+				Right:    file2,
+				Redactor: redactor,
+				Width:    diffWidth(),
+				Color:    cfg.useColor(stdout),
+			}))
+		}
+		return 0
+	}
+
 	// Compute diff
 	diffResult := parser.Diff(result1.Entries, result2.Entries)
+	diffResult.DetectRenames()
 
 	// Output diff (redact sensitive values)
-	if !quiet {
-		output := parser.FormatDiff(diffResult, true)
+	if !cfg.Quiet {
+		output := parser.FormatDiffWith(diffResult, redactor)
 		if output != "" {
 			fmt.Fprint(stdout, output)
 		}
@@ -0,0 +1,218 @@
+package cli
+
+import (
+	"encoding/json"
+	"path/filepath"
+
+	"env-audit/internal/audit"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	sarifToolURI = "https://github.com/0xWhisp/env-audit"
+)
+
+// SARIFFormatter outputs results as a SARIF 2.1.0 log for GitHub Code
+// Scanning and other security dashboards. Each issue type is a rule.
+type SARIFFormatter struct {
+	Metadata *reportMetadata // optional run details, recorded as artifacts and run properties
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	Artifacts   []sarifArtifact   `json:"artifacts,omitempty"`
+	Results     []sarifResult     `json:"results"`
+	Properties  map[string]any    `json:"properties,omitempty"`
+}
+
+type sarifArtifact struct {
+	Location sarifArtifactLocation `json:"location"`
+	Hashes   map[string]string     `json:"hashes,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	Properties           map[string]any     `json:"properties,omitempty"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations,omitempty"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          map[string]any    `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// Format implements Formatter interface for SARIFFormatter
+func (f *SARIFFormatter) Format(result *audit.Result) string {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "env-audit",
+			Version:        Version,
+			InformationURI: sarifToolURI,
+			Rules:          []sarifRule{},
+		}},
+		Invocations: []sarifInvocation{{ExecutionSuccessful: true}},
+		Results:     []sarifResult{},
+	}
+
+	if meta := f.Metadata; meta != nil {
+		for _, input := range meta.Inputs {
+			artifact := sarifArtifact{Location: sarifArtifactLocation{URI: filepath.ToSlash(input.Path)}}
+			if input.SHA256 != "" {
+				artifact.Hashes = map[string]string{"sha-256": input.SHA256}
+			}
+			run.Artifacts = append(run.Artifacts, artifact)
+		}
+		run.Properties = map[string]any{
+			"patternsVersion": meta.PatternsVersion,
+			"enabledRules":    meta.Rules,
+		}
+		if meta.Config != "" {
+			run.Properties["config"] = meta.Config
+		}
+	}
+
+	if result != nil {
+		for _, file := range result.Files {
+			if file.Error == nil {
+				continue
+			}
+			run.Invocations[0].ExecutionSuccessful = false
+			run.Invocations[0].ToolExecutionNotifications = append(run.Invocations[0].ToolExecutionNotifications, sarifNotification{
+				Level:     "error",
+				Message:   sarifMessage{Text: "failed to scan: " + file.Error.Error()},
+				Locations: sarifLocations(file.Path, 0),
+			})
+		}
+
+		// Only rules that fired are listed, in report order
+		ruleIndex := make(map[audit.IssueType]int)
+		for _, t := range issueTypeOrder {
+			for _, issue := range result.Issues {
+				if issue.Type == t {
+					ruleIndex[t] = len(run.Tool.Driver.Rules)
+					run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, newSARIFRule(t))
+					break
+				}
+			}
+		}
+
+		for _, issue := range result.Issues {
+			check := issueTypeToString(issue.Type)
+			entry := sarifResult{
+				RuleID:              check,
+				RuleIndex:           ruleIndex[issue.Type],
+				Level:               sarifLevel(issue.Level()),
+				Message:             sarifMessage{Text: issue.Key + ": " + issue.Message},
+				Locations:           sarifLocations(issue.File, issue.Line),
+				PartialFingerprints: map[string]string{"envAudit/v1": issue.ID()},
+			}
+			if issue.Rule != "" {
+				entry.Properties = map[string]any{"pattern": issue.Rule}
+			}
+			run.Results = append(run.Results, entry)
+		}
+	}
+
+	data, _ := json.MarshalIndent(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}}, "", "  ")
+	return string(data) + "\n"
+}
+
+// newSARIFRule describes an issue type; leaks carry a security severity so
+// GitHub Code Scanning ranks them as security alerts
+func newSARIFRule(t audit.IssueType) sarifRule {
+	rule := sarifRule{
+		ID:                   issueTypeToString(t),
+		Name:                 issueTypeNames[t],
+		ShortDescription:     sarifMessage{Text: issueTypeNames[t]},
+		DefaultConfiguration: sarifConfiguration{Level: sarifLevel(t.Severity())},
+	}
+	if t.IsSecret() {
+		rule.Properties = map[string]any{"tags": []string{"security"}, "security-severity": "9.0"}
+	}
+	return rule
+}
+
+// sarifLevel maps issue severities onto SARIF result levels
+func sarifLevel(level string) string {
+	if level == audit.SeverityInfo {
+		return "note"
+	}
+	return level
+}
+
+// sarifLocations points at a file and line; issues found in the process
+// environment have no location
+func sarifLocations(path string, line int) []sarifLocation {
+	if path == "" {
+		return nil
+	}
+	location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(path)},
+	}}
+	if line > 0 {
+		location.PhysicalLocation.Region = &sarifRegion{StartLine: line}
+	}
+	return []sarifLocation{location}
+}
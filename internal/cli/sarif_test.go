@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"env-audit/internal/audit"
+)
+
+func TestSARIFFormatter_Format(t *testing.T) {
+	result := &audit.Result{
+		Issues: []audit.Issue{
+			{Type: audit.IssueLeak, Key: "TOKEN", Message: "GitHub token detected", File: ".env", Line: 3, Rule: "github-pat"},
+			{Type: audit.IssueEmpty, Key: "PORT", Message: "empty value", File: ".env", Line: 1},
+			{Type: audit.IssueMissing, Key: "DB_URL", Message: "required variable is missing"},
+		},
+		Files: []audit.FileStatus{{Path: ".env"}, {Path: "broken.env", Error: errors.New("bad line")}},
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal([]byte((&SARIFFormatter{}).Format(result)), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log header: %+v", log)
+	}
+	run := log.Runs[0]
+
+	if len(run.Tool.Driver.Rules) != 3 {
+		t.Fatalf("expected a rule per fired issue type, got %+v", run.Tool.Driver.Rules)
+	}
+	if len(run.Results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(run.Results))
+	}
+	for _, r := range run.Results {
+		if run.Tool.Driver.Rules[r.RuleIndex].ID != r.RuleID {
+			t.Errorf("ruleIndex %d does not point at rule %s", r.RuleIndex, r.RuleID)
+		}
+		if len(r.PartialFingerprints["envAudit/v1"]) != 32 {
+			t.Errorf("expected the issue ID as fingerprint on %s", r.RuleID)
+		}
+	}
+
+	leak := run.Results[0]
+	if leak.RuleID != "leak" || leak.Level != "error" {
+		t.Errorf("unexpected leak result: %+v", leak)
+	}
+	loc := leak.Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != ".env" || loc.Region == nil || loc.Region.StartLine != 3 {
+		t.Errorf("unexpected leak location: %+v", loc)
+	}
+	if leak.Properties["pattern"] != "github-pat" {
+		t.Errorf("expected the leak pattern id, got %v", leak.Properties)
+	}
+	if run.Results[1].Level != "warning" {
+		t.Errorf("expected empty values to be warnings, got %s", run.Results[1].Level)
+	}
+	if len(run.Results[2].Locations) != 0 {
+		t.Errorf("expected no location for an issue without a file, got %+v", run.Results[2].Locations)
+	}
+
+	inv := run.Invocations[0]
+	if inv.ExecutionSuccessful || len(inv.ToolExecutionNotifications) != 1 {
+		t.Errorf("expected the failed file as a notification, got %+v", inv)
+	}
+}
+
+func TestSARIFFormatter_NoIssues(t *testing.T) {
+	var log sarifLog
+	if err := json.Unmarshal([]byte((&SARIFFormatter{}).Format(&audit.Result{})), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	if log.Runs[0].Results == nil || len(log.Runs[0].Results) != 0 {
+		t.Errorf("expected an empty results array, got %+v", log.Runs[0].Results)
+	}
+	if !log.Runs[0].Invocations[0].ExecutionSuccessful {
+		t.Error("expected a successful invocation")
+	}
+}
+
+func TestSARIFFormatter_Metadata(t *testing.T) {
+	meta := &reportMetadata{PatternsVersion: "p1", Config: ".env-audit.yaml", Rules: []string{"empty"}, Inputs: []inputFile{{Path: ".env", SHA256: "abc"}}}
+
+	var log sarifLog
+	if err := json.Unmarshal([]byte((&SARIFFormatter{Metadata: meta}).Format(&audit.Result{})), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	run := log.Runs[0]
+	if len(run.Artifacts) != 1 || run.Artifacts[0].Hashes["sha-256"] != "abc" {
+		t.Errorf("expected hashed artifact, got %+v", run.Artifacts)
+	}
+	if run.Properties["config"] != ".env-audit.yaml" || run.Properties["patternsVersion"] != "p1" {
+		t.Errorf("unexpected run properties: %+v", run.Properties)
+	}
+}
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	envauditv1 "env-audit/api/envaudit/v1"
+	"env-audit/internal/audit"
+	"env-audit/internal/parser"
+)
+
+// defaultServeAddr is where serve listens without --addr; loopback only,
+// since scan requests carry live env files
+const defaultServeAddr = "localhost:50051"
+
+// listen opens the serve listener; replaced in tests
+var listen = net.Listen
+
+// runServe implements "env-audit serve [--addr host:port]": the gRPC scan
+// service of api/envaudit/v1/scan.proto, until the process is stopped
+func runServe(args []string, stdout, stderr io.Writer) int {
+	addr := defaultServeAddr
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; arg {
+		case "--addr":
+			if i+1 >= len(args) {
+				fmt.Fprintln(stderr, "Error: missing value for --addr")
+				return 2
+			}
+			i++
+			addr = args[i]
+		default:
+			fmt.Fprintln(stderr, "Error: unknown argument:", arg)
+			return 2
+		}
+	}
+
+	ln, err := listen("tcp", addr)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	fmt.Fprintln(stdout, "Serving the gRPC scan service on", ln.Addr())
+	server := envauditv1.NewServer(addr, envauditv1.NewScanServiceHandler(scanService{}))
+	if err := server.Serve(ln); err != nil && !errors.Is(err, net.ErrClosed) {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	return 0
+}
+
+// scanService answers Scan calls with the checks of an audit run. Files
+// come from the request, never the server's disk or environment.
+type scanService struct{}
+
+func (scanService) Scan(req *envauditv1.ScanRequest, stream envauditv1.ScanServerStream) error {
+	cfg, err := scanRequestConfig(req)
+	if err != nil {
+		return envauditv1.Errorf(envauditv1.InvalidArgument, "%v", err)
+	}
+
+	examples := &exampleSet{}
+	var example map[string]string
+	if req.Example != nil {
+		result, err := parser.ParseEnvWith(bytes.NewReader(req.Example.Content), cfg.parseOptions())
+		if err != nil {
+			return envauditv1.Errorf(envauditv1.InvalidArgument, "example %s: %v", req.Example.Path, err)
+		}
+		examples.paths = []string{req.Example.Path}
+		examples.results = map[string]*parser.ParseResult{req.Example.Path: result}
+		example = result.Entries
+	}
+
+	var results []*audit.Result
+	var statuses []audit.FileStatus
+	for _, file := range req.Files {
+		result, err := parser.ParseEnvWith(bytes.NewReader(file.Content), cfg.parseOptions())
+		if err != nil {
+			statuses = append(statuses, audit.FileStatus{Path: file.Path, Error: err})
+			continue
+		}
+		scanned := scanEntries(cfg, scanInput{
+			Entries:    result.Entries,
+			Duplicates: result.Duplicates,
+			File:       file.Path,
+			Locations:  result.Locations(file.Path),
+			Rotated:    rotationAnnotations(result),
+			Unresolved: result.Unresolved,
+		}, example)
+		// Issues are streamed as each file is scanned
+		if err := sendIssues(stream, scanned.Issues); err != nil {
+			return err
+		}
+		results = append(results, scanned)
+		statuses = append(statuses, audit.FileStatus{Path: file.Path})
+	}
+
+	// Examples are committed, so check them for pasted-in real secrets
+	leaks := audit.NewResult(examples.leaks(cfg.Ignore, nil, nil), cfg.severityPolicy(), cfg.failOn())
+	if err := sendIssues(stream, leaks.Issues); err != nil {
+		return err
+	}
+
+	merged := audit.Merge(append(results, leaks), statuses)
+	return stream.Send(&envauditv1.ScanResponse{Summary: newScanSummary(merged)})
+}
+
+// scanRequestConfig validates a scan request and converts it to the
+// options of an audit run
+func scanRequestConfig(req *envauditv1.ScanRequest) (*Config, error) {
+	if len(req.Files) == 0 {
+		return nil, errors.New("no files to scan")
+	}
+	for i, file := range req.Files {
+		if file.Path == "" {
+			return nil, fmt.Errorf("file %d has no path", i+1)
+		}
+	}
+	if req.Example != nil && req.Example.Path == "" {
+		return nil, errors.New("example has no path")
+	}
+	if req.FailOn != "" && !audit.IsSeverity(req.FailOn) {
+		return nil, fmt.Errorf("fail_on must be error, warning or info, got %q", req.FailOn)
+	}
+	for _, name := range append(append([]string{}, req.Only...), req.Exclude...) {
+		if _, ok := audit.ParseIssueType(name); !ok {
+			return nil, fmt.Errorf("unknown issue type %q", name)
+		}
+	}
+	return &Config{
+		Required:   req.Required,
+		Ignore:     req.Ignore,
+		CheckLeaks: req.CheckLeaks,
+		FailOn:     req.FailOn,
+		Only:       req.Only,
+		Exclude:    req.Exclude,
+	}, nil
+}
+
+// sendIssues streams issues as they appear in the JSON report
+func sendIssues(stream envauditv1.ScanServerStream, issues []audit.Issue) error {
+	for _, issue := range issues {
+		if err := stream.Send(&envauditv1.ScanResponse{Issue: newScanIssue(issue)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newScanIssue converts an issue to its message, through its JSON
+// representation so that both reports agree
+func newScanIssue(issue audit.Issue) *envauditv1.Issue {
+	entry := newJSONIssue(issue)
+	m := &envauditv1.Issue{
+		ID:       entry.ID,
+		Type:     entry.Type,
+		Key:      entry.Key,
+		Message:  entry.Message,
+		File:     entry.File,
+		Line:     int32(entry.Line),
+		Rule:     entry.Rule,
+		Docs:     entry.Docs,
+		Severity: issue.Level(),
+	}
+	if fix := entry.Remediation; fix != nil {
+		m.Remediation = &envauditv1.Remediation{Action: fix.Action, Key: fix.Key, File: fix.File, Command: fix.Command}
+	}
+	return m
+}
+
+// newScanSummary converts the result of a scan to the closing summary
+func newScanSummary(result *audit.Result) *envauditv1.Summary {
+	summary := &envauditv1.Summary{
+		HasRisks:   result.HasRisks,
+		ByType:     make(map[string]int32),
+		BySeverity: make(map[string]int32),
+	}
+	for name, count := range jsonSummary(result.Summary) {
+		summary.ByType[name] = int32(count)
+	}
+	for name, count := range newJSONStats(result).BySeverity {
+		summary.BySeverity[name] = int32(count)
+	}
+	for _, file := range newJSONFiles(result.Files) {
+		summary.Files = append(summary.Files, &envauditv1.FileStatus{Path: file.Path, Status: file.Status, Error: file.Error})
+	}
+	return summary
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"env-audit/internal/audit"
+)
+
+// TeamCityFormatter outputs results as TeamCity service messages: each issue
+// is an inspection, so findings appear on the build's Inspections tab
+type TeamCityFormatter struct{}
+
+// Format implements Formatter interface for TeamCityFormatter
+func (f *TeamCityFormatter) Format(result *audit.Result) string {
+	if result == nil || (len(result.Issues) == 0 && result.Failed() == 0) {
+		return ""
+	}
+
+	var lines []string
+	for _, file := range result.Files {
+		if file.Error != nil {
+			lines = append(lines, fmt.Sprintf("##teamcity[message text='%s' status='ERROR']", teamCityEscape("failed to scan "+file.Path+": "+file.Error.Error())))
+		}
+	}
+
+	// Inspection types are declared once, before their first use
+	declared := make(map[audit.IssueType]bool)
+	for _, issue := range result.Issues {
+		typeID := "env-audit." + issueTypeToString(issue.Type)
+		if !declared[issue.Type] {
+			declared[issue.Type] = true
+			lines = append(lines, fmt.Sprintf("##teamcity[inspectionType id='%s' name='%s' category='env-audit' description='%s']",
+				teamCityEscape(typeID), teamCityEscape(issueTypeNames[issue.Type]), teamCityEscape(issueTypeNames[issue.Type])))
+		}
+
+		file := issue.File
+		if file == "" {
+			file = "environment"
+		}
+		line := fmt.Sprintf("##teamcity[inspection typeId='%s' message='%s' file='%s'", teamCityEscape(typeID), teamCityEscape(issue.Key+": "+issue.Message), teamCityEscape(file))
+		if issue.Line > 0 {
+			line += fmt.Sprintf(" line='%d'", issue.Line)
+		}
+		line += fmt.Sprintf(" SEVERITY='%s']", strings.ToUpper(issue.Level()))
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// teamCityEscape escapes a service message attribute value
+func teamCityEscape(s string) string {
+	return strings.NewReplacer("|", "||", "'", "|'", "\n", "|n", "\r", "|r", "[", "|[", "]", "|]").Replace(s)
+}
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"env-audit/internal/audit"
+)
+
+func TestTeamCityFormatter_Format(t *testing.T) {
+	result := &audit.Result{Issues: []audit.Issue{
+		{Type: audit.IssueLeak, Key: "TOKEN", Message: "token [github]", File: ".env", Line: 3},
+		{Type: audit.IssueLeak, Key: "KEY", Message: "it's a key", File: ".env", Line: 4},
+		{Type: audit.IssueSensitive, Key: "PASSWORD", Message: "sensitive"},
+	}}
+	output := (&TeamCityFormatter{}).Format(result)
+
+	want := []string{
+		"##teamcity[inspectionType id='env-audit.leak' name='Potential Leaks' category='env-audit' description='Potential Leaks']",
+		"##teamcity[inspection typeId='env-audit.leak' message='TOKEN: token |[github|]' file='.env' line='3' SEVERITY='ERROR']",
+		"##teamcity[inspection typeId='env-audit.leak' message='KEY: it|'s a key' file='.env' line='4' SEVERITY='ERROR']",
+		"##teamcity[inspectionType id='env-audit.sensitive' name='Sensitive Keys Detected' category='env-audit' description='Sensitive Keys Detected']",
+		"##teamcity[inspection typeId='env-audit.sensitive' message='PASSWORD: sensitive' file='environment' SEVERITY='INFO']",
+	}
+	if got := strings.TrimSuffix(output, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, strings.Join(want, "\n"))
+	}
+}
+
+func TestTeamCityEscape(t *testing.T) {
+	if got := teamCityEscape("a|b'c\nd[e]"); got != "a||b|'c|nd|[e|]" {
+		t.Errorf("unexpected escape: %q", got)
+	}
+}
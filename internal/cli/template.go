@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"env-audit/internal/audit"
+)
+
+// TemplateFormatter renders the result through a user-supplied Go
+// text/template, for formats env-audit does not ship (CSV, wiki markup, ...)
+type TemplateFormatter struct {
+	Template *template.Template
+	Metadata *reportMetadata // exposed to the template as .Metadata
+}
+
+// templateData is the value a --format-template template executes against.
+// It embeds the audit result, so .Issues, .Summary, .Files and .HasRisks
+// work as in Go code.
+type templateData struct {
+	*audit.Result
+	Metadata *reportMetadata
+}
+
+// templateFuncs are the helpers available to --format-template templates
+var templateFuncs = template.FuncMap{
+	// typeName is an issue type's JSON name, e.g. "leak"
+	"typeName": issueTypeToString,
+	// severity is "error", "warning" or "info": the level an issue is
+	// reported at, or an issue type's default
+	"severity": func(v any) string {
+		if issue, ok := v.(audit.Issue); ok {
+			return issue.Level()
+		}
+		return v.(audit.IssueType).Severity()
+	},
+	// csv quotes its arguments as one CSV record, without the newline
+	"csv": func(fields ...interface{}) (string, error) {
+		record := make([]string, len(fields))
+		for i, field := range fields {
+			record[i] = fmt.Sprint(field)
+		}
+		var sb strings.Builder
+		w := csv.NewWriter(&sb)
+		if err := w.Write(record); err != nil {
+			return "", err
+		}
+		w.Flush()
+		return strings.TrimSuffix(sb.String(), "\n"), w.Error()
+	},
+	// json encodes a value, e.g. a message inside a hand-written JSON document
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// loadFormatTemplate parses the --format-template file
+func loadFormatTemplate(path string) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid format template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// Format renders the result. Template errors surface through Render; Format
+// satisfies the Formatter interface and reports them inline.
+func (f *TemplateFormatter) Format(result *audit.Result) string {
+	output, err := f.Render(result)
+	if err != nil {
+		return "Error: " + err.Error() + "\n"
+	}
+	return output
+}
+
+// Render executes the template against result
+func (f *TemplateFormatter) Render(result *audit.Result) (string, error) {
+	if result == nil {
+		result = &audit.Result{Summary: map[audit.IssueType]int{}}
+	}
+	var sb strings.Builder
+	if err := f.Template.Execute(&sb, templateData{Result: result, Metadata: f.Metadata}); err != nil {
+		return "", fmt.Errorf("format template: %w", err)
+	}
+	return sb.String(), nil
+}
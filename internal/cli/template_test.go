@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"env-audit/internal/audit"
+)
+
+func mustTemplate(t *testing.T, text string) *template.Template {
+	t.Helper()
+	tmpl, err := template.New("test").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tmpl
+}
+
+func TestTemplateFormatter_CSV(t *testing.T) {
+	result := &audit.Result{Issues: []audit.Issue{
+		{Type: audit.IssueEmpty, Key: "PORT", Message: "variable has empty value", File: ".env", Line: 2},
+		{Type: audit.IssueLeak, Key: "TOKEN", Message: `looks like "a, token"`, File: ".env", Line: 3},
+	}}
+	tmpl := mustTemplate(t, "type,severity,key,file,line,message\n{{range .Issues}}{{csv (typeName .Type) (severity .Type) .Key .File .Line .Message}}\n{{end}}")
+
+	output, err := (&TemplateFormatter{Template: tmpl}).Render(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "type,severity,key,file,line,message\n" +
+		"empty,warning,PORT,.env,2,variable has empty value\n" +
+		`leak,error,TOKEN,.env,3,"looks like ""a, token"""` + "\n"
+	if output != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", output, want)
+	}
+}
+
+func TestTemplateFormatter_MetadataAndNilResult(t *testing.T) {
+	tmpl := mustTemplate(t, "{{.Metadata.Version}} {{len .Issues}} {{.HasRisks}}")
+	output, err := (&TemplateFormatter{Template: tmpl, Metadata: &reportMetadata{Version: "9.9.9"}}).Render(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != "9.9.9 0 false" {
+		t.Errorf("unexpected output: %q", output)
+	}
+}
+
+func TestTemplateFormatter_ExecutionError(t *testing.T) {
+	tmpl := mustTemplate(t, "{{.NoSuchField}}")
+	if _, err := (&TemplateFormatter{Template: tmpl}).Render(&audit.Result{}); err == nil {
+		t.Error("expected an execution error")
+	}
+}
+
+func TestRun_FormatTemplate(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, ".env")
+	tmplPath := filepath.Join(dir, "report.tmpl")
+	os.WriteFile(envPath, []byte("EMPTY=\n"), 0644)
+	os.WriteFile(tmplPath, []byte("{{range .Issues}}* {{.Key}} ({{typeName .Type}})\n{{end}}"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	exitCode := Run([]string{"-f", envPath, "--format-template", tmplPath}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit 0, got %d: %s", exitCode, stderr.String())
+	}
+	if stdout.String() != "* EMPTY (empty)\n" {
+		t.Errorf("unexpected output: %q", stdout.String())
+	}
+}
+
+func TestRun_FormatTemplate_ParseError(t *testing.T) {
+	tmplPath := filepath.Join(t.TempDir(), "bad.tmpl")
+	os.WriteFile(tmplPath, []byte("{{range .Issues}"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := Run([]string{"--format-template", tmplPath}, &stdout, &stderr); exitCode != 2 {
+		t.Errorf("expected exit 2, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "invalid format template") {
+		t.Errorf("expected parse error, got: %s", stderr.String())
+	}
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"env-audit/internal/discover"
+	"env-audit/internal/usage"
+)
+
+// usageConfig holds the arguments of the usage command
+type usageConfig struct {
+	Root     string // directory to analyze, defaults to .
+	JSON     bool   // --json output the report as JSON
+	NoIgnore bool   // --no-ignore also walk gitignored, vendor and node_modules dirs
+}
+
+// parseUsageArgs parses the arguments following "usage"
+func parseUsageArgs(args []string) (*usageConfig, error) {
+	cfg := &usageConfig{}
+	for _, arg := range args {
+		switch {
+		case arg == "--json":
+			cfg.JSON = true
+		case arg == "--no-ignore":
+			cfg.NoIgnore = true
+		case strings.HasPrefix(arg, "-"):
+			return nil, fmt.Errorf("unknown argument: %s", arg)
+		case cfg.Root != "":
+			return nil, fmt.Errorf("usage takes one directory, got extra argument %s", arg)
+		default:
+			cfg.Root = arg
+		}
+	}
+	if cfg.Root == "" {
+		cfg.Root = "."
+	}
+	return cfg, nil
+}
+
+// runUsage reports which services of a monorepo read each environment
+// variable, and the variables several services read from one shared env
+// file. It is a report, so the exit code is 0 unless the analysis fails.
+func runUsage(args []string, stdout, stderr io.Writer) int {
+	ucfg, err := parseUsageArgs(args)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	report, err := usage.Analyze(ucfg.Root, discover.Options{NoIgnore: ucfg.NoIgnore})
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 2
+	}
+	for _, long := range report.LongLines {
+		fmt.Fprintf(stderr, "Warning: skipped %s over %d MiB in %s\n", pluralize(long.Lines, "line"), usage.MaxLineLength>>20, long.File)
+	}
+
+	if ucfg.JSON {
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 2
+		}
+		fmt.Fprintln(stdout, string(out))
+		return 0
+	}
+
+	if len(report.Variables) == 0 {
+		fmt.Fprintln(stdout, "No environment variable reads found in", ucfg.Root)
+		return 0
+	}
+	width := len("VARIABLE")
+	for _, v := range report.Variables {
+		if len(v.Key) > width {
+			width = len(v.Key)
+		}
+	}
+	fmt.Fprintf(stdout, "%-*s  SERVICES  USED BY\n", width, "VARIABLE")
+	for _, v := range report.Variables {
+		fmt.Fprintf(stdout, "%-*s  %-8d  %s\n", width, v.Key, len(v.Services), strings.Join(v.Services, ", "))
+	}
+
+	if len(report.Shared) > 0 {
+		fmt.Fprintln(stdout, "")
+		fmt.Fprintln(stdout, "Read by several services from one env file (consider per-service config):")
+		for _, s := range report.Shared {
+			fmt.Fprintf(stdout, "  %s: %s (%s)\n", s.File, s.Key, strings.Join(s.Services, ", "))
+		}
+	}
+	return 0
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunUsage(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		".env":                "DATABASE_URL=postgres://db\n",
+		"api/go.mod":          "module api\n",
+		"api/main.go":         `os.Getenv("DATABASE_URL"); os.Getenv("PORT")`,
+		"worker/package.json": "{}",
+		"worker/index.js":     "process.env.DATABASE_URL",
+	} {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(p), 0755)
+		os.WriteFile(p, []byte(content), 0644)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"usage", root}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	want := "VARIABLE      SERVICES  USED BY\n" +
+		"DATABASE_URL  2         api, worker\n" +
+		"PORT          1         api\n" +
+		"\n" +
+		"Read by several services from one env file (consider per-service config):\n" +
+		"  " + filepath.Join(root, ".env") + ": DATABASE_URL (api, worker)\n"
+	if stdout.String() != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", stdout.String(), want)
+	}
+
+	stdout.Reset()
+	Run([]string{"usage", "--json", root}, &stdout, &stderr)
+	var report struct {
+		Variables []struct {
+			Key string `json:"key"`
+		} `json:"variables"`
+		Shared []struct {
+			Key string `json:"key"`
+		} `json:"shared"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout.String())
+	}
+	if len(report.Variables) != 2 || len(report.Shared) != 1 || report.Shared[0].Key != "DATABASE_URL" {
+		t.Errorf("unexpected JSON report: %s", stdout.String())
+	}
+}
+
+func TestRunUsage_MinifiedBundle(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "main.go"), []byte(`os.Getenv("PORT")`), 0644)
+	bundle := "process.env.API_URL;" + strings.Repeat("a", 1100*1024)
+	os.WriteFile(filepath.Join(root, "bundle.min.js"), []byte(bundle), 0644)
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"usage", root}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "PORT") {
+		t.Errorf("expected the other files to be reported, got:\n%s", stdout.String())
+	}
+	want := "Warning: skipped 1 line over 1 MiB in " + filepath.Join(root, "bundle.min.js") + "\n"
+	if stderr.String() != want {
+		t.Errorf("expected a warning for the bundle, got %q", stderr.String())
+	}
+}
+
+func TestParseUsageArgs_Invalid(t *testing.T) {
+	for _, args := range [][]string{{"--bogus"}, {"a", "b"}} {
+		if _, err := parseUsageArgs(args); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+	if cfg, err := parseUsageArgs(nil); err != nil || cfg.Root != "." {
+		t.Errorf("expected the working directory by default, got %+v, %v", cfg, err)
+	}
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"env-audit/internal/audit"
+	"env-audit/internal/netutil"
+)
+
+// releaseFeedURL is the latest-release endpoint queried by version --check;
+// replaced in tests
+var releaseFeedURL = "https://api.github.com/repos/0xWhisp/env-audit/releases/latest"
+
+// runVersion implements "env-audit version [--check]". The version goes to
+// stdout; the update check reports only on stderr and never fails the run,
+// so it is safe in CI next to JSON consumers.
+func runVersion(args []string, stdout, stderr io.Writer) int {
+	check, offline := false, false
+	for _, arg := range args {
+		switch arg {
+		case "--check":
+			check = true
+		case "--offline":
+			offline = true
+		default:
+			fmt.Fprintln(stderr, "Error: unknown argument:", arg)
+			return 2
+		}
+	}
+	if check && offline {
+		fmt.Fprintln(stderr, "Error: --offline: --check would access the network")
+		return 2
+	}
+
+	fmt.Fprintln(stdout, "env-audit version", Version)
+	fmt.Fprintln(stdout, "leak patterns", audit.PatternsVersion)
+	if !check {
+		return 0
+	}
+
+	latest, err := latestRelease()
+	if err != nil {
+		fmt.Fprintln(stderr, "Warning: could not check for updates:", err)
+		return 0
+	}
+	if compareVersions(latest, Version) > 0 {
+		fmt.Fprintf(stderr, "A newer version is available: %s (current %s), with updated leak patterns\n", latest, Version)
+	} else {
+		fmt.Fprintln(stderr, "env-audit is up to date")
+	}
+	return 0
+}
+
+// latestRelease returns the version of the newest published release
+func latestRelease() (string, error) {
+	client := netutil.Client(10 * time.Second)
+	req, err := http.NewRequest(http.MethodGet, releaseFeedURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("release feed returned HTTP %d", resp.StatusCode)
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", fmt.Errorf("invalid release feed: %w", err)
+	}
+	if release.TagName == "" {
+		return "", fmt.Errorf("release feed has no tag")
+	}
+	return strings.TrimPrefix(release.TagName, "v"), nil
+}
+
+// compareVersions compares dotted numeric versions, returning -1, 0 or 1.
+// A leading "v" and any pre-release suffix ("-rc.1") are ignored.
+func compareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x > y {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
+
+func versionParts(v string) []int {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	var parts []int
+	for _, s := range strings.Split(v, ".") {
+		n, _ := strconv.Atoi(s)
+		parts = append(parts, n)
+	}
+	return parts
+}
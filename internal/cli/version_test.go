@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func withReleaseFeed(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	orig := releaseFeedURL
+	releaseFeedURL = server.URL
+	t.Cleanup(func() {
+		releaseFeedURL = orig
+		server.Close()
+	})
+}
+
+func TestRun_VersionCheck_NewerRelease(t *testing.T) {
+	withReleaseFeed(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name":"v99.0.0"}`))
+	})
+
+	var stdout, stderr bytes.Buffer
+	exitCode := Run([]string{"version", "--check"}, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Errorf("expected exit 0, got %d", exitCode)
+	}
+	if !strings.HasPrefix(stdout.String(), "env-audit version "+Version+"\nleak patterns ") {
+		t.Errorf("expected only the version on stdout, got: %q", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "A newer version is available: 99.0.0") {
+		t.Errorf("expected update notice on stderr, got: %s", stderr.String())
+	}
+}
+
+func TestRun_VersionCheck_FeedUnavailable(t *testing.T) {
+	withReleaseFeed(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := Run([]string{"version", "--check"}, &stdout, &stderr); exitCode != 0 {
+		t.Errorf("expected a failed check not to fail the run, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "could not check for updates") {
+		t.Errorf("expected warning on stderr, got: %s", stderr.String())
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"0.3.0", "0.2.0", 1},
+		{"v0.2.0", "0.2.0", 0},
+		{"0.2", "0.2.0", 0},
+		{"0.10.0", "0.9.1", 1},
+		{"1.0.0-rc.1", "1.0.0", 0},
+		{"0.1.9", "0.2.0", -1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"env-audit/internal/audit"
+	"env-audit/internal/config"
+	"env-audit/internal/redact"
+)
+
+// watchDebounce is how long watch mode waits for a file's events to settle
+// before auditing: one save makes several, e.g. create, write and rename
+const watchDebounce = 100 * time.Millisecond
+
+// fileWatcher follows files through the directories that hold them. Editors
+// such as vim and VS Code save by writing a temp file and renaming it over
+// the original, which replaces the file a watch on the path itself follows,
+// so the directory is watched and its events filtered by name.
+type fileWatcher struct {
+	watcher  *fsnotify.Watcher
+	files    map[string]string // absolute path of each watched file to the path as given
+	debounce time.Duration
+}
+
+// newFileWatcher watches the directories of paths for changes to them
+func newFileWatcher(paths []string) (*fileWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &fileWatcher{watcher: watcher, files: make(map[string]string), debounce: watchDebounce}
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			watcher.Close()
+			return nil, err
+		}
+		if _, err := os.Stat(abs); err != nil {
+			watcher.Close()
+			return nil, err
+		}
+		w.files[abs] = path
+		if err := watcher.Add(filepath.Dir(abs)); err != nil {
+			watcher.Close()
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// Close stops watching
+func (w *fileWatcher) Close() error {
+	return w.watcher.Close()
+}
+
+// run calls changed with the watched files that changed, as given, sorted,
+// once their events have settled. A file that was removed is included, so
+// the caller can tell; when it is created again it changes again. run
+// returns when stop receives or the watcher is closed.
+func (w *fileWatcher) run(stop <-chan os.Signal, changed func(paths []string), stderr io.Writer) {
+	pending := make(map[string]bool)
+	var settled <-chan time.Time
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			path, watched := w.files[filepath.Clean(event.Name)]
+			// A chmod alone leaves the content as it was
+			if !watched || event.Op == fsnotify.Chmod {
+				continue
+			}
+			pending[path] = true
+			settled = time.After(w.debounce)
+		case <-settled:
+			settled = nil
+			paths := make([]string, 0, len(pending))
+			for path := range pending {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+			pending = make(map[string]bool)
+			changed(paths)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			fmt.Fprintln(stderr, "Error:", err)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// watchedFiles returns the files watch mode audits again when they change:
+// the env file, its example if there is one, and the config file
+func (cfg *Config) watchedFiles() []string {
+	paths := []string{cfg.FilePath}
+	if cfg.ExampleFile != "" && cfg.ExampleFile != cfg.FilePath {
+		if _, err := os.Stat(cfg.ExampleFile); err == nil {
+			paths = append(paths, cfg.ExampleFile)
+		}
+	}
+	if cfg.configPath != "" {
+		paths = append(paths, displayPath(cfg.configPath))
+	}
+	return paths
+}
+
+// reloadConfig layers the config file, as it is now, on the CLI flags
+// again, as Run did, so an edit to it applies without restarting watch
+// mode. It returns the new configuration and its redactor.
+func (cfg *Config) reloadConfig() (*Config, *redact.Redactor, error) {
+	fileCfg, err := loadConfigFile(cfg.configPath, config.LoadFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	next := *cfg.cliArgs
+	next.MergeWithFileConfig(toFileConfig(fileCfg))
+	if err := next.applyEnv(cfg.cliArgs, os.Getenv); err != nil {
+		return nil, nil, err
+	}
+	next.configPath = cfg.configPath
+	next.ndjson = cfg.ndjson
+
+	if next.Flow {
+		if err := resolveFlow(&next); err != nil {
+			return nil, nil, err
+		}
+	}
+	if next.Patterns != "" {
+		if err := loadPatternPack(next.Patterns); err != nil {
+			return nil, nil, err
+		}
+	}
+	audit.SensitiveKeys = audit.DefaultSensitiveKeys.Extend(next.SensitivePatterns, next.SensitiveExclude)
+	if next.FormatTemplate != "" {
+		if next.formatTemplate, err = loadFormatTemplate(next.FormatTemplate); err != nil {
+			return nil, nil, err
+		}
+	}
+	redactor, err := next.Redactor()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &next, redactor, nil
+}
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"env-audit/internal/audit"
+)
+
+// watchChanges runs w until stopped, sending each batch of changed paths
+func watchChanges(t *testing.T, w *fileWatcher) <-chan []string {
+	t.Helper()
+	changes := make(chan []string, 10)
+	stop := make(chan os.Signal)
+	done := make(chan struct{})
+	go func() {
+		w.run(stop, func(paths []string) { changes <- paths }, io.Discard)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		close(stop)
+		<-done
+		w.Close()
+	})
+	return changes
+}
+
+// nextChange waits for the next batch of changed paths
+func nextChange(t *testing.T, changes <-chan []string) []string {
+	t.Helper()
+	select {
+	case paths := <-changes:
+		return paths
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a change")
+		return nil
+	}
+}
+
+func TestFileWatcher_AtomicSave(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	os.WriteFile(path, []byte("PORT=1\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "other.txt"), []byte("x"), 0644)
+
+	w, err := newFileWatcher([]string{path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	changes := watchChanges(t, w)
+
+	// Saved like vim and VS Code: a temp file renamed over the original
+	for i := 0; i < 2; i++ {
+		tmp := filepath.Join(dir, ".env.swp")
+		os.WriteFile(tmp, []byte("PORT=2\n"), 0644)
+		if err := os.Rename(tmp, path); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := nextChange(t, changes); !reflect.DeepEqual(got, []string{path}) {
+			t.Errorf("save %d: expected %s changed, got %v", i+1, path, got)
+		}
+	}
+
+	// Unrelated files in the directory don't count
+	os.WriteFile(filepath.Join(dir, "other.txt"), []byte("y"), 0644)
+	select {
+	case paths := <-changes:
+		t.Errorf("expected no change, got %v", paths)
+	case <-time.After(3 * watchDebounce):
+	}
+}
+
+func TestFileWatcher_Debounce(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	os.WriteFile(path, []byte("PORT=1\n"), 0644)
+
+	w, err := newFileWatcher([]string{path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	changes := watchChanges(t, w)
+
+	for i := 0; i < 5; i++ {
+		os.WriteFile(path, []byte("PORT=2\n"), 0644)
+	}
+	nextChange(t, changes)
+	select {
+	case paths := <-changes:
+		t.Errorf("expected a burst of writes to be one change, got another: %v", paths)
+	case <-time.After(3 * watchDebounce):
+	}
+
+	os.Remove(path)
+	if got := nextChange(t, changes); !reflect.DeepEqual(got, []string{path}) {
+		t.Errorf("expected the removal reported, got %v", got)
+	}
+	os.WriteFile(path, []byte("PORT=3\n"), 0644)
+	if got := nextChange(t, changes); !reflect.DeepEqual(got, []string{path}) {
+		t.Errorf("expected the recreated file to be followed, got %v", got)
+	}
+}
+
+func TestNewFileWatcher_MissingFile(t *testing.T) {
+	if _, err := newFileWatcher([]string{filepath.Join(t.TempDir(), ".env")}); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestWatchedFiles(t *testing.T) {
+	dir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(oldWd)
+	os.WriteFile(".env.example", []byte("PORT=\n"), 0644)
+	wd, _ := os.Getwd()
+
+	cfg := &Config{FilePath: ".env", ExampleFile: ".env.example", configPath: filepath.Join(wd, ".env-audit.yaml")}
+	if got := cfg.watchedFiles(); !reflect.DeepEqual(got, []string{".env", ".env.example", ".env-audit.yaml"}) {
+		t.Errorf("expected the env, example and config files, got %v", got)
+	}
+
+	// An example that doesn't exist yet isn't watched
+	cfg = &Config{FilePath: ".env", ExampleFile: "missing.example"}
+	if got := cfg.watchedFiles(); !reflect.DeepEqual(got, []string{".env"}) {
+		t.Errorf("expected only the env file, got %v", got)
+	}
+}
+
+func TestReloadConfig(t *testing.T) {
+	dir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(oldWd)
+	defer func(keys audit.SensitiveMatcher) { audit.SensitiveKeys = keys }(audit.SensitiveKeys)
+	os.WriteFile(".env-audit.yaml", []byte("ignore: [DEBUG]\n"), 0644)
+	wd, _ := os.Getwd()
+
+	cfg, err := ParseArgs([]string{"--file", ".env"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cliArgs := *cfg
+	cfg.cliArgs = &cliArgs
+	cfg.configPath = filepath.Join(wd, ".env-audit.yaml")
+
+	os.WriteFile(".env-audit.yaml", []byte("ignore: [TRACE]\nsensitive_patterns: [PASSPHRASE]\n"), 0644)
+	reloaded, redactor, err := cfg.reloadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(reloaded.Ignore, []string{"TRACE"}) {
+		t.Errorf("expected the edited config, got %v", reloaded.Ignore)
+	}
+	if !redactor.Sensitive("DB_PASSPHRASE") {
+		t.Error("expected the edited sensitive patterns to apply")
+	}
+	if reloaded.configPath != cfg.configPath {
+		t.Errorf("expected the config path kept, got %q", reloaded.configPath)
+	}
+
+	os.WriteFile(".env-audit.yaml", []byte("ignre: [TRACE]\n"), 0644)
+	if _, _, err := cfg.reloadConfig(); err == nil || !strings.Contains(err.Error(), "unknown key") {
+		t.Errorf("expected the broken config reported, got %v", err)
+	}
+}
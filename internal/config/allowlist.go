@@ -0,0 +1,53 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// AllowFileName is the allowlist file read from the working directory: one
+// SHA-256 digest of a known-safe value per line, as printed by sha256sum
+const AllowFileName = ".env-audit.allow"
+
+// IsSHA256 reports whether s is a hex-encoded SHA-256 digest
+func IsSHA256(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadAllowFile reads the digests listed in an allowlist file. Blank lines
+// and # comments are skipped, and anything after the digest, such as the
+// file name sha256sum appends, is ignored. Digests are lowercased.
+func LoadAllowFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var hashes []string
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if !IsSHA256(fields[0]) {
+			return nil, fmt.Errorf("%s:%d: %q is not a SHA-256 digest", path, lineNum, fields[0])
+		}
+		hashes = append(hashes, strings.ToLower(fields[0]))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadAllowFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), AllowFileName)
+	os.WriteFile(path, []byte("# build hashes\n"+
+		"BA7816BF8F01CFEA414140DE5DAE2223B00361A396177A9CB410FF61F20015AD  -\n"+
+		"\n"+
+		"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08 public signing key\n"), 0644)
+
+	hashes, err := LoadAllowFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+	}
+	if !reflect.DeepEqual(hashes, want) {
+		t.Errorf("expected %v, got %v", want, hashes)
+	}
+
+	os.WriteFile(path, []byte("# values\nhunter2\n"), 0644)
+	if _, err := LoadAllowFile(path); err == nil || !strings.Contains(err.Error(), ":2:") {
+		t.Errorf("expected an error naming line 2, got %v", err)
+	}
+}
+
+func TestLoadFile_AllowHashes(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ".env-audit.yaml")
+
+	os.WriteFile(configPath, []byte("allow_hashes:\n  - 9F86D081884C7D659A2FEAA0C55AD015A3BF4F1B2B0B822CD15D6C15B0F00A08\n"), 0644)
+	cfg, err := LoadFile(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.AllowHashes) != 1 || cfg.AllowHashes[0] != "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08" {
+		t.Errorf("unexpected allow hashes: %v", cfg.AllowHashes)
+	}
+
+	os.WriteFile(configPath, []byte("allow_hashes:\n  - abc123\n"), 0644)
+	if _, err := LoadFile(configPath); err == nil || !strings.Contains(err.Error(), "allow_hashes[0]") {
+		t.Errorf("expected an invalid digest error, got %v", err)
+	}
+}
@@ -1,8 +1,16 @@
 package config
 
 import (
+	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"env-audit/internal/audit"
 
 	"gopkg.in/yaml.v3"
 )
@@ -17,8 +25,262 @@ type FileConfig struct {
 	Quiet      bool     `yaml:"quiet"`
 	JSON       bool     `yaml:"json"`
 	GitHub     bool     `yaml:"github"`
+	GitLab     bool     `yaml:"gitlab"`
+	Buildkite  bool     `yaml:"buildkite"`
+	CircleCI   bool     `yaml:"circleci"`
 	Ignore     []string `yaml:"ignore"`
+	AllowEmpty []string `yaml:"allow_empty"`
 	NoColor    bool     `yaml:"no_color"`
+	FailFast   bool     `yaml:"fail_fast"`
+	CheckOrder bool     `yaml:"check_order"`
+	WarnParse  bool     `yaml:"warn_parse"`
+
+	CheckSources bool `yaml:"check_sources"` // compare values with the stores their "# source:" comments name
+
+	RequireAllExample bool      `yaml:"require_all_example"` // every example key is required
+	Patterns          string    `yaml:"patterns"`            // path or URL of a leak pattern pack
+	SensitivePatterns []string  `yaml:"sensitive_patterns"`  // extra key name fragments marking a key sensitive
+	SensitiveExclude  []string  `yaml:"sensitive_exclude"`   // keys never treated as sensitive, e.g. AUTHOR
+	Redaction         Redaction `yaml:"redaction"`
+
+	// RequiredFromExample is an alias of RequireAllExample
+	RequiredFromExample bool `yaml:"required_from_example"`
+
+	Descriptions map[string]string  `yaml:"descriptions"`  // human documentation per variable
+	Defaults     map[string]Default `yaml:"defaults"`      // fallback values for absent variables
+	Rules        map[string]string  `yaml:"rules"`         // regular expression each key's value must match
+	PublicValues []PublicValue      `yaml:"public_values"` // values safe to expose, exempt from leak findings
+	AllowHashes  []string           `yaml:"allow_hashes"`  // SHA-256 digests of known-safe values exempt from leak findings
+
+	QuarantineRecipients []string `yaml:"quarantine_recipients"` // age recipients of the files --quarantine writes
+
+	RotateAfter string            `yaml:"rotate_after"` // credential rotation window, e.g. 90d
+	LastRotated map[string]string `yaml:"last_rotated"` // YYYY-MM-DD of each key's last rotation
+
+	Limits   Limits `yaml:"limits"`
+	Platform string `yaml:"platform"`  // deployment platform whose env constraints apply
+	DocsFile string `yaml:"docs_file"` // Markdown docs whose variables must match the example, default ENVIRONMENT.md
+
+	FailOn   string            `yaml:"fail_on"`  // lowest severity that fails the run, default error
+	Severity map[string]string `yaml:"severity"` // severity of an issue type, or ignore, e.g. duplicate: error
+	Only     []string          `yaml:"only"`     // issue types to report, all others are dropped
+	Exclude  []string          `yaml:"exclude"`  // issue types to drop from the report
+
+	HTTP HTTP `yaml:"http"`
+
+	Preset  string `yaml:"preset"`  // built-in name, path or URL of a policy preset the file builds on
+	Extends string `yaml:"extends"` // path or URL of a config file this one is layered over
+
+	Projects []Project `yaml:"projects"` // monorepo projects audited together by --all-projects
+}
+
+// Project is one application of a monorepo with its own env file and
+// policy. Paths are relative to the working directory, like file and example.
+type Project struct {
+	Name     string   `yaml:"name"`
+	File     string   `yaml:"file"`
+	Example  string   `yaml:"example"`
+	Required []string `yaml:"required"`
+	Strict   bool     `yaml:"strict"`
+}
+
+// Limits caps the size of each env file, e.g. to fit a runtime's limit on
+// the environment it accepts
+type Limits struct {
+	MaxVars        int    `yaml:"max_vars"`         // variables per file
+	MaxValueLength int    `yaml:"max_value_length"` // bytes in a single value
+	MaxTotalSize   string `yaml:"max_total_size"`   // size of all keys and values, e.g. 4KB
+}
+
+// HTTP configures the client used by remote integrations, such as pattern
+// packs and remote-diff, for networks that need a proxy or a private CA
+type HTTP struct {
+	Proxy     string  `yaml:"proxy"`      // proxy URL, default from HTTPS_PROXY and HTTP_PROXY
+	CABundle  string  `yaml:"ca_bundle"`  // PEM file of extra trusted CAs
+	Retries   *int    `yaml:"retries"`    // retries of a failed request, default 2
+	RateLimit float64 `yaml:"rate_limit"` // requests per second, 0 for no limit
+}
+
+// DateLayout is the format of dates in config files and env comments
+const DateLayout = "2006-01-02"
+
+// ParseWindow parses a duration written in days ("90d"), weeks ("12w") or
+// any unit accepted by time.ParseDuration
+func ParseWindow(s string) (time.Duration, error) {
+	if n := len(s); n > 1 && (s[n-1] == 'd' || s[n-1] == 'w') {
+		count, err := strconv.Atoi(s[:n-1])
+		if err != nil || count < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		days := count
+		if s[n-1] == 'w' {
+			days *= 7
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+// ParseSize parses a size in bytes, written as a plain number or with a B,
+// KB or MB suffix. KB and MB are 1024 and 1024² bytes, as runtime limits
+// such as Lambda's 4 KB are counted.
+func ParseSize(s string) (int, error) {
+	units := []struct {
+		suffix string
+		scale  int
+	}{{"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}}
+	number, scale := strings.TrimSpace(s), 1
+	for _, unit := range units {
+		if strings.HasSuffix(strings.ToUpper(number), unit.suffix) {
+			number, scale = strings.TrimSpace(number[:len(number)-len(unit.suffix)]), unit.scale
+			break
+		}
+	}
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n * scale, nil
+}
+
+// validate reports values that can't be used, so mistakes surface at load time
+func (c *FileConfig) validate() error {
+	if c.RotateAfter != "" {
+		if _, err := ParseWindow(c.RotateAfter); err != nil {
+			return fmt.Errorf("rotate_after: %w", err)
+		}
+	}
+	for i, public := range c.PublicValues {
+		if public.Value == "" {
+			return fmt.Errorf("public_values[%d]: value is required", i)
+		}
+		if strings.TrimSpace(public.Reason) == "" {
+			return fmt.Errorf("public_values[%d]: reason is required, document why %q is public", i, public.Value)
+		}
+	}
+	for i, hash := range c.AllowHashes {
+		if !IsSHA256(hash) {
+			return fmt.Errorf("allow_hashes[%d]: %q is not a SHA-256 digest", i, hash)
+		}
+		c.AllowHashes[i] = strings.ToLower(hash)
+	}
+	if c.Limits.MaxVars < 0 {
+		return fmt.Errorf("limits: max_vars must not be negative")
+	}
+	if c.Limits.MaxValueLength < 0 {
+		return fmt.Errorf("limits: max_value_length must not be negative")
+	}
+	if c.Limits.MaxTotalSize != "" {
+		if _, err := ParseSize(c.Limits.MaxTotalSize); err != nil {
+			return fmt.Errorf("limits: max_total_size: %w", err)
+		}
+	}
+	if c.FailOn != "" {
+		if !audit.IsSeverity(c.FailOn) {
+			return fmt.Errorf("fail_on: invalid severity %q (expected error, warning or info)", c.FailOn)
+		}
+		if c.Strict {
+			return fmt.Errorf("fail_on: cannot be combined with strict (strict is fail_on: warning)")
+		}
+	}
+	for name, level := range c.Severity {
+		if _, ok := audit.ParseIssueType(name); !ok {
+			return fmt.Errorf("severity: unknown issue type %q", name)
+		}
+		if !audit.IsSeverity(level) && level != audit.SeverityIgnore {
+			return fmt.Errorf("severity: %s: invalid severity %q (expected error, warning, info or ignore)", name, level)
+		}
+	}
+	if c.HTTP.Retries != nil && *c.HTTP.Retries < 0 {
+		return fmt.Errorf("http: retries must not be negative")
+	}
+	if c.HTTP.RateLimit < 0 {
+		return fmt.Errorf("http: rate_limit must not be negative")
+	}
+	if c.HTTP.Proxy != "" {
+		if u, err := url.Parse(c.HTTP.Proxy); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("http: invalid proxy URL %q", c.HTTP.Proxy)
+		}
+	}
+	for _, name := range c.Only {
+		if _, ok := audit.ParseIssueType(name); !ok {
+			return fmt.Errorf("only: unknown issue type %q", name)
+		}
+	}
+	for _, name := range c.Exclude {
+		if _, ok := audit.ParseIssueType(name); !ok {
+			return fmt.Errorf("exclude: unknown issue type %q", name)
+		}
+	}
+	if c.Platform != "" {
+		if _, ok := audit.PlatformByName(c.Platform); !ok {
+			return fmt.Errorf("platform: unknown platform %q (expected %s)", c.Platform, strings.Join(audit.PlatformNames(), ", "))
+		}
+	}
+	for key, pattern := range c.Rules {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("rules: %s: %w", key, err)
+		}
+	}
+	for key, date := range c.LastRotated {
+		if _, err := time.Parse(DateLayout, date); err != nil {
+			return fmt.Errorf("last_rotated: %s: invalid date %q (expected YYYY-MM-DD)", key, date)
+		}
+	}
+	for i, recipient := range c.QuarantineRecipients {
+		if !IsAgeRecipient(recipient) {
+			return fmt.Errorf("quarantine_recipients[%d]: %q is not an age recipient (age1... or an SSH public key)", i, recipient)
+		}
+	}
+	names := make(map[string]bool, len(c.Projects))
+	for i, project := range c.Projects {
+		if project.Name == "" {
+			return fmt.Errorf("projects[%d]: name is required", i)
+		}
+		if names[project.Name] {
+			return fmt.Errorf("projects[%d]: duplicate project name %q", i, project.Name)
+		}
+		names[project.Name] = true
+		if project.File == "" {
+			return fmt.Errorf("projects: %s: file is required", project.Name)
+		}
+	}
+	return nil
+}
+
+// Default is a fallback value for a variable. It is written either as a plain
+// value (`LOG_LEVEL: info`) or as a mapping with value and unsafe fields.
+type Default struct {
+	Value  string `yaml:"value"`
+	Unsafe bool   `yaml:"unsafe"` // the default must not be used in production
+}
+
+// UnmarshalYAML accepts both the scalar and the mapping form of a default
+func (d *Default) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		d.Value = node.Value
+		return nil
+	}
+	type plain Default
+	return node.Decode((*plain)(d))
+}
+
+// PublicValue declares a value as known-public, e.g. a publishable key.
+// Value may use * as a wildcard (pk_live_*); Reason documents why it is safe.
+type PublicValue struct {
+	Value  string `yaml:"value"`
+	Reason string `yaml:"reason"`
+}
+
+// Redaction configures how secret values are masked in output
+type Redaction struct {
+	Mode        string `yaml:"mode"`        // full (default) or partial
+	Placeholder string `yaml:"placeholder"` // defaults to [REDACTED]
+	Reveal      int    `yaml:"reveal"`      // prefix characters kept in partial mode
 }
 
 // configFileNames lists the supported config file names in priority order
@@ -27,32 +289,137 @@ var configFileNames = []string{
 	".env-audit.yml",
 }
 
+// IsAgeRecipient reports whether s looks like a public key age encrypts to:
+// a native age1... recipient or an ssh-ed25519 or ssh-rsa public key
+func IsAgeRecipient(s string) bool {
+	return strings.HasPrefix(s, "age1") || strings.HasPrefix(s, "ssh-ed25519 ") || strings.HasPrefix(s, "ssh-rsa ")
+}
+
 // LoadFile loads configuration from a YAML file
 func LoadFile(path string) (*FileConfig, error) {
+	return loadFile(path, loadOptions{preset: true, extends: true})
+}
+
+// LoadFileWithoutPreset loads a config file as written, without the preset
+// it names or the config file it extends, to tell the file's own settings
+// from the ones it builds on
+func LoadFileWithoutPreset(path string) (*FileConfig, error) {
+	return loadFile(path, loadOptions{})
+}
+
+// LoadFileWithoutExtends loads a config file with its preset but not the
+// config file it extends
+func LoadFileWithoutExtends(path string) (*FileConfig, error) {
+	return loadFile(path, loadOptions{preset: true})
+}
+
+// loadOptions selects what a config file is layered over when loaded
+type loadOptions struct {
+	preset  bool // the preset it names
+	extends bool // the config file it extends
+}
+
+func loadFile(path string, opts loadOptions) (*FileConfig, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
+	return parseFile(path, data, opts, nil)
+}
 
+// parseFile decodes a config file read from source, a path or URL. chain
+// holds the files extending it, outermost first, to detect a cycle.
+func parseFile(source string, data []byte, opts loadOptions, chain []string) (*FileConfig, error) {
 	var cfg FileConfig
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, err
+	if err := decodeStrict(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	if cfg.Preset != "" && opts.preset {
+		presetSource, dir := cfg.Preset, filepath.Dir(source)
+		if isURL(source) {
+			// A preset file of a remote config is relative to its URL
+			dir = ""
+			if strings.ContainsAny(presetSource, `/\.`) {
+				presetSource = relativeTo(source, presetSource)
+			}
+		}
+		preset, err := LoadPreset(presetSource, dir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: preset %s: %w", source, cfg.Preset, err)
+		}
+		cfg.applyPreset(preset)
+	}
+	if cfg.Extends != "" && opts.extends {
+		base, err := loadBase(cfg.Extends, source, chain)
+		if err != nil {
+			return nil, fmt.Errorf("%s: extends %s: %w", source, cfg.Extends, err)
+		}
+		cfg.applyBase(base)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
 	}
 
 	return &cfg, nil
 }
 
-// FindConfigFile looks for a config file in the current directory
-// Returns the path if found, empty string if not found
+// FindConfigFile looks for a config file in the current directory, then in
+// each parent up to the repository root (the first directory with a .git
+// entry) or the filesystem root. A config in the current directory is
+// returned by its name, one in a parent by its absolute path.
+// Returns empty string if not found
 func FindConfigFile() string {
 	for _, name := range configFileNames {
 		if _, err := os.Stat(name); err == nil {
 			return name
 		}
 	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	for !isRepoRoot(dir) {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+		if path := FindConfigFileInDir(dir); path != "" {
+			return path
+		}
+	}
 	return ""
 }
 
+// isRepoRoot reports whether dir is the top of a git work tree. .git is a
+// file in worktrees and submodules, so any entry counts.
+func isRepoRoot(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// ResolvePaths makes the relative file paths of a config loaded from dir
+// relative to the working directory instead, so a config found in a parent
+// directory names the same files wherever env-audit runs
+func (c *FileConfig) ResolvePaths(dir string) {
+	resolve := func(p *string) {
+		if *p != "" && !filepath.IsAbs(*p) {
+			*p = filepath.Join(dir, *p)
+		}
+	}
+	resolve(&c.File)
+	resolve(&c.Example)
+	resolve(&c.DocsFile)
+	resolve(&c.HTTP.CABundle)
+	if !isURL(c.Patterns) {
+		resolve(&c.Patterns)
+	}
+	for i := range c.Projects {
+		resolve(&c.Projects[i].File)
+		resolve(&c.Projects[i].Example)
+	}
+}
+
 // FindConfigFileInDir looks for a config file in the specified directory
 func FindConfigFileInDir(dir string) string {
 	for _, name := range configFileNames {
@@ -64,3 +431,21 @@ func FindConfigFileInDir(dir string) string {
 	return ""
 }
 
+// FindNearestConfigFile looks for a config file in dir and each of its parents,
+// returning the absolute path of the closest one, or empty string if none exists
+func FindNearestConfigFile(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		if path := FindConfigFileInDir(dir); path != "" {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
@@ -275,6 +276,71 @@ func TestFindConfigFile_PriorityYamlOverYml(t *testing.T) {
 	}
 }
 
+func TestFindConfigFile_WalksUpToRepoRoot(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo := filepath.Join(tmpDir, "repo")
+	nested := filepath.Join(repo, "services", "api")
+	os.MkdirAll(nested, 0755)
+	os.MkdirAll(filepath.Join(repo, ".git"), 0755)
+	repoConfig := filepath.Join(repo, ".env-audit.yml")
+	if err := os.WriteFile(repoConfig, []byte("strict: true"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Above the repository root, so never found from inside it
+	if err := os.WriteFile(filepath.Join(tmpDir, ".env-audit.yaml"), []byte("strict: true"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(nested)
+	defer os.Chdir(oldWd)
+
+	found := FindConfigFile()
+	if resolved, err := filepath.EvalSymlinks(found); err != nil || resolved != mustEvalSymlinks(t, repoConfig) {
+		t.Errorf("expected %q, got %q", repoConfig, found)
+	}
+
+	os.Remove(repoConfig)
+	if found := FindConfigFile(); found != "" {
+		t.Errorf("expected the search to stop at the repository root, got %q", found)
+	}
+}
+
+func mustEvalSymlinks(t *testing.T, path string) string {
+	t.Helper()
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resolved
+}
+
+func TestResolvePaths(t *testing.T) {
+	cfg := &FileConfig{
+		File:     ".env",
+		Example:  "/abs/.env.example",
+		Patterns: "https://example.com/patterns.yaml",
+		Projects: []Project{{Name: "api", File: "services/api/.env"}},
+	}
+	cfg.ResolvePaths("..")
+
+	if cfg.File != filepath.Join("..", ".env") {
+		t.Errorf("expected file relative to the config, got %q", cfg.File)
+	}
+	if cfg.Example != "/abs/.env.example" {
+		t.Errorf("expected absolute example unchanged, got %q", cfg.Example)
+	}
+	if cfg.Patterns != "https://example.com/patterns.yaml" {
+		t.Errorf("expected pattern URL unchanged, got %q", cfg.Patterns)
+	}
+	if cfg.Projects[0].File != filepath.Join("..", "services", "api", ".env") {
+		t.Errorf("expected project file relative to the config, got %q", cfg.Projects[0].File)
+	}
+	if cfg.DocsFile != "" {
+		t.Errorf("expected unset paths to stay unset, got %q", cfg.DocsFile)
+	}
+}
+
 func TestFindConfigFileInDir(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -292,3 +358,329 @@ func TestFindConfigFileInDir(t *testing.T) {
 	}
 }
 
+
+func TestLoadFile_Descriptions(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".env-audit.yaml")
+	content := "descriptions:\n  DATABASE_URL: Primary Postgres connection string\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Descriptions["DATABASE_URL"] != "Primary Postgres connection string" {
+		t.Errorf("expected description, got %v", cfg.Descriptions)
+	}
+}
+
+func TestLoadFile_Defaults(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".env-audit.yaml")
+	content := "defaults:\n  LOG_LEVEL: info\n  SECRET_KEY:\n    value: changeme\n    unsafe: true\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Defaults["LOG_LEVEL"] != (Default{Value: "info"}) {
+		t.Errorf("expected scalar default, got %v", cfg.Defaults["LOG_LEVEL"])
+	}
+	if cfg.Defaults["SECRET_KEY"] != (Default{Value: "changeme", Unsafe: true}) {
+		t.Errorf("expected mapping default, got %v", cfg.Defaults["SECRET_KEY"])
+	}
+}
+
+func TestFindNearestConfigFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	nested := filepath.Join(tmpDir, "services", "api", "config")
+	os.MkdirAll(nested, 0755)
+	apiConfig := filepath.Join(tmpDir, "services", "api", ".env-audit.yml")
+	if err := os.WriteFile(apiConfig, []byte("strict: true"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if found := FindNearestConfigFile(nested); found != apiConfig {
+		t.Errorf("expected %q, got %q", apiConfig, found)
+	}
+	if found := FindNearestConfigFile(filepath.Join(tmpDir, "services")); found != "" && strings.HasPrefix(found, tmpDir) {
+		t.Errorf("expected no config above the api service, got %q", found)
+	}
+}
+
+func TestParseWindow(t *testing.T) {
+	tests := map[string]time.Duration{
+		"90d":   90 * 24 * time.Hour,
+		"2w":    14 * 24 * time.Hour,
+		"36h":   36 * time.Hour,
+		"1h30m": 90 * time.Minute,
+	}
+	for input, want := range tests {
+		got, err := ParseWindow(input)
+		if err != nil || got != want {
+			t.Errorf("ParseWindow(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "d", "ninety days", "-3d"} {
+		if _, err := ParseWindow(input); err == nil {
+			t.Errorf("ParseWindow(%q): expected error", input)
+		}
+	}
+}
+
+func TestLoadFile_RotationValidation(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".env-audit.yaml")
+
+	os.WriteFile(configPath, []byte("rotate_after: 90d\nlast_rotated:\n  STRIPE_KEY: 2026-06-01\n"), 0644)
+	cfg, err := LoadFile(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.RotateAfter != "90d" || cfg.LastRotated["STRIPE_KEY"] != "2026-06-01" {
+		t.Errorf("unexpected rotation config: %q %v", cfg.RotateAfter, cfg.LastRotated)
+	}
+
+	os.WriteFile(configPath, []byte("rotate_after: soon\n"), 0644)
+	if _, err := LoadFile(configPath); err == nil || !strings.Contains(err.Error(), "rotate_after") {
+		t.Errorf("expected rotate_after error, got %v", err)
+	}
+
+	os.WriteFile(configPath, []byte("last_rotated:\n  STRIPE_KEY: June\n"), 0644)
+	if _, err := LoadFile(configPath); err == nil || !strings.Contains(err.Error(), "STRIPE_KEY") {
+		t.Errorf("expected last_rotated error, got %v", err)
+	}
+}
+
+func TestLoadFile_Rules(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".env-audit.yaml")
+
+	os.WriteFile(configPath, []byte("rules: {DATABASE_URL: '^postgres://'}\n"), 0644)
+	cfg, err := LoadFile(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Rules["DATABASE_URL"] != "^postgres://" {
+		t.Errorf("unexpected rules: %v", cfg.Rules)
+	}
+
+	os.WriteFile(configPath, []byte("rules:\n  PORT: '[0-9'\n"), 0644)
+	if _, err := LoadFile(configPath); err == nil || !strings.Contains(err.Error(), "rules: PORT") {
+		t.Errorf("expected rules error, got %v", err)
+	}
+}
+
+func TestLoadFile_SensitiveKeys(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".env-audit.yaml")
+	os.WriteFile(configPath, []byte("sensitive_patterns: [DSN]\nsensitive_exclude: [AUTHOR, OAUTH_CALLBACK_URL]\n"), 0644)
+
+	cfg, err := LoadFile(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.SensitivePatterns) != 1 || len(cfg.SensitiveExclude) != 2 {
+		t.Errorf("unexpected sensitive settings: %v %v", cfg.SensitivePatterns, cfg.SensitiveExclude)
+	}
+}
+
+func TestLoadFile_PublicValues(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".env-audit.yaml")
+
+	os.WriteFile(configPath, []byte("public_values:\n  - value: pk_live_*\n    reason: Stripe publishable keys ship to browsers\n"), 0644)
+	cfg, err := LoadFile(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.PublicValues) != 1 || cfg.PublicValues[0].Value != "pk_live_*" {
+		t.Errorf("unexpected public values: %+v", cfg.PublicValues)
+	}
+
+	os.WriteFile(configPath, []byte("public_values:\n  - value: pk_live_*\n"), 0644)
+	if _, err := LoadFile(configPath); err == nil || !strings.Contains(err.Error(), "reason is required") {
+		t.Errorf("expected a missing reason error, got %v", err)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := map[string]int{
+		"4096": 4096,
+		"512B": 512,
+		"4KB":  4096,
+		"4 kb": 4096,
+		"1MB":  1 << 20,
+		"0":    0,
+	}
+	for input, want := range tests {
+		got, err := ParseSize(input)
+		if err != nil || got != want {
+			t.Errorf("ParseSize(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "KB", "four KB", "-1KB", "4GB"} {
+		if _, err := ParseSize(input); err == nil {
+			t.Errorf("ParseSize(%q): expected error", input)
+		}
+	}
+}
+
+func TestLoadFile_SeverityPolicy(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".env-audit.yaml")
+
+	os.WriteFile(configPath, []byte("fail_on: warning\nseverity:\n  duplicate: error\n  sensitive: ignore\n"), 0644)
+	cfg, err := LoadFile(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.FailOn != "warning" || cfg.Severity["duplicate"] != "error" || cfg.Severity["sensitive"] != "ignore" {
+		t.Errorf("unexpected severity policy: %q %v", cfg.FailOn, cfg.Severity)
+	}
+
+	invalid := map[string]string{
+		"fail_on: ignore\n":                "fail_on",
+		"strict: true\nfail_on: info\n":    "strict",
+		"severity:\n  duplicates: error\n": "unknown issue type",
+		"severity:\n  duplicate: fatal\n":  "invalid severity",
+		"only: [empty, blank]\n":           "only: unknown issue type",
+		"exclude: [secrets]\n":             "exclude: unknown issue type",
+	}
+	for content, want := range invalid {
+		os.WriteFile(configPath, []byte(content), 0644)
+		if _, err := LoadFile(configPath); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: expected %q error, got %v", content, want, err)
+		}
+	}
+}
+
+func TestLoadFile_Platform(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".env-audit.yaml")
+
+	os.WriteFile(configPath, []byte("platform: cloudrun\n"), 0644)
+	cfg, err := LoadFile(configPath)
+	if err != nil || cfg.Platform != "cloudrun" {
+		t.Fatalf("unexpected platform: %v, %v", cfg, err)
+	}
+
+	os.WriteFile(configPath, []byte("platform: fly\n"), 0644)
+	if _, err := LoadFile(configPath); err == nil || !strings.Contains(err.Error(), "unknown platform") {
+		t.Errorf("expected unknown platform error, got %v", err)
+	}
+}
+
+func TestLoadFile_Limits(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".env-audit.yaml")
+
+	os.WriteFile(configPath, []byte("limits:\n  max_vars: 50\n  max_value_length: 1024\n  max_total_size: 4KB\n"), 0644)
+	cfg, err := LoadFile(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Limits != (Limits{MaxVars: 50, MaxValueLength: 1024, MaxTotalSize: "4KB"}) {
+		t.Errorf("unexpected limits: %+v", cfg.Limits)
+	}
+
+	os.WriteFile(configPath, []byte("limits:\n  max_total_size: big\n"), 0644)
+	if _, err := LoadFile(configPath); err == nil || !strings.Contains(err.Error(), "max_total_size") {
+		t.Errorf("expected max_total_size error, got %v", err)
+	}
+
+	os.WriteFile(configPath, []byte("limits:\n  max_vars: -1\n"), 0644)
+	if _, err := LoadFile(configPath); err == nil || !strings.Contains(err.Error(), "max_vars") {
+		t.Errorf("expected max_vars error, got %v", err)
+	}
+}
+
+func TestLoadFile_HTTP(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".env-audit.yaml")
+
+	os.WriteFile(configPath, []byte("http:\n  proxy: http://proxy.corp:3128\n  ca_bundle: /etc/ssl/corp.pem\n  retries: 0\n  rate_limit: 2.5\n"), 0644)
+	cfg, err := LoadFile(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.HTTP.Proxy != "http://proxy.corp:3128" || cfg.HTTP.CABundle != "/etc/ssl/corp.pem" || cfg.HTTP.Retries == nil || *cfg.HTTP.Retries != 0 || cfg.HTTP.RateLimit != 2.5 {
+		t.Errorf("unexpected http settings: %+v", cfg.HTTP)
+	}
+
+	invalid := map[string]string{
+		"http:\n  proxy: proxy.corp:3128\n": "invalid proxy URL",
+		"http:\n  retries: -1\n":            "retries",
+		"http:\n  rate_limit: -1\n":         "rate_limit",
+	}
+	for content, want := range invalid {
+		os.WriteFile(configPath, []byte(content), 0644)
+		if _, err := LoadFile(configPath); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: expected %q error, got %v", content, want, err)
+		}
+	}
+}
+
+func TestLoadFile_Projects(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".env-audit.yaml")
+
+	content := `projects:
+  - name: api
+    file: services/api/.env
+    example: services/api/.env.example
+    required: [DATABASE_URL]
+    strict: true
+  - name: web
+    file: apps/web/.env
+`
+	os.WriteFile(configPath, []byte(content), 0644)
+	cfg, err := LoadFile(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Projects) != 2 {
+		t.Fatalf("expected 2 projects, got %+v", cfg.Projects)
+	}
+	api := cfg.Projects[0]
+	if api.Name != "api" || api.File != "services/api/.env" || api.Example != "services/api/.env.example" ||
+		len(api.Required) != 1 || api.Required[0] != "DATABASE_URL" || !api.Strict {
+		t.Errorf("unexpected api project: %+v", api)
+	}
+	if cfg.Projects[1].Strict || cfg.Projects[1].Example != "" {
+		t.Errorf("unexpected web project: %+v", cfg.Projects[1])
+	}
+
+	invalid := map[string]string{
+		"projects:\n  - file: .env\n": "name is required",
+		"projects:\n  - name: api\n":  "file is required",
+		"projects:\n  - {name: api, file: a/.env}\n  - {name: api, file: b/.env}\n": "duplicate project name",
+	}
+	for content, want := range invalid {
+		os.WriteFile(configPath, []byte(content), 0644)
+		if _, err := LoadFile(configPath); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: expected %q error, got %v", content, want, err)
+		}
+	}
+}
+
+func TestLoadFile_QuarantineRecipients(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".env-audit.yaml")
+
+	os.WriteFile(configPath, []byte("quarantine_recipients:\n  - age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p\n  - ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIHsKLqeplhpW+uObz5dvMgjz1OxfM/XXUB+VHtZ6isGN alice\n"), 0644)
+	cfg, err := LoadFile(configPath)
+	if err != nil || len(cfg.QuarantineRecipients) != 2 {
+		t.Fatalf("unexpected recipients: %v, %v", cfg, err)
+	}
+
+	os.WriteFile(configPath, []byte("quarantine_recipients: [alice@example.com]\n"), 0644)
+	if _, err := LoadFile(configPath); err == nil || !strings.Contains(err.Error(), "not an age recipient") {
+		t.Errorf("expected a recipient error, got %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// isURL reports whether source is an http(s) URL rather than a path
+func isURL(source string) bool {
+	return strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://")
+}
+
+// relativeTo resolves ref, named in the config file read from source,
+// against the file's directory or URL
+func relativeTo(source, ref string) string {
+	if isURL(ref) || filepath.IsAbs(ref) {
+		return ref
+	}
+	if isURL(source) {
+		base, err := url.Parse(source)
+		if err != nil {
+			return ref
+		}
+		target, err := url.Parse(filepath.ToSlash(ref))
+		if err != nil {
+			return ref
+		}
+		return base.ResolveReference(target).String()
+	}
+	return filepath.Join(filepath.Dir(source), ref)
+}
+
+// loadBase loads the config file that the one read from source extends,
+// with its own preset and base. chain holds the files extending source.
+func loadBase(ref, source string, chain []string) (*FileConfig, error) {
+	chain = append(chain, canonicalSource(source))
+	target := relativeTo(source, ref)
+	for _, seen := range chain {
+		if seen == canonicalSource(target) {
+			return nil, fmt.Errorf("cycle: %s", strings.Join(append(chain, canonicalSource(target)), " -> "))
+		}
+	}
+
+	var data []byte
+	var err error
+	if isURL(target) {
+		data, err = fetchURL(target)
+	} else {
+		data, err = os.ReadFile(target)
+	}
+	if err != nil {
+		return nil, err
+	}
+	base, err := parseFile(target, data, loadOptions{preset: true, extends: true}, chain)
+	if err != nil {
+		return nil, err
+	}
+	// The base's paths are relative to it, the file's to the file
+	if !isURL(target) {
+		base.ResolvePaths(filepath.Dir(ref))
+	}
+	return base, nil
+}
+
+// canonicalSource is the absolute form of a config path, or the URL, so
+// the same file named two ways is recognised in a cycle
+func canonicalSource(source string) string {
+	if isURL(source) {
+		return source
+	}
+	if abs, err := filepath.Abs(source); err == nil {
+		return abs
+	}
+	return source
+}
+
+// applyBase layers the config file over base, the config file it extends.
+// The file's settings win; lists such as ignore and sensitive_patterns are
+// combined, maps such as severity and rules are combined per entry with the
+// file winning, and checks either enables stay enabled.
+func (c *FileConfig) applyBase(base *FileConfig) {
+	// strict and fail_on are one threshold, so either in the file wins
+	if !c.Strict && c.FailOn == "" {
+		c.Strict = base.Strict
+		c.FailOn = base.FailOn
+	}
+	for _, field := range []struct{ own, base *bool }{
+		{&c.CheckLeaks, &base.CheckLeaks},
+		{&c.Quiet, &base.Quiet},
+		{&c.JSON, &base.JSON},
+		{&c.GitHub, &base.GitHub},
+		{&c.GitLab, &base.GitLab},
+		{&c.Buildkite, &base.Buildkite},
+		{&c.CircleCI, &base.CircleCI},
+		{&c.NoColor, &base.NoColor},
+		{&c.FailFast, &base.FailFast},
+		{&c.CheckOrder, &base.CheckOrder},
+		{&c.WarnParse, &base.WarnParse},
+		{&c.CheckSources, &base.CheckSources},
+		{&c.RequireAllExample, &base.RequireAllExample},
+		{&c.RequiredFromExample, &base.RequiredFromExample},
+	} {
+		*field.own = *field.own || *field.base
+	}
+	for _, field := range []struct{ own, base *string }{
+		{&c.File, &base.File},
+		{&c.Example, &base.Example},
+		{&c.Patterns, &base.Patterns},
+		{&c.Redaction.Mode, &base.Redaction.Mode},
+		{&c.Redaction.Placeholder, &base.Redaction.Placeholder},
+		{&c.RotateAfter, &base.RotateAfter},
+		{&c.Limits.MaxTotalSize, &base.Limits.MaxTotalSize},
+		{&c.Platform, &base.Platform},
+		{&c.DocsFile, &base.DocsFile},
+		{&c.HTTP.Proxy, &base.HTTP.Proxy},
+		{&c.HTTP.CABundle, &base.HTTP.CABundle},
+	} {
+		if *field.own == "" {
+			*field.own = *field.base
+		}
+	}
+	for _, field := range []struct{ own, base *int }{
+		{&c.Redaction.Reveal, &base.Redaction.Reveal},
+		{&c.Limits.MaxVars, &base.Limits.MaxVars},
+		{&c.Limits.MaxValueLength, &base.Limits.MaxValueLength},
+	} {
+		if *field.own == 0 {
+			*field.own = *field.base
+		}
+	}
+	if c.HTTP.Retries == nil {
+		c.HTTP.Retries = base.HTTP.Retries
+	}
+	if c.HTTP.RateLimit == 0 {
+		c.HTTP.RateLimit = base.HTTP.RateLimit
+	}
+
+	c.Required = combine(base.Required, c.Required)
+	c.Ignore = combine(base.Ignore, c.Ignore)
+	c.AllowEmpty = combine(base.AllowEmpty, c.AllowEmpty)
+	c.SensitivePatterns = combine(base.SensitivePatterns, c.SensitivePatterns)
+	c.SensitiveExclude = combine(base.SensitiveExclude, c.SensitiveExclude)
+	c.PublicValues = combine(base.PublicValues, c.PublicValues)
+	c.AllowHashes = combine(base.AllowHashes, c.AllowHashes)
+	c.QuarantineRecipients = combine(base.QuarantineRecipients, c.QuarantineRecipients)
+
+	c.Descriptions = mergeMaps(base.Descriptions, c.Descriptions)
+	c.Defaults = mergeMaps(base.Defaults, c.Defaults)
+	c.Rules = mergeMaps(base.Rules, c.Rules)
+	c.Severity = mergeMaps(base.Severity, c.Severity)
+	c.LastRotated = mergeMaps(base.LastRotated, c.LastRotated)
+
+	// Filters and projects describe one repository, so the file's replace the base's
+	if c.Only == nil {
+		c.Only = base.Only
+	}
+	if c.Exclude == nil {
+		c.Exclude = base.Exclude
+	}
+	if c.Projects == nil {
+		c.Projects = base.Projects
+	}
+}
+
+// combine returns the items of base followed by those of own it lacks, or
+// own if base is empty
+func combine[T comparable](base, own []T) []T {
+	if len(base) == 0 {
+		return own
+	}
+	combined := append([]T{}, base...)
+	seen := make(map[T]bool, len(base)+len(own))
+	for _, item := range base {
+		seen[item] = true
+	}
+	for _, item := range own {
+		if !seen[item] {
+			seen[item] = true
+			combined = append(combined, item)
+		}
+	}
+	return combined
+}
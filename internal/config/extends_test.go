@@ -0,0 +1,124 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"env-audit/internal/netutil"
+)
+
+func TestLoadFile_Extends(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tmpDir, "base"), 0755)
+	os.MkdirAll(filepath.Join(tmpDir, "app"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "base", ".env-audit.yaml"), []byte(`check_leaks: true
+fail_on: warning
+patterns: leaks.yaml
+ignore: [CI_TOKEN]
+sensitive_patterns: [PASSPHRASE]
+severity:
+  duplicate: error
+  extra: info
+limits:
+  max_vars: 100
+`), 0644)
+	configPath := filepath.Join(tmpDir, "app", ".env-audit.yaml")
+	os.WriteFile(configPath, []byte(`extends: ../base/.env-audit.yaml
+ignore: [DEBUG, CI_TOKEN]
+severity:
+  extra: warning
+limits:
+  max_value_length: 256
+`), 0644)
+
+	cfg, err := LoadFile(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.CheckLeaks || cfg.FailOn != "warning" {
+		t.Errorf("expected the base's checks and threshold, got %+v", cfg)
+	}
+	if !reflect.DeepEqual(cfg.Ignore, []string{"CI_TOKEN", "DEBUG"}) {
+		t.Errorf("expected the ignore lists combined, got %v", cfg.Ignore)
+	}
+	if !reflect.DeepEqual(cfg.Severity, map[string]string{"duplicate": "error", "extra": "warning"}) {
+		t.Errorf("expected severities merged with the file winning, got %v", cfg.Severity)
+	}
+	if cfg.Limits != (Limits{MaxVars: 100, MaxValueLength: 256}) {
+		t.Errorf("expected limits merged per field, got %+v", cfg.Limits)
+	}
+	if cfg.Patterns != filepath.Join("..", "base", "leaks.yaml") {
+		t.Errorf("expected the base's pattern pack relative to the file, got %q", cfg.Patterns)
+	}
+
+	own, err := LoadFileWithoutPreset(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if own.CheckLeaks || own.Extends != "../base/.env-audit.yaml" {
+		t.Errorf("expected only the file's own settings, got %+v", own)
+	}
+}
+
+func TestLoadFile_ExtendsChain(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmpDir, "org.yaml"), []byte("check_order: true\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "team.yaml"), []byte("extends: org.yaml\npreset: ci-minimal\n"), 0644)
+	configPath := filepath.Join(tmpDir, ".env-audit.yaml")
+	os.WriteFile(configPath, []byte("extends: team.yaml\n"), 0644)
+
+	cfg, err := LoadFile(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.CheckOrder || !cfg.CheckLeaks {
+		t.Errorf("expected settings of every file in the chain and the base's preset, got %+v", cfg)
+	}
+
+	os.WriteFile(filepath.Join(tmpDir, "org.yaml"), []byte("extends: .env-audit.yaml\n"), 0644)
+	if _, err := LoadFile(configPath); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("expected a cycle error, got %v", err)
+	}
+
+	os.WriteFile(filepath.Join(tmpDir, "org.yaml"), []byte("chek_order: true\n"), 0644)
+	if _, err := LoadFile(configPath); err == nil || !strings.Contains(err.Error(), "extends team.yaml") || !strings.Contains(err.Error(), `did you mean "check_order"`) {
+		t.Errorf("expected the base's key error traced through the chain, got %v", err)
+	}
+}
+
+func TestLoadFile_ExtendsURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/org/env-audit.yaml":
+			w.Write([]byte("extends: common.yaml\nsensitive_patterns: [PASSPHRASE]\n"))
+		case "/org/common.yaml":
+			w.Write([]byte("check_leaks: true\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".env-audit.yaml")
+	os.WriteFile(configPath, []byte("extends: "+server.URL+"/org/env-audit.yaml\n"), 0644)
+
+	cfg, err := LoadFile(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.CheckLeaks || !reflect.DeepEqual(cfg.SensitivePatterns, []string{"PASSPHRASE"}) {
+		t.Errorf("expected the remote base and its own base, got %+v", cfg)
+	}
+
+	netutil.Configure(netutil.Options{Offline: true})
+	defer netutil.Configure(netutil.Options{})
+	if _, err := LoadFile(configPath); err == nil || !strings.Contains(err.Error(), "offline") {
+		t.Errorf("expected the remote base refused offline, got %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// decodeStrict decodes YAML into out, rejecting keys out has no field for,
+// so a typo such as chek_leaks fails instead of being silently ignored
+func decodeStrict(data []byte, out interface{}) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	if err := checkKeys(doc.Content[0], reflect.TypeOf(out).Elem(), ""); err != nil {
+		return err
+	}
+	return doc.Content[0].Decode(out)
+}
+
+// checkKeys checks the keys of the mappings in node against the yaml names
+// of the fields of t, recursing into nested structs, lists and maps of them.
+// prefix is the dotted path of node, empty at the top.
+func checkKeys(node *yaml.Node, t reflect.Type, prefix string) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return nil
+		}
+		for _, item := range node.Content {
+			if err := checkKeys(item, t.Elem(), prefix); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if err := checkKeys(node.Content[i+1], t.Elem(), prefix+node.Content[i].Value+"."); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+	default:
+		return nil
+	}
+	// A struct may also be written as a scalar, as defaults are
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	fields := make(map[string]reflect.Type)
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = t.Field(i).Type
+		names = append(names, name)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		fieldType, ok := fields[key.Value]
+		if !ok {
+			msg := fmt.Sprintf("line %d: unknown key %q", key.Line, prefix+key.Value)
+			if suggestion := closestName(key.Value, names); suggestion != "" {
+				msg += fmt.Sprintf(" (did you mean %q?)", prefix+suggestion)
+			}
+			return fmt.Errorf("%s", msg)
+		}
+		if err := checkKeys(node.Content[i+1], fieldType, prefix+key.Value+"."); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// closestName returns the name closest to s by edit distance, or empty
+// string if none is close enough to be a likely typo
+func closestName(s string, names []string) string {
+	best, bestDistance := "", len(s)/3+2
+	for _, name := range names {
+		if d := editDistance(s, name); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadFile_UnknownKeys(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"strict: true\nchek_leaks: true\n", `line 2: unknown key "chek_leaks" (did you mean "check_leaks"?)`},
+		{"redaction:\n  mod: partial\n", `line 2: unknown key "redaction.mod" (did you mean "redaction.mode"?)`},
+		{"projects:\n  - name: api\n    flie: api/.env\n", `line 3: unknown key "projects.flie" (did you mean "projects.file"?)`},
+		{"defaults:\n  SECRET_KEY:\n    value: x\n    unsave: true\n", `line 4: unknown key "defaults.SECRET_KEY.unsave" (did you mean "defaults.SECRET_KEY.unsafe"?)`},
+		{"colour_scheme: dark\n", `line 1: unknown key "colour_scheme"`},
+	}
+	for _, tt := range tests {
+		configPath := filepath.Join(t.TempDir(), ".env-audit.yaml")
+		if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := LoadFile(configPath)
+		if err == nil || !strings.HasSuffix(err.Error(), tt.want) {
+			t.Errorf("%q: expected error ending in %s, got %v", tt.content, tt.want, err)
+		}
+	}
+}
+
+func TestLoadFile_KnownKeys(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ".env-audit.yaml")
+	content := "file: .env\ndefaults:\n  LOG_LEVEL: info\nhttp:\n  retries: 3\nlimits:\n  max_vars: 10\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(configPath); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestClosestName(t *testing.T) {
+	names := []string{"check_leaks", "check_order", "json"}
+	if got := closestName("check-leaks", names); got != "check_leaks" {
+		t.Errorf("expected check_leaks, got %q", got)
+	}
+	if got := closestName("jsno", names); got != "json" {
+		t.Errorf("expected json, got %q", got)
+	}
+	if got := closestName("verbose", names); got != "" {
+		t.Errorf("expected no suggestion, got %q", got)
+	}
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"embed"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"env-audit/internal/netutil"
+)
+
+// builtinPresets are the presets shipped with the binary, one YAML file per
+// preset name
+//
+//go:embed presets/*.yaml
+var builtinPresets embed.FS
+
+// Preset is a shareable policy: which checks run, how severe each finding
+// is and the thresholds that fail a run. A config file names one with
+// preset:, as a built-in name, a path or an http(s) URL, and its own
+// settings take precedence over the preset's.
+type Preset struct {
+	Strict            bool              `yaml:"strict"`
+	FailOn            string            `yaml:"fail_on"`
+	CheckLeaks        bool              `yaml:"check_leaks"`
+	CheckOrder        bool              `yaml:"check_order"`
+	RequireAllExample bool              `yaml:"require_all_example"`
+	SensitivePatterns []string          `yaml:"sensitive_patterns"`
+	Severity          map[string]string `yaml:"severity"`
+	Only              []string          `yaml:"only"`
+	Exclude           []string          `yaml:"exclude"`
+	Rules             map[string]string `yaml:"rules"`
+	RotateAfter       string            `yaml:"rotate_after"`
+	Limits            Limits            `yaml:"limits"`
+}
+
+// PresetNames returns the names of the built-in presets, sorted
+func PresetNames() []string {
+	entries, _ := builtinPresets.ReadDir("presets")
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".yaml"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadPreset reads a preset by built-in name, from an http(s) URL, or from a
+// file; a relative path is relative to dir, the directory of the config
+// file naming it. Unknown fields are an error, so a typo can't silently
+// weaken the policy.
+func LoadPreset(source, dir string) (*Preset, error) {
+	var data []byte
+	var err error
+	switch {
+	case isURL(source):
+		data, err = fetchURL(source)
+	case !strings.ContainsAny(source, `/\.`):
+		data, err = builtinPresets.ReadFile("presets/" + source + ".yaml")
+		if err != nil {
+			return nil, fmt.Errorf("unknown preset (expected %s, a file or a URL)", strings.Join(PresetNames(), ", "))
+		}
+	default:
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(dir, source)
+		}
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var preset Preset
+	if err := decodeStrict(data, &preset); err != nil {
+		return nil, err
+	}
+	return &preset, nil
+}
+
+// fetchURL downloads a preset or an extended config file through the
+// shared HTTP client, so --offline and the proxy settings apply
+func fetchURL(url string) ([]byte, error) {
+	resp, err := netutil.Client(30 * time.Second).Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// applyPreset fills in the settings the config file leaves unset from
+// preset. Checks the preset enables stay enabled, and severities, rules and
+// limits are combined per entry with the config file's winning.
+func (c *FileConfig) applyPreset(preset *Preset) {
+	// strict and fail_on are one threshold, so either in the file wins
+	if !c.Strict && c.FailOn == "" {
+		c.Strict = preset.Strict
+		c.FailOn = preset.FailOn
+	}
+	c.CheckLeaks = c.CheckLeaks || preset.CheckLeaks
+	c.CheckOrder = c.CheckOrder || preset.CheckOrder
+	c.RequireAllExample = c.RequireAllExample || preset.RequireAllExample
+	if len(preset.SensitivePatterns) > 0 {
+		c.SensitivePatterns = append(append([]string{}, preset.SensitivePatterns...), c.SensitivePatterns...)
+	}
+	c.Severity = mergeMaps(preset.Severity, c.Severity)
+	c.Rules = mergeMaps(preset.Rules, c.Rules)
+	if c.Only == nil {
+		c.Only = preset.Only
+	}
+	if c.Exclude == nil {
+		c.Exclude = preset.Exclude
+	}
+	if c.RotateAfter == "" {
+		c.RotateAfter = preset.RotateAfter
+	}
+	if c.Limits.MaxVars == 0 {
+		c.Limits.MaxVars = preset.Limits.MaxVars
+	}
+	if c.Limits.MaxValueLength == 0 {
+		c.Limits.MaxValueLength = preset.Limits.MaxValueLength
+	}
+	if c.Limits.MaxTotalSize == "" {
+		c.Limits.MaxTotalSize = preset.Limits.MaxTotalSize
+	}
+}
+
+// mergeMaps returns base with the entries of override added or replacing
+// its own, or nil if both are empty
+func mergeMaps[V any](base, override map[string]V) map[string]V {
+	if len(base) == 0 {
+		return override
+	}
+	merged := make(map[string]V, len(base)+len(override))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range override {
+		merged[key] = value
+	}
+	return merged
+}
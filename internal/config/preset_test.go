@@ -0,0 +1,131 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadPreset_Builtin(t *testing.T) {
+	for _, name := range PresetNames() {
+		if _, err := LoadPreset(name, ""); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	preset, err := LoadPreset("strict-security", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !preset.CheckLeaks || preset.FailOn != "warning" || preset.Severity["rotation"] != "error" {
+		t.Errorf("unexpected strict-security preset: %+v", preset)
+	}
+
+	if _, err := LoadPreset("paranoid", ""); err == nil || !strings.Contains(err.Error(), "ci-minimal, strict-security") {
+		t.Errorf("expected an unknown preset error listing the built-ins, got %v", err)
+	}
+}
+
+func TestLoadPreset_FileAndURL(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tmpDir, "policy"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "policy", "org.yaml"), []byte("check_order: true\nseverity:\n  duplicate: error\n"), 0644)
+
+	preset, err := LoadPreset("policy/org.yaml", tmpDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !preset.CheckOrder || preset.Severity["duplicate"] != "error" {
+		t.Errorf("unexpected file preset: %+v", preset)
+	}
+
+	os.WriteFile(filepath.Join(tmpDir, "typo.yaml"), []byte("fail_onn: warning\n"), 0644)
+	if _, err := LoadPreset(filepath.Join(tmpDir, "typo.yaml"), ""); err == nil || !strings.Contains(err.Error(), "fail_onn") {
+		t.Errorf("expected an unknown field error, got %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("fail_on: info\n"))
+	}))
+	defer server.Close()
+	if preset, err := LoadPreset(server.URL+"/v2.yaml", ""); err != nil || preset.FailOn != "info" {
+		t.Errorf("unexpected URL preset: %+v, %v", preset, err)
+	}
+	if _, err := LoadPreset(server.URL+"/v3.yaml", ""); err == nil || !strings.Contains(err.Error(), "HTTP 404") {
+		t.Errorf("expected an HTTP error, got %v", err)
+	}
+}
+
+func TestLoadFile_Preset(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".env-audit.yaml")
+	os.WriteFile(filepath.Join(tmpDir, "team.yaml"), []byte(`fail_on: warning
+check_leaks: true
+exclude: [order]
+rotate_after: 90d
+severity:
+  duplicate: error
+  extra: info
+limits:
+  max_vars: 100
+`), 0644)
+	os.WriteFile(configPath, []byte(`preset: team.yaml
+rotate_after: 30d
+severity:
+  extra: ignore
+limits:
+  max_value_length: 256
+`), 0644)
+
+	cfg, err := LoadFile(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.FailOn != "warning" || !cfg.CheckLeaks || !reflect.DeepEqual(cfg.Exclude, []string{"order"}) {
+		t.Errorf("expected the preset's policy, got %+v", cfg)
+	}
+	if cfg.RotateAfter != "30d" {
+		t.Errorf("expected the file's rotate_after to win, got %s", cfg.RotateAfter)
+	}
+	if !reflect.DeepEqual(cfg.Severity, map[string]string{"duplicate": "error", "extra": "ignore"}) {
+		t.Errorf("expected severities merged per type, got %v", cfg.Severity)
+	}
+	if cfg.Limits != (Limits{MaxVars: 100, MaxValueLength: 256}) {
+		t.Errorf("expected limits merged per field, got %+v", cfg.Limits)
+	}
+
+	// strict in the file replaces the preset's fail_on rather than conflicting with it
+	os.WriteFile(configPath, []byte("preset: team.yaml\nstrict: true\n"), 0644)
+	if cfg, err := LoadFile(configPath); err != nil || cfg.FailOn != "" || !cfg.Strict {
+		t.Errorf("expected strict to win over the preset's fail_on: %+v, %v", cfg, err)
+	}
+
+	os.WriteFile(configPath, []byte("preset: missing.yaml\n"), 0644)
+	if _, err := LoadFile(configPath); err == nil || !strings.Contains(err.Error(), "preset missing.yaml") {
+		t.Errorf("expected a preset error, got %v", err)
+	}
+}
+
+func TestLoadFileWithoutPreset(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".env-audit.yaml")
+	os.WriteFile(configPath, []byte("preset: strict-security\nrequired: [API_KEY]\n"), 0644)
+
+	cfg, err := LoadFileWithoutPreset(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Preset != "strict-security" || cfg.CheckLeaks || cfg.FailOn != "" {
+		t.Errorf("expected only the file's own settings, got %+v", cfg)
+	}
+	if !reflect.DeepEqual(cfg.Required, []string{"API_KEY"}) {
+		t.Errorf("expected required from the file, got %v", cfg.Required)
+	}
+}
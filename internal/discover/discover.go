@@ -0,0 +1,129 @@
+// Package discover finds env files under directory trees for --recursive scans.
+package discover
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Options controls file discovery
+type Options struct {
+	// NoIgnore disables .gitignore rules and the default skipped directories
+	NoIgnore bool
+	// Exclude are .gitignore-style patterns, relative to each root, of
+	// directories and files never walked or returned, even with NoIgnore
+	Exclude []string
+}
+
+// skippedDirs are dependency trees that are never walked unless NoIgnore is set
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// templateSuffixes mark committed templates, which are compared with
+// --example rather than audited as real environments
+var templateSuffixes = []string{".example", ".sample", ".template", ".dist"}
+
+// IsEnvFile reports whether name looks like an env file to audit:
+// .env or .env.<something>, excluding example templates
+func IsEnvFile(name string) bool {
+	if name != ".env" && !strings.HasPrefix(name, ".env.") {
+		return false
+	}
+	for _, suffix := range templateSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// Find walks each root and returns the env files found, sorted. Roots that
+// are files are returned as is. Directories ignored by .gitignore are not
+// entered, and the .git directory is always skipped.
+func Find(roots []string, opts Options) ([]string, error) {
+	return FindFunc(roots, opts, IsEnvFile)
+}
+
+// FindFunc is Find for the files whose name match accepts, e.g. source files
+func FindFunc(roots []string, opts Options, match func(name string) bool) ([]string, error) {
+	var files []string
+	for _, root := range roots {
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, root)
+			continue
+		}
+		found, err := walk(root, opts, match)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, found...)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// walk finds the matching files under one root directory
+func walk(root string, opts Options, match func(name string) bool) ([]string, error) {
+	var files []string
+	var stack []*ignoreFile
+	exclude := []*ignoreFile{{}}
+	for _, line := range opts.Exclude {
+		if p, ok := parseIgnorePattern(line); ok {
+			exclude[0].patterns = append(exclude[0].patterns, p)
+		}
+	}
+
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			if rel == "." {
+				rel = ""
+			} else if ignored(exclude, rel, true) || (!opts.NoIgnore && (skippedDirs[d.Name()] || ignored(stack, rel, true))) {
+				return filepath.SkipDir
+			}
+			if opts.NoIgnore {
+				return nil
+			}
+			// Drop rules of directories we've left, then add this one's
+			for len(stack) > 0 && stack[len(stack)-1].dir != "" && !strings.HasPrefix(rel+"/", stack[len(stack)-1].dir+"/") {
+				stack = stack[:len(stack)-1]
+			}
+			ig, err := loadIgnoreFile(filepath.Join(p, ".gitignore"), rel)
+			if err != nil {
+				return err
+			}
+			if ig != nil {
+				stack = append(stack, ig)
+			}
+			return nil
+		}
+
+		// Env files are themselves gitignored by convention, so ignore rules
+		// only prune directories
+		if match(d.Name()) && !ignored(exclude, rel, false) {
+			files = append(files, p)
+		}
+		return nil
+	})
+	return files, err
+}
@@ -0,0 +1,144 @@
+package discover
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeTree creates files (with parent directories) under root
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func relPaths(t *testing.T, root string, paths []string) []string {
+	t.Helper()
+	var rel []string
+	for _, p := range paths {
+		r, err := filepath.Rel(root, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rel = append(rel, filepath.ToSlash(r))
+	}
+	return rel
+}
+
+func TestFind_RespectsGitignore(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		".gitignore":               ".env\ndist/\n/tmp\n**/generated/**\n",
+		".env":                     "A=1",
+		".env.example":             "A=",
+		"api/.env.production":      "A=1",
+		"api/.gitignore":           "fixtures/*\n!fixtures/keep/\n",
+		"api/fixtures/drop/.env":   "A=1",
+		"api/fixtures/keep/.env":   "A=1",
+		"dist/.env":                "A=1",
+		"tmp/.env":                 "A=1",
+		"web/tmp/.env":             "A=1",
+		"web/src/generated/x/.env": "A=1",
+		"node_modules/pkg/.env":    "A=1",
+		"vendor/lib/.env":          "A=1",
+		".git/.env":                "A=1",
+		"web/notes.txt":            "",
+		"web/.envrc":               "",
+	})
+
+	files, err := Find([]string{root}, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{".env", "api/.env.production", "api/fixtures/keep/.env", "web/tmp/.env"}
+	if got := relPaths(t, root, files); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestFind_NoIgnore(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		".gitignore":            "dist/\n",
+		"dist/.env":             "A=1",
+		"node_modules/pkg/.env": "A=1",
+		".git/.env":             "A=1",
+	})
+
+	files, err := Find([]string{root}, Options{NoIgnore: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"dist/.env", "node_modules/pkg/.env"}
+	if got := relPaths(t, root, files); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestFind_FileRoot(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"config/app.env": "A=1"})
+	file := filepath.Join(root, "config", "app.env")
+
+	files, err := Find([]string{file}, Options{})
+	if err != nil || len(files) != 1 || files[0] != file {
+		t.Errorf("expected file roots to be returned as is, got %v (%v)", files, err)
+	}
+	if _, err := Find([]string{filepath.Join(root, "missing")}, Options{}); err == nil {
+		t.Error("expected error for a missing root")
+	}
+}
+
+func TestIsEnvFile(t *testing.T) {
+	tests := map[string]bool{
+		".env":            true,
+		".env.local":      true,
+		".env.vault":      true,
+		".env.example":    false,
+		".env.sample":     false,
+		".env.production": true,
+		".envrc":          false,
+		"app.env":         false,
+	}
+	for name, want := range tests {
+		if got := IsEnvFile(name); got != want {
+			t.Errorf("IsEnvFile(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestFind_Exclude(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"api/.env":              "A=1",
+		"legacy/app/.env":       "A=1",
+		"web/.env":              "A=1",
+		"web/.env.local":        "A=1",
+		"node_modules/x/.env":   "A=1",
+		"services/old/.env":     "A=1",
+		"services/billing/.env": "A=1",
+	})
+
+	files, err := Find([]string{root}, Options{Exclude: []string{"legacy", "*.local", "services/old/"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"api/.env", "services/billing/.env", "web/.env"}
+	if got := relPaths(t, root, files); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	files, _ = Find([]string{root}, Options{NoIgnore: true, Exclude: []string{"legacy"}})
+	if got := relPaths(t, root, files); len(got) != 6 || got[1] == "legacy/app/.env" {
+		t.Errorf("expected excludes to apply with NoIgnore, got %v", got)
+	}
+}
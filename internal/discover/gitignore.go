@@ -0,0 +1,132 @@
+package discover
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"strings"
+)
+
+// ignorePattern is one rule of a .gitignore file
+type ignorePattern struct {
+	segments []string // pattern split on "/", may contain "**"
+	negate   bool     // "!pattern" re-includes a path
+	dirOnly  bool     // "pattern/" only matches directories
+	anchored bool     // contains a "/", so it matches from the .gitignore's directory
+}
+
+// ignoreFile holds the rules of one .gitignore and the directory they apply to
+type ignoreFile struct {
+	dir      string // slash-separated, relative to the walk root ("" for the root)
+	patterns []ignorePattern
+}
+
+// loadIgnoreFile parses the .gitignore in dir, returning nil if there is none
+func loadIgnoreFile(osPath, dir string) (*ignoreFile, error) {
+	f, err := os.Open(osPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ig := &ignoreFile{dir: dir}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if p, ok := parseIgnorePattern(scanner.Text()); ok {
+			ig.patterns = append(ig.patterns, p)
+		}
+	}
+	return ig, scanner.Err()
+}
+
+// parseIgnorePattern parses one .gitignore line; ok is false for blank lines and comments
+func parseIgnorePattern(line string) (ignorePattern, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignorePattern{}, false
+	}
+
+	var p ignorePattern
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\`) {
+		// \! and \# escape a literal leading character
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if strings.Contains(line, "/") {
+		p.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return ignorePattern{}, false
+	}
+	p.segments = strings.Split(line, "/")
+	return p, true
+}
+
+// match reports whether rel, relative to the .gitignore's directory, matches p
+func (p ignorePattern) match(rel string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+	if !p.anchored {
+		// A pattern without a slash matches the name at any depth
+		ok, _ := path.Match(p.segments[0], path.Base(rel))
+		return ok
+	}
+	return matchSegments(p.segments, strings.Split(rel, "/"))
+}
+
+// matchSegments matches path segments against pattern segments, where "**"
+// stands for zero or more whole segments
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(rest, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// ignored reports whether rel (relative to the walk root) is excluded by the
+// stack of .gitignore files from the root down. Later rules win, so deeper
+// files and later lines override earlier ones.
+func ignored(stack []*ignoreFile, rel string, isDir bool) bool {
+	result := false
+	for _, ig := range stack {
+		local := rel
+		if ig.dir != "" {
+			if !strings.HasPrefix(rel, ig.dir+"/") {
+				continue
+			}
+			local = strings.TrimPrefix(rel, ig.dir+"/")
+		}
+		for _, p := range ig.patterns {
+			if p.match(local, isDir) {
+				result = !p.negate
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,64 @@
+package discover
+
+import "testing"
+
+func TestIgnorePatternMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		isDir   bool
+		want    bool
+	}{
+		{"dist/", "dist", true, true},
+		{"dist/", "dist", false, false},
+		{"dist", "packages/web/dist", true, true},
+		{"/tmp", "tmp", true, true},
+		{"/tmp", "web/tmp", true, false},
+		{"build/*", "build/out", true, true},
+		{"**/generated", "a/b/generated", true, true},
+		{"a/**/z", "a/z", true, true},
+		{"a/**/z", "a/b/c/z", true, true},
+		{"*.log", "logs/app.log", false, true},
+		{`\#notes`, "#notes", false, true},
+	}
+	for _, tt := range tests {
+		p, ok := parseIgnorePattern(tt.pattern)
+		if !ok {
+			t.Fatalf("pattern %q did not parse", tt.pattern)
+		}
+		if got := p.match(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("%q matching %q (dir=%v): got %v, want %v", tt.pattern, tt.path, tt.isDir, got, tt.want)
+		}
+	}
+
+	for _, line := range []string{"", "   ", "# comment"} {
+		if _, ok := parseIgnorePattern(line); ok {
+			t.Errorf("expected %q to be skipped", line)
+		}
+	}
+}
+
+func TestIgnored_NegationAndNesting(t *testing.T) {
+	root := &ignoreFile{}
+	for _, line := range []string{"services/*", "!services/api"} {
+		p, _ := parseIgnorePattern(line)
+		root.patterns = append(root.patterns, p)
+	}
+	nested := &ignoreFile{dir: "services/api"}
+	p, _ := parseIgnorePattern("cache/")
+	nested.patterns = append(nested.patterns, p)
+	stack := []*ignoreFile{root, nested}
+
+	if !ignored(stack, "services/web", true) {
+		t.Error("expected services/web to be ignored")
+	}
+	if ignored(stack, "services/api", true) {
+		t.Error("expected services/api to be re-included")
+	}
+	if !ignored(stack, "services/api/cache", true) {
+		t.Error("expected nested rule to apply below its directory")
+	}
+	if ignored(stack, "cache", true) {
+		t.Error("expected nested rule not to apply above its directory")
+	}
+}
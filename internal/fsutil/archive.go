@@ -0,0 +1,142 @@
+package fsutil
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// archiveExts are the archive types whose members can be opened as
+// "archive:member"
+var archiveExts = []string{".tar.gz", ".tgz", ".tar", ".zip"}
+
+// SplitArchivePath splits a path such as "release.tar.gz:configs/.env" into
+// the archive and the member inside it. ok is false unless the part before a
+// colon names a tar, tar.gz or zip file.
+func SplitArchivePath(p string) (archive, member string, ok bool) {
+	for i := 0; i < len(p); i++ {
+		if p[i] != ':' || p[i+1:] == "" {
+			continue
+		}
+		if isArchive(p[:i]) {
+			return p[:i], p[i+1:], true
+		}
+	}
+	return "", "", false
+}
+
+// isArchive reports whether name has an archive extension
+func isArchive(name string) bool {
+	lower := strings.ToLower(name)
+	for _, ext := range archiveExts {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// Open opens the file at p for reading. A path that does not exist on disk
+// but names an archive member, e.g. "release.tar.gz:configs/.env", is read
+// from the archive without extracting it.
+func Open(p string) (io.ReadCloser, error) {
+	f, err := os.Open(p)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return f, err
+	}
+	archive, member, ok := SplitArchivePath(p)
+	if !ok {
+		return nil, err
+	}
+	if strings.HasSuffix(strings.ToLower(archive), ".zip") {
+		return openZipMember(archive, member)
+	}
+	return openTarMember(archive, member)
+}
+
+// memberName normalises an archive member name, e.g. "./configs/.env"
+func memberName(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+// notInArchive is the error for a member the archive does not contain
+func notInArchive(archive, member string) error {
+	return &fs.PathError{Op: "open", Path: archive + ":" + member, Err: fs.ErrNotExist}
+}
+
+// readCloser reads from one stream and closes another, such as an archive
+// member and its archive
+type readCloser struct {
+	io.Reader
+	close func() error
+}
+
+func (r readCloser) Close() error { return r.close() }
+
+// openZipMember opens member inside the zip file archive
+func openZipMember(archive, member string) (io.ReadCloser, error) {
+	zr, err := zip.OpenReader(archive)
+	if err != nil {
+		return nil, err
+	}
+	want := memberName(member)
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || memberName(f.Name) != want {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			zr.Close()
+			return nil, err
+		}
+		return readCloser{rc, func() error {
+			rc.Close()
+			return zr.Close()
+		}}, nil
+	}
+	zr.Close()
+	return nil, notInArchive(archive, member)
+}
+
+// openTarMember opens member inside the tar file archive, gzip-compressed
+// if its name says so
+func openTarMember(archive, member string) (io.ReadCloser, error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	var r io.Reader = f
+	lower := strings.ToLower(archive)
+	if strings.HasSuffix(lower, ".gz") || strings.HasSuffix(lower, ".tgz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		r = gz
+	}
+
+	want := memberName(member)
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		if header.Typeflag == tar.TypeReg && memberName(header.Name) == want {
+			return readCloser{tr, f.Close}, nil
+		}
+	}
+	f.Close()
+	return nil, notInArchive(archive, member)
+}
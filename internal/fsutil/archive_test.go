@@ -0,0 +1,106 @@
+package fsutil
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTarGz writes files into a gzip-compressed tar archive at path
+func writeTarGz(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeZip writes files into a zip archive at path
+func writeZip(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, _ := zw.Create(name)
+		w.Write([]byte(content))
+	}
+	zw.Close()
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSplitArchivePath(t *testing.T) {
+	tests := []struct {
+		path, archive, member string
+		ok                    bool
+	}{
+		{"release.tar.gz:configs/.env", "release.tar.gz", "configs/.env", true},
+		{"C:/builds/bundle.zip:.env", "C:/builds/bundle.zip", ".env", true},
+		{"bundle.TGZ:app/.env", "bundle.TGZ", "app/.env", true},
+		{"configs/.env", "", "", false},
+		{"release.tar.gz:", "", "", false},
+		{"notes.txt:.env", "", "", false},
+	}
+	for _, tt := range tests {
+		archive, member, ok := SplitArchivePath(tt.path)
+		if archive != tt.archive || member != tt.member || ok != tt.ok {
+			t.Errorf("SplitArchivePath(%q) = %q, %q, %v", tt.path, archive, member, ok)
+		}
+	}
+}
+
+func TestOpen_ArchiveMembers(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{"./configs/.env": "SECRET=1\n", "README": "hello"}
+	writeTarGz(t, filepath.Join(dir, "release.tar.gz"), files)
+	writeZip(t, filepath.Join(dir, "release.zip"), map[string]string{"configs/.env": "SECRET=1\n"})
+
+	for _, archive := range []string{"release.tar.gz", "release.zip"} {
+		rc, err := Open(filepath.Join(dir, archive) + ":configs/.env")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", archive, err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		if string(data) != "SECRET=1\n" {
+			t.Errorf("%s: unexpected content %q", archive, data)
+		}
+
+		if _, err := Open(filepath.Join(dir, archive) + ":missing/.env"); !os.IsNotExist(err) {
+			t.Errorf("%s: expected a not-exist error for a missing member, got %v", archive, err)
+		}
+	}
+}
+
+func TestOpen_PlainFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	os.WriteFile(path, []byte("PORT=1\n"), 0644)
+
+	rc, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(data) != "PORT=1\n" {
+		t.Errorf("unexpected content %q", data)
+	}
+
+	if _, err := Open(path + ".missing"); !os.IsNotExist(err) {
+		t.Errorf("expected a not-exist error, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+// Package fsutil provides crash-safe file writes for the modes that rewrite
+// user files, and reads env files packed inside tar and zip archives.
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic replaces path with data without ever leaving it truncated.
+// The data goes to a temp file in the same directory, which is synced and
+// renamed over path. An existing file keeps its permissions and, where the
+// platform allows, its owner; a new file is created with perm. A symlink is
+// written through, like os.WriteFile does: the file it points to is
+// replaced and the link is left in place.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) (err error) {
+	// Renaming over the link itself would replace it with a regular file
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	info, statErr := os.Stat(path)
+	if statErr == nil {
+		perm = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	// Never leave the temp file behind on failure
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if statErr == nil {
+		if err = chown(tmp, info); err != nil {
+			return err
+		}
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	syncDir(filepath.Dir(path))
+	return nil
+}
+
+// syncDir flushes a directory entry so a completed rename survives a crash.
+// Best effort: not every platform or filesystem supports it.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
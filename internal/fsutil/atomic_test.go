@@ -0,0 +1,110 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+// For any content, WriteFileAtomic SHALL leave exactly that content at path
+// and no temp files beside it.
+func TestProperty_WriteFileAtomicRoundTrip(t *testing.T) {
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+	properties := gopter.NewProperties(parameters)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+
+	properties.Property("content round-trips", prop.ForAll(
+		func(content string) bool {
+			if err := WriteFileAtomic(path, []byte(content), 0600); err != nil {
+				return false
+			}
+			data, err := os.ReadFile(path)
+			if err != nil || string(data) != content {
+				return false
+			}
+			entries, err := os.ReadDir(dir)
+			return err == nil && len(entries) == 1
+		},
+		gen.AnyString(),
+	))
+
+	properties.TestingRun(t)
+}
+
+func TestWriteFileAtomic_NewFileUsesPerm(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env.example")
+	if err := WriteFileAtomic(path, []byte("A=\n"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0640 {
+		t.Errorf("expected mode 0640, got %o", info.Mode().Perm())
+	}
+}
+
+func TestWriteFileAtomic_PreservesPermissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	os.WriteFile(path, []byte("SECRET=old\n"), 0600)
+	os.Chmod(path, 0600)
+
+	if err := WriteFileAtomic(path, []byte("SECRET=new\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected the original mode 0600 to be kept, got %o", info.Mode().Perm())
+	}
+	if data, _ := os.ReadFile(path); string(data) != "SECRET=new\n" {
+		t.Errorf("unexpected content: %q", data)
+	}
+}
+
+func TestWriteFileAtomic_MissingDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", ".env")
+	if err := WriteFileAtomic(path, []byte("A=1\n"), 0644); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+}
+
+func TestWriteFileAtomic_Symlink(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "shared"), 0755)
+	os.MkdirAll(filepath.Join(dir, "app"), 0755)
+	target := filepath.Join(dir, "shared", ".env")
+	os.WriteFile(target, []byte("A=1\n"), 0600)
+	link := filepath.Join(dir, "app", ".env")
+	if err := os.Symlink(filepath.Join("..", "shared", ".env"), link); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	if err := WriteFileAtomic(link, []byte("A=2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("expected the link to be kept, got %v, %v", info, err)
+	}
+	if data, _ := os.ReadFile(target); string(data) != "A=2\n" {
+		t.Errorf("expected the linked file to be written, got %q", data)
+	}
+	if info, _ := os.Stat(target); info.Mode().Perm() != 0600 {
+		t.Errorf("expected the linked file's mode kept, got %o", info.Mode().Perm())
+	}
+	for _, sub := range []string{"app", "shared"} {
+		if entries, _ := os.ReadDir(filepath.Join(dir, sub)); len(entries) != 1 {
+			t.Errorf("expected no temp files left in %s, got %d entries", sub, len(entries))
+		}
+	}
+}
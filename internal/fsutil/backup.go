@@ -0,0 +1,26 @@
+package fsutil
+
+import "os"
+
+// Backup copies path to path+suffix before it is rewritten, returning the
+// backup's path. The copy gets the original's permissions, so a backup of a
+// private .env is just as private.
+func Backup(path, suffix string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	backup := path + suffix
+	if err := WriteFileAtomic(backup, data, info.Mode().Perm()); err != nil {
+		return "", err
+	}
+	// An older backup may have looser permissions than the original
+	if err := os.Chmod(backup, info.Mode().Perm()); err != nil {
+		return "", err
+	}
+	return backup, nil
+}
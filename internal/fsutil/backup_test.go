@@ -0,0 +1,38 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackup_CopiesContentAndMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	os.WriteFile(path, []byte("SECRET=1\n"), 0600)
+	os.Chmod(path, 0600)
+	// A stale backup with looser permissions is replaced
+	os.WriteFile(path+".bak", []byte("old"), 0644)
+	os.Chmod(path+".bak", 0644)
+
+	backup, err := Backup(path, ".bak")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backup != path+".bak" {
+		t.Errorf("unexpected backup path %s", backup)
+	}
+	data, _ := os.ReadFile(backup)
+	if string(data) != "SECRET=1\n" {
+		t.Errorf("unexpected backup content %q", data)
+	}
+	info, _ := os.Stat(backup)
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected backup mode 0600, got %o", info.Mode().Perm())
+	}
+}
+
+func TestBackup_MissingFile(t *testing.T) {
+	if _, err := Backup(filepath.Join(t.TempDir(), "missing"), ".bak"); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
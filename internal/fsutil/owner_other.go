@@ -0,0 +1,10 @@
+//go:build !unix
+
+package fsutil
+
+import "os"
+
+// chown is a no-op where files have no Unix owner
+func chown(f *os.File, info os.FileInfo) error {
+	return nil
+}
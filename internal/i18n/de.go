@@ -0,0 +1,101 @@
+package i18n
+
+// german is the German catalog
+var german = Catalog{
+	// Issue messages
+	"variable has empty value":                                       "Variable hat einen leeren Wert",
+	"required variable has empty value":                              "Pflichtvariable hat einen leeren Wert",
+	"required variable is missing":                                   "Pflichtvariable fehlt",
+	"variable missing from example":                                  "Variable aus der Beispieldatei fehlt",
+	"variable not in example file":                                   "Variable nicht in der Beispieldatei",
+	"duplicate key definition":                                       "doppelte Schlüsseldefinition",
+	"sensitive key detected":                                         "sensibler Schlüssel erkannt",
+	"variable is missing and its default is unsafe for production":   "Variable fehlt und ihr Standardwert ist für die Produktion unsicher",
+	"using default value":                                            "verwendet Standardwert",
+	"using default %s":                                               "verwendet Standardwert %s",
+	"value equals a default that is unsafe for production":           "Wert entspricht einem für die Produktion unsicheren Standardwert",
+	"%s overrides %s":                                                "%s überschreibt %s",
+	"potential %s detected":                                          "mögliches Geheimnis erkannt: %s",
+	"credentials embedded in URL %s":                                 "Zugangsdaten in URL eingebettet: %s",
+	"key looks like a secret (%s)":                                   "Schlüssel sieht wie ein Geheimnis aus (%s)",
+	"high entropy":                                                   "hohe Entropie",
+	"%s in base64-encoded value":                                     "%s in Base64-kodiertem Wert",
+	"potential secret in base64-encoded value (high entropy)":        "mögliches Geheimnis in Base64-kodiertem Wert (hohe Entropie)",
+	"potential secret detected (high entropy)":                       "mögliches Geheimnis erkannt (hohe Entropie)",
+	"example value looks like a real secret (%s)":                    "Beispielwert sieht wie ein echtes Geheimnis aus (%s)",
+	"value does not match the %s format (expected %s)":               "Wert entspricht nicht dem Format %s (erwartet: %s)",
+	"value is wrapped in an extra pair of quotes":                    "Wert steht in einem zusätzlichen Paar Anführungszeichen",
+	"value contains escaped quotes":                                  "Wert enthält maskierte Anführungszeichen",
+	"value looks percent-encoded; decode it before use":              "Wert scheint prozentkodiert zu sein; vor der Verwendung dekodieren",
+	"value does not match rule %s":                                   "Wert entspricht nicht der Regel %s",
+	"value is %d bytes, over the limit of %d":                        "Wert ist %d Bytes groß, über dem Grenzwert von %d",
+	"%d variables defined, over the limit of %d":                     "%d Variablen definiert, über dem Grenzwert von %d",
+	"variables total %d bytes, over the limit of %d":                 "Variablen umfassen insgesamt %d Bytes, über dem Grenzwert von %d",
+	"key is reserved by %s":                                          "Schlüssel ist von %s reserviert",
+	"keys starting with %s are reserved by %s":                       "Schlüssel mit dem Präfix %s sind von %s reserviert",
+	"key name not accepted by %s, use %s":                            "Schlüsselname wird von %s nicht akzeptiert, erlaubt sind %s",
+	"%s on %s":                                                       "%s auf %s",
+	"shadows a system variable that controls %s":                     "überschreibt eine Systemvariable für %s",
+	"executable lookup":                                              "die Suche nach Programmen",
+	"shared library loading":                                         "das Laden gemeinsamer Bibliotheken",
+	"the Node.js runtime":                                            "die Node.js-Laufzeit",
+	"the Python runtime":                                             "die Python-Laufzeit",
+	"the Ruby runtime":                                               "die Ruby-Laufzeit",
+	"the Perl runtime":                                               "die Perl-Laufzeit",
+	"the Java runtime":                                               "die Java-Laufzeit",
+	"the shell":                                                      "die Shell",
+	"the user environment":                                           "die Benutzerumgebung",
+	"a letter followed by letters, digits or underscores":            "ein Buchstabe gefolgt von Buchstaben, Ziffern oder Unterstrichen",
+	"letters, digits and underscores":                                "Buchstaben, Ziffern und Unterstriche",
+	"references an undefined variable":                               "verweist auf eine undefinierte Variable",
+	"references %d undefined variables":                              "verweist auf %d undefinierte Variablen",
+	"key order differs from example (expected first)":                "Schlüsselreihenfolge weicht vom Beispiel ab (erwartet an erster Stelle)",
+	"key order differs from example (expected after %s)":             "Schlüsselreihenfolge weicht vom Beispiel ab (erwartet nach %s)",
+	"key is not grouped with its example section (%s)":               "Schlüssel steht nicht bei seinem Abschnitt aus dem Beispiel (%s)",
+	"last rotated %s (%d days ago), past its %d day rotation window": "zuletzt rotiert am %s (vor %d Tagen), Rotationsfenster von %d Tagen überschritten",
+	"documented in %s but missing from %s":                           "in %s dokumentiert, fehlt aber in %s",
+	"not documented in %s":                                           "nicht in %s dokumentiert",
+	"invalid source annotation %q":                                   "ungültige Quellenangabe %q",
+	"source %s no longer exists":                                     "Quelle %s existiert nicht mehr",
+	"value differs from its source %s":                               "Wert weicht von seiner Quelle %s ab",
+	"%s (%d bytes)":                                                  "%s (%d Bytes)",
+	"missing '=' separator":                                          "fehlendes Trennzeichen '='",
+	"empty key":                                                      "leerer Schlüssel",
+	"unterminated quoted value":                                      "nicht abgeschlossener Wert in Anführungszeichen",
+
+	// Report headings
+	"Empty Values":            "Leere Werte",
+	"Missing Required":        "Fehlende Pflichtvariablen",
+	"Sensitive Keys Detected": "Sensible Schlüssel erkannt",
+	"Duplicate Keys":          "Doppelte Schlüssel",
+	"Extra Variables":         "Zusätzliche Variablen",
+	"Potential Leaks":         "Mögliche Lecks",
+	"Example Secrets":         "Geheimnisse in Beispielen",
+	"Unsafe Defaults":         "Unsichere Standardwerte",
+	"Using Defaults":          "Verwendete Standardwerte",
+	"Overridden Values":       "Überschriebene Werte",
+	"Key Order":               "Schlüsselreihenfolge",
+	"Malformed Credentials":   "Fehlerhafte Zugangsdaten",
+	"Invalid Values":          "Ungültige Werte",
+	"Limits Exceeded":         "Grenzwerte überschritten",
+	"Platform Constraints":    "Plattformvorgaben",
+	"Unresolved References":   "Unaufgelöste Verweise",
+	"System Variables":        "Systemvariablen",
+	"Documentation Drift":     "Abweichende Dokumentation",
+	"Source Drift":            "Abweichung von der Quelle",
+	"Parse Errors":            "Syntaxfehler",
+	"Rotation Overdue":        "Rotation überfällig",
+	"Other":                   "Sonstige",
+	"Environment":             "Umgebung",
+
+	// Text report
+	"env-audit scan results":   "env-audit Prüfergebnisse",
+	"No issues found.":         "Keine Probleme gefunden.",
+	" (line %d)":               " (Zeile %d)",
+	"Files (%d):":              "Dateien (%d):",
+	"Unparsed Lines (%d):":     "Nicht gelesene Zeilen (%d):",
+	"error":                    "Fehler",
+	"Summary: %d issues found": "Zusammenfassung: %d Probleme gefunden",
+	"Summary: %d issues found, %d of %d files failed":                    "Zusammenfassung: %d Probleme gefunden, %d von %d Dateien fehlgeschlagen",
+	"and %d more… (raise --max-display or use --json for the full list)": "und %d weitere… (--max-display erhöhen oder --json für die vollständige Liste verwenden)",
+}
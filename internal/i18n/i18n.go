@@ -0,0 +1,88 @@
+// Package i18n translates user-facing messages. Messages are written in
+// English where they are produced and looked up in the active locale's
+// catalog by their English format string, so a message without a
+// translation falls back to English.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// DefaultLocale is the language messages are written in
+const DefaultLocale = "en"
+
+// Catalog maps English message formats to their translation
+type Catalog map[string]string
+
+// catalogs holds the supported locales; English needs no catalog
+var catalogs = map[string]Catalog{
+	DefaultLocale: nil,
+	"de":          german,
+}
+
+// active is the catalog of the selected locale, nil for English
+var active Catalog
+
+// Locales returns the supported locale names, sorted
+func Locales() []string {
+	names := make([]string, 0, len(catalogs))
+	for name := range catalogs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetLocale selects the language of messages, e.g. "de" or "de_DE.UTF-8"
+func SetLocale(lang string) error {
+	catalog, ok := catalogs[Normalize(lang)]
+	if !ok {
+		return fmt.Errorf("unsupported language %q (expected %s)", lang, strings.Join(Locales(), ", "))
+	}
+	active = catalog
+	return nil
+}
+
+// FromEnv returns the locale selected by LC_ALL, LC_MESSAGES or LANG, in
+// that order of precedence, or DefaultLocale if it is not supported
+func FromEnv() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			lang := Normalize(value)
+			if _, ok := catalogs[lang]; ok {
+				return lang
+			}
+			return DefaultLocale
+		}
+	}
+	return DefaultLocale
+}
+
+// Normalize reduces a locale such as "de_DE.UTF-8" to its language, "de".
+// The C and POSIX locales are English.
+func Normalize(lang string) string {
+	lang = strings.ToLower(lang)
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	switch lang {
+	case "", "c", "posix":
+		return DefaultLocale
+	}
+	return lang
+}
+
+// T translates the message format and formats it with args like
+// fmt.Sprintf; without args the message is returned as is
+func T(format string, args ...interface{}) string {
+	if translated, ok := active[format]; ok {
+		format = translated
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
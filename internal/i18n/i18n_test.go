@@ -0,0 +1,79 @@
+package i18n
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"":            "en",
+		"C":           "en",
+		"POSIX":       "en",
+		"de":          "de",
+		"de_DE.UTF-8": "de",
+		"de-AT":       "de",
+		"en_US":       "en",
+		"fr_FR@euro":  "fr",
+	}
+	for input, want := range tests {
+		if got := Normalize(input); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestT_TranslatesAndFallsBack(t *testing.T) {
+	t.Cleanup(func() { SetLocale(DefaultLocale) })
+
+	if got := T("value does not match rule %s", "^a$"); got != "value does not match rule ^a$" {
+		t.Errorf("expected English by default, got %q", got)
+	}
+	if err := SetLocale("de_DE.UTF-8"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := T("value does not match rule %s", "^a$"); got != "Wert entspricht nicht der Regel ^a$" {
+		t.Errorf("expected German, got %q", got)
+	}
+	if got := T("not in any catalog"); got != "not in any catalog" {
+		t.Errorf("expected untranslated messages in English, got %q", got)
+	}
+}
+
+func TestSetLocale_Unsupported(t *testing.T) {
+	if err := SetLocale("fr"); err == nil {
+		t.Error("expected an unsupported language to be rejected")
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "de_DE.UTF-8")
+	t.Setenv("LANG", "en_US.UTF-8")
+	if got := FromEnv(); got != "de" {
+		t.Errorf("expected LC_MESSAGES to win over LANG, got %q", got)
+	}
+
+	t.Setenv("LC_ALL", "fr_FR.UTF-8")
+	if got := FromEnv(); got != DefaultLocale {
+		t.Errorf("expected an unsupported locale to fall back to English, got %q", got)
+	}
+}
+
+// Every translation must keep the verbs of its English format, in order
+func TestGermanCatalog_FormatVerbs(t *testing.T) {
+	for format, translated := range german {
+		if verbs(format) != verbs(translated) {
+			t.Errorf("%q: verbs %q differ from %q in %q", format, verbs(translated), verbs(format), translated)
+		}
+	}
+}
+
+// verbs returns the formatting verbs of format, e.g. "%s%d"
+func verbs(format string) string {
+	var out []byte
+	for i := 0; i+1 < len(format); i++ {
+		if format[i] == '%' {
+			out = append(out, format[i], format[i+1])
+			i++
+		}
+	}
+	return string(out)
+}
@@ -0,0 +1,216 @@
+// Package netutil is the HTTP client layer shared by every remote
+// integration: pattern packs, release checks and hosting platform APIs. It
+// retries transient failures with backoff, spaces out requests, and honours
+// the proxy and CA bundle that enterprise networks require.
+package netutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultRetries is how often a failed idempotent request is retried
+const DefaultRetries = 2
+
+// Backoff bounds between retries; a var so tests need not wait
+var (
+	baseBackoff = 500 * time.Millisecond
+	maxBackoff  = 30 * time.Second
+)
+
+// Options configures the shared transport
+type Options struct {
+	Proxy     string  // proxy URL; empty uses HTTPS_PROXY, HTTP_PROXY and NO_PROXY
+	CABundle  string  // PEM file of CAs trusted in addition to the system pool
+	Retries   int     // retries of a failed idempotent request
+	RateLimit float64 // requests per second across all clients, 0 for no limit
+	Offline   bool    // fail every request with ErrOffline
+}
+
+// ErrOffline is the error of every request made in offline mode
+var ErrOffline = errors.New("network access is disabled in offline mode")
+
+var (
+	mu     sync.RWMutex
+	shared http.RoundTripper = newTransport(http.DefaultTransport, DefaultRetries, 0)
+)
+
+// Configure replaces the transport behind every Client. It fails if the
+// proxy URL is invalid or the CA bundle holds no certificates. In offline
+// mode the other options are ignored and every request fails.
+func Configure(opts Options) error {
+	if opts.Offline {
+		mu.Lock()
+		defer mu.Unlock()
+		shared = offline{}
+		return nil
+	}
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.Proxy != "" {
+		proxy, err := url.Parse(opts.Proxy)
+		if err != nil || proxy.Scheme == "" || proxy.Host == "" {
+			return fmt.Errorf("invalid proxy URL %q", opts.Proxy)
+		}
+		base.Proxy = http.ProxyURL(proxy)
+	}
+	if opts.CABundle != "" {
+		pem, err := os.ReadFile(opts.CABundle)
+		if err != nil {
+			return fmt.Errorf("CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("CA bundle %s: no PEM certificates found", opts.CABundle)
+		}
+		base.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	shared = newTransport(base, opts.Retries, opts.RateLimit)
+	return nil
+}
+
+// Client returns an HTTP client on the shared transport. The timeout bounds
+// a whole request, retries included.
+func Client(timeout time.Duration) *http.Client {
+	mu.RLock()
+	defer mu.RUnlock()
+	return &http.Client{Timeout: timeout, Transport: shared}
+}
+
+// offline refuses every request, so no code path can reach the network
+type offline struct{}
+
+// RoundTrip implements http.RoundTripper
+func (offline) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, ErrOffline
+}
+
+// transport retries requests that failed for a transient reason and paces
+// requests to the rate limit
+type transport struct {
+	base     http.RoundTripper
+	retries  int
+	interval time.Duration // minimum spacing between requests, 0 for none
+
+	mu   sync.Mutex
+	next time.Time // earliest start of the next request
+}
+
+func newTransport(base http.RoundTripper, retries int, rateLimit float64) *transport {
+	t := &transport{base: base, retries: retries}
+	if rateLimit > 0 {
+		t.interval = time.Duration(float64(time.Second) / rateLimit)
+	}
+	return t
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := t.wait(req); err != nil {
+			return nil, err
+		}
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.retries || !retryable(req, resp, err) {
+			return resp, err
+		}
+
+		delay := backoff(attempt, resp)
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := sleep(req, delay); err != nil {
+			return nil, err
+		}
+		if req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// wait blocks until the rate limit allows another request
+func (t *transport) wait(req *http.Request) error {
+	if t.interval <= 0 {
+		return nil
+	}
+	t.mu.Lock()
+	now := time.Now()
+	start := t.next
+	if start.Before(now) {
+		start = now
+	}
+	t.next = start.Add(t.interval)
+	t.mu.Unlock()
+	return sleep(req, time.Until(start))
+}
+
+// retryable reports whether a request may be sent again: it must be
+// idempotent, with a body that can be replayed, and have failed with a
+// network error, a rate limit or a server error
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		return req.Context().Err() == nil
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || (resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented)
+}
+
+// backoff is the delay before retry attempt+1: the server's Retry-After in
+// seconds if it sent one, else an exponential backoff, at most maxBackoff
+func backoff(attempt int, resp *http.Response) time.Duration {
+	delay := baseBackoff << attempt
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+			delay = time.Duration(secs) * time.Second
+		}
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
+
+// sleep waits for d or until the request is cancelled
+func sleep(req *http.Request, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
@@ -0,0 +1,199 @@
+package netutil
+
+import (
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastBackoff shortens retry delays for the duration of a test
+func fastBackoff(t *testing.T) {
+	t.Helper()
+	base, max := baseBackoff, maxBackoff
+	baseBackoff, maxBackoff = time.Millisecond, 10*time.Millisecond
+	t.Cleanup(func() {
+		baseBackoff, maxBackoff = base, max
+		Configure(Options{Retries: DefaultRetries})
+	})
+}
+
+func TestClient_RetriesTransientFailures(t *testing.T) {
+	fastBackoff(t)
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	if err := Configure(Options{Retries: 2}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := Client(5 * time.Second).Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls != 3 {
+		t.Errorf("expected success on the third attempt, got HTTP %d after %d calls", resp.StatusCode, calls)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	Configure(Options{Retries: 1})
+	resp, err = Client(5 * time.Second).Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || calls != 2 {
+		t.Errorf("expected the last failure after 2 calls, got HTTP %d after %d calls", resp.StatusCode, calls)
+	}
+}
+
+func TestClient_NoRetry(t *testing.T) {
+	fastBackoff(t)
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	Configure(Options{Retries: 3})
+
+	resp, err := Client(5*time.Second).Post(server.URL, "text/plain", strings.NewReader("x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if calls != 1 {
+		t.Errorf("expected a POST not to be retried, got %d calls", calls)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	resp, err = Client(5 * time.Second).Get(server.URL + "/missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if calls != 1 {
+		t.Errorf("expected a 404 not to be retried, got %d calls", calls)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	if got := backoff(2, nil); got != 4*baseBackoff {
+		t.Errorf("expected exponential backoff, got %v", got)
+	}
+	resp := &http.Response{Header: http.Header{"Retry-After": {"7"}}}
+	if got := backoff(0, resp); got != 7*time.Second {
+		t.Errorf("expected Retry-After to win, got %v", got)
+	}
+	resp.Header.Set("Retry-After", "3600")
+	if got := backoff(0, resp); got != maxBackoff {
+		t.Errorf("expected the delay to be capped, got %v", got)
+	}
+}
+
+func TestClient_RateLimit(t *testing.T) {
+	fastBackoff(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	Configure(Options{RateLimit: 20})
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		resp, err := Client(5 * time.Second).Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if elapsed := time.Since(start); elapsed < 140*time.Millisecond {
+		t.Errorf("expected 4 requests at 20/s to take at least 150ms, took %v", elapsed)
+	}
+}
+
+func TestConfigure_Proxy(t *testing.T) {
+	fastBackoff(t)
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+	}))
+	defer proxy.Close()
+
+	if err := Configure(Options{Proxy: proxy.URL}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := Client(5 * time.Second).Get("http://patterns.example.com/pack.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if proxied != "http://patterns.example.com/pack.json" {
+		t.Errorf("expected the request to go through the proxy, got %q", proxied)
+	}
+
+	if err := Configure(Options{Proxy: "proxy.corp:3128"}); err == nil {
+		t.Error("expected an error for a proxy URL without a scheme")
+	}
+}
+
+func TestConfigure_CABundle(t *testing.T) {
+	fastBackoff(t)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	if _, err := Client(5 * time.Second).Get(server.URL); err == nil {
+		t.Fatal("expected the test server's certificate to be untrusted by default")
+	}
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0644)
+	if err := Configure(Options{CABundle: bundle}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := Client(5 * time.Second).Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected the CA bundle to be trusted, got %v", err)
+	}
+	resp.Body.Close()
+
+	os.WriteFile(bundle, []byte("not a certificate"), 0644)
+	if err := Configure(Options{CABundle: bundle}); err == nil {
+		t.Error("expected an error for a bundle without certificates")
+	}
+}
+
+func TestConfigure_Offline(t *testing.T) {
+	fastBackoff(t)
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	if err := Configure(Options{Offline: true, Proxy: "not a URL"}); err != nil {
+		t.Fatalf("expected offline mode to ignore the other options, got %v", err)
+	}
+	_, err := Client(5 * time.Second).Get(server.URL)
+	if !errors.Is(err, ErrOffline) {
+		t.Errorf("expected ErrOffline, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no request to reach the server, got %d", calls)
+	}
+}
@@ -0,0 +1,167 @@
+package parser
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// CanonicalOptions controls FormatCanonical
+type CanonicalOptions struct {
+	SortKeys bool // sort entries by key within each blank-line separated block
+	Dedupe   bool // drop all but the last assignment of each key
+}
+
+// canonicalUnit is an entry together with the comment lines directly above
+// it, or, with an empty key, lines at the end of a block that precede no entry
+type canonicalUnit struct {
+	key   string
+	lines []string
+}
+
+// FormatCanonical rewrites env content in a canonical style, like gofmt for
+// env files: no indentation or spaces around "=", values quoted only when
+// they need it, one blank line between blocks, "\n" line endings and a final
+// newline. Comments stay with the entry below them, and every value parses
+// to the same result as before.
+func FormatCanonical(r io.Reader, opts CanonicalOptions) (string, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return "", err
+	}
+
+	var blocks [][]canonicalUnit
+	var block []canonicalUnit
+	var pending []string
+	endBlock := func() {
+		if len(pending) > 0 {
+			block = append(block, canonicalUnit{lines: pending})
+			pending = nil
+		}
+		if len(block) > 0 {
+			blocks = append(blocks, block)
+			block = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		idx := strings.Index(line, "=")
+		switch {
+		case line == "":
+			endBlock()
+			continue
+		case strings.HasPrefix(line, "#") || idx == -1:
+			pending = append(pending, line)
+			continue
+		}
+
+		value := strings.TrimSpace(line[idx+1:])
+		if end := quotedValueEnd(lines, i, value); end > i {
+			// Continuation lines are part of the value, so they stay as written
+			rest := append([]string{}, lines[i+1:end+1]...)
+			rest[len(rest)-1] = strings.TrimRightFunc(rest[len(rest)-1], unicode.IsSpace)
+			value = strings.Join(append([]string{value}, rest...), "\n")
+			i = end
+		}
+
+		assignment := parseKey(line[:idx]) + "=" + canonicalValue(value)
+		if hasExport(line[:idx]) {
+			assignment = "export " + assignment
+		}
+		block = append(block, canonicalUnit{key: parseKey(line[:idx]), lines: append(pending, assignment)})
+		pending = nil
+	}
+	endBlock()
+	if opts.Dedupe {
+		blocks = dedupeUnits(blocks)
+	}
+
+	var sb strings.Builder
+	for i, block := range blocks {
+		if opts.SortKeys {
+			// Lines that precede no entry close their block
+			sort.SliceStable(block, func(a, b int) bool {
+				if (block[a].key == "") != (block[b].key == "") {
+					return block[b].key == ""
+				}
+				return block[a].key < block[b].key
+			})
+		}
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		for _, unit := range block {
+			for _, line := range unit.lines {
+				sb.WriteString(line + "\n")
+			}
+		}
+	}
+	return sb.String(), nil
+}
+
+// dedupeUnits drops every assignment of a key but the last, which is the
+// one that takes effect. The comments above a dropped assignment are kept
+// with the entry that follows it in its block.
+func dedupeUnits(blocks [][]canonicalUnit) [][]canonicalUnit {
+	count := make(map[string]int)
+	for _, block := range blocks {
+		for _, unit := range block {
+			if unit.key != "" {
+				count[unit.key]++
+			}
+		}
+	}
+
+	var deduped [][]canonicalUnit
+	for _, block := range blocks {
+		var kept []canonicalUnit
+		var comments []string
+		for _, unit := range block {
+			if unit.key != "" && count[unit.key] > 1 {
+				count[unit.key]--
+				comments = append(comments, unit.lines[:len(unit.lines)-1]...)
+				continue
+			}
+			unit.lines = append(comments, unit.lines...)
+			comments = nil
+			kept = append(kept, unit)
+		}
+		if len(comments) > 0 {
+			kept = append(kept, canonicalUnit{lines: comments})
+		}
+		if len(kept) > 0 {
+			deduped = append(deduped, kept)
+		}
+	}
+	return deduped
+}
+
+// canonicalValue quotes a value as written only if it needs quotes: double
+// quotes around whitespace or "#", none otherwise. Single quotes are kept
+// where they stop $ or \ from being expanded, and anything whose meaning
+// could change, such as a multiline value, is left as written.
+func canonicalValue(v string) string {
+	if strings.Contains(v, "\n") {
+		return v
+	}
+	inner, quote := v, byte(0)
+	if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+		inner, quote = v[1:len(v)-1], v[0]
+	}
+
+	switch {
+	case inner == "":
+		return ""
+	case quote == '\'' && strings.ContainsAny(inner, `$\`):
+		return v
+	case inner[0] == '"' || inner[0] == '\'' || strings.HasSuffix(inner, `\`):
+		// Dropping or adding quotes here would change how the value parses
+		return v
+	case strings.ContainsAny(inner, " \t#"):
+		return `"` + inner + `"`
+	default:
+		return inner
+	}
+}
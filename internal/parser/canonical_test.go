@@ -0,0 +1,148 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestFormatCanonical(t *testing.T) {
+	input := "\r\n" +
+		"  # Database\r\n" +
+		"DB_HOST = localhost   \r\n" +
+		"export DB_NAME='app'\r\n" +
+		"DB_PASS='pa$$word'\r\n" +
+		"\r\n\r\n\r\n" +
+		"GREETING=hello world\r\n" +
+		"QUOTED=\"plain\"\r\n" +
+		"CERT=\"-----BEGIN CERTIFICATE-----\r\n" +
+		"  MIIB  \r\n" +
+		"-----END CERTIFICATE-----\"   \r\n" +
+		"EMPTY=''\r\n" +
+		"not an assignment\r\n"
+
+	want := "# Database\n" +
+		"DB_HOST=localhost\n" +
+		"export DB_NAME=app\n" +
+		"DB_PASS='pa$$word'\n" +
+		"\n" +
+		"GREETING=\"hello world\"\n" +
+		"QUOTED=plain\n" +
+		"CERT=\"-----BEGIN CERTIFICATE-----\n" +
+		"  MIIB  \n" +
+		"-----END CERTIFICATE-----\"\n" +
+		"EMPTY=\n" +
+		"not an assignment\n"
+
+	got, err := FormatCanonical(strings.NewReader(input), CanonicalOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+
+	again, _ := FormatCanonical(strings.NewReader(got), CanonicalOptions{})
+	if again != got {
+		t.Errorf("expected formatting to be idempotent, got:\n%s", again)
+	}
+}
+
+func TestFormatCanonical_SortKeys(t *testing.T) {
+	input := "PORT=8080\n# the app name\nAPP=demo\n# trailing note\n\nZONE=eu\nREGION=west\n"
+	want := "# the app name\nAPP=demo\nPORT=8080\n# trailing note\n\nREGION=west\nZONE=eu\n"
+
+	got, err := FormatCanonical(strings.NewReader(input), CanonicalOptions{SortKeys: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFormatCanonical_Dedupe(t *testing.T) {
+	input := "# old port\nPORT=3000\nHOST=localhost\nexport PORT=8080\n\nDEBUG=true\n\nDEBUG=false\n"
+	want := "# old port\nHOST=localhost\nexport PORT=8080\n\nDEBUG=false\n"
+
+	got, err := FormatCanonical(strings.NewReader(input), CanonicalOptions{Dedupe: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCanonicalValue(t *testing.T) {
+	tests := []struct{ value, want string }{
+		{`plain`, `plain`},
+		{`"plain"`, `plain`},
+		{`'plain'`, `plain`},
+		{`two words`, `"two words"`},
+		{`'two words'`, `"two words"`},
+		{`color#1`, `"color#1"`},
+		{`'$LITERAL'`, `'$LITERAL'`},
+		{`"'inner'"`, `"'inner'"`},
+		{`"unterminated`, `"unterminated`},
+		{`ends with\`, `ends with\`},
+		{`""`, ``},
+		{`${HOST}:5432`, `${HOST}:5432`},
+	}
+	for _, tt := range tests {
+		if got := canonicalValue(tt.value); got != tt.want {
+			t.Errorf("canonicalValue(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+// Formatting never changes what a file parses to
+func TestProperty_FormatCanonicalPreservesEntries(t *testing.T) {
+	parameters := gopter.DefaultTestParameters()
+	properties := gopter.NewProperties(parameters)
+
+	genValue := gen.OneGenOf(
+		gen.AlphaString(),
+		gen.AlphaString().Map(func(s string) string { return "'" + s + " x'" }),
+		gen.AlphaString().Map(func(s string) string { return `"` + s + `"` }),
+		gen.AlphaString().Map(func(s string) string { return s + " #" + s }),
+		gen.Const(`'$HOME'`),
+		gen.Const(`pa"ss`),
+	)
+
+	properties.Property("parsed entries are unchanged", prop.ForAll(
+		func(keys []string, values []string, sortKeys bool) bool {
+			var sb strings.Builder
+			for i, key := range keys {
+				if i < len(values) {
+					sb.WriteString("  " + key + " = " + values[i] + "  \n")
+				}
+				if i%3 == 0 {
+					sb.WriteString("\n# note\n")
+				}
+			}
+			before, err := ParseEnv(strings.NewReader(sb.String()))
+			if err != nil {
+				return false
+			}
+			formatted, err := FormatCanonical(strings.NewReader(sb.String()), CanonicalOptions{SortKeys: sortKeys})
+			if err != nil {
+				return false
+			}
+			after, err := ParseEnv(strings.NewReader(formatted))
+			if err != nil {
+				return false
+			}
+			return reflect.DeepEqual(before.Entries, after.Entries)
+		},
+		gen.SliceOf(gen.Identifier().Map(strings.ToUpper)),
+		gen.SliceOf(genValue),
+		gen.Bool(),
+	))
+
+	properties.TestingRun(t)
+}
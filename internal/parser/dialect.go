@@ -0,0 +1,233 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Dialects are the dotenv implementations whose parsing DialectValues
+// emulates: the dotenv packages of Node.js, Python (python-dotenv), Ruby
+// and Go (godotenv)
+var Dialects = []string{"node", "python", "ruby", "go"}
+
+// IsDialect reports whether name is one of Dialects
+func IsDialect(name string) bool {
+	for _, d := range Dialects {
+		if d == name {
+			return true
+		}
+	}
+	return false
+}
+
+// DialectValues returns the value each entry of the document loads as under
+// a dotenv dialect, in entry order. Each dialect only expands references to
+// keys defined earlier in the file, never to the process environment.
+func (d *Document) DialectValues(dialect string) []string {
+	defined := make(map[string]string)
+	values := make([]string, len(d.Entries))
+	for i, entry := range d.Entries {
+		_, raw, _ := strings.Cut(entry.Raw, "=")
+		raw = strings.TrimLeft(raw, " \t")
+
+		var value string
+		switch dialect {
+		case "node":
+			value = nodeValue(raw)
+		case "python":
+			value = pythonValue(raw, defined)
+		case "ruby":
+			value = rubyValue(raw, defined)
+		case "go":
+			value = goValue(raw, defined)
+		}
+		values[i] = value
+		defined[entry.Key] = value
+	}
+	return values
+}
+
+// quotedPrefix splits a value that opens with one of quotes into the text
+// inside the quotes and whatever follows the closing quote. A quote preceded
+// by a backslash does not close the value.
+func quotedPrefix(raw, quotes string) (inner, rest string, quote byte, ok bool) {
+	if raw == "" || !strings.ContainsRune(quotes, rune(raw[0])) {
+		return "", "", 0, false
+	}
+	quote = raw[0]
+	for i := 1; i < len(raw); i++ {
+		if raw[i] == quote && raw[i-1] != '\\' {
+			return raw[1:i], raw[i+1:], quote, true
+		}
+	}
+	return "", "", 0, false
+}
+
+// onlyComment reports whether the text after a closing quote is blank or a
+// comment, so the quoted form of the value applies
+func onlyComment(rest string) bool {
+	rest = strings.TrimSpace(rest)
+	return rest == "" || strings.HasPrefix(rest, "#")
+}
+
+// cutComment drops a comment from an unquoted value. With needSpace, a # only
+// starts a comment after whitespace; last picks the last such #, as godotenv
+// searches from the end of the line.
+func cutComment(raw string, needSpace, last bool) string {
+	for i := 0; i < len(raw); i++ {
+		j := i
+		if last {
+			j = len(raw) - 1 - i
+		}
+		if raw[j] != '#' {
+			continue
+		}
+		if !needSpace || (j > 0 && unicode.IsSpace(rune(raw[j-1]))) {
+			return raw[:j]
+		}
+	}
+	return raw
+}
+
+// nodeValue parses a value like the dotenv npm package: # starts a comment
+// anywhere in an unquoted value, and only \n and \r are escapes, in double
+// quotes. Nothing is expanded.
+func nodeValue(raw string) string {
+	if inner, rest, quote, ok := quotedPrefix(raw, "'\"`"); ok && onlyComment(rest) {
+		if quote == '"' {
+			inner = strings.NewReplacer(`\n`, "\n", `\r`, "\r").Replace(inner)
+		}
+		return inner
+	}
+	return strings.TrimSpace(cutComment(raw, false, false))
+}
+
+// pythonEscapes are the escapes python-dotenv decodes in double quotes
+var pythonEscapes = regexp.MustCompile(`\\[\\'"abfnrtv]`)
+
+// pythonControlChars are the characters python-dotenv's letter escapes stand for
+var pythonControlChars = map[byte]string{'a': "\a", 'b': "\b", 'f': "\f", 'n': "\n", 'r': "\r", 't': "\t", 'v': "\v"}
+
+// escapedChar is a backslash before any character but $, which the dotenv
+// gem and godotenv drop
+var escapedChar = regexp.MustCompile(`\\([^$])`)
+
+// pythonVariable is a ${NAME} or ${NAME:-default} reference; python-dotenv
+// leaves $NAME without braces alone
+var pythonVariable = regexp.MustCompile(`\$\{([^}:]*)(?::-([^}]*))?\}`)
+
+// pythonValue parses a value like python-dotenv: a comment needs whitespace
+// before the #, double quotes decode C-style escapes, and ${NAME} is
+// expanded outside single quotes, a default applying only to unset keys
+func pythonValue(raw string, defined map[string]string) string {
+	value := ""
+	if inner, _, quote, ok := quotedPrefix(raw, `'"`); ok {
+		if quote == '\'' {
+			return strings.NewReplacer(`\\`, `\`, `\'`, `'`).Replace(inner)
+		}
+		value = pythonEscapes.ReplaceAllStringFunc(inner, func(escape string) string {
+			if decoded, ok := pythonControlChars[escape[1]]; ok {
+				return decoded
+			}
+			return escape[1:]
+		})
+	} else {
+		value = strings.TrimRightFunc(cutComment(raw, true, false), unicode.IsSpace)
+	}
+	return pythonVariable.ReplaceAllStringFunc(value, func(ref string) string {
+		m := pythonVariable.FindStringSubmatch(ref)
+		if v, ok := defined[m[1]]; ok {
+			return v
+		}
+		return m[2]
+	})
+}
+
+// rubyVariable is a $NAME or ${NAME} reference as the dotenv gem matches it;
+// the closing brace is optional, so ${NAME:-default} keeps ":-default}"
+var rubyVariable = regexp.MustCompile(`(?i)(\\)?\$(\{)?([A-Z0-9_]+)?(\})?`)
+
+// rubyCommand is a $(command) substitution, which the dotenv gem runs
+var rubyCommand = regexp.MustCompile(`(\\)?\$\(([^()]*)\)`)
+
+// rubyValue parses a value like the dotenv gem: # starts a comment anywhere
+// in an unquoted value, backslashes escape any character but $, and outside
+// single quotes $NAME, ${NAME} and $(command) are substituted. Commands are
+// not run; their output is shown as a placeholder.
+func rubyValue(raw string, defined map[string]string) string {
+	value, quote := "", byte(0)
+	if inner, rest, q, ok := quotedPrefix(raw, `'"`); ok && onlyComment(rest) {
+		value, quote = inner, q
+	} else {
+		value = strings.TrimSpace(cutComment(raw, false, false))
+	}
+	if quote == '\'' {
+		return value
+	}
+	if quote == '"' {
+		value = strings.NewReplacer(`\n`, "\n", `\r`, "\r").Replace(value)
+	}
+	value = escapedChar.ReplaceAllString(value, "$1")
+	value = rubyCommand.ReplaceAllStringFunc(value, func(ref string) string {
+		m := rubyCommand.FindStringSubmatch(ref)
+		if m[1] != "" {
+			return ref[1:]
+		}
+		return "<output of " + m[2] + ">"
+	})
+	return rubyVariable.ReplaceAllStringFunc(value, func(ref string) string {
+		m := rubyVariable.FindStringSubmatch(ref)
+		switch {
+		case m[1] != "":
+			return ref[1:]
+		case m[3] != "":
+			return defined[m[3]]
+		default:
+			return ref
+		}
+	})
+}
+
+// goVariable is a reference as godotenv matches it: upper-case names only,
+// with optional braces
+var goVariable = regexp.MustCompile(`(\\)?(\$)(\()?\{?([A-Z0-9_]+)?\}?`)
+
+// goEscape is any backslash escape; godotenv decodes \n and \r
+var goEscape = regexp.MustCompile(`\\.`)
+
+// goValue parses a value like godotenv: a comment needs whitespace before
+// the last #, double quotes decode \n and \r and drop other backslashes,
+// and outside single quotes $NAME and ${NAME} are expanded
+func goValue(raw string, defined map[string]string) string {
+	value := ""
+	if inner, _, quote, ok := quotedPrefix(raw, `'"`); ok {
+		if quote == '\'' {
+			return inner
+		}
+		value = goEscape.ReplaceAllStringFunc(inner, func(escape string) string {
+			switch escape[1] {
+			case 'n':
+				return "\n"
+			case 'r':
+				return "\r"
+			default:
+				return escape
+			}
+		})
+		value = escapedChar.ReplaceAllString(value, "$1")
+	} else {
+		value = strings.TrimSpace(cutComment(raw, true, true))
+	}
+	return goVariable.ReplaceAllStringFunc(value, func(ref string) string {
+		m := goVariable.FindStringSubmatch(ref)
+		switch {
+		case m[1] == `\`:
+			return ref[1:]
+		case m[4] != "":
+			return defined[m[4]]
+		default:
+			return ref
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestDocument_DialectValues(t *testing.T) {
+	doc := writeDoc(t, strings.Join([]string{
+		`HOST=db`,
+		`COLOR=blue#1`,
+		`NOTE=a # comment`,
+		`TAB="a\tb"`,
+		`NEWLINE="a\nb"`,
+		`URL=${HOST:-x}/app`,
+		`FALLBACK=${UNSET:-x}`,
+		`PLAIN=$HOST`,
+		`RAW='$HOST'`,
+		`QUOTED="a" # comment`,
+		`TICKS=` + "`a`",
+		`CMD=$(whoami)`,
+	}, "\n"))
+
+	want := map[string][]string{
+		"node":   {"db", "blue", "a", `a\tb`, "a\nb", "${HOST:-x}/app", "${UNSET:-x}", "$HOST", "$HOST", "a", "a", "$(whoami)"},
+		"python": {"db", "blue#1", "a", "a\tb", "a\nb", "db/app", "x", "$HOST", "$HOST", "a", "`a`", "$(whoami)"},
+		"ruby":   {"db", "blue", "a", "atb", "a\nb", "db:-x}/app", ":-x}", "db", "$HOST", "a", "`a`", "<output of whoami>"},
+		"go":     {"db", "blue#1", "a", "atb", "a\nb", "db:-x}/app", ":-x}", "db", "$HOST", "a", "`a`", "$(whoami)"},
+	}
+	for _, dialect := range Dialects {
+		got := doc.DialectValues(dialect)
+		for i, entry := range doc.Entries {
+			if got[i] != want[dialect][i] {
+				t.Errorf("%s: %s = %q, want %q", dialect, entry.Key, got[i], want[dialect][i])
+			}
+		}
+	}
+}
+
+func TestIsDialect(t *testing.T) {
+	if !IsDialect("python") || IsDialect("php") {
+		t.Error("unexpected IsDialect result")
+	}
+}
+
+// Plain values without quotes, comments, escapes or references load the same
+// under every dialect
+func TestProperty_DialectsAgreeOnPlainValues(t *testing.T) {
+	properties := gopter.NewProperties(gopter.DefaultTestParameters())
+
+	properties.Property("plain values are identical", prop.ForAll(
+		func(value string) bool {
+			doc := &Document{Entries: []DocEntry{{Key: "KEY", Raw: "KEY=" + value}}}
+			for _, dialect := range Dialects {
+				if doc.DialectValues(dialect)[0] != value {
+					return false
+				}
+			}
+			return true
+		},
+		gen.AlphaString(),
+	))
+
+	properties.TestingRun(t)
+}
@@ -4,7 +4,7 @@ import (
 	"sort"
 	"strings"
 
-	"env-audit/internal/audit"
+	"env-audit/internal/redact"
 )
 
 // DiffResult contains the differences between two env files
@@ -12,6 +12,42 @@ type DiffResult struct {
 	Added   map[string]string    // keys in file2 but not in file1
 	Removed map[string]string    // keys in file1 but not in file2
 	Changed map[string][2]string // keys with different values [old, new]
+	Renamed map[string]string    // probable renames, old key -> new key, set by DetectRenames
+}
+
+// minRenameValueLength is the shortest value that can identify a rename;
+// shorter values such as ports and flags are shared by coincidence
+const minRenameValueLength = 8
+
+// DetectRenames turns a removed key and an added key holding the same value
+// into a probable rename. Only unambiguous pairs count: a value held by
+// several removed or several added keys is left as separate entries.
+func (d *DiffResult) DetectRenames() {
+	removedByValue := make(map[string][]string)
+	for key, value := range d.Removed {
+		if len(value) >= minRenameValueLength {
+			removedByValue[value] = append(removedByValue[value], key)
+		}
+	}
+	addedByValue := make(map[string][]string)
+	for key, value := range d.Added {
+		if len(value) >= minRenameValueLength {
+			addedByValue[value] = append(addedByValue[value], key)
+		}
+	}
+
+	for value, removed := range removedByValue {
+		added := addedByValue[value]
+		if len(removed) != 1 || len(added) != 1 {
+			continue
+		}
+		if d.Renamed == nil {
+			d.Renamed = make(map[string]string)
+		}
+		d.Renamed[removed[0]] = added[0]
+		delete(d.Removed, removed[0])
+		delete(d.Added, added[0])
+	}
 }
 
 // Diff compares two environment maps and returns their differences
@@ -41,9 +77,15 @@ func Diff(file1, file2 map[string]string) *DiffResult {
 	return result
 }
 
-// FormatDiff formats a DiffResult as a human-readable string with +/- prefixes.
+// FormatDiff formats a DiffResult as a human-readable string with +/-/~/> prefixes.
 // If redact is true, sensitive values are replaced with [REDACTED].
 func FormatDiff(result *DiffResult, redact bool) string {
+	return FormatDiffWith(result, redactorFor(redact))
+}
+
+// FormatDiffWith formats a DiffResult, masking sensitive values, and keys
+// that are secrets, through r. A nil Redactor leaves them untouched.
+func FormatDiffWith(result *DiffResult, r *redact.Redactor) string {
 	if result == nil {
 		return ""
 	}
@@ -57,32 +99,29 @@ func FormatDiff(result *DiffResult, redact bool) string {
 
 	// Format removed lines (-)
 	for _, key := range removedKeys {
-		val := redactValue(key, result.Removed[key], redact)
-		lines = append(lines, "- "+key+"="+val)
+		val := r.Value(key, result.Removed[key])
+		lines = append(lines, "- "+r.Key(key)+"="+val)
 	}
 
 	// Format added lines (+)
 	for _, key := range addedKeys {
-		val := redactValue(key, result.Added[key], redact)
-		lines = append(lines, "+ "+key+"="+val)
+		val := r.Value(key, result.Added[key])
+		lines = append(lines, "+ "+r.Key(key)+"="+val)
 	}
 
 	// Format changed lines (~)
 	for _, key := range changedKeys {
-		oldVal := redactValue(key, result.Changed[key][0], redact)
-		newVal := redactValue(key, result.Changed[key][1], redact)
-		lines = append(lines, "~ "+key+"="+oldVal+" -> "+newVal)
+		oldVal := r.Value(key, result.Changed[key][0])
+		newVal := r.Value(key, result.Changed[key][1])
+		lines = append(lines, "~ "+r.Key(key)+"="+oldVal+" -> "+newVal)
 	}
 
-	return strings.Join(lines, "\n")
-}
-
-// redactValue returns [REDACTED] if redact is true and key is sensitive
-func redactValue(key, value string, redact bool) string {
-	if redact && audit.IsSensitiveKey(key) {
-		return "[REDACTED]"
+	// Format renamed keys (>); the shared value is never shown
+	for _, oldKey := range sortedKeys(result.Renamed) {
+		lines = append(lines, "> "+r.Key(oldKey)+" -> "+r.Key(result.Renamed[oldKey])+" (renamed)")
 	}
-	return value
+
+	return strings.Join(lines, "\n")
 }
 
 // sortedKeys returns sorted keys from a map
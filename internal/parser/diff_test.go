@@ -440,3 +440,69 @@ func TestFormatDiff_NonSensitiveNotRedacted(t *testing.T) {
 	}
 }
 
+
+// DetectRenames SHALL only move entries: every removed and added key ends up
+// either where it was or in exactly one rename pair with an equal value.
+func TestProperty_DetectRenamesPreservesEntries(t *testing.T) {
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+	properties := gopter.NewProperties(parameters)
+
+	genKey := gen.AlphaString().SuchThat(func(s string) bool { return len(s) > 0 })
+	// A small value space makes shared values, and so renames, likely
+	genValue := gen.OneConstOf("", "short", "long-value-1", "long-value-2")
+	genEnvMap := gen.MapOf(genKey, genValue)
+
+	properties.Property("renames account for every removed and added key", prop.ForAll(
+		func(file1, file2 map[string]string) bool {
+			before := Diff(file1, file2)
+			removed := len(before.Removed)
+			added := len(before.Added)
+
+			result := Diff(file1, file2)
+			result.DetectRenames()
+
+			if len(result.Removed)+len(result.Renamed) != removed || len(result.Added)+len(result.Renamed) != added {
+				return false
+			}
+			for oldKey, newKey := range result.Renamed {
+				if file1[oldKey] != file2[newKey] || len(file1[oldKey]) < minRenameValueLength {
+					return false
+				}
+			}
+			return true
+		},
+		genEnvMap,
+		genEnvMap,
+	))
+
+	properties.TestingRun(t)
+}
+
+func TestDetectRenames(t *testing.T) {
+	result := Diff(
+		map[string]string{"DB_PASS": "hunter2-long", "PORT": "5432", "A": "shared-value", "B": "shared-value"},
+		map[string]string{"DATABASE_PASSWORD": "hunter2-long", "DB_PORT": "5432", "C": "shared-value"},
+	)
+	result.DetectRenames()
+
+	if len(result.Renamed) != 1 || result.Renamed["DB_PASS"] != "DATABASE_PASSWORD" {
+		t.Errorf("expected DB_PASS -> DATABASE_PASSWORD, got %v", result.Renamed)
+	}
+	// Trivial values are not evidence of a rename
+	if _, ok := result.Removed["PORT"]; !ok {
+		t.Error("expected PORT to stay removed")
+	}
+	// Ambiguous matches stay separate entries
+	if _, ok := result.Added["C"]; !ok {
+		t.Error("expected C to stay added")
+	}
+}
+
+func TestFormatDiff_RenameHidesValue(t *testing.T) {
+	result := &DiffResult{Renamed: map[string]string{"DB_PASS": "DATABASE_PASSWORD"}}
+	output := FormatDiff(result, true)
+	if output != "> DB_PASS -> DATABASE_PASSWORD (renamed)" {
+		t.Errorf("unexpected output %q", output)
+	}
+}
@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"env-audit/internal/fsutil"
+)
+
+// docsKey is an inline code span naming a variable, such as `DATABASE_URL`
+// or `PORT=3000`
+var docsKey = regexp.MustCompile("`([A-Z][A-Z0-9_]*)(?:=[^`]*)?`")
+
+// ParseDocsKeys reads the variables documented in a Markdown file, such as
+// ENVIRONMENT.md, and the line each is first mentioned on. A variable is
+// documented by an upper-case name in backticks; fenced code blocks are
+// skipped, as they hold examples rather than documentation.
+func ParseDocsKeys(path string) (map[string]int, error) {
+	file, err := fsutil.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	lines, err := readLines(file)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]int)
+	fenced := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			continue
+		}
+		for _, m := range docsKey.FindAllStringSubmatch(line, -1) {
+			if _, ok := keys[m[1]]; !ok {
+				keys[m[1]] = i + 1
+			}
+		}
+	}
+	return keys, nil
+}
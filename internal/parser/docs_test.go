@@ -0,0 +1,40 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDocsKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ENVIRONMENT.md")
+	os.WriteFile(path, []byte(strings.Join([]string{
+		"# Environment",
+		"",
+		"| Variable | Description |",
+		"| --- | --- |",
+		"| `DATABASE_URL` | Postgres connection string |",
+		"| `PORT` | HTTP port, see also `DATABASE_URL` |",
+		"",
+		"Set `LOG_LEVEL=debug` for verbose logs. `npm start` and `lower_case` are not variables.",
+		"",
+		"```bash",
+		"`IN_FENCE`",
+		"```",
+	}, "\n")), 0644)
+
+	keys, err := ParseDocsKeys(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]int{"DATABASE_URL": 5, "PORT": 6, "LOG_LEVEL": 8}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("ParseDocsKeys = %v, want %v", keys, want)
+	}
+
+	if _, err := ParseDocsKeys(filepath.Join(t.TempDir(), "missing.md")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
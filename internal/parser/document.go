@@ -0,0 +1,182 @@
+package parser
+
+import (
+	"strings"
+
+	"env-audit/internal/audit"
+	"env-audit/internal/fsutil"
+)
+
+// Document is a line-preserving model of an env file, used by commands that
+// rewrite files so comments and formatting survive
+type Document struct {
+	Entries  []DocEntry
+	Trailing []string // comment and malformed lines after the last entry
+}
+
+// DocEntry is one assignment line together with the comments above it
+type DocEntry struct {
+	Key      string
+	Raw      string   // the assignment as written, spanning several lines for a multiline value
+	Comments []string // comment lines directly preceding the entry
+	Line     int
+	Section  int // index of the blank-line separated block containing the entry
+}
+
+// ParseDocument reads an env file into a Document
+func ParseDocument(path string) (*Document, error) {
+	file, err := fsutil.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	doc := &Document{}
+	var pending []string
+	section := 0
+	sectionHasEntries := false
+	lines, err := readLines(file)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(lines); i++ {
+		lineNum := i + 1
+		raw := lines[i]
+		line := strings.TrimSpace(raw)
+
+		if line == "" {
+			if sectionHasEntries {
+				section++
+				sectionHasEntries = false
+			}
+			continue
+		}
+
+		idx := strings.Index(line, "=")
+		if strings.HasPrefix(line, "#") || idx == -1 {
+			pending = append(pending, raw)
+			continue
+		}
+
+		// Keep a multiline double-quoted value together with its key
+		if end := quotedValueEnd(lines, i, strings.TrimSpace(line[idx+1:])); end > i {
+			raw = strings.Join(lines[i:end+1], "\n")
+			i = end
+		}
+
+		doc.Entries = append(doc.Entries, DocEntry{
+			Key:      parseKey(line[:idx]),
+			Raw:      raw,
+			Comments: pending,
+			Line:     lineNum,
+			Section:  section,
+		})
+		pending = nil
+		sectionHasEntries = true
+	}
+
+	doc.Trailing = pending
+	return doc, nil
+}
+
+// Ordering returns the key order and section layout of the document
+func (d *Document) Ordering() audit.Ordering {
+	ordering := audit.Ordering{Section: make(map[string]int)}
+	for _, entry := range d.Entries {
+		if _, seen := ordering.Section[entry.Key]; seen {
+			continue
+		}
+		ordering.Keys = append(ordering.Keys, entry.Key)
+		ordering.Section[entry.Key] = entry.Section
+	}
+	return ordering
+}
+
+// ReorderLike returns the document's content rearranged to follow the key
+// order and sections of reference. Keys missing from reference keep their
+// relative order in a final section.
+func (d *Document) ReorderLike(reference audit.Ordering) string {
+	rank := make(map[string]int, len(reference.Keys))
+	for i, key := range reference.Keys {
+		rank[key] = i
+	}
+
+	// Group entries by reference section, preserving reference order within each
+	groups := make(map[int][]DocEntry)
+	var sectionOrder []int
+	var unknown []DocEntry
+	for _, key := range reference.Keys {
+		for _, entry := range d.Entries {
+			if entry.Key != key {
+				continue
+			}
+			section := reference.Section[key]
+			if _, ok := groups[section]; !ok {
+				sectionOrder = append(sectionOrder, section)
+			}
+			groups[section] = append(groups[section], entry)
+		}
+	}
+	for _, entry := range d.Entries {
+		if _, ok := rank[entry.Key]; !ok {
+			unknown = append(unknown, entry)
+		}
+	}
+
+	var blocks [][]DocEntry
+	for _, section := range sectionOrder {
+		blocks = append(blocks, groups[section])
+	}
+	if len(unknown) > 0 {
+		blocks = append(blocks, unknown)
+	}
+
+	var lines []string
+	for i, block := range blocks {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		for _, entry := range block {
+			lines = append(lines, entry.Comments...)
+			lines = append(lines, entry.Raw)
+		}
+	}
+	if len(d.Trailing) > 0 {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, d.Trailing...)
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// BlankValues empties the values of keys in content, the file the document
+// was parsed from, and leaves every other byte as written: comments, blank
+// lines, an "export " prefix and line endings survive. It returns the new
+// content and the assignments it emptied, in file order.
+func (d *Document) BlankValues(content string, keys map[string]bool) (string, []string) {
+	lines := strings.SplitAfter(content, "\n")
+	var blanked []string
+	// Later entries first, so replacing a multiline value keeps the line
+	// numbers of earlier ones valid
+	for i := len(d.Entries) - 1; i >= 0; i-- {
+		entry := d.Entries[i]
+		first, last := entry.Line-1, entry.Line-1+strings.Count(entry.Raw, "\n")
+		if !keys[entry.Key] || last >= len(lines) {
+			continue
+		}
+		assignment := lines[first][:strings.Index(lines[first], "=")+1]
+		ending := lines[last][len(strings.TrimRight(lines[last], "\r\n")):]
+		lines = append(lines[:first], append([]string{assignment + ending}, lines[last+1:]...)...)
+		blanked = append(blanked, strings.TrimSpace(entry.Raw))
+	}
+	for i, j := 0, len(blanked)-1; i < j; i, j = i+1, j-1 {
+		blanked[i], blanked[j] = blanked[j], blanked[i]
+	}
+	return strings.Join(lines, ""), blanked
+}
@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeDoc(t *testing.T, content string) *Document {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := ParseDocument(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return doc
+}
+
+func TestParseDocument_Sections(t *testing.T) {
+	doc := writeDoc(t, "# database\nDB_HOST=x\nDB_PORT=1\n\n\n# mail\nSMTP_HOST=y\n# trailing\n")
+
+	if len(doc.Entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(doc.Entries))
+	}
+	if doc.Entries[0].Comments[0] != "# database" || doc.Entries[0].Line != 2 {
+		t.Errorf("unexpected first entry: %+v", doc.Entries[0])
+	}
+	if doc.Entries[1].Section != 0 || doc.Entries[2].Section != 1 {
+		t.Errorf("unexpected sections: %+v", doc.Entries)
+	}
+	if len(doc.Trailing) != 1 || doc.Trailing[0] != "# trailing" {
+		t.Errorf("unexpected trailing lines: %v", doc.Trailing)
+	}
+}
+
+func TestDocument_ReorderLike(t *testing.T) {
+	example := writeDoc(t, "A=\nB=\n\nC=\n")
+	doc := writeDoc(t, "LOCAL=1\n# about c\nC=3\nB=2\nA=1\n")
+
+	got := doc.ReorderLike(example.Ordering())
+	want := "A=1\nB=2\n\n# about c\nC=3\n\nLOCAL=1\n"
+	if got != want {
+		t.Errorf("unexpected reorder:\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestParseDocument_MultilineValue(t *testing.T) {
+	doc := writeDoc(t, "KEY=\"line one\nKEY=not a key\"\nPORT=1\n")
+
+	if len(doc.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", doc.Entries)
+	}
+	if doc.Entries[0].Raw != "KEY=\"line one\nKEY=not a key\"" || doc.Entries[1].Line != 3 {
+		t.Errorf("expected the multiline value in one entry, got %+v", doc.Entries)
+	}
+}
+
+func TestDocument_BlankValues(t *testing.T) {
+	content := "# api\r\nexport API_KEY=sk_live_abc # prod\r\nPORT=8080\r\nPEM=\"-----BEGIN-----\nabc\n-----END-----\"\nTOKEN=t0k3n"
+	doc := writeDoc(t, content)
+
+	got, blanked := doc.BlankValues(content, map[string]bool{"API_KEY": true, "PEM": true, "TOKEN": true})
+	want := "# api\r\nexport API_KEY=\r\nPORT=8080\r\nPEM=\nTOKEN="
+	if got != want {
+		t.Errorf("unexpected content:\n%q\nwant\n%q", got, want)
+	}
+	wantBlanked := []string{"export API_KEY=sk_live_abc # prod", "PEM=\"-----BEGIN-----\nabc\n-----END-----\"", "TOKEN=t0k3n"}
+	if len(blanked) != len(wantBlanked) {
+		t.Fatalf("unexpected blanked assignments: %q", blanked)
+	}
+	for i := range wantBlanked {
+		if blanked[i] != wantBlanked[i] {
+			t.Errorf("blanked[%d] = %q, want %q", i, blanked[i], wantBlanked[i])
+		}
+	}
+}
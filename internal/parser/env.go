@@ -2,72 +2,342 @@ package parser
 
 import (
 	"bufio"
-	"os"
+	"fmt"
+	"io"
+	"sort"
 	"strings"
+	"unicode"
 
 	"env-audit/internal/audit"
+	"env-audit/internal/fsutil"
+	"env-audit/internal/redact"
 )
 
 // ParseResult contains parsed entries and any issues found
 type ParseResult struct {
 	Entries    map[string]string
+	Keys       []string // keys in order of first definition
 	Duplicates []string
-	Errors     []error
+	Errors     []ParseError        // lines that could not be read as an assignment, in file order
+	Lines      map[string]int      // line number of the effective definition of each key
+	Raw        map[string]string   // source line of the effective definition of each key, as written
+	Comments   map[string][]string // comment lines directly above each key's effective definition, without "#"
+	Unresolved map[string][]string // references that could not be expanded in each key's effective definition
+}
+
+// ParseError is a line that could not be read as an assignment. It records
+// the line's length rather than its text, which may hold a secret.
+type ParseError struct {
+	Line   int    // 1-based line number
+	Reason string // what is wrong with the line
+	Length int    // length of the line in bytes
+}
+
+// Reasons of a ParseError
+const (
+	ReasonNoSeparator  = "missing '=' separator"
+	ReasonEmptyKey     = "empty key"
+	ReasonUnterminated = "unterminated quoted value"
+)
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s (%d bytes)", e.Line, e.Reason, e.Length)
 }
 
 // ParseEnvFile reads and parses a .env file
 func ParseEnvFile(path string) (*ParseResult, error) {
-	file, err := os.Open(path)
+	return ParseEnvFileWith(path, ParseOptions{})
+}
+
+// ParseEnvFileWith reads and parses a .env file, expanding references if
+// opts asks for it
+func ParseEnvFileWith(path string, opts ParseOptions) (*ParseResult, error) {
+	file, err := fsutil.Open(path)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
+	return ParseEnvWith(file, opts)
+}
+
+// ParseEnv parses .env content from r
+func ParseEnv(r io.Reader) (*ParseResult, error) {
+	return ParseEnvWith(r, ParseOptions{})
+}
+
+// ParseEnvWith parses .env content from r with opts
+func ParseEnvWith(r io.Reader, opts ParseOptions) (*ParseResult, error) {
 	result := &ParseResult{
 		Entries:    make(map[string]string),
 		Duplicates: []string{},
-		Errors:     []error{},
+		Errors:     []ParseError{},
+		Lines:      make(map[string]int),
+		Raw:        make(map[string]string),
+		Comments:   make(map[string][]string),
+		Unresolved: make(map[string][]string),
+	}
+
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
 	}
 
 	seen := make(map[string]bool)
-	scanner := bufio.NewScanner(file)
+	var comments []string
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+	for i := 0; i < len(lines); i++ {
+		lineNum := i + 1
+		raw := lines[i]
+		line := strings.TrimSpace(raw)
 
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "#") {
+		// Skip empty lines and comments, remembering comments for the next key
+		if line == "" {
+			comments = nil
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			comments = append(comments, strings.TrimSpace(strings.TrimPrefix(line, "#")))
 			continue
 		}
 
 		// Find the first = sign
 		idx := strings.Index(line, "=")
 		if idx == -1 {
+			result.Errors = append(result.Errors, ParseError{Line: lineNum, Reason: ReasonNoSeparator, Length: len(raw)})
+			comments = nil
 			continue // Skip malformed lines
 		}
 
-		key := strings.TrimSpace(line[:idx])
+		key := parseKey(line[:idx])
 		value := strings.TrimSpace(line[idx+1:])
+		if key == "" {
+			result.Errors = append(result.Errors, ParseError{Line: lineNum, Reason: ReasonEmptyKey, Length: len(raw)})
+			comments = nil
+			continue
+		}
+
+		// A double-quoted value may continue over the following lines
+		value = cutInlineComment(value)
+		if end := quotedValueEnd(lines, i, value); end > i {
+			last := strings.TrimRightFunc(lines[end], unicode.IsSpace)
+			last = last[:closingQuote(last)+1]
+			value = strings.Join(append(append([]string{value}, lines[i+1:end]...), last), "\n")
+			raw = strings.Join(lines[i:end+1], "\n")
+			i = end
+		} else if strings.HasPrefix(value, `"`) && quoteEnd(value[1:]) < 0 {
+			// Parsed as written, quote included
+			result.Errors = append(result.Errors, ParseError{Line: lineNum, Reason: ReasonUnterminated, Length: len(raw)})
+		}
 
-		// Handle quoted values
+		// Handle quoted values; single quotes keep references literal
+		singleQuoted := len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\''
 		value = unquote(value)
+		delete(result.Unresolved, key)
+		if opts.Expand && !singleQuoted {
+			e := &expander{defined: result.Entries, lookupEnv: opts.LookupEnv}
+			value = e.expand(value)
+			if len(e.unresolved) > 0 {
+				result.Unresolved[key] = dedupe(e.unresolved)
+			}
+		}
 
 		// Track duplicates
 		if seen[key] {
 			result.Duplicates = append(result.Duplicates, key)
+		} else {
+			result.Keys = append(result.Keys, key)
 		}
 		seen[key] = true
 
 		result.Entries[key] = value
+		result.Lines[key] = lineNum
+		result.Raw[key] = raw
+		if len(comments) > 0 {
+			result.Comments[key] = comments
+		} else {
+			delete(result.Comments, key)
+		}
+		comments = nil
 	}
 
+	return result, nil
+}
+
+// parseKey returns the key of an assignment, dropping the "export" keyword
+// of shell-sourceable files
+func parseKey(s string) string {
+	key := strings.TrimSpace(s)
+	if hasExport(key) {
+		return strings.TrimSpace(strings.TrimPrefix(key, "export"))
+	}
+	return key
+}
+
+// hasExport reports whether the key part of an assignment starts with the
+// "export" keyword
+func hasExport(s string) bool {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(s), "export")
+	return ok && rest != strings.TrimLeftFunc(rest, unicode.IsSpace)
+}
+
+// readLines reads all lines of r
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
 	if err := scanner.Err(); err != nil {
 		return nil, err
 	}
+	return lines, nil
+}
 
-	return result, nil
+// quotedValueEnd returns the index of the line closing a double-quoted value
+// that opens on lines[i] without closing there, such as a PEM key or a JSON
+// blob. It returns i when the value's quote is closed on its own line, or
+// never closed, in which case the line is parsed as written.
+func quotedValueEnd(lines []string, i int, value string) int {
+	if !strings.HasPrefix(value, `"`) || quoteEnd(value[1:]) >= 0 {
+		return i
+	}
+	for j := i + 1; j < len(lines); j++ {
+		if closingQuote(lines[j]) >= 0 {
+			return j
+		}
+	}
+	return i
+}
+
+// cutInlineComment drops blanks and a "# comment" after the closing quote
+// of a double-quoted value, so that A="foo" # note is foo
+func cutInlineComment(value string) string {
+	if !strings.HasPrefix(value, `"`) {
+		return value
+	}
+	end := quoteEnd(value[1:])
+	if end < 0 || !isTrailingComment(value[end+2:]) {
+		return value
+	}
+	return value[:end+2]
+}
+
+// quoteEnd returns the index of the first double quote in s that is not
+// escaped, or -1
+func quoteEnd(s string) int {
+	for k := 0; k < len(s); k++ {
+		if s[k] == '"' && !escaped(s, k) {
+			return k
+		}
+	}
+	return -1
+}
+
+// closingQuote returns the index of the last double quote of a line ending
+// a multiline value, which only blanks or a "# comment" may follow, or -1
+func closingQuote(line string) int {
+	for k := len(line) - 1; k >= 0; k-- {
+		if line[k] == '"' && !escaped(line, k) {
+			if isTrailingComment(line[k+1:]) {
+				return k
+			}
+			return -1
+		}
+	}
+	return -1
+}
+
+// escaped reports whether the byte at k follows an odd run of backslashes
+func escaped(s string, k int) bool {
+	backslashes := 0
+	for k--; k >= 0 && s[k] == '\\'; k-- {
+		backslashes++
+	}
+	return backslashes%2 == 1
+}
+
+// isTrailingComment reports whether s, following a closing quote, is only
+// blanks or a "# comment"
+func isTrailingComment(s string) bool {
+	s = strings.TrimSpace(s)
+	return s == "" || strings.HasPrefix(s, "#")
+}
+
+// Locations returns the file and line of each key's effective definition
+func (r *ParseResult) Locations(path string) map[string]audit.Location {
+	locations := make(map[string]audit.Location, len(r.Lines))
+	for key, line := range r.Lines {
+		locations[key] = audit.Location{File: path, Line: line}
+	}
+	return locations
 }
 
+// Diagnostics returns the parse errors as diagnostics of the file at path
+func (r *ParseResult) Diagnostics(path string) []audit.Diagnostic {
+	diagnostics := make([]audit.Diagnostic, 0, len(r.Errors))
+	for _, e := range r.Errors {
+		diagnostics = append(diagnostics, audit.Diagnostic{File: path, Line: e.Line, Reason: e.Reason, Length: e.Length})
+	}
+	return diagnostics
+}
+
+// Annotation returns the value of a "# name: value" comment directly above key
+func (r *ParseResult) Annotation(key, name string) (string, bool) {
+	prefix := name + ":"
+	for _, comment := range r.Comments[key] {
+		if strings.HasPrefix(comment, prefix) {
+			return strings.TrimSpace(strings.TrimPrefix(comment, prefix)), true
+		}
+	}
+	return "", false
+}
+
+// Annotations are the names of machine-readable "# name: value" comments,
+// which are not part of a key's description
+var Annotations = []string{"last_rotated", "source"}
+
+// Descriptions returns the comment block above each key's effective
+// definition as its description, one line per comment line, without
+// annotations. Keys without comments are left out.
+func (r *ParseResult) Descriptions() map[string]string {
+	descriptions := make(map[string]string)
+	for key, comments := range r.Comments {
+		var lines []string
+		for _, comment := range comments {
+			if !isAnnotation(comment) {
+				lines = append(lines, comment)
+			}
+		}
+		if desc := strings.TrimSpace(strings.Join(lines, "\n")); desc != "" {
+			descriptions[key] = desc
+		}
+	}
+	return descriptions
+}
+
+// isAnnotation reports whether a comment line is one of the Annotations
+func isAnnotation(comment string) bool {
+	for _, name := range Annotations {
+		if strings.HasPrefix(comment, name+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// dedupe removes repeated names, keeping the first occurrence of each
+func dedupe(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	var unique []string
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	return unique
+}
 
 // unquote removes surrounding quotes from a value
 func unquote(s string) string {
@@ -79,15 +349,96 @@ func unquote(s string) string {
 	return s
 }
 
-// FormatEnv outputs config as KEY=VALUE lines with optional redaction
+// Entry is a single variable with its effective value
+type Entry struct {
+	Key   string
+	Value string
+}
+
+// Ordered returns the entries in the order their keys were first defined,
+// each with its effective value
+func (r *ParseResult) Ordered() []Entry {
+	entries := make([]Entry, 0, len(r.Keys))
+	for _, key := range r.Keys {
+		entries = append(entries, Entry{Key: key, Value: r.Entries[key]})
+	}
+	return entries
+}
+
+// SortedEntries returns the entries of env sorted by key
+func SortedEntries(env map[string]string) []Entry {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	entries := make([]Entry, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, Entry{Key: key, Value: env[key]})
+	}
+	return entries
+}
+
+// FormatEnv outputs config as KEY=VALUE lines sorted by key with optional
+// redaction
 func FormatEnv(entries map[string]string, redact bool) string {
+	return FormatEnvWith(entries, redactorFor(redact))
+}
+
+// FormatEnvWith outputs config as KEY=VALUE lines sorted by key, masking
+// values through r. A nil Redactor leaves values untouched.
+func FormatEnvWith(entries map[string]string, r *redact.Redactor) string {
+	return FormatEntries(SortedEntries(entries), r)
+}
+
+// FormatEntries outputs entries as KEY=VALUE lines in the given order,
+// masking values, and keys that are secrets, through r. A nil Redactor
+// leaves them untouched.
+func FormatEntries(entries []Entry, r *redact.Redactor) string {
 	var lines []string
-	for key, value := range entries {
-		if redact && audit.IsSensitiveKey(key) {
-			lines = append(lines, key+"=[REDACTED]")
-		} else {
-			lines = append(lines, key+"="+value)
-		}
+	for _, entry := range entries {
+		lines = append(lines, r.Key(entry.Key)+"="+r.Value(entry.Key, entry.Value))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FormatShellWith outputs config as "export KEY=VALUE" lines sorted by key,
+// quoting values so the output can be sourced by a POSIX shell. Values are
+// masked through r; a nil Redactor leaves them untouched.
+func FormatShellWith(entries map[string]string, r *redact.Redactor) string {
+	return FormatShellEntries(SortedEntries(entries), r)
+}
+
+// FormatShellEntries is FormatShellWith for entries in the given order
+func FormatShellEntries(entries []Entry, r *redact.Redactor) string {
+	var lines []string
+	for _, entry := range entries {
+		lines = append(lines, "export "+r.Key(entry.Key)+"="+ShellQuote(r.Value(entry.Key, entry.Value)))
 	}
 	return strings.Join(lines, "\n")
 }
+
+// ShellQuote single-quotes s unless it only holds characters a shell leaves
+// alone
+func ShellQuote(s string) string {
+	safe := s != ""
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune("_@%+=:,./-", c)) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// redactorFor returns the default Redactor when enabled is true, nil otherwise
+func redactorFor(enabled bool) *redact.Redactor {
+	if !enabled {
+		return nil
+	}
+	return redact.Default()
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -327,8 +328,13 @@ func TestProperty_SensitiveValueRedaction(t *testing.T) {
 					if strings.Contains(output, value) {
 						return false
 					}
-					// [REDACTED] should appear for this key
-					if !strings.Contains(output, key+"=[REDACTED]") {
+					// [REDACTED] should appear for this key, itself masked if it
+					// looks like a secret
+					shown := key
+					if audit.IsSecretKey(key) {
+						shown = audit.MaskKey(key)
+					}
+					if !strings.Contains(output, shown+"=[REDACTED]") {
 						return false
 					}
 				}
@@ -341,3 +347,195 @@ func TestProperty_SensitiveValueRedaction(t *testing.T) {
 
 	properties.TestingRun(t)
 }
+
+func TestParseEnv_Comments(t *testing.T) {
+	content := "# header\n\n# Stripe live key\n# last_rotated: 2026-06-01\nSTRIPE_KEY=sk\nPORT=3000\n# stale\n\nAPP=1\n"
+	result, err := ParseEnv(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Comments["STRIPE_KEY"]; len(got) != 2 || got[0] != "Stripe live key" {
+		t.Errorf("expected comments directly above STRIPE_KEY, got %v", got)
+	}
+	if _, ok := result.Comments["PORT"]; ok {
+		t.Error("expected no comments for PORT")
+	}
+	if _, ok := result.Comments["APP"]; ok {
+		t.Error("expected a blank line to detach comments")
+	}
+	if date, ok := result.Annotation("STRIPE_KEY", "last_rotated"); !ok || date != "2026-06-01" {
+		t.Errorf("expected last_rotated annotation, got %q", date)
+	}
+}
+
+func TestParseEnv_RawLines(t *testing.T) {
+	result, err := ParseEnv(strings.NewReader("# header\n  PORT = 8080\nNAME=\"app\"\nPORT=9090\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Lines["PORT"] != 4 || result.Raw["PORT"] != "PORT=9090" {
+		t.Errorf("expected the effective PORT definition, got line %d %q", result.Lines["PORT"], result.Raw["PORT"])
+	}
+	if result.Lines["NAME"] != 3 || result.Raw["NAME"] != `NAME="app"` {
+		t.Errorf("expected NAME's raw line, got line %d %q", result.Lines["NAME"], result.Raw["NAME"])
+	}
+}
+
+func TestParseEnv_MultilineQuotedValues(t *testing.T) {
+	content := "CERT=\"-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\"\nCONFIG=\"{\n  \\\"debug\\\": true\n}\"\nPORT=8080\n"
+	result, err := ParseEnv(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Entries["CERT"]; got != "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----" {
+		t.Errorf("unexpected CERT value: %q", got)
+	}
+	if got := result.Entries["CONFIG"]; got != "{\n  \\\"debug\\\": true\n}" {
+		t.Errorf("unexpected CONFIG value: %q", got)
+	}
+	if result.Lines["CONFIG"] != 4 || result.Lines["PORT"] != 7 {
+		t.Errorf("expected values to keep their opening line, got %v", result.Lines)
+	}
+	if _, ok := result.Entries["MIIB"]; ok {
+		t.Error("expected continuation lines not to be parsed as keys")
+	}
+}
+
+func TestParseEnv_QuotedValueWithInlineComment(t *testing.T) {
+	content := "A=\"foo\" # comment\nB=1\nAPI_KEY=secret\nC=\"bar\"\nD=2\nE=\"baz\"   \n"
+	result, err := ParseEnv(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{"A": "foo", "B": "1", "API_KEY": "secret", "C": "bar", "D": "2", "E": "baz"}
+	for key, value := range want {
+		if got, ok := result.Entries[key]; !ok || got != value {
+			t.Errorf("expected %s=%q, got %q (present: %v)", key, value, got, ok)
+		}
+	}
+	if result.Lines["B"] != 2 || result.Lines["D"] != 5 {
+		t.Errorf("expected keys after the comment on their own lines, got %v", result.Lines)
+	}
+	if len(result.Errors) != 0 {
+		t.Errorf("expected no parse errors, got %v", result.Errors)
+	}
+}
+
+func TestParseEnv_MultilineValueWithInlineComment(t *testing.T) {
+	content := "KEY=\"line one\nline two\" # rotated yearly\nPORT=8080\n"
+	result, err := ParseEnv(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Entries["KEY"]; got != "line one\nline two" {
+		t.Errorf("unexpected KEY value: %q", got)
+	}
+	if result.Entries["PORT"] != "8080" || result.Lines["PORT"] != 3 {
+		t.Errorf("expected PORT after the value, got %v", result.Entries)
+	}
+}
+
+func TestParseEnv_UnterminatedQuote(t *testing.T) {
+	result, err := ParseEnv(strings.NewReader("NAME=\"app\nPORT=8080\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Entries["NAME"] != `"app` || result.Entries["PORT"] != "8080" {
+		t.Errorf("expected an unclosed quote to stay on its line, got %v", result.Entries)
+	}
+}
+
+func TestParseEnv_ExportPrefix(t *testing.T) {
+	result, err := ParseEnv(strings.NewReader("export PORT=8080\nexport\tHOST=\"db\"\nexport=1\nexporter=2\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{"PORT": "8080", "HOST": "db", "export": "1", "exporter": "2"}
+	for key, value := range want {
+		if result.Entries[key] != value {
+			t.Errorf("expected %s=%s, got %v", key, value, result.Entries)
+		}
+	}
+}
+
+func TestFormatShellWith(t *testing.T) {
+	output := FormatShellWith(map[string]string{
+		"PORT":     "8080",
+		"GREETING": "it's here",
+		"EMPTY":    "",
+		"URL":      "https://example.com/a?b=1",
+	}, nil)
+	want := "export EMPTY=''\nexport GREETING='it'\\''s here'\nexport PORT=8080\nexport URL='https://example.com/a?b=1'"
+	if output != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", output, want)
+	}
+}
+
+func TestParseResult_Ordered(t *testing.T) {
+	result, err := ParseEnv(strings.NewReader("PORT=8080\nAPP_NAME=demo\n\nexport HOST=localhost\nPORT=9090\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Entry{{"PORT", "9090"}, {"APP_NAME", "demo"}, {"HOST", "localhost"}}
+	if got := result.Ordered(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestFormatEnv_Sorted(t *testing.T) {
+	env := map[string]string{"PORT": "8080", "APP_NAME": "demo", "HOST": "localhost", "DEBUG": "true"}
+	want := "APP_NAME=demo\nDEBUG=true\nHOST=localhost\nPORT=8080"
+	for i := 0; i < 10; i++ {
+		if output := FormatEnv(env, false); output != want {
+			t.Fatalf("expected sorted output, got:\n%s", output)
+		}
+	}
+}
+
+func TestParseResult_Descriptions(t *testing.T) {
+	result, err := ParseEnv(strings.NewReader("# Stripe live key\n# last_rotated: 2026-06-01\nSTRIPE_KEY=sk\n# last_rotated: 2026-06-01\nTOKEN=t\nPORT=1\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	descriptions := result.Descriptions()
+	if descriptions["STRIPE_KEY"] != "Stripe live key" {
+		t.Errorf("expected the comment without annotations, got %q", descriptions["STRIPE_KEY"])
+	}
+	if _, ok := descriptions["TOKEN"]; ok {
+		t.Error("expected no description when only annotations precede a key")
+	}
+	if _, ok := descriptions["PORT"]; ok {
+		t.Error("expected no description for PORT")
+	}
+}
+
+func TestParseEnv_Errors(t *testing.T) {
+	content := "APP=web\nsk_live_pasted_without_key\n=orphan\nTOKEN=\"unclosed\n"
+	result, err := ParseEnv(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []ParseError{
+		{Line: 2, Reason: ReasonNoSeparator, Length: 26},
+		{Line: 3, Reason: ReasonEmptyKey, Length: 7},
+		{Line: 4, Reason: ReasonUnterminated, Length: 15},
+	}
+	if !reflect.DeepEqual(result.Errors, want) {
+		t.Errorf("expected %v, got %v", want, result.Errors)
+	}
+	if _, ok := result.Entries[""]; ok {
+		t.Error("expected a line with an empty key to be skipped")
+	}
+	if result.Entries["TOKEN"] != `"unclosed` {
+		t.Errorf("expected the unterminated value parsed as written, got %q", result.Entries["TOKEN"])
+	}
+	if msg := result.Errors[0].Error(); strings.Contains(msg, "sk_live") || msg != "line 2: missing '=' separator (26 bytes)" {
+		t.Errorf("unexpected error text %q", msg)
+	}
+
+	diagnostics := result.Diagnostics(".env")
+	if len(diagnostics) != 3 || diagnostics[0].File != ".env" || diagnostics[2].Line != 4 {
+		t.Errorf("unexpected diagnostics %v", diagnostics)
+	}
+}
@@ -0,0 +1,137 @@
+package parser
+
+import "strings"
+
+// ParseOptions controls optional parsing behaviour
+type ParseOptions struct {
+	// Expand resolves ${VAR}, ${VAR:-default}, ${VAR-default} and $VAR
+	// references against keys defined earlier in the file, like dotenv-expand.
+	// Single-quoted values and \$ are left literal, and so is a bare $VAR
+	// that resolves to nothing, as in a password like pa$$w0rd.
+	Expand bool
+	// LookupEnv resolves references to keys the file has not defined (yet),
+	// e.g. os.LookupEnv; nil leaves them unresolved
+	LookupEnv func(key string) (string, bool)
+}
+
+// expander expands references in values as a file is parsed
+type expander struct {
+	defined    map[string]string
+	lookupEnv  func(string) (string, bool)
+	unresolved []string
+}
+
+// lookup resolves a referenced key, preferring keys defined in the file
+func (e *expander) lookup(name string) (string, bool) {
+	if value, ok := e.defined[name]; ok {
+		return value, true
+	}
+	if e.lookupEnv != nil {
+		return e.lookupEnv(name)
+	}
+	return "", false
+}
+
+// expand replaces the references in value. Unresolvable ${VAR} references
+// expand to "" and are recorded in e.unresolved; an unresolvable bare $VAR
+// is kept as written.
+func (e *expander) expand(value string) string {
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c == '\\' && i+1 < len(value) && value[i+1] == '$' {
+			sb.WriteByte('$')
+			i++
+			continue
+		}
+		if c != '$' || i+1 >= len(value) {
+			sb.WriteByte(c)
+			continue
+		}
+
+		if value[i+1] == '{' {
+			end := matchingBrace(value, i+2)
+			if end < 0 {
+				sb.WriteByte(c)
+				continue
+			}
+			sb.WriteString(e.expandBraced(value[i+2 : end]))
+			i = end
+			continue
+		}
+
+		n := nameLength(value[i+1:])
+		if n == 0 {
+			sb.WriteByte(c)
+			continue
+		}
+		name := value[i+1 : i+1+n]
+		if resolved, ok := e.lookup(name); ok {
+			sb.WriteString(resolved)
+		} else {
+			sb.WriteString(value[i : i+1+n])
+		}
+		i += n
+	}
+	return sb.String()
+}
+
+// expandBraced resolves the inside of ${...}: NAME, NAME:-default (default
+// when unset or empty) or NAME-default (default when unset). The default may
+// itself contain references.
+func (e *expander) expandBraced(inner string) string {
+	n := nameLength(inner)
+	name, rest := inner[:n], inner[n:]
+	if name == "" {
+		return "${" + inner + "}"
+	}
+	resolved, ok := e.lookup(name)
+	switch {
+	case strings.HasPrefix(rest, ":-"):
+		if !ok || resolved == "" {
+			return e.expand(rest[2:])
+		}
+	case strings.HasPrefix(rest, "-"):
+		if !ok {
+			return e.expand(rest[1:])
+		}
+	case rest != "":
+		// Unsupported modifier, keep it as written
+		return "${" + inner + "}"
+	default:
+		if !ok {
+			e.unresolved = append(e.unresolved, name)
+		}
+	}
+	return resolved
+}
+
+// matchingBrace returns the index of the "}" closing a "${" whose contents
+// start at start, allowing nested ${...} in defaults, or -1
+func matchingBrace(s string, start int) int {
+	depth := 1
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// nameLength returns the length of the variable name at the start of s
+func nameLength(s string) int {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (i > 0 && c >= '0' && c <= '9') {
+			continue
+		}
+		return i
+	}
+	return len(s)
+}
@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestParseEnvWith_Expand(t *testing.T) {
+	content := strings.Join([]string{
+		"HOST=db.internal",
+		"PORT=5432",
+		"URL=postgres://${HOST}:$PORT/app",
+		"LITERAL='${HOST}'",
+		`ESCAPED=cost \$5`,
+		"FALLBACK=${MISSING:-localhost}",
+		"EMPTY=",
+		"DASH_DEFAULT=${EMPTY-unused}",
+		"COLON_DEFAULT=${EMPTY:-used}",
+		"NESTED=${MISSING:-${HOST}}",
+		"LATER=${DEFINED_BELOW}",
+		"BARE_LATER=$DEFINED_BELOW",
+		"DEFINED_BELOW=x",
+		"FROM_OS=${HOME_DIR}",
+		"PRICE=$5 and $",
+		"PASSWORD=pa$$w0rd",
+	}, "\n")
+	lookup := func(key string) (string, bool) {
+		if key == "HOME_DIR" {
+			return "/home/app", true
+		}
+		return "", false
+	}
+
+	result, err := ParseEnvWith(strings.NewReader(content), ParseOptions{Expand: true, LookupEnv: lookup})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{
+		"URL":           "postgres://db.internal:5432/app",
+		"LITERAL":       "${HOST}",
+		"ESCAPED":       "cost $5",
+		"FALLBACK":      "localhost",
+		"DASH_DEFAULT":  "",
+		"COLON_DEFAULT": "used",
+		"NESTED":        "db.internal",
+		"LATER":         "",
+		"BARE_LATER":    "$DEFINED_BELOW",
+		"PASSWORD":      "pa$$w0rd",
+		"FROM_OS":       "/home/app",
+		"PRICE":         "$5 and $",
+	}
+	for key, value := range want {
+		if got := result.Entries[key]; got != value {
+			t.Errorf("%s: expected %q, got %q", key, value, got)
+		}
+	}
+	if want := map[string][]string{"LATER": {"DEFINED_BELOW"}}; !reflect.DeepEqual(result.Unresolved, want) {
+		t.Errorf("expected only LATER unresolved, got %v", result.Unresolved)
+	}
+}
+
+func TestParseEnv_NoExpandByDefault(t *testing.T) {
+	result, err := ParseEnv(strings.NewReader("A=1\nB=${A}\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Entries["B"] != "${A}" || len(result.Unresolved) != 0 {
+		t.Errorf("expected references kept literally, got %q %v", result.Entries["B"], result.Unresolved)
+	}
+}
+
+func TestParseEnvWith_RedefinitionClearsUnresolved(t *testing.T) {
+	result, _ := ParseEnvWith(strings.NewReader("A=${NOPE}\nA=fixed\n"), ParseOptions{Expand: true})
+	if len(result.Unresolved) != 0 {
+		t.Errorf("expected the effective definition to decide, got %v", result.Unresolved)
+	}
+}
+
+// Values without "$" or "\" are never changed by expansion
+func TestProperty_ExpandKeepsPlainValues(t *testing.T) {
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("plain values are unchanged", prop.ForAll(
+		func(value string) bool {
+			e := &expander{defined: map[string]string{}}
+			return e.expand(value) == value && len(e.unresolved) == 0
+		},
+		gen.AnyString().Map(func(s string) string {
+			return strings.NewReplacer("$", "", "\\", "").Replace(s)
+		}),
+	))
+
+	properties.TestingRun(t)
+}
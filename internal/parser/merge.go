@@ -0,0 +1,78 @@
+package parser
+
+import "env-audit/internal/audit"
+
+// MergedEnv is the effective environment produced by layering several env files
+type MergedEnv struct {
+	Entries    map[string]string
+	Duplicates []string                    // keys defined more than once within a single file
+	Locations  map[string]audit.Location   // where each effective value was defined
+	Overrides  map[string][]audit.Location // definitions of overridden keys, highest precedence first
+	Unresolved map[string][]string         // references that could not be expanded in each effective value
+}
+
+// MergeFiles parses paths in order and layers them so later files override
+// earlier ones, recording the origin of every effective value
+func MergeFiles(paths []string) (*MergedEnv, error) {
+	return MergeFilesWith(paths, ParseOptions{})
+}
+
+// MergeFilesWith is MergeFiles with parse options. When expanding, a file
+// may reference keys from the files layered below it.
+func MergeFilesWith(paths []string, opts ParseOptions) (*MergedEnv, error) {
+	merged := &MergedEnv{
+		Entries:    make(map[string]string),
+		Locations:  make(map[string]audit.Location),
+		Overrides:  make(map[string][]audit.Location),
+		Unresolved: make(map[string][]string),
+	}
+	chains := make(map[string][]audit.Location)
+	seenDuplicate := make(map[string]bool)
+
+	for _, path := range paths {
+		fileOpts := opts
+		fileOpts.LookupEnv = func(key string) (string, bool) {
+			if value, ok := merged.Entries[key]; ok {
+				return value, true
+			}
+			if opts.LookupEnv != nil {
+				return opts.LookupEnv(key)
+			}
+			return "", false
+		}
+		result, err := ParseEnvFileWith(path, fileOpts)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range result.Entries {
+			loc := audit.Location{File: path, Line: result.Lines[key]}
+			merged.Entries[key] = value
+			merged.Locations[key] = loc
+			chains[key] = append(chains[key], loc)
+			if names, ok := result.Unresolved[key]; ok {
+				merged.Unresolved[key] = names
+			} else {
+				delete(merged.Unresolved, key)
+			}
+		}
+		for _, key := range result.Duplicates {
+			if !seenDuplicate[key] {
+				seenDuplicate[key] = true
+				merged.Duplicates = append(merged.Duplicates, key)
+			}
+		}
+	}
+
+	for key, chain := range chains {
+		if len(chain) < 2 {
+			continue
+		}
+		reversed := make([]audit.Location, len(chain))
+		for i, loc := range chain {
+			reversed[len(chain)-1-i] = loc
+		}
+		merged.Overrides[key] = reversed
+	}
+
+	return merged, nil
+}
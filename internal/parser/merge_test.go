@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"env-audit/internal/audit"
+)
+
+func TestMergeFiles_LaterFilesOverride(t *testing.T) {
+	tmpDir := t.TempDir()
+	base := filepath.Join(tmpDir, ".env")
+	local := filepath.Join(tmpDir, ".env.local")
+	os.WriteFile(base, []byte("APP=base\nPORT=80\nPORT=81\n"), 0644)
+	os.WriteFile(local, []byte("PORT=8080\nEXTRA=1\n"), 0644)
+
+	merged, err := MergeFiles([]string{base, local})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if merged.Entries["PORT"] != "8080" || merged.Entries["APP"] != "base" || merged.Entries["EXTRA"] != "1" {
+		t.Errorf("unexpected merged entries: %v", merged.Entries)
+	}
+	if merged.Locations["PORT"] != (audit.Location{File: local, Line: 1}) {
+		t.Errorf("expected PORT from %s:1, got %v", local, merged.Locations["PORT"])
+	}
+	chain := merged.Overrides["PORT"]
+	if len(chain) != 2 || chain[0].File != local || chain[1] != (audit.Location{File: base, Line: 3}) {
+		t.Errorf("unexpected override chain: %v", chain)
+	}
+	if _, ok := merged.Overrides["APP"]; ok {
+		t.Error("APP is not overridden")
+	}
+	if len(merged.Duplicates) != 1 || merged.Duplicates[0] != "PORT" {
+		t.Errorf("expected in-file duplicate PORT, got %v", merged.Duplicates)
+	}
+}
+
+func TestMergeFiles_MissingFile(t *testing.T) {
+	_, err := MergeFiles([]string{"/nonexistent/.env"})
+	if err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestParseEnvFile_Lines(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, ".env")
+	os.WriteFile(path, []byte("# comment\n\nA=1\nB=2\nA=3\n"), 0644)
+
+	result, err := ParseEnvFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Lines["A"] != 5 || result.Lines["B"] != 4 {
+		t.Errorf("unexpected lines: %v", result.Lines)
+	}
+}
+
+func TestMergeFilesWith_ExpandAcrossLayers(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, ".env")
+	local := filepath.Join(dir, ".env.local")
+	os.WriteFile(base, []byte("HOST=db\n"), 0644)
+	os.WriteFile(local, []byte("URL=postgres://${HOST}/app\nBROKEN=${NOPE}\n"), 0644)
+
+	merged, err := MergeFilesWith([]string{base, local}, ParseOptions{Expand: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if merged.Entries["URL"] != "postgres://db/app" {
+		t.Errorf("expected a reference to the base file to expand, got %q", merged.Entries["URL"])
+	}
+	if len(merged.Unresolved["BROKEN"]) != 1 {
+		t.Errorf("expected BROKEN unresolved, got %v", merged.Unresolved)
+	}
+}
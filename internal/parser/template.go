@@ -9,7 +9,15 @@ import (
 
 // GenerateTemplate creates .env.example content from an environment map.
 // Sensitive keys get empty values, non-sensitive keys get placeholder values.
+// Keys that are secrets themselves, such as a pasted token, are left out:
+// the example is committed.
 func GenerateTemplate(env map[string]string) string {
+	return GenerateDocumentedTemplate(env, nil)
+}
+
+// GenerateDocumentedTemplate is like GenerateTemplate but writes each key's
+// description, if any, as a comment line above the entry.
+func GenerateDocumentedTemplate(env map[string]string, descriptions map[string]string) string {
 	if len(env) == 0 {
 		return ""
 	}
@@ -17,12 +25,19 @@ func GenerateTemplate(env map[string]string) string {
 	// Sort keys for consistent output
 	keys := make([]string, 0, len(env))
 	for key := range env {
-		keys = append(keys, key)
+		if !audit.IsSecretKey(key) {
+			keys = append(keys, key)
+		}
 	}
 	sort.Strings(keys)
 
 	var lines []string
 	for _, key := range keys {
+		if desc := descriptions[key]; desc != "" {
+			for _, line := range strings.Split(desc, "\n") {
+				lines = append(lines, strings.TrimRight("# "+line, " "))
+			}
+		}
 		if audit.IsSensitiveKey(key) {
 			lines = append(lines, key+"=")
 		} else {
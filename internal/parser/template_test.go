@@ -189,3 +189,20 @@ func TestGenerateTemplate_SortedOutput(t *testing.T) {
 		t.Errorf("expected third line to be ZEBRA, got %q", lines[2])
 	}
 }
+
+func TestGenerateDocumentedTemplate_Descriptions(t *testing.T) {
+	env := map[string]string{
+		"PORT":    "8080",
+		"API_KEY": "secret",
+	}
+	descriptions := map[string]string{
+		"PORT":    "HTTP listen port",
+		"API_KEY": "Issued by the billing team\nRotate quarterly",
+	}
+	result := GenerateDocumentedTemplate(env, descriptions)
+
+	expected := "# Issued by the billing team\n# Rotate quarterly\nAPI_KEY=\n# HTTP listen port\nPORT=your_port_here"
+	if result != expected {
+		t.Errorf("unexpected template:\n%s", result)
+	}
+}
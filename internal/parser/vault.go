@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// vaultKeyPrefix prefixes each encrypted environment in a .env.vault file
+const vaultKeyPrefix = "DOTENV_VAULT_"
+
+// VaultEnvironment is one decrypted environment from a .env.vault file
+type VaultEnvironment struct {
+	Name   string // environment name from the DOTENV_KEY, e.g. "production"
+	Result *ParseResult
+}
+
+// IsVaultFile reports whether path is a dotenv-vault file
+func IsVaultFile(path string) bool {
+	return strings.HasSuffix(filepath.Base(path), ".vault")
+}
+
+// ParseVaultFile decrypts every environment of a .env.vault file for which
+// dotenvKeys (a comma-separated list of DOTENV_KEY URIs) holds a key. The
+// plaintext is only ever held in memory.
+func ParseVaultFile(path, dotenvKeys string) ([]VaultEnvironment, error) {
+	if strings.TrimSpace(dotenvKeys) == "" {
+		return nil, fmt.Errorf("%s: DOTENV_KEY is required to decrypt vault files", path)
+	}
+
+	vault, err := ParseEnvFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var environments []VaultEnvironment
+	for _, uri := range strings.Split(dotenvKeys, ",") {
+		key, environment, err := parseVaultKey(strings.TrimSpace(uri))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		ciphertext, ok := vault.Entries[vaultKeyPrefix+strings.ToUpper(environment)]
+		if !ok {
+			return nil, fmt.Errorf("%s: no %s environment in vault", path, environment)
+		}
+		plaintext, err := decryptVaultValue(ciphertext, key)
+		if err != nil {
+			return nil, fmt.Errorf("%s: cannot decrypt %s environment: %w", path, environment, err)
+		}
+
+		result, err := ParseEnv(strings.NewReader(plaintext))
+		if err != nil {
+			return nil, err
+		}
+		environments = append(environments, VaultEnvironment{Name: environment, Result: result})
+	}
+
+	return environments, nil
+}
+
+// parseVaultKey extracts the AES key and environment from a DOTENV_KEY URI:
+// dotenv://:key_<64 hex chars>@dotenv.org/vault/.env.vault?environment=production
+func parseVaultKey(uri string) ([]byte, string, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.User == nil {
+		return nil, "", errors.New("invalid DOTENV_KEY format")
+	}
+	password, _ := u.User.Password()
+	if len(password) < 64 {
+		return nil, "", errors.New("invalid DOTENV_KEY: missing key part")
+	}
+	environment := u.Query().Get("environment")
+	if environment == "" {
+		return nil, "", errors.New("invalid DOTENV_KEY: missing environment")
+	}
+
+	key, err := hex.DecodeString(password[len(password)-64:])
+	if err != nil {
+		return nil, "", errors.New("invalid DOTENV_KEY: key is not hex")
+	}
+	return key, environment, nil
+}
+
+// decryptVaultValue decrypts a base64 nonce||ciphertext||tag blob with AES-256-GCM
+func decryptVaultValue(encoded string, key []byte) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errors.New("ciphertext is not valid base64")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize()+gcm.Overhead() {
+		return "", errors.New("ciphertext too short")
+	}
+
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", errors.New("wrong key or corrupted data")
+	}
+	return string(plaintext), nil
+}
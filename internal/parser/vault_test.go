@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testVaultKeyHex = "e31ef7b8f5e1e5c1b5a4a6d1c3e2f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3"
+
+// encryptVaultValue mirrors dotenv-vault's encryption: base64(nonce||ciphertext||tag)
+func encryptVaultValue(t *testing.T, plaintext string) string {
+	t.Helper()
+	key, _ := hex.DecodeString(testVaultKeyHex)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed)
+}
+
+func testVaultKey(environment string) string {
+	return "dotenv://:key_" + testVaultKeyHex + "@dotenv.org/vault/.env.vault?environment=" + environment
+}
+
+func writeVault(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".env.vault")
+	content := `DOTENV_VAULT_DEVELOPMENT="` + encryptVaultValue(t, "APP=dev\nDB_URL=\n") + `"` + "\n" +
+		`DOTENV_VAULT_PRODUCTION="` + encryptVaultValue(t, "APP=prod\n") + `"` + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseVaultFile(t *testing.T) {
+	path := writeVault(t)
+
+	envs, err := ParseVaultFile(path, testVaultKey("development")+", "+testVaultKey("production"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(envs) != 2 || envs[0].Name != "development" || envs[1].Name != "production" {
+		t.Fatalf("unexpected environments: %+v", envs)
+	}
+	if envs[0].Result.Entries["APP"] != "dev" || envs[1].Result.Entries["APP"] != "prod" {
+		t.Errorf("unexpected decrypted entries: %v %v", envs[0].Result.Entries, envs[1].Result.Entries)
+	}
+	if envs[0].Result.Lines["DB_URL"] != 2 {
+		t.Errorf("expected line numbers within decrypted content, got %v", envs[0].Result.Lines)
+	}
+}
+
+func TestParseVaultFile_Errors(t *testing.T) {
+	path := writeVault(t)
+	wrongKey := "dotenv://:key_" + strings.Repeat("0", 64) + "@dotenv.org/vault/.env.vault?environment=production"
+
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{"no key", ""},
+		{"malformed key", "not-a-uri"},
+		{"missing environment", "dotenv://:key_" + testVaultKeyHex + "@dotenv.org/vault/.env.vault"},
+		{"unknown environment", testVaultKey("staging")},
+		{"wrong key", wrongKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseVaultFile(path, tt.key); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestIsVaultFile(t *testing.T) {
+	if !IsVaultFile("config/.env.vault") {
+		t.Error("expected .env.vault to be a vault file")
+	}
+	if IsVaultFile(".env") {
+		t.Error("expected .env not to be a vault file")
+	}
+}
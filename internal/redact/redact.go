@@ -0,0 +1,104 @@
+package redact
+
+import "env-audit/internal/audit"
+
+// Placeholder is the default replacement for redacted values
+const Placeholder = "[REDACTED]"
+
+// Mode controls how much of a redacted value is masked
+type Mode int
+
+const (
+	// ModeFull replaces the whole value with the placeholder
+	ModeFull Mode = iota
+	// ModePartial keeps a short prefix and masks the rest
+	ModePartial
+)
+
+// minPartialLength is the shortest value that may be partially revealed.
+// Shorter values are always fully masked so the prefix can't give them away.
+const minPartialLength = 12
+
+// Redactor is the single place where secret values are masked before output.
+// Every formatter and writer goes through a Redactor so the policy is consistent.
+type Redactor struct {
+	Mode        Mode
+	Placeholder string
+	Reveal      int                   // prefix characters kept in partial mode
+	IsSensitive func(key string) bool // defaults to audit.IsSensitiveKey
+}
+
+// Default returns a Redactor that fully masks sensitive values
+func Default() *Redactor {
+	return &Redactor{Mode: ModeFull, Placeholder: Placeholder}
+}
+
+// ParseMode converts a mode name from config ("full", "partial") to a Mode
+func ParseMode(name string) (Mode, bool) {
+	switch name {
+	case "", "full":
+		return ModeFull, true
+	case "partial":
+		return ModePartial, true
+	default:
+		return ModeFull, false
+	}
+}
+
+// Sensitive reports whether values of key must be redacted
+func (r *Redactor) Sensitive(key string) bool {
+	if r != nil && r.IsSensitive != nil {
+		return r.IsSensitive(key)
+	}
+	return audit.IsSensitiveKey(key)
+}
+
+// Secret reports whether value must be redacted: its key is sensitive, or
+// the value itself looks like a credential, such as an AWS key under NOTE.
+// A nil Redactor redacts nothing.
+func (r *Redactor) Secret(key, value string) bool {
+	if r == nil {
+		return false
+	}
+	return r.Sensitive(key) || audit.LooksLikeSecret(value)
+}
+
+// Value returns value masked if it is a secret, unchanged otherwise.
+// A nil Redactor disables redaction.
+func (r *Redactor) Value(key, value string) string {
+	if !r.Secret(key, value) {
+		return value
+	}
+	return r.Mask(value)
+}
+
+// Key returns key masked if the name is itself a secret, such as a token
+// pasted on the left of "=", unchanged otherwise. A nil Redactor disables
+// redaction.
+func (r *Redactor) Key(key string) string {
+	if r == nil || !audit.IsSecretKey(key) {
+		return key
+	}
+	return audit.MaskKey(key)
+}
+
+// Mask unconditionally masks value according to the policy
+func (r *Redactor) Mask(value string) string {
+	placeholder := Placeholder
+	if r != nil && r.Placeholder != "" {
+		placeholder = r.Placeholder
+	}
+	if r == nil || r.Mode != ModePartial || r.Reveal <= 0 {
+		return placeholder
+	}
+
+	// Never reveal more than a quarter of the value
+	reveal := r.Reveal
+	if limit := len(value) / 4; reveal > limit {
+		reveal = limit
+	}
+	if len(value) < minPartialLength || reveal == 0 {
+		return placeholder
+	}
+	return value[:reveal] + placeholder
+}
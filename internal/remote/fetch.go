@@ -0,0 +1,87 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultWorkers is the number of requests FetchAll runs at once by default
+const DefaultWorkers = 4
+
+// Fetch is the outcome of reading the config vars of one deployment target
+type Fetch struct {
+	Target string
+	Vars   map[string]string
+	Err    error
+}
+
+// FetchAll reads the config vars of app for every target, running up to
+// workers requests at once (DefaultWorkers if workers < 1). Targets still
+// pending when timeout elapses fail with a timeout error, and their requests
+// are cancelled; a zero timeout waits for every request. Cancelling ctx
+// cancels them too. Results follow the order of targets.
+func FetchAll(ctx context.Context, p Provider, app string, targets []string, workers int, timeout time.Duration) []Fetch {
+	if workers < 1 {
+		workers = DefaultWorkers
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	results := make([]Fetch, len(targets))
+	done := make(chan int, len(targets))
+	slots := make(chan struct{}, workers)
+
+	for i, target := range targets {
+		results[i].Target = target
+		go func(i int, target string) {
+			// A target still queued at the deadline is never requested
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-slots }()
+			vars, err := p.ConfigVars(ctx, app, target)
+			// Results are only written here before signalling done, and read
+			// by FetchAll after receiving it
+			results[i].Vars, results[i].Err = vars, err
+			done <- i
+		}(i, target)
+	}
+
+	finished := make([]bool, len(targets))
+	for remaining := len(targets); remaining > 0; remaining-- {
+		select {
+		case i := <-done:
+			finished[i] = true
+		case <-ctx.Done():
+			// Returning cancels the requests still running; their results
+			// are discarded
+			out := make([]Fetch, len(targets))
+			for i, target := range targets {
+				out[i] = Fetch{Target: target}
+				switch {
+				case finished[i]:
+					out[i] = results[i]
+				case ctx.Err() == context.DeadlineExceeded && timeout > 0:
+					out[i].Err = fmt.Errorf("%s: timed out after %s", targetLabel(target), timeout)
+				default:
+					out[i].Err = fmt.Errorf("%s: %w", targetLabel(target), ctx.Err())
+				}
+			}
+			return out
+		}
+	}
+	return results
+}
+
+// targetLabel names a deployment target in messages
+func targetLabel(target string) string {
+	if target == "" {
+		return "default target"
+	}
+	return "target " + target
+}
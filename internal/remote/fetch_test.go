@@ -0,0 +1,125 @@
+package remote
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// slowProvider answers after a delay per target and records how many
+// requests ran at once and which were cancelled
+type slowProvider struct {
+	delay map[string]time.Duration
+
+	mu        sync.Mutex
+	inFlight  int
+	peak      int
+	cancelled []string
+}
+
+func (p *slowProvider) ConfigVars(ctx context.Context, app, target string) (map[string]string, error) {
+	p.mu.Lock()
+	p.inFlight++
+	if p.inFlight > p.peak {
+		p.peak = p.inFlight
+	}
+	p.mu.Unlock()
+
+	var err error
+	select {
+	case <-time.After(p.delay[target]):
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.inFlight--
+	if err != nil {
+		p.cancelled = append(p.cancelled, target)
+		return nil, err
+	}
+	return map[string]string{"TARGET": target}, nil
+}
+
+func TestFetchAll_KeepsOrderAndBoundsConcurrency(t *testing.T) {
+	targets := []string{"a", "b", "c", "d", "e", "f"}
+	p := &slowProvider{delay: map[string]time.Duration{"a": 30 * time.Millisecond}}
+	for _, target := range targets[1:] {
+		p.delay[target] = 10 * time.Millisecond
+	}
+
+	fetches := FetchAll(context.Background(), p, "app", targets, 2, 0)
+
+	for i, fetch := range fetches {
+		if fetch.Err != nil || fetch.Target != targets[i] || fetch.Vars["TARGET"] != targets[i] {
+			t.Errorf("fetch %d: unexpected %+v", i, fetch)
+		}
+	}
+	if p.peak != 2 {
+		t.Errorf("expected at most 2 requests at once, peaked at %d", p.peak)
+	}
+}
+
+func TestFetchAll_Timeout(t *testing.T) {
+	p := &slowProvider{delay: map[string]time.Duration{"slow": time.Second}}
+
+	start := time.Now()
+	fetches := FetchAll(context.Background(), p, "app", []string{"fast", "slow"}, 2, 50*time.Millisecond)
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected FetchAll to give up at the timeout, took %s", elapsed)
+	}
+	if fetches[0].Err != nil || fetches[0].Vars["TARGET"] != "fast" {
+		t.Errorf("expected the fast target to complete, got %+v", fetches[0])
+	}
+	if fetches[1].Err == nil || !strings.Contains(fetches[1].Err.Error(), "target slow: timed out after 50ms") {
+		t.Errorf("expected the slow target to time out, got %v", fetches[1].Err)
+	}
+}
+
+func TestFetchAll_TimeoutCancelsPendingRequests(t *testing.T) {
+	p := &slowProvider{delay: map[string]time.Duration{"slow": time.Second}}
+
+	FetchAll(context.Background(), p, "app", []string{"fast", "slow"}, 2, 50*time.Millisecond)
+
+	// The slow request sees its context cancelled instead of running on
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for {
+		p.mu.Lock()
+		cancelled := append([]string(nil), p.cancelled...)
+		p.mu.Unlock()
+		if len(cancelled) == 1 && cancelled[0] == "slow" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the slow request to be cancelled, got %v", cancelled)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestFetchAll_TimeoutCancelsHTTPRequests(t *testing.T) {
+	cancelled := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		close(cancelled)
+	}))
+	defer server.Close()
+	h := &Heroku{Token: "t", BaseURL: server.URL, Client: server.Client()}
+
+	fetches := FetchAll(context.Background(), h, "myapp", []string{""}, 1, 50*time.Millisecond)
+
+	if fetches[0].Err == nil || !strings.Contains(fetches[0].Err.Error(), "timed out after 50ms") {
+		t.Errorf("expected a timeout, got %v", fetches[0].Err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("expected the abandoned request to be cancelled on the server")
+	}
+}
@@ -0,0 +1,50 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// herokuAPI is the base URL of the Heroku Platform API
+const herokuAPI = "https://api.heroku.com"
+
+// Heroku reads config vars through the Heroku Platform API
+type Heroku struct {
+	Token   string
+	BaseURL string       // defaults to the public Platform API
+	Client  *http.Client // defaults to a client with a 30s timeout
+}
+
+// NewHeroku returns a Heroku provider authenticated with token
+func NewHeroku(token string) *Heroku {
+	return &Heroku{Token: token, BaseURL: herokuAPI}
+}
+
+// ConfigVars returns the config vars of a Heroku app. Heroku apps have a
+// single environment, so only the production target is accepted.
+func (h *Heroku) ConfigVars(ctx context.Context, app, target string) (map[string]string, error) {
+	if app == "" {
+		return nil, fmt.Errorf("heroku: app name is required")
+	}
+	if target != "" && target != TargetProduction {
+		return nil, fmt.Errorf("heroku: apps have no %s target; use a separate app", target)
+	}
+	base := h.BaseURL
+	if base == "" {
+		base = herokuAPI
+	}
+
+	header := bearer(h.Token)
+	header.Set("Accept", "application/vnd.heroku+json; version=3")
+
+	var vars map[string]string
+	if err := getJSON(ctx, h.Client, "heroku", base+"/apps/"+url.PathEscape(app)+"/config-vars", header, &vars); err != nil {
+		return nil, err
+	}
+	if vars == nil {
+		vars = map[string]string{}
+	}
+	return vars, nil
+}
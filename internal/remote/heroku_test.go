@@ -0,0 +1,75 @@
+package remote
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHeroku_ConfigVars(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/apps/myapp/config-vars" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			t.Errorf("missing bearer token, got %q", r.Header.Get("Authorization"))
+		}
+		if !strings.Contains(r.Header.Get("Accept"), "version=3") {
+			t.Errorf("missing API version header, got %q", r.Header.Get("Accept"))
+		}
+		w.Write([]byte(`{"DATABASE_URL":"postgres://prod","PORT":"5000"}`))
+	}))
+	defer server.Close()
+
+	h := &Heroku{Token: "tok", BaseURL: server.URL}
+	vars, err := h.ConfigVars(context.Background(), "myapp", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(vars) != 2 || vars["PORT"] != "5000" {
+		t.Errorf("unexpected vars: %v", vars)
+	}
+}
+
+func TestHeroku_ConfigVars_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"id":"not_found","message":"Couldn't find that app."}`))
+	}))
+	defer server.Close()
+
+	h := &Heroku{Token: "tok", BaseURL: server.URL}
+	_, err := h.ConfigVars(context.Background(), "missing", "")
+	if err == nil || !strings.Contains(err.Error(), "Couldn't find that app.") {
+		t.Errorf("expected API message in error, got %v", err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Setenv("HEROKU_API_KEY", "")
+	if _, err := New("heroku"); err == nil || !strings.Contains(err.Error(), "HEROKU_API_KEY") {
+		t.Errorf("expected missing token error, got %v", err)
+	}
+
+	t.Setenv("HEROKU_API_KEY", "tok")
+	p, err := New("heroku")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h, ok := p.(*Heroku); !ok || h.Token != "tok" {
+		t.Errorf("expected authenticated Heroku provider, got %#v", p)
+	}
+
+	if _, err := New("nope"); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
+
+func TestHeroku_ConfigVars_RejectsPreviewTarget(t *testing.T) {
+	h := &Heroku{Token: "tok", BaseURL: "http://unused.invalid"}
+	if _, err := h.ConfigVars(context.Background(), "myapp", TargetPreview); err == nil {
+		t.Error("expected error for a preview target")
+	}
+}
@@ -0,0 +1,87 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// netlifyAPI is the base URL of the Netlify API
+const netlifyAPI = "https://api.netlify.com/api/v1"
+
+// netlifyContexts maps deployment targets to Netlify deploy contexts
+var netlifyContexts = map[string]string{
+	TargetProduction:  "production",
+	TargetPreview:     "deploy-preview",
+	TargetDevelopment: "dev",
+}
+
+// Netlify reads site environment variables through the Netlify API
+type Netlify struct {
+	Token   string
+	BaseURL string       // defaults to the public API
+	Client  *http.Client // defaults to a client with a 30s timeout
+}
+
+// NewNetlify returns a Netlify provider authenticated with token
+func NewNetlify(token string) *Netlify {
+	return &Netlify{Token: token, BaseURL: netlifyAPI}
+}
+
+// netlifyEnv is one entry of the account env response
+type netlifyEnv struct {
+	Key    string `json:"key"`
+	Values []struct {
+		Value   string `json:"value"`
+		Context string `json:"context"`
+	} `json:"values"`
+}
+
+// ConfigVars returns the variables of a Netlify site that apply to target.
+// A value set for the "all" context applies unless the target overrides it.
+func (n *Netlify) ConfigVars(ctx context.Context, site, target string) (map[string]string, error) {
+	if site == "" {
+		return nil, fmt.Errorf("netlify: site name or ID is required")
+	}
+	if target == "" {
+		target = TargetProduction
+	}
+	context, ok := netlifyContexts[target]
+	if !ok {
+		return nil, fmt.Errorf("netlify: unknown target %s (expected production, preview or development)", target)
+	}
+	base := n.BaseURL
+	if base == "" {
+		base = netlifyAPI
+	}
+
+	// Environment variables live on the account, filtered by site
+	var siteInfo struct {
+		ID        string `json:"id"`
+		AccountID string `json:"account_id"`
+	}
+	if err := getJSON(ctx, n.Client, "netlify", base+"/sites/"+url.PathEscape(site), bearer(n.Token), &siteInfo); err != nil {
+		return nil, err
+	}
+	var envs []netlifyEnv
+	endpoint := base + "/accounts/" + url.PathEscape(siteInfo.AccountID) + "/env?site_id=" + url.QueryEscape(siteInfo.ID)
+	if err := getJSON(ctx, n.Client, "netlify", endpoint, bearer(n.Token), &envs); err != nil {
+		return nil, err
+	}
+
+	vars := make(map[string]string)
+	for _, env := range envs {
+		for _, v := range env.Values {
+			switch v.Context {
+			case context:
+				vars[env.Key] = v.Value
+			case "all":
+				if _, set := vars[env.Key]; !set {
+					vars[env.Key] = v.Value
+				}
+			}
+		}
+	}
+	return vars, nil
+}
@@ -0,0 +1,50 @@
+package remote
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNetlify_ConfigVars_ResolvesContexts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sites/blog":
+			w.Write([]byte(`{"id":"site_1","account_id":"acct_1"}`))
+		case "/accounts/acct_1/env":
+			if r.URL.Query().Get("site_id") != "site_1" {
+				t.Errorf("expected site_id filter, got %q", r.URL.RawQuery)
+			}
+			w.Write([]byte(`[
+				{"key":"API_URL","values":[
+					{"value":"https://prod","context":"production"},
+					{"value":"https://default","context":"all"}
+				]},
+				{"key":"ANALYTICS_ID","values":[{"value":"UA-1","context":"production"}]}
+			]`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	n := &Netlify{Token: "tok", BaseURL: server.URL}
+
+	production, err := n.ConfigVars(context.Background(), "blog", TargetProduction)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if production["API_URL"] != "https://prod" || production["ANALYTICS_ID"] != "UA-1" {
+		t.Errorf("unexpected production vars: %v", production)
+	}
+
+	preview, err := n.ConfigVars(context.Background(), "blog", TargetPreview)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(preview) != 1 || preview["API_URL"] != "https://default" {
+		t.Errorf("expected only the all-context value for previews, got %v", preview)
+	}
+}
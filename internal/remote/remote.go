@@ -0,0 +1,157 @@
+// Package remote reads config vars from hosting platforms so they can be
+// compared against local env files, and writes secrets to secret stores.
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"env-audit/internal/netutil"
+)
+
+// Deployment targets shared by platforms that scope variables per environment
+const (
+	TargetProduction  = "production"
+	TargetPreview     = "preview"
+	TargetDevelopment = "development"
+)
+
+// Provider fetches the live config vars of an app on a hosting platform
+type Provider interface {
+	// ConfigVars returns every config var set on app for the deployment
+	// target. An empty target selects the platform's production environment.
+	// Cancelling ctx abandons the request.
+	ConfigVars(ctx context.Context, app, target string) (map[string]string, error)
+}
+
+// factory builds a Provider from the API token in the environment
+type factory struct {
+	tokenEnv string
+	build    func(token string) Provider
+}
+
+var providers = map[string]factory{
+	"heroku":  {tokenEnv: "HEROKU_API_KEY", build: func(token string) Provider { return NewHeroku(token) }},
+	"vercel":  {tokenEnv: "VERCEL_TOKEN", build: func(token string) Provider { return NewVercel(token, os.Getenv("VERCEL_TEAM_ID")) }},
+	"netlify": {tokenEnv: "NETLIFY_AUTH_TOKEN", build: func(token string) Provider { return NewNetlify(token) }},
+}
+
+// New returns the named provider, authenticated with the platform API token
+// read from that provider's environment variable
+func New(name string) (Provider, error) {
+	f, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider: %s (expected %s)", name, strings.Join(Names(), ", "))
+	}
+	token := os.Getenv(f.tokenEnv)
+	if token == "" {
+		return nil, fmt.Errorf("%s is required to query %s", f.tokenEnv, name)
+	}
+	return f.build(token), nil
+}
+
+// Names returns the supported provider names, sorted
+func Names() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// getJSON performs an authenticated GET and decodes the JSON response into out.
+// Error responses are reported with the API's own message when it has one.
+func getJSON(ctx context.Context, client *http.Client, provider, url string, header http.Header, out interface{}) error {
+	return sendJSON(ctx, client, provider, http.MethodGet, url, header, nil, out)
+}
+
+// sendJSON performs an authenticated request with in, if not nil, as its JSON
+// body and decodes the response into out, if not nil. Any 2xx status is a
+// success; other responses are returned as an *APIError. Cancelling ctx
+// abandons the request.
+func sendJSON(ctx context.Context, client *http.Client, provider, method, url string, header http.Header, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if in != nil && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if client == nil {
+		client = netutil.Client(30 * time.Second)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// Heroku, Netlify and AWS put the message at the top level, Vercel
+		// nests it and Vault lists errors
+		var apiErr struct {
+			Message string `json:"message"`
+			Error   struct {
+				Message string `json:"message"`
+			} `json:"error"`
+			Errors []string `json:"errors"`
+		}
+		msg := ""
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil {
+			msg = apiErr.Message + apiErr.Error.Message + strings.Join(apiErr.Errors, "; ")
+		}
+		return &APIError{Provider: provider, Status: resp.StatusCode, Message: msg}
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s: invalid response: %w", provider, err)
+	}
+	return nil
+}
+
+// APIError is an error response from a platform or secret store API
+type APIError struct {
+	Provider string
+	Status   int    // HTTP status code
+	Message  string // the API's own message, empty if it sent none
+}
+
+// Error implements error
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s: unexpected HTTP %d", e.Provider, e.Status)
+	}
+	return fmt.Sprintf("%s: %s (HTTP %d)", e.Provider, e.Message, e.Status)
+}
+
+// bearer returns request headers authenticating with an API token
+func bearer(token string) http.Header {
+	return http.Header{
+		"Accept":        {"application/json"},
+		"Authorization": {"Bearer " + token},
+	}
+}
@@ -0,0 +1,147 @@
+package remote
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"env-audit/internal/netutil"
+)
+
+// SSM stores each secret as a SecureString parameter in AWS Systems Manager
+// Parameter Store: prefix app/prod and key API_KEY is /app/prod/API_KEY
+type SSM struct {
+	Region      string
+	Credentials awsCredentials
+	BaseURL     string       // defaults to the regional SSM endpoint
+	Client      *http.Client // defaults to a client with a 30s timeout
+}
+
+// newSSMFromEnv configures SSM from AWS_REGION (or AWS_DEFAULT_REGION),
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN
+func newSSMFromEnv(getenv func(string) string) (Store, error) {
+	region := getenv("AWS_REGION")
+	if region == "" {
+		region = getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		return nil, fmt.Errorf("AWS_REGION is required to use ssm")
+	}
+	values, err := requireEnv(getenv, "ssm", "AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY")
+	if err != nil {
+		return nil, err
+	}
+	return &SSM{Region: region, Credentials: awsCredentials{AccessKeyID: values[0], SecretAccessKey: values[1], SessionToken: getenv("AWS_SESSION_TOKEN")}}, nil
+}
+
+// ssmPath returns the parameter hierarchy of prefix, e.g. /app/prod
+func ssmPath(prefix string) string {
+	return "/" + strings.Trim(prefix, "/")
+}
+
+// call invokes an SSM API action with a signed JSON request
+func (s *SSM) call(action string, in, out interface{}) error {
+	base := s.BaseURL
+	if base == "" {
+		base = "https://ssm." + s.Region + ".amazonaws.com"
+	}
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, base+"/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "AmazonSSM."+action)
+	signV4(req, body, s.Credentials, s.Region, "ssm", time.Now())
+
+	client := s.Client
+	if client == nil {
+		client = netutil.Client(30 * time.Second)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ssm: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		msg := apiErr.Message
+		if msg == "" {
+			msg = apiErr.Type
+		}
+		return &APIError{Provider: "ssm", Status: resp.StatusCode, Message: msg}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("ssm: invalid response: %w", err)
+	}
+	return nil
+}
+
+// Secrets returns the decrypted parameters directly below prefix
+func (s *SSM) Secrets(prefix string) (map[string]string, error) {
+	path := ssmPath(prefix)
+	secrets := make(map[string]string)
+	next := ""
+	for {
+		in := map[string]interface{}{"Path": path, "WithDecryption": true}
+		if next != "" {
+			in["NextToken"] = next
+		}
+		var page struct {
+			Parameters []struct {
+				Name  string `json:"Name"`
+				Value string `json:"Value"`
+			} `json:"Parameters"`
+			NextToken string `json:"NextToken"`
+		}
+		if err := s.call("GetParametersByPath", in, &page); err != nil {
+			return nil, err
+		}
+		for _, p := range page.Parameters {
+			secrets[strings.TrimPrefix(p.Name, path+"/")] = p.Value
+		}
+		if page.NextToken == "" {
+			return secrets, nil
+		}
+		next = page.NextToken
+	}
+}
+
+// PutSecrets creates or overwrites one SecureString parameter per secret,
+// in key order. SSM rejects empty values, so they fail before any is sent.
+func (s *SSM) PutSecrets(prefix string, secrets map[string]string) error {
+	keys := make([]string, 0, len(secrets))
+	for key, value := range secrets {
+		if value == "" {
+			return fmt.Errorf("ssm: %s is empty, which SSM parameters cannot be", key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		in := map[string]interface{}{
+			"Name":      ssmPath(prefix) + "/" + key,
+			"Value":     secrets[key],
+			"Type":      "SecureString",
+			"Overwrite": true,
+		}
+		var out struct {
+			Version int `json:"Version"`
+		}
+		if err := s.call("PutParameter", in, &out); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
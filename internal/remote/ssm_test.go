@@ -0,0 +1,76 @@
+package remote
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSSM_Secrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "AmazonSSM.GetParametersByPath" {
+			t.Errorf("unexpected action %q", r.Header.Get("X-Amz-Target"))
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+			t.Errorf("expected a signed request, got %q", r.Header.Get("Authorization"))
+		}
+		var in map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&in)
+		if in["Path"] != "/app/prod" || in["WithDecryption"] != true {
+			t.Errorf("unexpected request %v", in)
+		}
+		if in["NextToken"] == nil {
+			w.Write([]byte(`{"Parameters":[{"Name":"/app/prod/API_KEY","Value":"secret"}],"NextToken":"page2"}`))
+			return
+		}
+		w.Write([]byte(`{"Parameters":[{"Name":"/app/prod/DB_PASSWORD","Value":"hunter2"}]}`))
+	}))
+	defer server.Close()
+
+	s := &SSM{Region: "eu-west-1", Credentials: awsCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, BaseURL: server.URL}
+	secrets, err := s.Secrets("app/prod")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(secrets) != 2 || secrets["API_KEY"] != "secret" || secrets["DB_PASSWORD"] != "hunter2" {
+		t.Errorf("expected both pages of parameters, got %v", secrets)
+	}
+}
+
+func TestSSM_PutSecrets(t *testing.T) {
+	var names []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&in)
+		if in["Type"] != "SecureString" || in["Overwrite"] != true {
+			t.Errorf("unexpected request %v", in)
+		}
+		names = append(names, in["Name"].(string))
+		if in["Name"] == "/app/prod/LOCKED" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type":"AccessDeniedException","message":"not authorized"}`))
+			return
+		}
+		w.Write([]byte(`{"Version":1}`))
+	}))
+	defer server.Close()
+
+	s := &SSM{Region: "eu-west-1", BaseURL: server.URL}
+	if err := s.PutSecrets("app/prod", map[string]string{"B_KEY": "b", "A_KEY": "a"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(names, ",") != "/app/prod/A_KEY,/app/prod/B_KEY" {
+		t.Errorf("expected one parameter per key in key order, got %v", names)
+	}
+
+	if err := s.PutSecrets("app/prod", map[string]string{"LOCKED": "x"}); err == nil || !strings.Contains(err.Error(), "LOCKED: ssm: not authorized (HTTP 400)") {
+		t.Errorf("expected the API error, got %v", err)
+	}
+
+	names = nil
+	if err := s.PutSecrets("app/prod", map[string]string{"A_KEY": "a", "EMPTY": ""}); err == nil || len(names) != 0 {
+		t.Errorf("expected an empty value to fail before anything is sent, got %v after %v", err, names)
+	}
+}
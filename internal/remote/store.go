@@ -0,0 +1,77 @@
+package remote
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Store is a secret store that env vars can be pushed to
+type Store interface {
+	// Secrets returns the secrets stored under prefix, by key. A prefix
+	// that holds nothing yet has no secrets, not an error.
+	Secrets(prefix string) (map[string]string, error)
+	// PutSecrets stores secrets under prefix. Secrets already stored there
+	// and not in secrets keep their value.
+	PutSecrets(prefix string, secrets map[string]string) error
+}
+
+// stores builds each Store from its settings in the environment
+var stores = map[string]func(getenv func(string) string) (Store, error){
+	"vault": newVaultFromEnv,
+	"ssm":   newSSMFromEnv,
+}
+
+// NewStore returns the named secret store, configured and authenticated
+// from the environment variables its own CLI uses
+func NewStore(name string) (Store, error) {
+	build, ok := stores[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown secret store: %s (expected %s)", name, strings.Join(StoreNames(), ", "))
+	}
+	return build(os.Getenv)
+}
+
+// OpenSource returns the named store and the prefix in it of a source path,
+// as "# source:" comments write them. A vault path starts with the KV
+// mount, as in "vault kv get secret/app"; an ssm path is a parameter
+// hierarchy such as /app/prod.
+func OpenSource(name, path string) (Store, string, error) {
+	store, err := NewStore(name)
+	if err != nil {
+		return nil, "", err
+	}
+	vault, ok := store.(*Vault)
+	if !ok {
+		return store, path, nil
+	}
+	mount, prefix, found := strings.Cut(strings.Trim(path, "/"), "/")
+	if !found || prefix == "" {
+		return nil, "", fmt.Errorf("vault source %q needs a mount and a secret path, e.g. secret/app", path)
+	}
+	vault.Mount = mount
+	return vault, prefix, nil
+}
+
+// StoreNames returns the supported secret store names, sorted
+func StoreNames() []string {
+	names := make([]string, 0, len(stores))
+	for name := range stores {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// requireEnv returns the values of the named variables, or an error naming
+// the first one that is unset
+func requireEnv(getenv func(string) string, store string, names ...string) ([]string, error) {
+	values := make([]string, len(names))
+	for i, name := range names {
+		if values[i] = getenv(name); values[i] == "" {
+			return nil, fmt.Errorf("%s is required to use %s", name, store)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,61 @@
+package remote
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewStore(t *testing.T) {
+	t.Setenv("VAULT_ADDR", "https://vault.example.com")
+	t.Setenv("VAULT_TOKEN", "")
+	if _, err := NewStore("vault"); err == nil || !strings.Contains(err.Error(), "VAULT_TOKEN") {
+		t.Errorf("expected missing token error, got %v", err)
+	}
+	t.Setenv("VAULT_TOKEN", "tok")
+	store, err := NewStore("vault")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, ok := store.(*Vault); !ok || v.Token != "tok" || v.url("app") != "https://vault.example.com/v1/secret/data/app" {
+		t.Errorf("expected a configured Vault store, got %#v", store)
+	}
+
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "us-east-2")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	store, err = NewStore("ssm")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s, ok := store.(*SSM); !ok || s.Region != "us-east-2" {
+		t.Errorf("expected an SSM store in the default region, got %#v", store)
+	}
+
+	if _, err := NewStore("keychain"); err == nil || !strings.Contains(err.Error(), "ssm, vault") {
+		t.Errorf("expected an unknown store error listing the stores, got %v", err)
+	}
+}
+
+func TestOpenSource(t *testing.T) {
+	t.Setenv("VAULT_ADDR", "https://vault.example.com")
+	t.Setenv("VAULT_TOKEN", "tok")
+	t.Setenv("VAULT_MOUNT", "kv")
+	store, prefix, err := OpenSource("vault", "secret/app/prod")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v := store.(*Vault); prefix != "app/prod" || v.url(prefix) != "https://vault.example.com/v1/secret/data/app/prod" {
+		t.Errorf("expected the mount to come from the path, got %s at %s", v.url(prefix), prefix)
+	}
+	if _, _, err := OpenSource("vault", "secret"); err == nil || !strings.Contains(err.Error(), "needs a mount") {
+		t.Errorf("expected an error for a path without a secret, got %v", err)
+	}
+
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	if _, prefix, err := OpenSource("ssm", "/app/prod"); err != nil || prefix != "/app/prod" {
+		t.Errorf("expected the ssm path as the prefix, got %q, %v", prefix, err)
+	}
+}
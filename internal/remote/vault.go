@@ -0,0 +1,90 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// defaultVaultMount is the path of the KV version 2 engine Vault enables by
+// default in dev mode
+const defaultVaultMount = "secret"
+
+// Vault stores secrets as the fields of one secret in a HashiCorp Vault KV
+// version 2 engine: prefix app/prod is the secret at <mount>/data/app/prod
+type Vault struct {
+	Addr      string       // server URL, e.g. https://vault.example.com:8200
+	Token     string       // client token
+	Mount     string       // path of the KV v2 engine, defaults to "secret"
+	Namespace string       // Vault Enterprise namespace, empty for none
+	Client    *http.Client // defaults to a client with a 30s timeout
+}
+
+// newVaultFromEnv configures Vault from VAULT_ADDR, VAULT_TOKEN and the
+// optional VAULT_MOUNT and VAULT_NAMESPACE
+func newVaultFromEnv(getenv func(string) string) (Store, error) {
+	values, err := requireEnv(getenv, "vault", "VAULT_ADDR", "VAULT_TOKEN")
+	if err != nil {
+		return nil, err
+	}
+	return &Vault{Addr: values[0], Token: values[1], Mount: getenv("VAULT_MOUNT"), Namespace: getenv("VAULT_NAMESPACE")}, nil
+}
+
+// url returns the KV v2 data URL of the secret at prefix
+func (v *Vault) url(prefix string) string {
+	mount := v.Mount
+	if mount == "" {
+		mount = defaultVaultMount
+	}
+	return strings.TrimRight(v.Addr, "/") + "/v1/" + strings.Trim(mount, "/") + "/data/" + strings.Trim(prefix, "/")
+}
+
+// header returns the request headers authenticating with the token
+func (v *Vault) header() http.Header {
+	header := http.Header{"X-Vault-Token": {v.Token}}
+	if v.Namespace != "" {
+		header.Set("X-Vault-Namespace", v.Namespace)
+	}
+	return header
+}
+
+// Secrets returns the fields of the latest version of the secret at prefix
+func (v *Vault) Secrets(prefix string) (map[string]string, error) {
+	var secret struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	err := getJSON(context.Background(), v.Client, "vault", v.url(prefix), v.header(), &secret)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	secrets := make(map[string]string, len(secret.Data.Data))
+	for key, value := range secret.Data.Data {
+		if s, ok := value.(string); ok {
+			secrets[key] = s
+		} else {
+			secrets[key] = fmt.Sprint(value)
+		}
+	}
+	return secrets, nil
+}
+
+// PutSecrets writes a new version of the secret at prefix holding its
+// current fields with secrets layered over them
+func (v *Vault) PutSecrets(prefix string, secrets map[string]string) error {
+	data, err := v.Secrets(prefix)
+	if err != nil {
+		return err
+	}
+	for key, value := range secrets {
+		data[key] = value
+	}
+	return sendJSON(context.Background(), v.Client, "vault", http.MethodPost, v.url(prefix), v.header(), map[string]interface{}{"data": data}, nil)
+}
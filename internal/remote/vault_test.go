@@ -0,0 +1,69 @@
+package remote
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVault_PutSecrets(t *testing.T) {
+	stored := map[string]interface{}{"OLD_KEY": "old", "API_KEY": "v1"}
+	found := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/kv/data/app/prod" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("X-Vault-Token") != "tok" || r.Header.Get("X-Vault-Namespace") != "team" {
+			t.Errorf("missing token or namespace headers: %v", r.Header)
+		}
+		switch r.Method {
+		case http.MethodGet:
+			if !found {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"errors":[]}`))
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"data": stored}})
+		case http.MethodPost:
+			var body struct {
+				Data map[string]interface{} `json:"data"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			stored = body.Data
+			w.Write([]byte(`{"data":{"version":2}}`))
+		}
+	}))
+	defer server.Close()
+
+	v := &Vault{Addr: server.URL + "/", Token: "tok", Mount: "kv", Namespace: "team"}
+	if err := v.PutSecrets("/app/prod/", map[string]string{"API_KEY": "v2", "NEW_KEY": "new"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	secrets, err := v.Secrets("app/prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secrets) != 3 || secrets["OLD_KEY"] != "old" || secrets["API_KEY"] != "v2" || secrets["NEW_KEY"] != "new" {
+		t.Errorf("expected the new values merged over the stored ones, got %v", secrets)
+	}
+
+	found = false
+	if secrets, err := v.Secrets("app/prod"); err != nil || len(secrets) != 0 {
+		t.Errorf("expected a missing secret to have no fields, got %v, %v", secrets, err)
+	}
+}
+
+func TestVault_Secrets_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errors":["permission denied"]}`))
+	}))
+	defer server.Close()
+
+	v := &Vault{Addr: server.URL, Token: "tok"}
+	if _, err := v.Secrets("app/prod"); err == nil || !strings.Contains(err.Error(), "vault: permission denied (HTTP 403)") {
+		t.Errorf("expected Vault's error in the message, got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// vercelAPI is the base URL of the Vercel REST API
+const vercelAPI = "https://api.vercel.com"
+
+// Vercel reads project environment variables through the Vercel REST API
+type Vercel struct {
+	Token   string
+	TeamID  string       // team owning the project, empty for personal accounts
+	BaseURL string       // defaults to the public REST API
+	Client  *http.Client // defaults to a client with a 30s timeout
+}
+
+// NewVercel returns a Vercel provider authenticated with token
+func NewVercel(token, teamID string) *Vercel {
+	return &Vercel{Token: token, TeamID: teamID, BaseURL: vercelAPI}
+}
+
+// vercelEnv is one entry of the project env response
+type vercelEnv struct {
+	Key    string   `json:"key"`
+	Value  string   `json:"value"`
+	Target []string `json:"target"`
+}
+
+// ConfigVars returns the variables of a Vercel project that apply to target
+func (v *Vercel) ConfigVars(ctx context.Context, project, target string) (map[string]string, error) {
+	if project == "" {
+		return nil, fmt.Errorf("vercel: project name is required")
+	}
+	if target == "" {
+		target = TargetProduction
+	}
+	if target != TargetProduction && target != TargetPreview && target != TargetDevelopment {
+		return nil, fmt.Errorf("vercel: unknown target %s (expected production, preview or development)", target)
+	}
+	base := v.BaseURL
+	if base == "" {
+		base = vercelAPI
+	}
+
+	query := url.Values{"decrypt": {"true"}}
+	if v.TeamID != "" {
+		query.Set("teamId", v.TeamID)
+	}
+	var body struct {
+		Envs []vercelEnv `json:"envs"`
+	}
+	endpoint := base + "/v9/projects/" + url.PathEscape(project) + "/env?" + query.Encode()
+	if err := getJSON(ctx, v.Client, "vercel", endpoint, bearer(v.Token), &body); err != nil {
+		return nil, err
+	}
+
+	vars := make(map[string]string)
+	for _, env := range body.Envs {
+		for _, t := range env.Target {
+			if t == target {
+				vars[env.Key] = env.Value
+				break
+			}
+		}
+	}
+	return vars, nil
+}
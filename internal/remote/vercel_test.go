@@ -0,0 +1,60 @@
+package remote
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVercel_ConfigVars_FiltersByTarget(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v9/projects/web/env" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("teamId") != "team_1" {
+			t.Errorf("expected teamId query, got %q", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"envs":[
+			{"key":"API_URL","value":"https://api","target":["production","preview"]},
+			{"key":"PREVIEW_ONLY","value":"1","target":["preview"]},
+			{"key":"DEV_ONLY","value":"1","target":["development"]}
+		]}`))
+	}))
+	defer server.Close()
+
+	v := &Vercel{Token: "tok", TeamID: "team_1", BaseURL: server.URL}
+
+	production, err := v.ConfigVars(context.Background(), "web", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(production) != 1 || production["API_URL"] != "https://api" {
+		t.Errorf("unexpected production vars: %v", production)
+	}
+
+	preview, err := v.ConfigVars(context.Background(), "web", TargetPreview)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(preview) != 2 || preview["PREVIEW_ONLY"] != "1" {
+		t.Errorf("unexpected preview vars: %v", preview)
+	}
+}
+
+func TestVercel_ConfigVars_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":{"code":"forbidden","message":"Not authorized"}}`))
+	}))
+	defer server.Close()
+
+	v := &Vercel{Token: "tok", BaseURL: server.URL}
+	if _, err := v.ConfigVars(context.Background(), "web", ""); err == nil || !strings.Contains(err.Error(), "Not authorized") {
+		t.Errorf("expected API message in error, got %v", err)
+	}
+	if _, err := v.ConfigVars(context.Background(), "web", "staging"); err == nil {
+		t.Error("expected error for unknown target")
+	}
+}
@@ -0,0 +1,295 @@
+// Package usage finds where source code reads environment variables, so a
+// monorepo can see which services depend on which variables and which
+// variables several services load from one shared env file.
+package usage
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"env-audit/internal/discover"
+	"env-audit/internal/parser"
+)
+
+// Reference is a read of an environment variable in source code
+type Reference struct {
+	Key  string
+	File string
+	Line int
+}
+
+// readPatterns match the common ways each language reads a variable, with
+// the variable name as the first group, by source file extension
+var readPatterns = map[string][]*regexp.Regexp{}
+
+func init() {
+	languages := []struct {
+		exts     []string
+		patterns []string
+	}{
+		{[]string{".go"}, []string{`os\.(?:Getenv|LookupEnv)\(\s*"(\w+)"`}},
+		{[]string{".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx", ".vue", ".svelte"}, []string{
+			`process\.env\.([A-Za-z_]\w*)`,
+			`process\.env\[\s*["'](\w+)["']\s*\]`,
+			`import\.meta\.env\.([A-Za-z_]\w*)`,
+		}},
+		{[]string{".py"}, []string{
+			`os\.(?:environ\.get|getenv)\(\s*["'](\w+)["']`,
+			`os\.environ\[\s*["'](\w+)["']\s*\]`,
+		}},
+		{[]string{".rb"}, []string{`ENV(?:\.fetch\(|\[)\s*["'](\w+)["']`}},
+		{[]string{".rs"}, []string{`env::var(?:_os)?\(\s*"(\w+)"`, `env!\(\s*"(\w+)"`}},
+		{[]string{".java", ".kt", ".scala"}, []string{`System\.getenv\(\s*"(\w+)"`}},
+		{[]string{".php"}, []string{`getenv\(\s*["'](\w+)["']`, `\$_(?:ENV|SERVER)\[\s*["'](\w+)["']\s*\]`}},
+		{[]string{".cs"}, []string{`Environment\.GetEnvironmentVariable\(\s*"(\w+)"`}},
+	}
+	for _, lang := range languages {
+		var res []*regexp.Regexp
+		for _, p := range lang.patterns {
+			res = append(res, regexp.MustCompile(p))
+		}
+		for _, ext := range lang.exts {
+			readPatterns[ext] = res
+		}
+	}
+}
+
+// manifests mark the root directory of a service or package
+var manifests = []string{
+	"go.mod", "package.json", "pyproject.toml", "setup.py", "requirements.txt", "Gemfile",
+	"Cargo.toml", "pom.xml", "build.gradle", "build.gradle.kts", "composer.json", "Dockerfile",
+}
+
+// IsSource reports whether name is a source file whose variable reads
+// ScanFile recognizes
+func IsSource(name string) bool {
+	_, ok := readPatterns[filepath.Ext(name)]
+	return ok
+}
+
+// MaxLineLength is the longest line ScanFile reads. Longer lines, such as
+// those of minified bundles, are skipped.
+const MaxLineLength = 1 << 20
+
+// ScanFile returns the environment variable reads in a source file, and the
+// number of lines skipped for exceeding MaxLineLength
+func ScanFile(file string) ([]Reference, int, error) {
+	patterns := readPatterns[filepath.Ext(file)]
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	var refs []Reference
+	skipped := 0
+	r := bufio.NewReaderSize(f, 64*1024)
+	var buf []byte
+	for line := 1; ; line++ {
+		text, long, err := readLine(r, buf[:0])
+		if err == io.EOF {
+			return refs, skipped, nil
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		buf = text
+		if long {
+			skipped++
+			continue
+		}
+		// Reads on one line are listed in the order they appear
+		var found [][]int
+		for _, re := range patterns {
+			found = append(found, re.FindAllSubmatchIndex(text, -1)...)
+		}
+		sort.Slice(found, func(i, j int) bool { return found[i][0] < found[j][0] })
+		for _, m := range found {
+			refs = append(refs, Reference{Key: string(text[m[2]:m[3]]), File: file, Line: line})
+		}
+	}
+}
+
+// readLine appends the next line of r to buf, without its line ending. A
+// line over MaxLineLength is read to its end but not kept, and reported as
+// long. It returns io.EOF once r has no more lines.
+func readLine(r *bufio.Reader, buf []byte) ([]byte, bool, error) {
+	long := false
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if !long && len(buf)+len(bytes.TrimRight(chunk, "\r\n")) > MaxLineLength {
+			long, buf = true, buf[:0]
+		}
+		if !long {
+			buf = append(buf, chunk...)
+		}
+		switch {
+		case err == bufio.ErrBufferFull:
+			continue
+		case err == io.EOF && (len(buf) > 0 || long):
+		case err != nil:
+			return buf, false, err
+		}
+		return bytes.TrimRight(buf, "\r\n"), long, nil
+	}
+}
+
+// Variable is a variable and the services whose source reads it
+type Variable struct {
+	Key        string   `json:"key"`
+	Services   []string `json:"services"`   // service directories relative to the root, sorted
+	References int      `json:"references"` // reads across all services
+}
+
+// Shared is a variable that several services read from one env file, a
+// sign that the file should be split per service
+type Shared struct {
+	File     string   `json:"file"`
+	Key      string   `json:"key"`
+	Services []string `json:"services"`
+}
+
+// LongLines is a source file with lines too long to scan, whose reads on
+// those lines are missing from the report
+type LongLines struct {
+	File  string `json:"file"`
+	Lines int    `json:"lines"`
+}
+
+// Report is the variable usage of a source tree
+type Report struct {
+	Variables []Variable  `json:"variables"`  // most widely used first
+	Shared    []Shared    `json:"shared"`     // by file, then key
+	LongLines []LongLines `json:"long_lines"` // in the order the files were found
+}
+
+// Analyze scans the source and env files under root. A source file belongs
+// to the service of the nearest directory at or above it with a manifest
+// such as go.mod or package.json, or to the root ("."). An env file is
+// shared by the services at or below its directory. Lines over
+// MaxLineLength are skipped and listed in LongLines.
+func Analyze(root string, opts discover.Options) (*Report, error) {
+	sources, err := discover.FindFunc([]string{root}, opts, IsSource)
+	if err != nil {
+		return nil, err
+	}
+	services := newServiceFinder(root)
+	readers := make(map[string]map[string]int) // key -> service -> reads
+	longLines := []LongLines{}
+	for _, file := range sources {
+		refs, skipped, err := ScanFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if skipped > 0 {
+			longLines = append(longLines, LongLines{File: file, Lines: skipped})
+		}
+		service := services.of(file)
+		for _, ref := range refs {
+			if readers[ref.Key] == nil {
+				readers[ref.Key] = make(map[string]int)
+			}
+			readers[ref.Key][service]++
+		}
+	}
+
+	report := &Report{Variables: []Variable{}, Shared: []Shared{}, LongLines: longLines}
+	for key, byService := range readers {
+		v := Variable{Key: key}
+		for service, n := range byService {
+			v.Services = append(v.Services, service)
+			v.References += n
+		}
+		sort.Strings(v.Services)
+		report.Variables = append(report.Variables, v)
+	}
+	sort.Slice(report.Variables, func(i, j int) bool {
+		a, b := report.Variables[i], report.Variables[j]
+		if len(a.Services) != len(b.Services) {
+			return len(a.Services) > len(b.Services)
+		}
+		return a.Key < b.Key
+	})
+
+	envFiles, err := discover.Find([]string{root}, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range envFiles {
+		env, err := parser.ParseEnvFile(file)
+		if err != nil {
+			return nil, err
+		}
+		dir := services.rel(filepath.Dir(file))
+		for _, key := range env.Keys {
+			var users []string
+			for service := range readers[key] {
+				if within(service, dir) {
+					users = append(users, service)
+				}
+			}
+			if len(users) > 1 {
+				sort.Strings(users)
+				report.Shared = append(report.Shared, Shared{File: file, Key: key, Services: users})
+			}
+		}
+	}
+	return report, nil
+}
+
+// within reports whether the slash-separated directory dir is parent or a
+// directory below it; "." is the root
+func within(dir, parent string) bool {
+	return parent == "." || dir == parent || strings.HasPrefix(dir, parent+"/")
+}
+
+// serviceFinder maps files to their service directory, caching the
+// manifest lookups of each directory
+type serviceFinder struct {
+	root  string
+	cache map[string]string // directory relative to root -> service
+}
+
+func newServiceFinder(root string) *serviceFinder {
+	return &serviceFinder{root: root, cache: make(map[string]string)}
+}
+
+// rel returns a path relative to the root, slash-separated
+func (s *serviceFinder) rel(p string) string {
+	rel, err := filepath.Rel(s.root, p)
+	if err != nil {
+		return filepath.ToSlash(p)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// of returns the service a file belongs to
+func (s *serviceFinder) of(file string) string {
+	return s.dirService(s.rel(filepath.Dir(file)))
+}
+
+// dirService returns the service of a directory relative to the root
+func (s *serviceFinder) dirService(dir string) string {
+	if service, ok := s.cache[dir]; ok {
+		return service
+	}
+	service := "."
+	if dir != "." {
+		service = s.dirService(path.Dir(dir))
+		for _, name := range manifests {
+			if _, err := os.Stat(filepath.Join(s.root, filepath.FromSlash(dir), name)); err == nil {
+				service = dir
+				break
+			}
+		}
+	}
+	s.cache[dir] = service
+	return service
+}
@@ -0,0 +1,119 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"env-audit/internal/discover"
+)
+
+// writeTree creates files below dir from a map of slash paths to contents
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestScanFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"main.go", `port := os.Getenv("PORT"); _, ok := os.LookupEnv("DEBUG")`, []string{"PORT", "DEBUG"}},
+		{"app.ts", "const url = process.env.DATABASE_URL ?? process.env['FALLBACK_URL']\nimport.meta.env.VITE_API", []string{"DATABASE_URL", "FALLBACK_URL", "VITE_API"}},
+		{"app.py", `os.environ["SECRET_KEY"]; os.environ.get('REDIS_URL'); os.getenv("PORT", "80")`, []string{"SECRET_KEY", "REDIS_URL", "PORT"}},
+		{"app.rb", `ENV["RAILS_ENV"]; ENV.fetch('DATABASE_URL')`, []string{"RAILS_ENV", "DATABASE_URL"}},
+		{"main.rs", `env::var("RUST_LOG"); env!("CARGO_PKG_NAME")`, []string{"RUST_LOG", "CARGO_PKG_NAME"}},
+		{"App.java", `System.getenv("JAVA_OPTS")`, []string{"JAVA_OPTS"}},
+		{"notes.md", `os.Getenv("PORT")`, nil},
+	}
+	dir := t.TempDir()
+	for _, tt := range tests {
+		file := filepath.Join(dir, tt.name)
+		os.WriteFile(file, []byte(tt.content), 0644)
+		if !IsSource(tt.name) {
+			if tt.want != nil {
+				t.Errorf("%s: expected a source file", tt.name)
+			}
+			continue
+		}
+		refs, skipped, err := ScanFile(file)
+		if err != nil || skipped != 0 {
+			t.Fatalf("%s: unexpected %d skipped lines, %v", tt.name, skipped, err)
+		}
+		var got []string
+		for _, ref := range refs {
+			got = append(got, ref.Key)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestScanFile_SkipsLongLines(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "bundle.min.js")
+	bundle := "process.env.FIRST\r\n" +
+		"var a=process.env.MINIFIED;" + strings.Repeat("x", MaxLineLength) + "\n" +
+		"process.env.AFTER\n" +
+		strings.Repeat("y", MaxLineLength+1) // last line, no newline
+	os.WriteFile(file, []byte(bundle), 0644)
+
+	refs, skipped, err := ScanFile(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if skipped != 2 {
+		t.Errorf("expected 2 skipped lines, got %d", skipped)
+	}
+	want := []Reference{{Key: "FIRST", File: file, Line: 1}, {Key: "AFTER", File: file, Line: 3}}
+	if !reflect.DeepEqual(refs, want) {
+		t.Errorf("got %+v, want %+v", refs, want)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		".env":                         "DATABASE_URL=postgres://db\nPORT=8080\nUNUSED=x\n",
+		"services/api/go.mod":          "module api\n",
+		"services/api/main.go":         `db := os.Getenv("DATABASE_URL")` + "\n" + `port := os.Getenv("PORT")`,
+		"services/api/internal/db.go":  `os.Getenv("DATABASE_URL")`,
+		"services/worker/package.json": "{}",
+		"services/worker/src/index.js": "process.env.DATABASE_URL\nprocess.env.QUEUE_URL",
+		"services/worker/.env":         "QUEUE_URL=amqp://q\nDATABASE_URL=postgres://worker\n",
+		"scripts/seed.py":              `os.environ["DATABASE_URL"]`,
+		"node_modules/lib/index.js":    "process.env.IGNORED",
+	})
+
+	report, err := Analyze(root, discover.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantVars := []Variable{
+		{Key: "DATABASE_URL", Services: []string{".", "services/api", "services/worker"}, References: 4},
+		{Key: "PORT", Services: []string{"services/api"}, References: 1},
+		{Key: "QUEUE_URL", Services: []string{"services/worker"}, References: 1},
+	}
+	if !reflect.DeepEqual(report.Variables, wantVars) {
+		t.Errorf("unexpected variables:\n got %+v\nwant %+v", report.Variables, wantVars)
+	}
+	wantShared := []Shared{{File: filepath.Join(root, ".env"), Key: "DATABASE_URL", Services: []string{".", "services/api", "services/worker"}}}
+	if !reflect.DeepEqual(report.Shared, wantShared) {
+		t.Errorf("unexpected shared variables:\n got %+v\nwant %+v", report.Shared, wantShared)
+	}
+	if len(report.LongLines) != 0 {
+		t.Errorf("expected no long lines, got %+v", report.LongLines)
+	}
+}